@@ -0,0 +1,347 @@
+// Package a11y checks rendered markup for common accessibility mistakes:
+// images without alternative text, form controls without labels, buttons
+// without accessible names and invalid ARIA.
+//
+// Check renders a tree and returns the problems as Findings, for use in
+// tests. Assert wraps a node so that renders with assertions enabled through
+// node.WithAssertions, typically development servers, report each Finding as
+// an error while other renders pass the node through untouched. Headings
+// checks a whole document's heading outline.
+//
+// The checks work on the output alone, so they see exactly what a browser
+// would, but they cannot follow label associations into markup outside the
+// checked tree. They catch the mistakes that are easy to make and easy to
+// detect; they are no substitute for testing with assistive technology.
+//
+// Usage:
+//
+//	for _, f := range a11y.Check(page) {
+//	    t.Error(f)
+//	}
+//
+//	ctx := node.WithAssertions(r.Context(), func(err error) { log.Print(err) })
+//	fluent.RenderContext(ctx, a11y.Assert(page), w)
+package a11y
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Rules reported in Finding.Rule.
+const (
+	// RuleAlt flags images and image controls without an alt attribute.
+	RuleAlt = "alt"
+	// RuleLabel flags form controls without a label or other accessible name.
+	RuleLabel = "label"
+	// RuleButtonName flags buttons without an accessible name.
+	RuleButtonName = "button-name"
+	// RuleARIA flags unknown roles and ARIA attributes, invalid ARIA values,
+	// references to missing ids and ARIA that hides focusable elements.
+	RuleARIA = "aria"
+)
+
+// Finding is an accessibility problem in rendered markup.
+type Finding struct {
+	// Rule is one of the Rule constants.
+	Rule string
+	// Path locates the offending element, e.g. "body > form > input#email".
+	Path    string
+	Message string
+}
+
+// Error formats the finding so it can be reported through node.Fail.
+func (f Finding) Error() string {
+	return "a11y: " + f.Path + ": " + f.Message + " (" + f.Rule + ")"
+}
+
+// Check renders n and returns its accessibility findings in document order.
+func Check(n node.Node) []Finding {
+	if n == nil {
+		return nil
+	}
+	return CheckHTML(string(n.Render()))
+}
+
+// CheckHTML returns the accessibility findings in rendered markup.
+func CheckHTML(src string) []Finding {
+	doc := markup.Parse(src)
+	c := &checker{ids: map[string]bool{}, labelled: map[string]bool{}}
+	doc.Walk(func(n *markup.Node) bool {
+		if n.Type != markup.ElementNode {
+			return true
+		}
+		if id, ok := n.Attr("id"); ok {
+			c.ids[id] = true
+		}
+		if n.Tag == "label" {
+			if f, ok := n.Attr("for"); ok {
+				c.labelled[f] = true
+			}
+		}
+		return true
+	})
+	doc.Walk(func(n *markup.Node) bool {
+		if n.Type == markup.ElementNode {
+			c.element(n)
+		}
+		return true
+	})
+	return c.findings
+}
+
+// Assert wraps n so that renders with assertions enabled check its output
+// and report each Finding through node.Fail.
+func Assert(n node.Node) node.Node {
+	return &asserted{node: n}
+}
+
+type asserted struct {
+	node node.Node
+}
+
+func (a *asserted) Render(w ...io.Writer) []byte {
+	return node.Render(a, w...)
+}
+
+// RenderBuilder writes the wrapped node and, when the render has assertions
+// enabled, checks the output.
+func (a *asserted) RenderBuilder(buf *bytes.Buffer) {
+	if a.node == nil {
+		return
+	}
+	if !node.Checking(buf) {
+		a.node.RenderBuilder(buf)
+		return
+	}
+	start := buf.Len()
+	a.node.RenderBuilder(buf)
+	for _, f := range CheckHTML(string(buf.Bytes()[start:])) {
+		node.Fail(buf, f)
+	}
+}
+
+func (a *asserted) Nodes() []node.Node {
+	if a.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{a.node}
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (a *asserted) SetAttribute(key string, value string) {
+	if a.node != nil {
+		a.node.SetAttribute(key, value)
+	}
+}
+
+type checker struct {
+	ids      map[string]bool
+	labelled map[string]bool // ids named by a label's for attribute
+	findings []Finding
+}
+
+func (c *checker) report(n *markup.Node, rule, msg string) {
+	c.findings = append(c.findings, Finding{Rule: rule, Path: n.Path(), Message: msg})
+}
+
+func (c *checker) element(n *markup.Node) {
+	typ, _ := n.Attr("type")
+	typ = strings.ToLower(typ)
+	switch {
+	case n.Tag == "img" || n.Tag == "area" || n.Tag == "input" && typ == "image":
+		if _, ok := n.Attr("alt"); !ok && !hidden(n) {
+			c.report(n, RuleAlt, "<"+n.Tag+"> has no alt attribute; use alt=\"\" if it is decorative")
+		}
+	case n.Tag == "button":
+		if !hidden(n) && !c.named(n) && content(n) == "" {
+			c.report(n, RuleButtonName, "<button> has no text or label")
+		}
+	case n.Tag == "input" && typ == "button":
+		if v, _ := n.Attr("value"); strings.TrimSpace(v) == "" && !c.named(n) && !hidden(n) {
+			c.report(n, RuleButtonName, `<input type="button"> has no value or label`)
+		}
+	case n.Tag == "input" && typ != "hidden" && typ != "submit" && typ != "reset",
+		n.Tag == "select", n.Tag == "textarea":
+		if !hidden(n) && !c.named(n) && !c.labelledBy(n) {
+			c.report(n, RuleLabel, "<"+n.Tag+"> has no label")
+		}
+	}
+	c.aria(n)
+}
+
+// named reports whether n has an explicit accessible name.
+func (c *checker) named(n *markup.Node) bool {
+	if v, _ := n.Attr("aria-label"); strings.TrimSpace(v) != "" {
+		return true
+	}
+	if v, _ := n.Attr("title"); strings.TrimSpace(v) != "" {
+		return true
+	}
+	if v, _ := n.Attr("aria-labelledby"); strings.TrimSpace(v) != "" {
+		for _, id := range strings.Fields(v) {
+			if c.ids[id] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// labelledBy reports whether a label element refers to n or contains it.
+func (c *checker) labelledBy(n *markup.Node) bool {
+	if id, ok := n.Attr("id"); ok && c.labelled[id] {
+		return true
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == markup.ElementNode && p.Tag == "label" {
+			return true
+		}
+	}
+	return false
+}
+
+// content returns the text a screen reader would announce for n's content:
+// its text plus the alt text of images, skipping hidden elements.
+func content(n *markup.Node) string {
+	var b strings.Builder
+	n.Walk(func(d *markup.Node) bool {
+		switch {
+		case d.Type == markup.TextNode:
+			b.WriteString(d.Data)
+		case d.Type == markup.ElementNode && d != n && hidden(d):
+			return false
+		case d.Type == markup.ElementNode && d.Tag == "img":
+			alt, _ := d.Attr("alt")
+			b.WriteString(alt)
+		case d.Type == markup.ElementNode && d != n:
+			if v, _ := d.Attr("aria-label"); v != "" {
+				b.WriteString(v)
+				return false
+			}
+		}
+		return true
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// hidden reports whether n is hidden from assistive technology.
+func hidden(n *markup.Node) bool {
+	if _, ok := n.Attr("hidden"); ok {
+		return true
+	}
+	v, _ := n.Attr("aria-hidden")
+	return v == "true"
+}
+
+// focusable reports whether n takes keyboard focus.
+func focusable(n *markup.Node) bool {
+	if _, ok := n.Attr("disabled"); ok {
+		return false
+	}
+	if v, ok := n.Attr("tabindex"); ok {
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		return err == nil && i >= 0
+	}
+	switch n.Tag {
+	case "a", "area":
+		_, ok := n.Attr("href")
+		return ok
+	case "button", "select", "textarea", "summary", "iframe":
+		return true
+	case "input":
+		typ, _ := n.Attr("type")
+		return !strings.EqualFold(typ, "hidden")
+	}
+	_, ok := n.Attr("contenteditable")
+	return ok
+}
+
+func (c *checker) aria(n *markup.Node) {
+	for _, a := range n.Attrs {
+		if a.Key == "role" {
+			for _, role := range strings.Fields(a.Val) {
+				if !roles[role] {
+					c.report(n, RuleARIA, "unknown role "+strconv.Quote(role))
+				}
+			}
+			continue
+		}
+		name, ok := strings.CutPrefix(a.Key, "aria-")
+		if !ok {
+			continue
+		}
+		kind, known := attributes[name]
+		switch {
+		case !known:
+			c.report(n, RuleARIA, "unknown attribute "+a.Key)
+		case kind == boolean && a.Val != "true" && a.Val != "false",
+			kind == tristate && a.Val != "true" && a.Val != "false" && a.Val != "mixed":
+			c.report(n, RuleARIA, a.Key+"="+strconv.Quote(a.Val)+" is not a valid value")
+		case kind == idrefs:
+			for _, id := range strings.Fields(a.Val) {
+				if !c.ids[id] {
+					c.report(n, RuleARIA, a.Key+" refers to missing id "+strconv.Quote(id))
+				}
+			}
+		}
+	}
+	if !focusable(n) {
+		return
+	}
+	if v, _ := n.Attr("aria-hidden"); v == "true" {
+		c.report(n, RuleARIA, `aria-hidden="true" on a focusable element`)
+	}
+	if role, _ := n.Attr("role"); role == "presentation" || role == "none" {
+		c.report(n, RuleARIA, `role="`+role+`" on a focusable element`)
+	}
+}
+
+// Value types of ARIA attributes that are validated.
+const (
+	other = iota
+	boolean
+	tristate
+	idrefs
+)
+
+// attributes lists the ARIA 1.2 attributes, without the aria- prefix.
+var attributes = map[string]int{
+	"activedescendant": other, "atomic": boolean, "autocomplete": other,
+	"braillelabel": other, "brailleroledescription": other, "busy": boolean,
+	"checked": tristate, "colcount": other, "colindex": other, "colindextext": other,
+	"colspan": other, "controls": idrefs, "current": other, "describedby": idrefs,
+	"description": other, "details": idrefs, "disabled": boolean, "dropeffect": other,
+	"errormessage": idrefs, "expanded": boolean, "flowto": idrefs, "grabbed": other,
+	"haspopup": other, "hidden": boolean, "invalid": other, "keyshortcuts": other,
+	"label": other, "labelledby": idrefs, "level": other, "live": other,
+	"modal": boolean, "multiline": boolean, "multiselectable": boolean,
+	"orientation": other, "owns": idrefs, "placeholder": other, "posinset": other,
+	"pressed": tristate, "readonly": boolean, "relevant": other, "required": boolean,
+	"roledescription": other, "rowcount": other, "rowindex": other, "rowindextext": other,
+	"rowspan": other, "selected": boolean, "setsize": other, "sort": other,
+	"valuemax": other, "valuemin": other, "valuenow": other, "valuetext": other,
+}
+
+// roles lists the ARIA 1.2 roles, excluding abstract roles.
+var roles = map[string]bool{}
+
+func init() {
+	for _, r := range strings.Fields(`alert alertdialog application article banner
+		blockquote button caption cell checkbox code columnheader combobox
+		complementary contentinfo definition deletion dialog directory document
+		emphasis feed figure form generic grid gridcell group heading img
+		insertion link list listbox listitem log main mark marquee math menu
+		menubar menuitem menuitemcheckbox menuitemradio meter navigation none
+		note option paragraph presentation progressbar radio radiogroup region
+		row rowgroup rowheader scrollbar search searchbox separator slider
+		spinbutton status strong subscript superscript switch tab table tablist
+		tabpanel term textbox time timer toolbar tooltip tree treegrid treeitem`) {
+		roles[r] = true
+	}
+}
@@ -0,0 +1,99 @@
+package a11y
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestCheckHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // "rule path" for each finding
+	}{
+		{"img without alt", `<div><img src="a.png"></div>`, []string{"alt div > img"}},
+		{"decorative img", `<img src="a.png" alt="">`, nil},
+		{"hidden img", `<img src="a.png" aria-hidden="true">`, nil},
+		{"image input", `<input type="image" src="go.png">`, []string{"alt input"}},
+		{"unlabelled input", `<form><input type="text" name="q"></form>`, []string{"label form > input"}},
+		{"label for", `<label for="q">Search</label><input id="q" type="text">`, nil},
+		{"wrapping label", `<label>Search <input type="search"></label>`, nil},
+		{"aria-label", `<textarea aria-label="Message"></textarea>`, nil},
+		{"labelledby", `<h2 id="h">Notes</h2><textarea aria-labelledby="h"></textarea>`, nil},
+		{"labelledby missing", `<select aria-labelledby="nope"></select>`,
+			[]string{"label select", "aria select"}},
+		{"hidden and submit inputs", `<input type="hidden" name="t"><input type="submit">`, nil},
+		{"empty button", `<button><svg></svg></button>`, []string{"button-name button"}},
+		{"icon button with alt", `<button><img src="x.png" alt="Close"></button>`, nil},
+		{"button with hidden text", `<button><span aria-hidden="true">&times;</span></button>`, []string{"button-name button"}},
+		{"labelled button", `<button aria-label="Close"><span aria-hidden="true">&times;</span></button>`, nil},
+		{"input button", `<input type="button">`, []string{"button-name input"}},
+		{"unknown role", `<div role="buton"></div>`, []string{"aria div"}},
+		{"role fallback", `<div role="switch checkbox"></div>`, nil},
+		{"unknown aria", `<div aria-lable="x"></div>`, []string{"aria div"}},
+		{"bad boolean", `<div aria-hidden="yes"></div>`, []string{"aria div"}},
+		{"mixed", `<div role="checkbox" aria-checked="mixed" tabindex="0" aria-label="All"></div>`, nil},
+		{"hidden focusable", `<a href="/" aria-hidden="true">Home</a>`, []string{"aria a"}},
+		{"presentation focusable", `<button role="presentation">Go</button>`, []string{"aria button"}},
+		{"describedby", `<p id="help">Hint</p><input aria-label="x" aria-describedby="help missing">`, []string{"aria input"}},
+		{"path", `<main><section><img></section><section id="s"><img></section></main>`,
+			[]string{"alt main > section:nth-of-type(1) > img", "alt main > section#s > img"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range CheckHTML(tt.src) {
+				got = append(got, f.Rule+" "+f.Path)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	findings := Check(div.New(img.New().Src("a.png")))
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if got, want := findings[0].Error(), `a11y: div > img: <img> has no alt attribute; use alt="" if it is decorative (alt)`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if Check(nil) != nil {
+		t.Error("expected no findings for a nil node")
+	}
+}
+
+func TestAssert(t *testing.T) {
+	page := Assert(div.New(img.New().Src("a.png")))
+
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div><img src="a.png" /></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+
+	errs = nil
+	buf.Reset()
+	if err := fluent.RenderContext(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("got %d errors without assertions, want 0", len(errs))
+	}
+}
@@ -0,0 +1,93 @@
+package a11y
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Heading rules reported by Headings.
+const (
+	// RuleHeadingOrder flags a heading more than one level below the heading
+	// before it, such as an h4 following an h2.
+	RuleHeadingOrder = "heading-order"
+	// RuleHeadingH1 flags every h1 after the first.
+	RuleHeadingH1 = "heading-h1"
+	// RuleHeadingEmpty flags headings with no text.
+	RuleHeadingEmpty = "heading-empty"
+)
+
+// Headings renders n and checks its heading outline: levels must not skip
+// on the way down, there must be at most one h1 and no heading may be
+// empty. Elements with role="heading" count at their aria-level. Hidden
+// headings are ignored.
+//
+// The rules describe a whole document, so unlike Check they are not run by
+// Assert: a component rendered on its own may legitimately start at h3.
+func Headings(n node.Node) []Finding {
+	if n == nil {
+		return nil
+	}
+	return HeadingsHTML(string(n.Render()))
+}
+
+// HeadingsHTML checks the heading outline of rendered markup.
+func HeadingsHTML(src string) []Finding {
+	var findings []Finding
+	report := func(n *markup.Node, rule, msg string) {
+		findings = append(findings, Finding{Rule: rule, Path: n.Path(), Message: msg})
+	}
+	prev, h1s := 0, 0
+	markup.Parse(src).Walk(func(n *markup.Node) bool {
+		if n.Type != markup.ElementNode {
+			return true
+		}
+		if hidden(n) {
+			return false
+		}
+		level := headingLevel(n)
+		if level == 0 {
+			return true
+		}
+		if content(n) == "" && !named(n) {
+			report(n, RuleHeadingEmpty, fmt.Sprintf("empty level %d heading", level))
+		}
+		if level == 1 {
+			if h1s++; h1s > 1 {
+				report(n, RuleHeadingH1, "more than one level 1 heading")
+			}
+		}
+		if prev > 0 && level > prev+1 {
+			report(n, RuleHeadingOrder, fmt.Sprintf("level %d heading follows level %d", level, prev))
+		}
+		prev = level
+		return false
+	})
+	return findings
+}
+
+// headingLevel returns n's heading level, or 0 if it is not a heading.
+func headingLevel(n *markup.Node) int {
+	if len(n.Tag) == 2 && n.Tag[0] == 'h' && n.Tag[1] >= '1' && n.Tag[1] <= '6' {
+		return int(n.Tag[1] - '0')
+	}
+	role, _ := n.Attr("role")
+	if f := strings.Fields(role); len(f) > 0 && f[0] == "heading" {
+		if v, ok := n.Attr("aria-level"); ok {
+			if l, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && l > 0 {
+				return l
+			}
+		}
+		return 2
+	}
+	return 0
+}
+
+// named reports whether n has a non-empty aria-label.
+func named(n *markup.Node) bool {
+	v, _ := n.Attr("aria-label")
+	return strings.TrimSpace(v) != ""
+}
@@ -0,0 +1,51 @@
+package a11y
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/h3"
+)
+
+func TestHeadingsHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // "rule path" for each finding
+	}{
+		{"outline", `<h1>Title</h1><h2>A</h2><h3>A.1</h3><h2>B</h2><h3>B.1</h3>`, nil},
+		{"back up several levels", `<h1>T</h1><h2>A</h2><h3>A.1</h3><h4>x</h4><h2>B</h2>`, nil},
+		{"skip", `<h1>T</h1><h2>A</h2><h4>x</h4>`, []string{"heading-order h4"}},
+		{"first heading may start deep", `<h3>Card</h3><h4>Detail</h4>`, nil},
+		{"two h1s", `<header><h1>Site</h1></header><main><h1>Page</h1></main>`, []string{"heading-h1 main > h1"}},
+		{"empty", `<h1>T</h1><h2> </h2>`, []string{"heading-empty h2"}},
+		{"image heading", `<h1><img src="logo.png" alt="Example Ltd"></h1>`, nil},
+		{"labelled heading", `<h1 aria-label="Home"><svg></svg></h1>`, nil},
+		{"hidden heading ignored", `<h1>T</h1><div hidden><h4>x</h4></div><h2>A</h2>`, nil},
+		{"role heading", `<h1>T</h1><div role="heading" aria-level="4">x</div>`, []string{"heading-order div"}},
+		{"role heading default level", `<h1>T</h1><div role="heading">x</div>`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range HeadingsHTML(tt.src) {
+				got = append(got, f.Rule+" "+f.Path)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadings(t *testing.T) {
+	findings := Headings(div.New(h1.Text("Title"), h3.Text("Sub")))
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if got, want := findings[0].Error(), "a11y: div > h3: level 3 heading follows level 1 (heading-order)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+// Package assets lets components declare the scripts, styles and SVG
+// symbols they depend on, so shared components stay self-contained without
+// duplicating tags.
+//
+// Declarations are collected in the render context, deduplicated, ordered by
+// dependency and written once by an outlet in the layout.
+//
+// Usage:
+//
+//	func DatePicker(name string) node.Node {
+//	    return div.New(
+//	        assets.Stylesheet("/css/calendar.css"),
+//	        assets.Src("/js/calendar.js"),
+//	        assets.Inline("datepicker", "Calendar.attach('[data-date]')", "/js/calendar.js"),
+//	        input.Date(name).SetData("date", ""),
+//	    )
+//	}
+//
+//	layout := html.New(
+//	    head.New(meta.UTF8(), assets.HeadStyles()),
+//	    body.New(content, assets.BodyScripts()),
+//	)
+//	err := assets.Render(r.Context(), layout, w)
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Render renders n to w through fluent.RenderContext with empty collectors
+// in the context, unless ctx already carries them.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) error {
+	if ScriptsFrom(ctx) == nil {
+		ctx = WithScripts(ctx, NewScripts())
+	}
+	if StylesFrom(ctx) == nil {
+		ctx = WithStyles(ctx, NewStyles())
+	}
+	if SpritesFrom(ctx) == nil {
+		ctx = WithSprites(ctx, NewSprites())
+	}
+	return fluent.RenderContext(ctx, n, w, policy...)
+}
+
+// hash returns the CSP source expression for inline content.
+func hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
@@ -0,0 +1,271 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ErrCycle is returned by Scripts.Ordered when dependencies form a cycle.
+var ErrCycle = errors.New("assets: dependency cycle")
+
+// Script is a script dependency: an external Src or Inline code.
+type Script struct {
+	// ID identifies the script for deduplication and as a dependency target.
+	// It defaults to Src, or to the CSP hash of Inline.
+	ID     string
+	Src    string
+	Inline string
+	// Deps lists the IDs of scripts that must run first. IDs that were never
+	// declared are ignored, so a dependency can also be loaded by the layout.
+	Deps   []string
+	Module bool
+	Async  bool
+}
+
+// id returns the script's ID, its Src, or the CSP hash of its Inline code.
+func (s Script) id() string {
+	switch {
+	case s.ID != "":
+		return s.ID
+	case s.Src != "":
+		return s.Src
+	}
+	return hash(s.Inline)
+}
+
+// Scripts collects script declarations during a render. It is safe for
+// concurrent use.
+type Scripts struct {
+	// Nonce is added to every emitted script for a nonce-based CSP.
+	Nonce string
+
+	mu    sync.Mutex
+	items []Script
+	index map[string]int
+}
+
+// NewScripts creates an empty collector.
+func NewScripts() *Scripts {
+	return &Scripts{index: make(map[string]int)}
+}
+
+// Add declares scripts. A script whose ID was already declared is merged into
+// the first declaration, adding any new dependencies.
+func (s *Scripts) Add(scripts ...Script) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sc := range scripts {
+		id := sc.id()
+		if i, ok := s.index[id]; ok {
+			for _, d := range sc.Deps {
+				if !slices.Contains(s.items[i].Deps, d) {
+					s.items[i].Deps = append(s.items[i].Deps, d)
+				}
+			}
+			continue
+		}
+		sc.ID = id
+		s.index[id] = len(s.items)
+		s.items = append(s.items, sc)
+	}
+}
+
+// Ordered returns the scripts with every script after its dependencies and
+// otherwise in declaration order. On a cycle it returns the scripts in
+// declaration order together with ErrCycle.
+func (s *Scripts) Ordered() ([]Script, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(s.items))
+	out := make([]Script, 0, len(s.items))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[i] = visiting
+		for _, d := range s.items[i].Deps {
+			if j, ok := s.index[d]; ok && !visit(j) {
+				return false
+			}
+		}
+		state[i] = done
+		out = append(out, s.items[i])
+		return true
+	}
+	for i := range s.items {
+		if !visit(i) {
+			return slices.Clone(s.items), ErrCycle
+		}
+	}
+	return out, nil
+}
+
+// Hashes returns the CSP hash sources of the inline scripts, for a
+// script-src directive.
+func (s *Scripts) Hashes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, sc := range s.items {
+		if sc.Inline != "" {
+			out = append(out, hash(sc.Inline))
+		}
+	}
+	return out
+}
+
+// write renders the scripts in dependency order. In the head, external
+// scripts are deferred and inline scripts become modules, which browsers also
+// defer, so everything still runs in order after the document is parsed.
+func (s *Scripts) write(buf *bytes.Buffer, head bool) {
+	scripts, _ := s.Ordered()
+	for _, sc := range scripts {
+		buf.WriteString("<script")
+		if sc.Src != "" {
+			buf.WriteString(` src="`)
+			escape.WriteString(buf, sc.Src)
+			buf.WriteByte('"')
+		}
+		if sc.Module || (head && sc.Src == "") {
+			buf.WriteString(` type="module"`)
+		} else if head {
+			buf.WriteString(` defer`)
+		}
+		if sc.Async && sc.Src != "" {
+			buf.WriteString(` async`)
+		}
+		if s.Nonce != "" {
+			buf.WriteString(` nonce="`)
+			escape.WriteString(buf, s.Nonce)
+			buf.WriteByte('"')
+		}
+		buf.WriteByte('>')
+		if sc.Src == "" {
+			buf.WriteString(sc.Inline)
+		}
+		buf.WriteString("</script>")
+	}
+}
+
+type scriptsKey struct{}
+
+// WithScripts returns a context whose renders declare scripts to s.
+func WithScripts(ctx context.Context, s *Scripts) context.Context {
+	return context.WithValue(ctx, scriptsKey{}, s)
+}
+
+// ScriptsFrom returns the collector carried by ctx, or nil.
+func ScriptsFrom(ctx context.Context) *Scripts {
+	s, _ := ctx.Value(scriptsKey{}).(*Scripts)
+	return s
+}
+
+// Require declares scripts with the collector of the render it is part of. It
+// renders nothing itself. Without a collector in the context it is a no-op.
+func Require(scripts ...Script) node.Node {
+	return require(scripts)
+}
+
+// Src declares an external script that depends on deps.
+func Src(src string, deps ...string) node.Node {
+	return Require(Script{Src: src, Deps: deps})
+}
+
+// Inline declares inline code under id that depends on deps. Inline code is
+// written verbatim, so it must not come from user input.
+func Inline(id, js string, deps ...string) node.Node {
+	return Require(Script{ID: id, Inline: js, Deps: deps})
+}
+
+type require []Script
+
+// Render renders the declaration on its own. A declaration writes nothing,
+// and outside a render with a Scripts collector it is not recorded either.
+func (r require) Render(w ...io.Writer) []byte {
+	return node.Render(r, w...)
+}
+
+// RenderBuilder adds the scripts to the collector in the render's context.
+func (r require) RenderBuilder(buf *bytes.Buffer) {
+	if s := ScriptsFrom(node.Context(buf)); s != nil {
+		s.Add(r...)
+	}
+}
+
+// Nodes returns an empty slice as the declaration has no children.
+func (r require) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the effect depends on the render context.
+func (r require) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for require as it does not have attributes.
+func (r require) SetAttribute(_ string, _ string) {
+	// require does not support attributes
+}
+
+// BodyScripts writes the collected scripts. Place it at the end of <body>.
+func BodyScripts() node.Node {
+	return scriptOutlet{head: false}
+}
+
+// HeadScripts writes the collected scripts deferred. Place it in <head>.
+// Inline scripts are written as modules so they run after the deferred
+// scripts they depend on; they therefore run in strict mode with their own
+// scope.
+func HeadScripts() node.Node {
+	return scriptOutlet{head: true}
+}
+
+type scriptOutlet struct {
+	head bool
+}
+
+// Render renders the outlet on its own. Scripts are only collected during a
+// render with a Scripts collector, so outside one it produces no output.
+func (o scriptOutlet) Render(w ...io.Writer) []byte {
+	return node.Render(o, w...)
+}
+
+// RenderBuilder defers writing the collected scripts until the render
+// completes, so declarations made after the outlet are included.
+func (o scriptOutlet) RenderBuilder(buf *bytes.Buffer) {
+	if s := ScriptsFrom(node.Context(buf)); s != nil {
+		node.Defer(buf, func(b *bytes.Buffer) { s.write(b, o.head) })
+	}
+}
+
+// Nodes returns an empty slice as the outlet has no children.
+func (o scriptOutlet) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output depends on the render context.
+func (o scriptOutlet) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for scriptOutlet as it does not have attributes.
+func (o scriptOutlet) SetAttribute(_ string, _ string) {
+	// scriptOutlet does not support attributes
+}
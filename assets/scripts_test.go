@@ -0,0 +1,76 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/node"
+)
+
+func widget() node.Node {
+	return div.New(
+		Inline("init", "Widget.init()", "/widget.js"),
+		Src("/widget.js", "/lib.js"),
+		Src("/lib.js"),
+	)
+}
+
+func TestBodyScripts(t *testing.T) {
+	page := html.New(head.New(), body.New(widget(), widget(), BodyScripts()))
+
+	var buf bytes.Buffer
+	if err := Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<!DOCTYPE html><html><head></head><body><div></div><div></div>` +
+		`<script src="/lib.js"></script><script src="/widget.js"></script><script>Widget.init()</script>` +
+		`</body></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHeadScripts(t *testing.T) {
+	s := NewScripts()
+	s.Nonce = "abc"
+	ctx := WithScripts(context.Background(), s)
+	page := html.New(head.New(HeadScripts()), body.New(widget(), Require(Script{Src: "/m.js", Module: true, Async: true})))
+
+	var buf bytes.Buffer
+	if err := Render(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<!DOCTYPE html><html><head>` +
+		`<script src="/lib.js" defer nonce="abc"></script>` +
+		`<script src="/widget.js" defer nonce="abc"></script>` +
+		`<script type="module" nonce="abc">Widget.init()</script>` +
+		`<script src="/m.js" type="module" async nonce="abc"></script>` +
+		`</head><body><div></div></body></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if h := s.Hashes(); len(h) != 1 || h[0] != hash("Widget.init()") {
+		t.Errorf("hashes: got %v", h)
+	}
+}
+
+func TestOrderedCycle(t *testing.T) {
+	s := NewScripts()
+	s.Add(Script{Src: "a", Deps: []string{"b"}}, Script{Src: "b", Deps: []string{"a"}})
+	got, err := s.Ordered()
+	if !errors.Is(err, ErrCycle) || len(got) != 2 || got[0].ID != "a" {
+		t.Errorf("got %v, %v", got, err)
+	}
+}
+
+func TestWithoutCollector(t *testing.T) {
+	if got := string(div.New(widget(), BodyScripts()).Render()); got != "<div><div></div></div>" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,156 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Symbol is an SVG <symbol> for the page's sprite sheet, referenced from
+// the page with <use href="#ID">.
+type Symbol struct {
+	// ID is the id of the symbol.
+	ID string
+	// Markup is the complete <symbol> element. It is written verbatim, so it
+	// must not come from user input.
+	Markup string
+}
+
+// Sprites collects the SVG symbols used during a render, so the page's
+// sprite sheet holds only those it needs. It is safe for concurrent use.
+type Sprites struct {
+	mu    sync.Mutex
+	items []Symbol
+	index map[string]bool
+}
+
+// NewSprites creates an empty collector.
+func NewSprites() *Sprites {
+	return &Sprites{index: make(map[string]bool)}
+}
+
+// Add declares symbols. Symbols whose ID was already declared are ignored.
+func (s *Sprites) Add(symbols ...Symbol) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sym := range symbols {
+		if s.index[sym.ID] {
+			continue
+		}
+		s.index[sym.ID] = true
+		s.items = append(s.items, sym)
+	}
+}
+
+// All returns the symbols in declaration order.
+func (s *Sprites) All() []Symbol {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.items)
+}
+
+// write writes a sprite sheet hidden from view and from assistive
+// technology, or nothing when no symbols were declared.
+func (s *Sprites) write(buf *bytes.Buffer) {
+	symbols := s.All()
+	if len(symbols) == 0 {
+		return
+	}
+	buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">`)
+	for _, sym := range symbols {
+		buf.WriteString(sym.Markup)
+	}
+	buf.WriteString("</svg>")
+}
+
+type spritesKey struct{}
+
+// WithSprites returns a context whose renders declare symbols to s.
+func WithSprites(ctx context.Context, s *Sprites) context.Context {
+	return context.WithValue(ctx, spritesKey{}, s)
+}
+
+// SpritesFrom returns the collector carried by ctx, or nil.
+func SpritesFrom(ctx context.Context) *Sprites {
+	s, _ := ctx.Value(spritesKey{}).(*Sprites)
+	return s
+}
+
+// RequireSymbols declares symbols with the collector of the render it is
+// part of. It renders nothing itself. Without a collector in the context it
+// is a no-op.
+func RequireSymbols(symbols ...Symbol) node.Node {
+	return requireSymbols(symbols)
+}
+
+type requireSymbols []Symbol
+
+// Render renders the declaration on its own. A declaration writes nothing,
+// and outside a render with a Sprites collector it is not recorded either.
+func (r requireSymbols) Render(w ...io.Writer) []byte {
+	return node.Render(r, w...)
+}
+
+// RenderBuilder adds the symbols to the collector in the render's context.
+func (r requireSymbols) RenderBuilder(buf *bytes.Buffer) {
+	if s := SpritesFrom(node.Context(buf)); s != nil {
+		s.Add(r...)
+	}
+}
+
+// Nodes returns an empty slice as the declaration has no children.
+func (r requireSymbols) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the effect depends on the render context.
+func (r requireSymbols) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for requireSymbols as it does not have attributes.
+func (r requireSymbols) SetAttribute(_ string, _ string) {
+	// requireSymbols does not support attributes
+}
+
+// SpriteSheet writes the collected symbols as a hidden sprite sheet once
+// the render completes, so it may be placed before the nodes using them.
+// Place it once, at the start of <body>.
+func SpriteSheet() node.Node {
+	return spriteOutlet{}
+}
+
+type spriteOutlet struct{}
+
+// Render renders the outlet on its own. Symbols are only collected during a
+// render with a Sprites collector, so outside one it produces no output.
+func (o spriteOutlet) Render(w ...io.Writer) []byte {
+	return node.Render(o, w...)
+}
+
+// RenderBuilder defers writing the collected symbols until the render
+// completes, so declarations made after the outlet are included.
+func (o spriteOutlet) RenderBuilder(buf *bytes.Buffer) {
+	if s := SpritesFrom(node.Context(buf)); s != nil {
+		node.Defer(buf, s.write)
+	}
+}
+
+// Nodes returns an empty slice as the outlet has no children.
+func (o spriteOutlet) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output depends on the render context.
+func (o spriteOutlet) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for spriteOutlet as it does not have attributes.
+func (o spriteOutlet) SetAttribute(_ string, _ string) {
+	// spriteOutlet does not support attributes
+}
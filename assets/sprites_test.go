@@ -0,0 +1,34 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+)
+
+func TestSpriteSheet(t *testing.T) {
+	dot := Symbol{ID: "dot", Markup: `<symbol id="dot"><circle r="1"/></symbol>`}
+	page := body.New(SpriteSheet(), div.New(RequireSymbols(dot)), RequireSymbols(dot, Symbol{ID: "dot", Markup: "ignored"}))
+
+	var buf bytes.Buffer
+	if err := Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<body><svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">` +
+		dot.Markup + `</svg><div></div></body>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// Nothing is written when no symbols were declared.
+	buf.Reset()
+	if err := Render(context.Background(), body.New(SpriteSheet()), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != `<body></body>` {
+		t.Errorf("got %q, want an empty body", got)
+	}
+}
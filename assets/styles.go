@@ -0,0 +1,214 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Style is a stylesheet dependency: an external Href or Inline CSS.
+type Style struct {
+	// ID identifies the style for deduplication. It defaults to Href, or to
+	// the CSP hash of Inline.
+	ID     string
+	Href   string
+	Inline string
+	// Media restricts the style to a media query, e.g. "print".
+	Media string
+}
+
+// id returns the style's ID, its Href, or the CSP hash of its Inline CSS.
+func (s Style) id() string {
+	switch {
+	case s.ID != "":
+		return s.ID
+	case s.Href != "":
+		return s.Href
+	}
+	return hash(s.Inline)
+}
+
+// Styles collects style declarations during a render. It is safe for
+// concurrent use.
+type Styles struct {
+	// Nonce is added to every emitted <style> and <link> for a nonce-based CSP.
+	Nonce string
+
+	mu    sync.Mutex
+	items []Style
+	index map[string]bool
+}
+
+// NewStyles creates an empty collector.
+func NewStyles() *Styles {
+	return &Styles{index: make(map[string]bool)}
+}
+
+// Add declares styles. Styles whose ID was already declared are ignored, so
+// the cascade follows first use.
+func (s *Styles) Add(styles ...Style) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range styles {
+		id := st.id()
+		if s.index[id] {
+			continue
+		}
+		st.ID = id
+		s.index[id] = true
+		s.items = append(s.items, st)
+	}
+}
+
+// All returns the styles in declaration order.
+func (s *Styles) All() []Style {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.items)
+}
+
+// Hashes returns the CSP hash sources of the inline styles, for a
+// style-src directive.
+func (s *Styles) Hashes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, st := range s.items {
+		if st.Inline != "" {
+			out = append(out, hash(st.Inline))
+		}
+	}
+	return out
+}
+
+// write renders the styles in declaration order.
+func (s *Styles) write(buf *bytes.Buffer) {
+	for _, st := range s.All() {
+		if st.Href != "" {
+			buf.WriteString(`<link rel="stylesheet" href="`)
+			escape.WriteString(buf, st.Href)
+			buf.WriteByte('"')
+		} else {
+			buf.WriteString("<style")
+		}
+		if st.Media != "" {
+			buf.WriteString(` media="`)
+			escape.WriteString(buf, st.Media)
+			buf.WriteByte('"')
+		}
+		if s.Nonce != "" {
+			buf.WriteString(` nonce="`)
+			escape.WriteString(buf, s.Nonce)
+			buf.WriteByte('"')
+		}
+		if st.Href != "" {
+			buf.WriteString(" />")
+			continue
+		}
+		buf.WriteByte('>')
+		buf.WriteString(st.Inline)
+		buf.WriteString("</style>")
+	}
+}
+
+type stylesKey struct{}
+
+// WithStyles returns a context whose renders declare styles to s.
+func WithStyles(ctx context.Context, s *Styles) context.Context {
+	return context.WithValue(ctx, stylesKey{}, s)
+}
+
+// StylesFrom returns the collector carried by ctx, or nil.
+func StylesFrom(ctx context.Context) *Styles {
+	s, _ := ctx.Value(stylesKey{}).(*Styles)
+	return s
+}
+
+// RequireStyles declares styles with the collector of the render it is part
+// of. It renders nothing itself. Without a collector in the context it is a
+// no-op.
+func RequireStyles(styles ...Style) node.Node {
+	return requireStyles(styles)
+}
+
+// Stylesheet declares an external stylesheet.
+func Stylesheet(href string) node.Node {
+	return RequireStyles(Style{Href: href})
+}
+
+// CSS declares inline CSS under id. The CSS is written verbatim, so it must
+// not come from user input.
+func CSS(id, css string) node.Node {
+	return RequireStyles(Style{ID: id, Inline: css})
+}
+
+type requireStyles []Style
+
+// Render renders the declaration on its own. A declaration writes nothing,
+// and outside a render with a Styles collector it is not recorded either.
+func (r requireStyles) Render(w ...io.Writer) []byte {
+	return node.Render(r, w...)
+}
+
+// RenderBuilder adds the styles to the collector in the render's context.
+func (r requireStyles) RenderBuilder(buf *bytes.Buffer) {
+	if s := StylesFrom(node.Context(buf)); s != nil {
+		s.Add(r...)
+	}
+}
+
+// Nodes returns an empty slice as the declaration has no children.
+func (r requireStyles) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the effect depends on the render context.
+func (r requireStyles) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for requireStyles as it does not have attributes.
+func (r requireStyles) SetAttribute(_ string, _ string) {
+	// requireStyles does not support attributes
+}
+
+// HeadStyles writes the collected styles. Place it in <head>.
+func HeadStyles() node.Node {
+	return styleOutlet{}
+}
+
+type styleOutlet struct{}
+
+// Render renders the outlet on its own. Styles are only collected during a
+// render with a Styles collector, so outside one it produces no output.
+func (o styleOutlet) Render(w ...io.Writer) []byte {
+	return node.Render(o, w...)
+}
+
+// RenderBuilder defers writing the collected styles until the render
+// completes, so declarations made after the outlet are included.
+func (o styleOutlet) RenderBuilder(buf *bytes.Buffer) {
+	if s := StylesFrom(node.Context(buf)); s != nil {
+		node.Defer(buf, s.write)
+	}
+}
+
+// Nodes returns an empty slice as the outlet has no children.
+func (o styleOutlet) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output depends on the render context.
+func (o styleOutlet) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for styleOutlet as it does not have attributes.
+func (o styleOutlet) SetAttribute(_ string, _ string) {
+	// styleOutlet does not support attributes
+}
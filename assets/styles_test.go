@@ -0,0 +1,51 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/node"
+)
+
+func card() node.Node {
+	return div.New(
+		Stylesheet("/base.css"),
+		CSS("card", ".card{padding:1rem}"),
+		RequireStyles(Style{Href: "/print.css", Media: "print"}),
+	).Class("card")
+}
+
+func TestHeadStyles(t *testing.T) {
+	s := NewStyles()
+	s.Nonce = "n1"
+	ctx := WithStyles(context.Background(), s)
+	page := html.New(head.New(HeadStyles()), body.New(card(), card(), CSS("card", ".ignored{}")))
+
+	var buf bytes.Buffer
+	if err := Render(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<!DOCTYPE html><html><head>` +
+		`<link rel="stylesheet" href="/base.css" nonce="n1" />` +
+		`<style nonce="n1">.card{padding:1rem}</style>` +
+		`<link rel="stylesheet" href="/print.css" media="print" nonce="n1" />` +
+		`</head><body><div class="card"></div><div class="card"></div></body></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if h := s.Hashes(); len(h) != 1 || h[0] != hash(".card{padding:1rem}") {
+		t.Errorf("hashes: got %v", h)
+	}
+}
+
+func TestHash(t *testing.T) {
+	// Matches the value browsers report for a CSP violation of "alert(1)".
+	if got := hash("alert(1)"); got != "'sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI='" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,7 @@
+// Package attr provides helpers for computing attribute values before they
+// are set on an element.
+//
+// The html5 element builders escape attribute values as they render; the
+// helpers here resolve, combine and validate values that come from several
+// sources, such as a component's defaults and a caller's overrides.
+package attr
@@ -0,0 +1,384 @@
+package attr
+
+import (
+	"slices"
+	"strings"
+)
+
+// TwMerge joins Tailwind CSS class lists, dropping utilities overridden by a
+// later conflicting utility, so a component's defaults can be overridden by
+// the classes its caller passes in.
+//
+// Utilities conflict when they set the same property under the same variants
+// and importance: "p-2" and "p-4" conflict, as do "text-blue-500" and
+// "text-red-500", but "text-lg" and "text-red-500" do not, nor do "p-2" and
+// "hover:p-4". A shorthand overrides its earlier longhands ("px-2 p-4" is
+// "p-4") but not the reverse ("p-4 px-2" keeps both). Unknown classes are
+// kept, with exact duplicates removed.
+//
+// Usage:
+//
+//	func Button(extra string, nodes ...node.Node) node.Node {
+//	    return button.New(nodes...).Class(attr.TwMerge("px-4 py-2 bg-blue-600 text-white", extra))
+//	}
+//
+//	Button("bg-red-600 px-6") // class="py-2 text-white bg-red-600 px-6"
+func TwMerge(classes ...string) string {
+	var tokens []string
+	for _, c := range classes {
+		tokens = append(tokens, strings.Fields(c)...)
+	}
+	claimed := make(map[string]bool)
+	kept := make([]string, 0, len(tokens))
+	for i := len(tokens) - 1; i >= 0; i-- {
+		tok := tokens[i]
+		prefix, g := classify(tok)
+		if g == "" {
+			if claimed["class:"+tok] {
+				continue
+			}
+			claimed["class:"+tok] = true
+			kept = append(kept, tok)
+			continue
+		}
+		if claimed[prefix+g] {
+			continue
+		}
+		claimed[prefix+g] = true
+		for _, o := range overrides[g] {
+			claimed[prefix+o] = true
+		}
+		kept = append(kept, tok)
+	}
+	slices.Reverse(kept)
+	return strings.Join(kept, " ")
+}
+
+// classify splits a class into its conflict prefix (sorted variants and
+// importance) and the group of its utility. The group is empty for classes
+// that are not recognised Tailwind utilities.
+func classify(class string) (prefix, group string) {
+	variants, base := splitVariants(class)
+	important := false
+	if b, ok := strings.CutPrefix(base, "!"); ok {
+		base, important = b, true
+	} else if b, ok := strings.CutSuffix(base, "!"); ok {
+		base, important = b, true
+	}
+	base = strings.TrimPrefix(base, "-")
+
+	if strings.HasPrefix(base, "[") && strings.HasSuffix(base, "]") {
+		// Arbitrary property, e.g. [mask-type:luminance].
+		if prop, _, ok := strings.Cut(base[1:], ":"); ok {
+			group = "[" + prop + "]"
+		}
+	} else {
+		group = utility(base)
+	}
+
+	slices.Sort(variants)
+	prefix = strings.Join(variants, ":") + ":"
+	if important {
+		prefix += "!"
+	}
+	return prefix, group
+}
+
+// splitVariants separates the variants of a class from its utility,
+// ignoring colons inside arbitrary values.
+func splitVariants(class string) ([]string, string) {
+	var variants []string
+	depth, start := 0, 0
+	for i := 0; i < len(class); i++ {
+		switch class[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ':':
+			if depth == 0 {
+				variants = append(variants, class[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return variants, class[start:]
+}
+
+// keywords maps utilities without a value to their group.
+var keywords = map[string]string{
+	"block": "display", "inline-block": "display", "inline": "display", "flex": "display",
+	"inline-flex": "display", "grid": "display", "inline-grid": "display", "table": "display",
+	"contents": "display", "flow-root": "display", "list-item": "display", "hidden": "display",
+	"static": "position", "fixed": "position", "absolute": "position", "relative": "position", "sticky": "position",
+	"visible": "visibility", "invisible": "visibility", "collapse": "visibility",
+	"italic": "font-style", "not-italic": "font-style",
+	"underline": "text-decoration", "overline": "text-decoration", "line-through": "text-decoration", "no-underline": "text-decoration",
+	"uppercase": "text-transform", "lowercase": "text-transform", "capitalize": "text-transform", "normal-case": "text-transform",
+	"truncate":    "text-overflow",
+	"antialiased": "font-smoothing", "subpixel-antialiased": "font-smoothing",
+	"isolate": "isolation", "isolation-auto": "isolation",
+	"container": "container", "sr-only": "sr", "not-sr-only": "sr",
+	"grow": "grow", "shrink": "shrink",
+	"border": "border-w", "rounded": "rounded", "shadow": "shadow", "ring": "ring-w", "outline": "outline-style",
+	"transition": "transition", "transform": "transform",
+}
+
+// prefixes maps utility prefixes to their group, or to "" when the group
+// depends on the value (see byValue).
+var prefixes = map[string]string{
+	"p": "p", "px": "px", "py": "py", "ps": "ps", "pe": "pe", "pt": "pt", "pr": "pr", "pb": "pb", "pl": "pl",
+	"m": "m", "mx": "mx", "my": "my", "ms": "ms", "me": "me", "mt": "mt", "mr": "mr", "mb": "mb", "ml": "ml",
+	"space-x": "space-x", "space-y": "space-y",
+	"w": "w", "h": "h", "size": "size", "min-w": "min-w", "min-h": "min-h", "max-w": "max-w", "max-h": "max-h",
+	"inset": "inset", "inset-x": "inset-x", "inset-y": "inset-y",
+	"top": "top", "right": "right", "bottom": "bottom", "left": "left", "start": "start", "end": "end",
+	"z": "z", "order": "order", "opacity": "opacity", "aspect": "aspect", "columns": "columns",
+	"gap": "gap", "gap-x": "gap-x", "gap-y": "gap-y",
+	"basis": "basis", "grow": "grow", "shrink": "shrink",
+	"grid-cols": "grid-cols", "grid-rows": "grid-rows", "grid-flow": "grid-flow",
+	"col": "col", "col-span": "col", "col-start": "col-start", "col-end": "col-end",
+	"row": "row", "row-span": "row", "row-start": "row-start", "row-end": "row-end",
+	"auto-cols": "auto-cols", "auto-rows": "auto-rows",
+	"justify": "justify", "justify-items": "justify-items", "justify-self": "justify-self",
+	"items": "items", "self": "self", "content": "content",
+	"place-content": "place-content", "place-items": "place-items", "place-self": "place-self",
+	"overflow": "overflow", "overflow-x": "overflow-x", "overflow-y": "overflow-y",
+	"overscroll": "overscroll", "overscroll-x": "overscroll-x", "overscroll-y": "overscroll-y",
+	"leading": "leading", "tracking": "tracking", "indent": "indent", "align": "align",
+	"whitespace": "whitespace", "break": "break", "hyphens": "hyphens", "line-clamp": "line-clamp",
+	"list": "list", "underline-offset": "underline-offset",
+	"rounded":   "rounded",
+	"rounded-t": "rounded-t", "rounded-r": "rounded-r", "rounded-b": "rounded-b", "rounded-l": "rounded-l",
+	"rounded-s": "rounded-s", "rounded-e": "rounded-e",
+	"rounded-tl": "rounded-tl", "rounded-tr": "rounded-tr", "rounded-br": "rounded-br", "rounded-bl": "rounded-bl",
+	"rounded-ss": "rounded-ss", "rounded-se": "rounded-se", "rounded-es": "rounded-es", "rounded-ee": "rounded-ee",
+	"outline-offset": "outline-offset",
+	"cursor":         "cursor", "select": "select", "pointer-events": "pointer-events", "resize": "resize",
+	"appearance": "appearance", "touch": "touch", "scroll": "scroll", "snap": "snap", "will-change": "will-change",
+	"duration": "duration", "ease": "ease", "delay": "delay", "animate": "animate",
+	"scale": "scale", "scale-x": "scale-x", "scale-y": "scale-y", "rotate": "rotate",
+	"translate-x": "translate-x", "translate-y": "translate-y", "skew-x": "skew-x", "skew-y": "skew-y", "origin": "origin",
+	"blur": "blur", "brightness": "brightness", "contrast": "contrast", "grayscale": "grayscale",
+	"invert": "invert", "saturate": "saturate", "sepia": "sepia", "drop-shadow": "drop-shadow",
+	"backdrop-blur": "backdrop-blur", "backdrop-opacity": "backdrop-opacity",
+	"mix-blend": "mix-blend", "bg-blend": "bg-blend",
+	"from": "from", "via": "via", "to": "to",
+	"fill": "fill", "accent": "accent", "caret": "caret",
+	"float": "float", "clear": "clear", "object": "",
+	"text": "", "font": "", "bg": "", "flex": "", "shadow": "", "ring": "", "ring-offset": "",
+	"outline": "", "decoration": "", "stroke": "", "divide-x": "", "divide-y": "", "divide": "",
+	"border": "", "border-x": "", "border-y": "", "border-t": "", "border-r": "", "border-b": "",
+	"border-l": "", "border-s": "", "border-e": "",
+}
+
+// overrides lists the groups a shorthand group replaces.
+var overrides = map[string][]string{
+	"p":  {"px", "py", "ps", "pe", "pt", "pr", "pb", "pl"},
+	"px": {"pr", "pl"},
+	"py": {"pt", "pb"},
+	"m":  {"mx", "my", "ms", "me", "mt", "mr", "mb", "ml"},
+	"mx": {"mr", "ml"},
+	"my": {"mt", "mb"},
+
+	"size":    {"w", "h"},
+	"inset":   {"inset-x", "inset-y", "top", "right", "bottom", "left", "start", "end"},
+	"inset-x": {"right", "left"},
+	"inset-y": {"top", "bottom"},
+	"gap":     {"gap-x", "gap-y"},
+
+	"overflow":   {"overflow-x", "overflow-y"},
+	"overscroll": {"overscroll-x", "overscroll-y"},
+	"scale":      {"scale-x", "scale-y"},
+
+	"rounded":   {"rounded-t", "rounded-r", "rounded-b", "rounded-l", "rounded-s", "rounded-e", "rounded-tl", "rounded-tr", "rounded-br", "rounded-bl", "rounded-ss", "rounded-se", "rounded-es", "rounded-ee"},
+	"rounded-t": {"rounded-tl", "rounded-tr"},
+	"rounded-r": {"rounded-tr", "rounded-br"},
+	"rounded-b": {"rounded-br", "rounded-bl"},
+	"rounded-l": {"rounded-tl", "rounded-bl"},
+	"rounded-s": {"rounded-ss", "rounded-es"},
+	"rounded-e": {"rounded-se", "rounded-ee"},
+
+	"border-w":       {"border-w-x", "border-w-y", "border-w-t", "border-w-r", "border-w-b", "border-w-l", "border-w-s", "border-w-e"},
+	"border-w-x":     {"border-w-r", "border-w-l"},
+	"border-w-y":     {"border-w-t", "border-w-b"},
+	"border-color":   {"border-color-x", "border-color-y", "border-color-t", "border-color-r", "border-color-b", "border-color-l", "border-color-s", "border-color-e"},
+	"border-color-x": {"border-color-r", "border-color-l"},
+	"border-color-y": {"border-color-t", "border-color-b"},
+}
+
+// utility returns the group of a Tailwind utility without variants, or ""
+// when it is not recognised.
+func utility(u string) string {
+	if g, ok := keywords[u]; ok {
+		return g
+	}
+	// A prefix on its own, such as "rounded-t" or "border-x", uses the default value.
+	if g, ok := prefixes[u]; ok {
+		if g != "" {
+			return g
+		}
+		return byValue(u, "")
+	}
+	// Prefer the longest prefix, so "rounded-tl-lg" is "rounded-tl", not "rounded".
+	for i := len(u) - 1; i > 0; i-- {
+		if u[i] != '-' {
+			continue
+		}
+		prefix, value := u[:i], u[i+1:]
+		g, ok := prefixes[prefix]
+		if !ok {
+			continue
+		}
+		if g != "" {
+			return g
+		}
+		return byValue(prefix, value)
+	}
+	return ""
+}
+
+// byValue resolves the group of prefixes shared by several properties.
+func byValue(prefix, v string) string {
+	switch prefix {
+	case "text":
+		switch {
+		case in(v, "xs", "sm", "base", "lg", "xl") || strings.HasSuffix(v, "xl") && isNumber(strings.TrimSuffix(v, "xl")) || isLength(v):
+			return "font-size"
+		case in(v, "left", "center", "right", "justify", "start", "end"):
+			return "text-align"
+		case in(v, "wrap", "nowrap", "balance", "pretty"):
+			return "text-wrap"
+		case in(v, "ellipsis", "clip"):
+			return "text-overflow"
+		}
+		return "text-color"
+	case "font":
+		if in(v, "thin", "extralight", "light", "normal", "medium", "semibold", "bold", "extrabold", "black") || isNumber(v) {
+			return "font-weight"
+		}
+		return "font-family"
+	case "bg":
+		switch {
+		case in(v, "auto", "cover", "contain"):
+			return "bg-size"
+		case in(v, "center", "top", "bottom", "left", "right", "left-top", "left-bottom", "right-top", "right-bottom"):
+			return "bg-position"
+		case in(v, "repeat", "no-repeat", "repeat-x", "repeat-y", "repeat-round", "repeat-space"):
+			return "bg-repeat"
+		case in(v, "fixed", "local", "scroll"):
+			return "bg-attachment"
+		case v == "none" || strings.HasPrefix(v, "gradient-"):
+			return "bg-image"
+		case strings.HasPrefix(v, "clip-"):
+			return "bg-clip"
+		case strings.HasPrefix(v, "origin-"):
+			return "bg-origin"
+		}
+		return "bg-color"
+	case "flex":
+		switch {
+		case in(v, "row", "row-reverse", "col", "col-reverse"):
+			return "flex-direction"
+		case in(v, "wrap", "wrap-reverse", "nowrap"):
+			return "flex-wrap"
+		}
+		return "flex"
+	case "object":
+		if in(v, "contain", "cover", "fill", "none", "scale-down") {
+			return "object-fit"
+		}
+		return "object-position"
+	case "shadow":
+		if in(v, "sm", "md", "lg", "xl", "2xl", "inner", "none") || isLength(v) {
+			return "shadow"
+		}
+		return "shadow-color"
+	case "ring":
+		switch {
+		case v == "inset":
+			return "ring-inset"
+		case isNumber(v) || isLength(v):
+			return "ring-w"
+		}
+		return "ring-color"
+	case "ring-offset", "stroke":
+		if isNumber(v) || isLength(v) {
+			return prefix + "-w"
+		}
+		return prefix + "-color"
+	case "outline":
+		switch {
+		case in(v, "none", "dashed", "dotted", "double", "solid"):
+			return "outline-style"
+		case isNumber(v) || isLength(v):
+			return "outline-w"
+		}
+		return "outline-color"
+	case "decoration":
+		switch {
+		case in(v, "solid", "double", "dotted", "dashed", "wavy"):
+			return "decoration-style"
+		case in(v, "auto", "from-font") || isNumber(v) || isLength(v):
+			return "decoration-thickness"
+		}
+		return "decoration-color"
+	case "divide-x", "divide-y":
+		return prefix
+	case "divide":
+		if in(v, "solid", "dashed", "dotted", "double", "none") {
+			return "divide-style"
+		}
+		return "divide-color"
+	}
+
+	// border and its sides.
+	side := strings.TrimPrefix(prefix, "border")
+	switch {
+	case side == "" && in(v, "solid", "dashed", "dotted", "double", "hidden", "none"):
+		return "border-style"
+	case side == "" && in(v, "collapse", "separate"):
+		return "border-collapse"
+	case v == "" || isNumber(v) || isLength(v):
+		return "border-w" + side
+	}
+	return "border-color" + side
+}
+
+func in(v string, values ...string) bool {
+	return slices.Contains(values, v)
+}
+
+// isNumber reports whether v is a plain number, such as "2" or "0.5".
+func isNumber(v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, r := range v {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// isLength reports whether v is "px" or an arbitrary value that is not a
+// colour, such as "[3px]" or "[length:var(--w)]".
+func isLength(v string) bool {
+	if v == "px" {
+		return true
+	}
+	inner, ok := strings.CutPrefix(v, "[")
+	if !ok || !strings.HasSuffix(inner, "]") {
+		return false
+	}
+	inner = strings.TrimSuffix(inner, "]")
+	if strings.HasPrefix(inner, "length:") {
+		return true
+	}
+	if strings.HasPrefix(inner, "color:") || strings.HasPrefix(inner, "#") || strings.HasPrefix(inner, "rgb") ||
+		strings.HasPrefix(inner, "hsl") || strings.HasPrefix(inner, "oklch") {
+		return false
+	}
+	return len(inner) > 0 && (inner[0] >= '0' && inner[0] <= '9' || inner[0] == '.' || strings.HasPrefix(inner, "calc(") || strings.HasPrefix(inner, "var("))
+}
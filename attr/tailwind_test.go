@@ -0,0 +1,38 @@
+package attr
+
+import "testing"
+
+func TestTwMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []string
+		want    string
+	}{
+		{"later wins", []string{"p-2 p-4"}, "p-4"},
+		{"across arguments", []string{"px-4 py-2 bg-blue-600 text-white", "bg-red-600 px-6"}, "py-2 text-white bg-red-600 px-6"},
+		{"colour", []string{"text-blue-500", "text-red-500"}, "text-red-500"},
+		{"size and colour", []string{"text-lg text-red-500"}, "text-lg text-red-500"},
+		{"size", []string{"text-lg text-2xl text-[14px]"}, "text-[14px]"},
+		{"arbitrary colour", []string{"text-[#fff] text-sm text-red-500"}, "text-sm text-red-500"},
+		{"shorthand overrides longhand", []string{"px-2 pt-1 p-4"}, "p-4"},
+		{"longhand refines shorthand", []string{"p-4 px-2"}, "p-4 px-2"},
+		{"variants", []string{"p-2 hover:p-4 md:hover:p-1 hover:md:p-3"}, "p-2 hover:p-4 hover:md:p-3"},
+		{"important", []string{"p-2 !p-4 p-3"}, "!p-4 p-3"},
+		{"negative", []string{"mt-2 -mt-4"}, "-mt-4"},
+		{"display", []string{"block flex hidden"}, "hidden"},
+		{"border", []string{"border border-2 border-red-500 border-t-4 border-t"}, "border-2 border-red-500 border-t"},
+		{"rounded", []string{"rounded-tl-lg rounded-t-md rounded"}, "rounded"},
+		{"font", []string{"font-bold font-sans font-medium"}, "font-sans font-medium"},
+		{"bg", []string{"bg-cover bg-red-500 bg-center bg-blue-500"}, "bg-cover bg-center bg-blue-500"},
+		{"arbitrary property", []string{"[mask-type:luminance] [mask-type:alpha]"}, "[mask-type:alpha]"},
+		{"unknown kept", []string{"card card-lg card", "btn"}, "card-lg card btn"},
+		{"empty", []string{"", "  "}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TwMerge(tt.classes...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package attr
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// URLBuilder builds a URL from a base and separately encoded parts. Create
+// one with URL.
+type URLBuilder struct {
+	base     string
+	path     []string
+	query    []string // encoded key=value pairs
+	fragment *string
+}
+
+// URL returns a builder for a URL starting at base, such as "/search" or
+// "https://example.com/api". Path segments, query parameters and the
+// fragment added to it are percent-encoded for their position, so values
+// from user input cannot end a parameter early, add parameters of their own
+// or break out of the attribute the URL is written to.
+//
+// Usage:
+//
+//	a.New().Href(attr.URL("/search").Query("q", term).Query("page", strconv.Itoa(n)).String())
+//	img.New().Src(attr.URL(cdn).Path("avatars", user.Handle+".png").String())
+func URL(base string) *URLBuilder {
+	return &URLBuilder{base: base}
+}
+
+// Path appends segments to the base path. Each segment is escaped whole,
+// so a "/" inside one is encoded rather than starting a new segment. Empty
+// segments are skipped.
+func (b *URLBuilder) Path(segments ...string) *URLBuilder {
+	for _, s := range segments {
+		if s != "" {
+			b.path = append(b.path, url.PathEscape(s))
+		}
+	}
+	return b
+}
+
+// Query appends the query parameter key=value, after any in the base.
+// Parameters keep the order they were added in, and a key may be added
+// more than once.
+func (b *URLBuilder) Query(key, value string) *URLBuilder {
+	b.query = append(b.query, url.QueryEscape(key)+"="+url.QueryEscape(value))
+	return b
+}
+
+// Fragment sets the fragment, replacing any in the base. An empty fragment
+// removes it.
+func (b *URLBuilder) Fragment(fragment string) *URLBuilder {
+	b.fragment = &fragment
+	return b
+}
+
+// errUnsafeScheme is reported for bases that would run script.
+var errUnsafeScheme = errors.New("attr: unsafe URL scheme")
+
+// Err reports whether the base cannot be used: it does not parse, or its
+// scheme is one that runs script, such as javascript:.
+func (b *URLBuilder) Err() error {
+	_, err := b.parse()
+	return err
+}
+
+func (b *URLBuilder) parse() (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(b.base))
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "javascript", "vbscript", "data":
+		return nil, errUnsafeScheme
+	}
+	return u, nil
+}
+
+// String returns the URL, or "" when the base cannot be used (see Err).
+// The result contains no quotes, angle brackets or spaces, so it may be
+// passed to href and src setters as it is.
+func (b *URLBuilder) String() string {
+	u, err := b.parse()
+	if err != nil {
+		return ""
+	}
+	if len(b.path) > 0 {
+		raw := strings.TrimSuffix(u.EscapedPath(), "/") + "/" + strings.Join(b.path, "/")
+		if u.Host != "" && !strings.HasPrefix(raw, "/") {
+			raw = "/" + raw
+		}
+		path, err := url.PathUnescape(raw)
+		if err != nil {
+			return ""
+		}
+		u.Path, u.RawPath = path, raw
+	}
+	if len(b.query) > 0 {
+		q := b.query
+		if u.RawQuery != "" {
+			q = append([]string{u.RawQuery}, q...)
+		}
+		u.RawQuery = strings.Join(q, "&")
+	}
+	if b.fragment != nil {
+		u.Fragment, u.RawFragment = *b.fragment, ""
+	}
+	return unsafeChars.Replace(u.String())
+}
+
+// unsafeChars encodes characters the base may carry that could end an
+// attribute value or be misread around it.
+var unsafeChars = strings.NewReplacer(`"`, "%22", "'", "%27", "<", "%3C", ">", "%3E", " ", "%20", "`", "%60")
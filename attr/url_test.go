@@ -0,0 +1,42 @@
+package attr
+
+import "testing"
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  *URLBuilder
+		want string
+	}{
+		{"query", URL("/search").Query("q", `a&b=c "d"`).Query("page", "2"), "/search?q=a%26b%3Dc+%22d%22&page=2"},
+		{"repeated key", URL("/f").Query("tag", "x").Query("tag", "y"), "/f?tag=x&tag=y"},
+		{"base query kept", URL("/f?sort=asc").Query("q", "z"), "/f?sort=asc&q=z"},
+		{"path", URL("https://cdn.example.com/users/").Path("a b", "x/y", "", "c.png"), "https://cdn.example.com/users/a%20b/x%2Fy/c.png"},
+		{"host only", URL("https://example.com").Path("docs"), "https://example.com/docs"},
+		{"fragment", URL("/doc#old").Fragment("sec 2<x>"), "/doc#sec%202%3Cx%3E"},
+		{"no fragment", URL("/doc#old").Fragment(""), "/doc"},
+		{"all parts", URL("/u").Path("ann").Query("tab", "posts").Fragment("top"), "/u/ann?tab=posts#top"},
+		{"quoted base", URL(`/x?a="><script>`), "/x?a=%22%3E%3Cscript%3E"},
+		{"javascript", URL("javascript:alert(1)").Query("a", "b"), ""},
+		{"data", URL(" DATA:text/html,x"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.url.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLErr(t *testing.T) {
+	if err := URL("/ok").Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+	if err := URL("javascript:void(0)").Err(); err == nil {
+		t.Error("Err() = nil for a javascript: URL")
+	}
+	if err := URL("http://[::1").Err(); err == nil {
+		t.Error("Err() = nil for an unparsable URL")
+	}
+}
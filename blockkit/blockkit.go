@@ -0,0 +1,473 @@
+// Package blockkit serializes components to Slack Block Kit, so one component
+// definition can target both the web UI and Slack messages.
+//
+// A node is rendered and its markup mapped onto blocks. Only a constrained
+// subset of HTML has a Block Kit equivalent:
+//
+//	h1 to h6                  header
+//	p, blockquote, pre        section (mrkdwn)
+//	ul, ol                    section with one line per item
+//	dl                        section fields, one per dt/dd pair
+//	hr                        divider
+//	img                       image
+//	footer                    context
+//	button, a[role=button]    buttons, with consecutive buttons in one actions block
+//
+// Within text, strong/b, em/i, s/del, code and links become mrkdwn and br a
+// line break. Other elements are treated as containers and loose text inside
+// them becomes a section. Script, style, template and head are skipped, as is
+// any element marked hidden, aria-hidden="true" or data-slack="skip".
+//
+// A button's name (or id) becomes its action_id and its value its value; a
+// "primary" or "danger" class or data-style selects the button style.
+//
+// Usage:
+//
+//	body, err := blockkit.Marshal(DeployAlert(run))
+package blockkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ErrLimit is returned when the content needs more blocks than a Slack
+// message allows.
+var ErrLimit = errors.New("blockkit: too many blocks")
+
+// Slack Block Kit limits.
+const (
+	MaxBlocks  = 50
+	maxHeader  = 150
+	maxSection = 3000
+	maxField   = 2000
+	maxFields  = 10
+	maxActions = 25
+	maxButton  = 75
+)
+
+// Text is a text object.
+type Text struct {
+	// Type is "plain_text" or "mrkdwn".
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Element is an interactive element. Only buttons are produced.
+type Element struct {
+	Type     string `json:"type"`
+	Text     *Text  `json:"text,omitempty"`
+	ActionID string `json:"action_id,omitempty"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Style    string `json:"style,omitempty"`
+}
+
+// Block is a layout block. Fields unused by the block's Type are empty.
+type Block struct {
+	Type     string `json:"type"`
+	BlockID  string `json:"block_id,omitempty"`
+	Text     *Text  `json:"text,omitempty"`
+	Fields   []Text `json:"fields,omitempty"`
+	Elements []any  `json:"elements,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	AltText  string `json:"alt_text,omitempty"`
+}
+
+// Blocks renders n and maps its markup onto blocks.
+func Blocks(n node.Node) ([]Block, error) {
+	if n == nil {
+		return nil, nil
+	}
+	return Parse(string(n.Render()))
+}
+
+// Parse maps rendered markup onto blocks.
+func Parse(src string) ([]Block, error) {
+	c := &converter{}
+	c.container(markup.Parse(src))
+	if len(c.blocks) > MaxBlocks {
+		return c.blocks, ErrLimit
+	}
+	return c.blocks, nil
+}
+
+// Marshal renders n and returns a Slack message payload {"blocks": [...]}.
+func Marshal(n node.Node) ([]byte, error) {
+	blocks, err := Blocks(n)
+	if err != nil {
+		return nil, err
+	}
+	return encode(struct {
+		Blocks []Block `json:"blocks"`
+	}{blocks})
+}
+
+// encode marshals v without escaping HTML characters, which are already
+// escaped for mrkdwn and would otherwise be escaped twice over in the payload.
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// inline lists elements whose content flows into the surrounding text.
+var inline = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "br": true,
+	"cite": true, "code": true, "data": true, "del": true, "dfn": true, "em": true,
+	"i": true, "ins": true, "kbd": true, "label": true, "mark": true, "q": true,
+	"s": true, "samp": true, "small": true, "span": true, "strike": true,
+	"strong": true, "sub": true, "sup": true, "time": true, "u": true, "var": true,
+}
+
+// skipped lists elements that never contribute content.
+var skipped = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true, "noscript": true,
+	"input": true, "select": true, "textarea": true,
+}
+
+type converter struct {
+	blocks []Block
+}
+
+func (c *converter) add(b Block) {
+	c.blocks = append(c.blocks, b)
+}
+
+// container maps the children of n, collecting loose text and inline
+// elements into sections between block-level children.
+func (c *converter) container(n *markup.Node) {
+	var text strings.Builder
+	flush := func() {
+		c.section(tidy(text.String()), "")
+		text.Reset()
+	}
+	for _, ch := range n.Children {
+		switch {
+		case ch.Type == markup.TextNode, ch.Type == markup.ElementNode && inline[ch.Tag] && !isButton(ch) && !skip(ch):
+			writeInline(&text, ch)
+		case ch.Type == markup.ElementNode:
+			flush()
+			c.block(ch)
+		}
+	}
+	flush()
+}
+
+func (c *converter) block(n *markup.Node) {
+	if skip(n) {
+		return
+	}
+	id, _ := n.Attr("id")
+	switch n.Tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if t := collapse(n.Text()); t != "" {
+			c.add(Block{Type: "header", BlockID: id, Text: &Text{Type: "plain_text", Text: clip(t, maxHeader)}})
+		}
+	case "p":
+		c.section(mrkdwn(n), id)
+	case "blockquote":
+		lines := strings.Split(mrkdwn(n), "\n")
+		for i, l := range lines {
+			lines[i] = "> " + l
+		}
+		c.section(strings.Join(lines, "\n"), id)
+	case "pre":
+		if t := strings.Trim(n.Text(), "\n"); t != "" {
+			c.section("```"+escape(t)+"```", id)
+		}
+	case "ul", "ol":
+		c.list(n, id)
+	case "dl":
+		c.fields(n, id)
+	case "hr":
+		c.add(Block{Type: "divider"})
+	case "img":
+		c.image(n, id)
+	case "footer":
+		if t := mrkdwn(n); t != "" {
+			c.add(Block{Type: "context", BlockID: id, Elements: []any{Text{Type: "mrkdwn", Text: clip(t, maxSection)}}})
+		}
+	case "button":
+		c.button(n, "")
+	default:
+		if isButton(n) {
+			href, _ := n.Attr("href")
+			c.button(n, href)
+			return
+		}
+		c.container(n)
+	}
+}
+
+// section adds text as one or more sections, splitting it at the section
+// length limit.
+func (c *converter) section(text, id string) {
+	for _, chunk := range chunks(text, maxSection) {
+		c.add(Block{Type: "section", BlockID: id, Text: &Text{Type: "mrkdwn", Text: chunk}})
+		id = ""
+	}
+}
+
+func (c *converter) list(n *markup.Node, id string) {
+	var lines []string
+	for _, li := range n.Elements() {
+		if li.Tag != "li" || skip(li) {
+			continue
+		}
+		marker := "• "
+		if n.Tag == "ol" {
+			marker = strconv.Itoa(len(lines)+1) + ". "
+		}
+		lines = append(lines, marker+strings.ReplaceAll(mrkdwn(li), "\n", "\n    "))
+	}
+	c.section(strings.Join(lines, "\n"), id)
+}
+
+func (c *converter) fields(n *markup.Node, id string) {
+	var fields []Text
+	for _, el := range n.Elements() {
+		if skip(el) {
+			continue
+		}
+		switch el.Tag {
+		case "dt":
+			fields = append(fields, Text{Type: "mrkdwn", Text: "*" + strings.TrimSpace(mrkdwn(el)) + "*"})
+		case "dd":
+			t := mrkdwn(el)
+			if len(fields) == 0 {
+				fields = append(fields, Text{Type: "mrkdwn", Text: t})
+			} else {
+				fields[len(fields)-1].Text += "\n" + t
+			}
+		}
+	}
+	for len(fields) > 0 {
+		batch := fields[:min(len(fields), maxFields)]
+		fields = fields[len(batch):]
+		for i := range batch {
+			batch[i].Text = clip(batch[i].Text, maxField)
+		}
+		c.add(Block{Type: "section", BlockID: id, Fields: batch})
+		id = ""
+	}
+}
+
+func (c *converter) image(n *markup.Node, id string) {
+	src, _ := n.Attr("src")
+	if src == "" {
+		return
+	}
+	alt, _ := n.Attr("alt")
+	if alt == "" {
+		alt = "image"
+	}
+	c.add(Block{Type: "image", BlockID: id, ImageURL: src, AltText: alt})
+}
+
+// button adds a button, joining the previous actions block when there is room.
+func (c *converter) button(n *markup.Node, url string) {
+	label := collapse(n.Text())
+	if label == "" {
+		return
+	}
+	e := Element{Type: "button", Text: &Text{Type: "plain_text", Text: clip(label, maxButton)}, URL: url}
+	if name, ok := n.Attr("name"); ok {
+		e.ActionID = name
+	} else {
+		e.ActionID, _ = n.Attr("id")
+	}
+	e.Value, _ = n.Attr("value")
+	if s, _ := n.Attr("data-style"); s == "primary" || s == "danger" {
+		e.Style = s
+	} else if n.HasClass("primary") {
+		e.Style = "primary"
+	} else if n.HasClass("danger") {
+		e.Style = "danger"
+	}
+	if last := len(c.blocks) - 1; last >= 0 && c.blocks[last].Type == "actions" && len(c.blocks[last].Elements) < maxActions {
+		c.blocks[last].Elements = append(c.blocks[last].Elements, e)
+		return
+	}
+	c.add(Block{Type: "actions", Elements: []any{e}})
+}
+
+// isButton reports whether an element other than <button> acts as one.
+func isButton(n *markup.Node) bool {
+	role, _ := n.Attr("role")
+	return role == "button"
+}
+
+// skip reports whether an element is excluded from the message.
+func skip(n *markup.Node) bool {
+	if skipped[n.Tag] {
+		return true
+	}
+	if _, ok := n.Attr("hidden"); ok {
+		return true
+	}
+	if v, _ := n.Attr("aria-hidden"); v == "true" {
+		return true
+	}
+	v, _ := n.Attr("data-slack")
+	return v == "skip"
+}
+
+// mrkdwn returns the content of n as mrkdwn text.
+func mrkdwn(n *markup.Node) string {
+	var b strings.Builder
+	for _, ch := range n.Children {
+		writeInline(&b, ch)
+	}
+	return tidy(b.String())
+}
+
+// writeInline writes n as mrkdwn. Block-level descendants are flattened onto
+// their own lines.
+func writeInline(b *strings.Builder, n *markup.Node) {
+	switch n.Type {
+	case markup.TextNode:
+		b.WriteString(escape(collapseSpace(n.Data)))
+		return
+	case markup.ElementNode:
+	default:
+		return
+	}
+	if skip(n) {
+		return
+	}
+	inner := func() string {
+		var ib strings.Builder
+		for _, ch := range n.Children {
+			writeInline(&ib, ch)
+		}
+		return ib.String()
+	}
+	switch n.Tag {
+	case "br":
+		b.WriteByte('\n')
+	case "strong", "b":
+		b.WriteString(wrap("*", inner()))
+	case "em", "i":
+		b.WriteString(wrap("_", inner()))
+	case "s", "del", "strike":
+		b.WriteString(wrap("~", inner()))
+	case "code", "kbd", "samp":
+		if t := n.Text(); t != "" {
+			b.WriteString("`" + escape(t) + "`")
+		}
+	case "a":
+		href, _ := n.Attr("href")
+		text := strings.TrimSpace(inner())
+		if href == "" || strings.ContainsAny(href, "|<>") {
+			b.WriteString(text)
+		} else if text == "" || text == escape(href) {
+			b.WriteString("<" + href + ">")
+		} else {
+			b.WriteString("<" + href + "|" + text + ">")
+		}
+	case "img":
+		alt, _ := n.Attr("alt")
+		b.WriteString(escape(alt))
+	default:
+		if inline[n.Tag] {
+			b.WriteString(inner())
+		} else {
+			b.WriteString("\n" + inner() + "\n")
+		}
+	}
+}
+
+// wrap surrounds s with a mrkdwn mark, keeping surrounding spaces outside it
+// as Slack only recognises marks adjacent to text.
+func wrap(mark, s string) string {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return s
+	}
+	lead := s[:strings.Index(s, t)]
+	trail := s[len(lead)+len(t):]
+	return lead + mark + t + mark + trail
+}
+
+// escape escapes the characters Slack treats as control sequences.
+var escape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// collapseSpace reduces runs of whitespace to single spaces, keeping a leading
+// or trailing one.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// collapse trims s and reduces runs of whitespace to single spaces.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// tidy trims every line of s and drops blank lines at either end.
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// chunks splits s into pieces of at most n bytes, preferring to break at a
+// newline or space so escape sequences and words stay whole.
+func chunks(s string, n int) []string {
+	var out []string
+	for len(s) > n {
+		cut := strings.LastIndexAny(s[:n], "\n ")
+		if cut <= 0 {
+			cut = n
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+		}
+		out = append(out, strings.TrimRight(s[:cut], " \n"))
+		s = strings.TrimLeft(s[cut:], " \n")
+	}
+	if s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// clip returns the longest prefix of s of at most n runes.
+func clip(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
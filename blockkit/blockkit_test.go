@@ -0,0 +1,65 @@
+package blockkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"header", `<h2> Deploy  failed </h2>`, `[{"type":"header","text":{"type":"plain_text","text":"Deploy failed"}}]`},
+		{"paragraph", `<p id="s1">Build <b>#42</b> on <a href="https://ci.example.com/42">CI</a> &amp; <code>a&lt;b</code></p>`,
+			`[{"type":"section","block_id":"s1","text":{"type":"mrkdwn","text":"Build *#42* on <https://ci.example.com/42|CI> &amp; ` + "`a&lt;b`" + `"}}]`},
+		{"loose text", `<div>Hello <em>there </em>you<br>next</div>`, `[{"type":"section","text":{"type":"mrkdwn","text":"Hello _there_ you\nnext"}}]`},
+		{"list", `<ol><li>one</li><li>two</li></ol>`, `[{"type":"section","text":{"type":"mrkdwn","text":"1. one\n2. two"}}]`},
+		{"fields", `<dl><dt>Env</dt><dd>prod</dd><dt>By</dt><dd>ana</dd></dl>`, `[{"type":"section","fields":[{"type":"mrkdwn","text":"*Env*\nprod"},{"type":"mrkdwn","text":"*By*\nana"}]}]`},
+		{"divider and image", `<hr /><img src="https://x/y.png" />`, `[{"type":"divider"},{"type":"image","image_url":"https://x/y.png","alt_text":"image"}]`},
+		{"footer", `<footer>Sent by <i>ops</i></footer>`, `[{"type":"context","elements":[{"type":"mrkdwn","text":"Sent by _ops_"}]}]`},
+		{"buttons", `<div><button name="ack" value="42" class="primary">Acknowledge</button> <a role="button" href="https://x/42">Open</a></div>`,
+			`[{"type":"actions","elements":[{"type":"button","text":{"type":"plain_text","text":"Acknowledge"},"action_id":"ack","value":"42","style":"primary"},{"type":"button","text":{"type":"plain_text","text":"Open"},"url":"https://x/42"}]}]`},
+		{"skipped", `<script>x()</script><p hidden>no</p><p data-slack="skip">no</p><nav aria-hidden="true"><p>no</p></nav>`, `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := encode(blocks)
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	blocks, err := Parse("<p>" + strings.Repeat("word ", 1000) + "</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 || len(blocks[0].Text.Text) > maxSection {
+		t.Errorf("got %d sections, first of %d bytes", len(blocks), len(blocks[0].Text.Text))
+	}
+	if _, err := Parse(strings.Repeat("<hr>", MaxBlocks+1)); !errors.Is(err, ErrLimit) {
+		t.Errorf("got %v, want ErrLimit", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	got, err := Marshal(div.New(p.Text("a < b")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"blocks":[{"type":"section","text":{"type":"mrkdwn","text":"a &lt; b"}}]}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
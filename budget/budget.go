@@ -0,0 +1,204 @@
+// Package budget lets components declare limits on the output they render -
+// bytes, elements and nesting depth - so payload bloat is caught while a page
+// evolves rather than discovered in production.
+//
+// Budgets are checked only in renders with assertions enabled through
+// node.WithAssertions, typically tests and development servers. Elsewhere a
+// budgeted component renders exactly like the node it wraps. Each exceeded
+// limit is reported as an *Error naming the component and pointing at the
+// part of its output responsible.
+//
+// Usage:
+//
+//	func ProductCard(p Product) node.Node {
+//	    return budget.Component("ProductCard", budget.Limits{Bytes: 4 << 10, Depth: 6}, div.New(
+//	        ...
+//	    ))
+//	}
+//
+//	ctx := node.WithAssertions(context.Background(), func(err error) { t.Error(err) })
+//	fluent.RenderContext(ctx, page, &buf)
+package budget
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Limits are the maximums a component may render. A zero limit is unchecked.
+type Limits struct {
+	// Bytes limits the size of the rendered HTML.
+	Bytes int
+	// Nodes limits the number of elements rendered.
+	Nodes int
+	// Depth limits element nesting, counting the outermost elements as 1.
+	Depth int
+}
+
+// Error reports a component exceeding one of its limits.
+type Error struct {
+	Component string
+	// Limit is "bytes", "nodes" or "depth".
+	Limit  string
+	Max    int
+	Actual int
+	// Detail points at the cause: the largest top-level element for bytes
+	// and nodes, or the path to the deepest element for depth.
+	Detail string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("budget: %s rendered %d %s, over its budget of %d", e.Component, e.Actual, e.Limit, e.Max)
+	if e.Detail != "" {
+		msg += "; " + e.Detail
+	}
+	return msg
+}
+
+// Component wraps n with a name and limits. It renders as
+// node.Component(name, n).
+func Component(name string, limits Limits, n node.Node) *Budgeted {
+	return &Budgeted{name: name, limits: limits, node: node.Component(name, n)}
+}
+
+// Budgeted is a component with output limits. Create one with Component.
+type Budgeted struct {
+	name   string
+	limits Limits
+	node   *node.NamedComponent
+}
+
+// Limits returns the component's limits.
+func (b *Budgeted) Limits() Limits {
+	return b.limits
+}
+
+// Render generates the HTML of the wrapped node.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (b *Budgeted) Render(w ...io.Writer) []byte {
+	return node.Render(b, w...)
+}
+
+// RenderBuilder writes the wrapped node and, when the render has assertions
+// enabled, measures the output against the limits.
+func (b *Budgeted) RenderBuilder(buf *bytes.Buffer) {
+	if !node.Checking(buf) {
+		b.node.RenderBuilder(buf)
+		return
+	}
+	start := buf.Len()
+	b.node.RenderBuilder(buf)
+	for _, err := range b.Check(buf.Bytes()[start:]) {
+		node.Fail(buf, err)
+	}
+}
+
+// Check measures out, the component's rendered HTML, against its limits and
+// returns an *Error for each limit exceeded.
+func (b *Budgeted) Check(out []byte) []error {
+	var errs []error
+	doc := markup.Parse(string(out))
+	if limit := b.limits.Bytes; limit > 0 && len(out) > limit {
+		errs = append(errs, &Error{Component: b.name, Limit: "bytes", Max: limit, Actual: len(out),
+			Detail: largest(doc, func(n *markup.Node) int { return size(n, out) }, "bytes")})
+	}
+	if limit := b.limits.Nodes; limit > 0 {
+		if count := elements(doc); count > limit {
+			errs = append(errs, &Error{Component: b.name, Limit: "nodes", Max: limit, Actual: count,
+				Detail: largest(doc, elements, "elements")})
+		}
+	}
+	if limit := b.limits.Depth; limit > 0 {
+		if d, deepest := depth(doc); d > limit {
+			errs = append(errs, &Error{Component: b.name, Limit: "depth", Max: limit, Actual: d,
+				Detail: "deepest element " + deepest.Path()})
+		}
+	}
+	return errs
+}
+
+// Nodes returns the wrapped node as the only child.
+func (b *Budgeted) Nodes() []node.Node {
+	return []node.Node{b.node}
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (b *Budgeted) SetAttribute(key string, value string) {
+	b.node.SetAttribute(key, value)
+}
+
+// elements counts the elements in n and its descendants.
+func elements(n *markup.Node) int {
+	count := 0
+	n.Walk(func(c *markup.Node) bool {
+		if c.Type == markup.ElementNode {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// depth returns the deepest element nesting below n and that element.
+func depth(n *markup.Node) (int, *markup.Node) {
+	best, deepest := 0, n
+	for _, c := range n.Elements() {
+		d, el := depth(c)
+		if d+1 > best {
+			best, deepest = d+1, el
+		}
+	}
+	if best == 0 && n.Type == markup.ElementNode {
+		deepest = n
+	}
+	return best, deepest
+}
+
+// size returns the number of bytes of out spanned by element n.
+func size(n *markup.Node, out []byte) int {
+	return end(n, out) - n.Offset
+}
+
+// end returns the offset where n's content ends: the start of its next
+// sibling, or the end of its parent. A last child is counted up to the close
+// of its parent.
+func end(n *markup.Node, out []byte) int {
+	p := n.Parent
+	if p == nil {
+		return len(out)
+	}
+	for i, c := range p.Children {
+		if c == n && i+1 < len(p.Children) {
+			return p.Children[i+1].Offset
+		}
+	}
+	if p.Type == markup.DocumentNode {
+		return len(out)
+	}
+	return end(p, out)
+}
+
+// largest describes the top-level element of doc scoring highest by measure.
+func largest(doc *markup.Node, measure func(*markup.Node) int, unit string) string {
+	top := doc.Elements()
+	if len(top) == 1 {
+		// A single root says nothing; look one level down.
+		top = top[0].Elements()
+	}
+	var best *markup.Node
+	bestScore := 0
+	for _, el := range top {
+		if score := measure(el); score > bestScore {
+			best, bestScore = el, score
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return fmt.Sprintf("largest part %s with %d %s", best.Path(), bestScore, unit)
+}
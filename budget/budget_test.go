@@ -0,0 +1,98 @@
+package budget
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+func card() node.Node {
+	return div.New(
+		p.Text("short"),
+		ul.New(li.Text("one"), li.Text("two"), li.New(span.Text(strings.Repeat("x", 100)))),
+	).Class("card")
+}
+
+func render(t *testing.T, n node.Node) (string, []error) {
+	t.Helper()
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String(), errs
+}
+
+func TestBudget(t *testing.T) {
+	want := string(card().Render())
+	tests := []struct {
+		name   string
+		limits Limits
+		limit  string
+		actual int
+		detail string
+	}{
+		{"within", Limits{Bytes: 1000, Nodes: 10, Depth: 4}, "", 0, ""},
+		{"bytes", Limits{Bytes: 100}, "bytes", len(want), "largest part div > ul with"},
+		{"nodes", Limits{Nodes: 5}, "nodes", 7, "largest part div > ul with 5 elements"},
+		{"depth", Limits{Depth: 3}, "depth", 4, "deepest element div > ul > li:nth-of-type(3) > span"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := render(t, Component("Card", tt.limits, card()))
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+			if tt.limit == "" {
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+			}
+			var be *Error
+			if !errors.As(errs[0], &be) {
+				t.Fatalf("got %T, want *Error", errs[0])
+			}
+			if be.Component != "Card" || be.Limit != tt.limit || be.Actual != tt.actual {
+				t.Errorf("got %s %s %d, want Card %s %d", be.Component, be.Limit, be.Actual, tt.limit, tt.actual)
+			}
+			if !strings.Contains(be.Detail, tt.detail) {
+				t.Errorf("detail %q does not contain %q", be.Detail, tt.detail)
+			}
+		})
+	}
+}
+
+func TestBudgetNotChecked(t *testing.T) {
+	n := Component("Card", Limits{Bytes: 1}, card())
+	if got, want := string(n.Render()), string(card().Render()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), n, &buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBudgetNested(t *testing.T) {
+	inner := Component("Inner", Limits{Nodes: 1}, div.New(span.Static("a"), span.Static("b")))
+	outer := Component("Outer", Limits{Nodes: 10}, div.New(inner))
+	_, errs := render(t, outer)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Inner rendered 3 nodes, over its budget of 1") {
+		t.Errorf("got %v", errs)
+	}
+}
@@ -0,0 +1,244 @@
+// Package cache provides in-memory caching of rendered output.
+//
+// The Store holds rendered bytes keyed by string with a per-entry expiry.
+// It backs full-page caching (see Page), fragment caching (see Fragment) and
+// any other layer that needs to serve previously rendered output without
+// re-rendering.
+package cache
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a single cached value.
+type entry struct {
+	body    []byte
+	header  http.Header
+	status  int
+	tags    []string
+	expires time.Time
+	// vary marks an entry standing in for a page whose response varies on
+	// request headers beyond its dimensions. It names those headers, whose
+	// values select the variant stored under a longer key.
+	vary []string
+}
+
+// expired reports whether the entry has passed its expiry time.
+// A zero expiry never expires.
+func (e *entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// DefaultMaxEntries is the number of entries a Store created by New holds
+// before it starts evicting. Change it with SetMaxEntries.
+const DefaultMaxEntries = 10000
+
+// Store is a concurrency-safe in-memory cache of rendered output.
+// The zero value is not usable; create one with New.
+//
+// A Store holds at most its maximum number of entries. Storing a new key in
+// a full Store first removes expired entries and then, if that did not free
+// enough room, arbitrary live ones, so keys derived from requests cannot
+// grow it without bound.
+type Store struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+	tags    map[string]map[string]struct{}
+	gen     uint64
+	now     func() time.Time
+	max     int
+
+	fmu     sync.Mutex
+	flights map[string]*flight
+}
+
+// New creates an empty Store.
+func New() *Store {
+	return &Store{
+		entries: make(map[string]*entry),
+		tags:    make(map[string]map[string]struct{}),
+		now:     time.Now,
+		max:     DefaultMaxEntries,
+		flights: make(map[string]*flight),
+	}
+}
+
+// SetMaxEntries sets the number of entries s holds before it starts
+// evicting. Zero or less removes the limit.
+func (s *Store) SetMaxEntries(n int) {
+	s.mu.Lock()
+	s.max = n
+	s.mu.Unlock()
+}
+
+// Default is the Store used by package-level helpers.
+var Default = New()
+
+// Get returns the cached bytes for key, if present and not expired.
+// The returned slice must not be modified.
+func (s *Store) Get(key string) ([]byte, bool) {
+	e, ok := s.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return e.body, true
+}
+
+// Set stores b under key for the given ttl. A ttl of zero or less never expires.
+func (s *Store) Set(key string, b []byte, ttl time.Duration) {
+	s.store(key, &entry{body: b}, ttl)
+}
+
+// Delete removes key from the store.
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+}
+
+// DeletePrefix removes every key starting with prefix and returns the number removed.
+func (s *Store) DeletePrefix(prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	return n
+}
+
+// Clear removes every entry from the store.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	clear(s.entries)
+	clear(s.tags)
+	s.gen++
+	s.mu.Unlock()
+}
+
+// SetTagged stores b under key for ttl, like Set, and associates the entry
+// with tags so InvalidateTag can remove it.
+func (s *Store) SetTagged(key string, b []byte, ttl time.Duration, tags ...string) {
+	s.store(key, &entry{body: b, tags: tags}, ttl)
+}
+
+// InvalidateTag removes every entry stored with tag and returns the number
+// removed. Fragments being built when it is called are not stored, as they
+// may have been built from the data the invalidation is for.
+func (s *Store) InvalidateTag(tag string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for key := range s.tags[tag] {
+		// The key may since have been replaced by an entry without the tag.
+		if e, ok := s.entries[key]; ok && slices.Contains(e.tags, tag) {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	delete(s.tags, tag)
+	s.gen++
+	return n
+}
+
+// InvalidateTag removes every entry stored with tag from the Default store.
+//
+// Usage:
+//
+//	// After updating prices
+//	cache.InvalidateTag("prices")
+func InvalidateTag(tag string) int {
+	return Default.InvalidateTag(tag)
+}
+
+// Len returns the number of entries currently held, including any that have
+// expired but not yet been evicted.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// lookup returns the live entry for key, evicting it if it has expired.
+func (s *Store) lookup(key string) (*entry, bool) {
+	s.mu.RLock()
+	e, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if e.expired(s.now()) {
+		s.mu.Lock()
+		if cur, ok := s.entries[key]; ok && cur == e {
+			delete(s.entries, key)
+		}
+		s.mu.Unlock()
+		return nil, false
+	}
+	return e, true
+}
+
+// store writes e under key with the given ttl.
+func (s *Store) store(key string, e *entry, ttl time.Duration) {
+	if ttl > 0 {
+		e.expires = s.now().Add(ttl)
+	}
+	s.mu.Lock()
+	s.put(key, e)
+	s.mu.Unlock()
+}
+
+// put writes e under key and indexes its tags, making room first when the
+// store is full. The caller must hold mu.
+func (s *Store) put(key string, e *entry) {
+	if _, ok := s.entries[key]; !ok && s.max > 0 && len(s.entries) >= s.max {
+		s.evict()
+	}
+	s.entries[key] = e
+	for _, tag := range e.tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// evict removes expired entries and then, until an eighth of the store is
+// free, arbitrary live ones, so that a full store evicts in batches rather
+// than on every put. The caller must hold mu.
+func (s *Store) evict() {
+	now := s.now()
+	for key, e := range s.entries {
+		if e.expired(now) {
+			s.remove(key, e)
+		}
+	}
+	target := s.max - max(s.max/8, 1)
+	for key, e := range s.entries {
+		if len(s.entries) <= target {
+			break
+		}
+		s.remove(key, e)
+	}
+}
+
+// remove deletes the entry e held under key and its tag index entries.
+// The caller must hold mu.
+func (s *Store) remove(key string, e *entry) {
+	delete(s.entries, key)
+	for _, tag := range e.tags {
+		delete(s.tags[tag], key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStoreExpiry(t *testing.T) {
+	s := New()
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	s.Set("a", []byte("hello"), time.Minute)
+	if got, ok := s.Get("a"); !ok || string(got) != "hello" {
+		t.Fatalf("Get() = %q, %v, want hello, true", got, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := s.Get("a"); ok {
+		t.Error("Get() after expiry returned a value")
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want expired entry evicted", s.Len())
+	}
+}
+
+func TestStoreDeletePrefix(t *testing.T) {
+	s := New()
+	s.Set("/blog\x00a", []byte("1"), 0)
+	s.Set("/blog\x00b", []byte("2"), 0)
+	s.Set("/about\x00", []byte("3"), 0)
+
+	if n := s.DeletePrefix("/blog\x00"); n != 2 {
+		t.Errorf("DeletePrefix() = %d, want 2", n)
+	}
+	if _, ok := s.Get("/about\x00"); !ok {
+		t.Error("DeletePrefix() removed an unrelated key")
+	}
+}
+
+func TestPageVary(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, "lang=%s", r.Header.Get("Accept-Language"))
+	})
+	p := NewPage(time.Minute, Locale(), Cookie("theme"), Cookie("segment"))
+	srv := p.Handler(h)
+
+	get := func(lang string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/home", nil)
+		req.Header.Set("Accept-Language", lang)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("en")
+	if first.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("first X-Cache = %q, want MISS", first.Header().Get("X-Cache"))
+	}
+	if got := first.Header().Get("Vary"); got != "Accept-Language, Cookie" {
+		t.Errorf("Vary = %q, want %q", got, "Accept-Language, Cookie")
+	}
+
+	second := get("en")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != "lang=en" {
+		t.Errorf("second = %q %q, want HIT lang=en", second.Header().Get("X-Cache"), second.Body.String())
+	}
+
+	if got := get("fr").Body.String(); got != "lang=fr" {
+		t.Errorf("fr variant = %q, want lang=fr", got)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestPagePurge(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("ok"))
+	})
+	p := NewPage(time.Minute)
+	var purged []string
+	p.OnPurge(func(route string) { purged = append(purged, route) })
+	srv := p.Handler(h)
+
+	for range 2 {
+		srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/blog", nil))
+	}
+	if n := p.Purge("/blog"); n != 1 {
+		t.Errorf("Purge() = %d, want 1", n)
+	}
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/blog", nil))
+
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+	if len(purged) != 1 || purged[0] != "/blog" {
+		t.Errorf("purge hooks = %v, want [/blog]", purged)
+	}
+}
+
+func TestPageSkipsUncacheable(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "1"})
+	})
+	srv := NewPage(time.Minute).Handler(h)
+
+	for _, path := range []string{"/missing", "/missing", "/login", "/login"} {
+		srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil))
+	if calls != 5 {
+		t.Errorf("handler calls = %d, want 5", calls)
+	}
+}
+
+func TestPageSkipsPrivate(t *testing.T) {
+	for _, cc := range []string{"private", "no-store", "max-age=60, Private"} {
+		calls := 0
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Cache-Control", cc)
+			fmt.Fprint(w, "mine")
+		})
+		srv := NewPage(time.Minute).Handler(h)
+		for range 2 {
+			srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/account", nil))
+		}
+		if calls != 2 {
+			t.Errorf("%s: handler calls = %d, want 2", cc, calls)
+		}
+	}
+}
+
+func TestPageSkipsAuthorization(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, "user=%s", r.Header.Get("Authorization"))
+	})
+	srv := NewPage(time.Minute).Handler(h)
+	get := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/home", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get("Bearer a")
+	if got := get("").Body.String(); got != "user=" {
+		t.Errorf("anonymous got %q, want user=", got)
+	}
+	if got := get("Bearer b").Body.String(); got != "user=Bearer b" {
+		t.Errorf("second user got %q", got)
+	}
+	if calls != 3 {
+		t.Errorf("handler calls = %d, want 3", calls)
+	}
+}
+
+func TestPageHandlerVary(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/any" {
+			w.Header().Set("Vary", "*")
+		} else {
+			w.Header().Set("Vary", "X-Tenant")
+		}
+		fmt.Fprintf(w, "tenant=%s", r.Header.Get("X-Tenant"))
+	})
+	srv := NewPage(time.Minute).Handler(h)
+	get := func(path, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Tenant", tenant)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	get("/home", "a")
+	if got := get("/home", "b").Body.String(); got != "tenant=b" {
+		t.Errorf("tenant b got %q", got)
+	}
+	if rec := get("/home", "a"); rec.Header().Get("X-Cache") != "HIT" || rec.Body.String() != "tenant=a" {
+		t.Errorf("tenant a again = %q %q, want HIT tenant=a", rec.Header().Get("X-Cache"), rec.Body.String())
+	}
+	get("/any", "a")
+	get("/any", "a")
+	if calls != 4 {
+		t.Errorf("handler calls = %d, want 4", calls)
+	}
+}
+
+func TestPageHead(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Vary", "Accept-Encoding")
+		if r.Method != http.MethodHead {
+			fmt.Fprint(w, "body")
+		}
+	})
+	srv := NewPage(time.Minute, Locale()).Handler(h)
+	do := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, "/home", nil))
+		return rec
+	}
+
+	do(http.MethodHead)
+	get := do(http.MethodGet)
+	if got := get.Body.String(); got != "body" {
+		t.Errorf("GET after HEAD = %q, want body", got)
+	}
+	if got := get.Header().Values("Vary"); len(got) != 2 || got[0] != "Accept-Encoding" || got[1] != "Accept-Language" {
+		t.Errorf("Vary = %q, want the handler's and the page's", got)
+	}
+	if got := do(http.MethodHead).Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("HEAD after GET X-Cache = %q, want HIT", got)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestStoreMaxEntries(t *testing.T) {
+	s := New()
+	s.SetMaxEntries(16)
+	for i := range 100 {
+		s.SetTagged(fmt.Sprint(i), []byte("x"), 0, "t")
+	}
+	if n := s.Len(); n > 16 {
+		t.Errorf("Len() = %d, want at most 16", n)
+	}
+	if _, ok := s.Get("99"); !ok {
+		t.Error("latest entry was evicted")
+	}
+	s.InvalidateTag("t")
+	if n := s.Len(); n != 0 {
+		t.Errorf("InvalidateTag() left %d entries", n)
+	}
+}
+
+func TestStoreInvalidateTag(t *testing.T) {
+	s := New()
+	s.SetTagged("a", []byte("1"), 0, "x", "y")
+	s.SetTagged("b", []byte("2"), 0, "y")
+	s.Set("c", []byte("3"), 0)
+
+	// Replacing an entry without the tag keeps it out of the invalidation.
+	s.Set("b", []byte("4"), 0)
+
+	if n := s.InvalidateTag("y"); n != 1 {
+		t.Errorf("InvalidateTag() = %d, want 1", n)
+	}
+	if _, ok := s.Get("a"); ok {
+		t.Error("tagged entry survived InvalidateTag()")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := s.Get(key); !ok {
+			t.Errorf("InvalidateTag() removed untagged entry %q", key)
+		}
+	}
+	if n := s.InvalidateTag("x"); n != 0 {
+		t.Errorf("InvalidateTag() of an emptied tag = %d, want 0", n)
+	}
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Fragment returns a node that renders build and caches the output in the
+// Default store under key for ttl. See Store.Fragment.
+//
+// Usage:
+//
+//	cache.Fragment("home:popular", 5*time.Minute, func() node.Node {
+//	    return ProductList(db.Popular())
+//	})
+//
+//	cache.Fragment("product:42:card", time.Hour, func() node.Node {
+//	    return ProductCard(db.Product(42))
+//	}, cache.Tags("product:42", "prices"))
+func Fragment(key string, ttl time.Duration, build func() node.Node, opts ...Option) node.Node {
+	return Default.Fragment(key, ttl, build, opts...)
+}
+
+// Option configures a fragment.
+type Option func(*fragment)
+
+// Tags associates the fragment's cached output with tags, so that
+// InvalidateTag expires it when the data it was built from changes rather
+// than waiting for its TTL.
+func Tags(tags ...string) Option {
+	return func(f *fragment) {
+		f.tags = append(f.tags, tags...)
+	}
+}
+
+// Fragment returns a node that serves the bytes cached under key, rendering
+// build and storing its output for ttl when there is no live entry. A ttl of
+// zero or less keeps the output until the key is deleted.
+//
+// When an entry is missing or expired, only one goroutine runs build; others
+// rendering the same key at the same time wait for and share its output, so
+// an expensive fragment is not rebuilt by every request that arrives as it
+// expires.
+//
+// Options such as Tags configure how the output is stored.
+//
+// The fragment is rendered on its own, outside the render of the page that
+// contains it, so its output must not depend on the request: it cannot read
+// render context values or declare assets.
+func (s *Store) Fragment(key string, ttl time.Duration, build func() node.Node, opts ...Option) node.Node {
+	f := &fragment{store: s, key: key, ttl: ttl, build: build}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// flight is a build in progress for a key.
+type flight struct {
+	wg   sync.WaitGroup
+	body []byte
+	ok   bool
+}
+
+// load returns the live bytes for key, or runs fn once across concurrent
+// callers and stores its result for ttl with tags. The result is not stored
+// if the store was invalidated while fn ran. If fn panics, the panic
+// propagates to its caller and waiting callers run fn themselves.
+func (s *Store) load(key string, ttl time.Duration, tags []string, fn func() []byte) []byte {
+	if b, ok := s.Get(key); ok {
+		return b
+	}
+
+	s.fmu.Lock()
+	if f, ok := s.flights[key]; ok {
+		s.fmu.Unlock()
+		f.wg.Wait()
+		if f.ok {
+			return f.body
+		}
+		return fn()
+	}
+	// Another goroutine may have stored the key between Get and taking the lock.
+	if b, ok := s.Get(key); ok {
+		s.fmu.Unlock()
+		return b
+	}
+	f := &flight{}
+	f.wg.Add(1)
+	s.flights[key] = f
+	s.fmu.Unlock()
+
+	defer func() {
+		s.fmu.Lock()
+		delete(s.flights, key)
+		s.fmu.Unlock()
+		f.wg.Done()
+	}()
+	s.mu.RLock()
+	gen := s.gen
+	s.mu.RUnlock()
+
+	f.body = fn()
+	e := &entry{body: f.body, tags: tags}
+	if ttl > 0 {
+		e.expires = s.now().Add(ttl)
+	}
+	s.mu.Lock()
+	if s.gen == gen {
+		s.put(key, e)
+	}
+	s.mu.Unlock()
+	f.ok = true
+	return f.body
+}
+
+type fragment struct {
+	store *Store
+	key   string
+	ttl   time.Duration
+	tags  []string
+	build func() node.Node
+}
+
+func (f *fragment) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+// RenderBuilder writes the cached output, building it first if needed.
+func (f *fragment) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(f.store.load(f.key, f.ttl, f.tags, func() []byte {
+		n := f.build()
+		if n == nil {
+			return nil
+		}
+		return n.Render()
+	}))
+}
+
+// Nodes returns an empty slice as the fragment is only built on a cache miss.
+func (f *fragment) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output depends on the cache state at render time.
+func (f *fragment) Dynamic() bool {
+	return true
+}
+
+func (f *fragment) SetAttribute(_ string, _ string) {
+	// fragment does not support attributes
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestFragment(t *testing.T) {
+	s := New()
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	builds := 0
+	f := s.Fragment("popular", time.Minute, func() node.Node {
+		builds++
+		return text.Textf("build %d", builds)
+	})
+	page := div.New(f)
+
+	tests := []struct {
+		advance time.Duration
+		want    string
+	}{
+		{0, "<div>build 1</div>"},
+		{30 * time.Second, "<div>build 1</div>"},
+		{30 * time.Second, "<div>build 2</div>"},
+	}
+	for i, tt := range tests {
+		now = now.Add(tt.advance)
+		if got := string(page.Render()); got != tt.want {
+			t.Errorf("render %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+
+	s.Delete("popular")
+	if got := string(f.Render()); got != "build 3" {
+		t.Errorf("after Delete: got %q, want %q", got, "build 3")
+	}
+}
+
+func TestFragmentSingleflight(t *testing.T) {
+	s := New()
+	var builds atomic.Int32
+	release := make(chan struct{})
+	f := s.Fragment("slow", 0, func() node.Node {
+		builds.Add(1)
+		<-release
+		return text.Static("done")
+	})
+
+	const n = 20
+	var wg sync.WaitGroup
+	out := make([]string, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i] = string(f.Render())
+		}()
+	}
+	// Let the renders queue up behind the first build.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := builds.Load(); got != 1 {
+		t.Errorf("build ran %d times, want 1", got)
+	}
+	for i, got := range out {
+		if got != "done" {
+			t.Errorf("render %d: got %q, want %q", i, got, "done")
+		}
+	}
+}
+
+func TestFragmentPanic(t *testing.T) {
+	s := New()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic in build was not propagated")
+			}
+		}()
+		s.Fragment("bad", 0, func() node.Node { panic("boom") }).Render()
+	}()
+
+	if got := string(s.Fragment("bad", 0, func() node.Node { return text.Static("ok") }).Render()); got != "ok" {
+		t.Errorf("after panic: got %q, want %q", got, "ok")
+	}
+	if got := string(s.Fragment("nil", 0, func() node.Node { return nil }).Render()); got != "" {
+		t.Errorf("nil build: got %q", got)
+	}
+}
+
+func TestFragmentTags(t *testing.T) {
+	s := New()
+	builds := map[string]int{}
+	card := func(id string, tags ...string) node.Node {
+		return s.Fragment("card:"+id, time.Hour, func() node.Node {
+			builds[id]++
+			return text.Static(id)
+		}, Tags(tags...))
+	}
+	render := func() {
+		div.New(card("1", "product:1", "prices"), card("2", "product:2", "prices"), card("3")).Render()
+	}
+
+	render()
+	if n := s.InvalidateTag("product:1"); n != 1 {
+		t.Errorf("InvalidateTag(product:1) = %d, want 1", n)
+	}
+	render()
+	if n := s.InvalidateTag("prices"); n != 2 {
+		t.Errorf("InvalidateTag(prices) = %d, want 2", n)
+	}
+	render()
+
+	want := map[string]int{"1": 3, "2": 2, "3": 1}
+	for id, n := range want {
+		if builds[id] != n {
+			t.Errorf("card %s built %d times, want %d", id, builds[id], n)
+		}
+	}
+	if n := s.InvalidateTag("unknown"); n != 0 {
+		t.Errorf("InvalidateTag(unknown) = %d, want 0", n)
+	}
+}
+
+func TestFragmentInvalidatedWhileBuilding(t *testing.T) {
+	s := New()
+	builds := 0
+	f := s.Fragment("stale", 0, func() node.Node {
+		builds++
+		if builds == 1 {
+			s.InvalidateTag("data")
+		}
+		return text.Textf("%d", builds)
+	}, Tags("data"))
+
+	if got := string(f.Render()); got != "1" {
+		t.Errorf("first render: got %q, want %q", got, "1")
+	}
+	if got := string(f.Render()); got != "2" {
+		t.Errorf("output built across an invalidation was cached: got %q", got)
+	}
+	if got := string(f.Render()); got != "2" {
+		t.Errorf("third render: got %q, want %q", got, "2")
+	}
+}
@@ -0,0 +1,346 @@
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dimension is a request property that produces a distinct cached variant
+// of a page, such as the locale, theme, or authentication segment.
+type Dimension struct {
+	// Header is the request header named in the Vary response header.
+	// Dimensions derived from cookies should use "Cookie".
+	Header string
+
+	// Value extracts the variant value from the request.
+	Value func(r *http.Request) string
+}
+
+// Header creates a dimension keyed on the value of a request header.
+//
+// Usage:
+//
+//	cache.Header("Accept-Encoding")
+func Header(name string) Dimension {
+	return Dimension{
+		Header: name,
+		Value: func(r *http.Request) string {
+			return r.Header.Get(name)
+		},
+	}
+}
+
+// Cookie creates a dimension keyed on the value of a named cookie.
+// Missing cookies produce an empty value.
+//
+// Usage:
+//
+//	cache.Cookie("theme")
+func Cookie(name string) Dimension {
+	return Dimension{
+		Header: "Cookie",
+		Value: func(r *http.Request) string {
+			c, err := r.Cookie(name)
+			if err != nil {
+				return ""
+			}
+			return c.Value
+		},
+	}
+}
+
+// Locale creates a dimension keyed on the Accept-Language header.
+func Locale() Dimension {
+	return Header("Accept-Language")
+}
+
+// Segment creates a dimension from a custom function, typically used to split
+// cached pages by authentication segment (anonymous, member, staff) rather than
+// by individual user. header names the request header the segment is derived from.
+//
+// Usage:
+//
+//	cache.Segment("Cookie", func(r *http.Request) string {
+//	    if session.IsStaff(r) {
+//	        return "staff"
+//	    }
+//	    return "public"
+//	})
+func Segment(header string, fn func(r *http.Request) string) Dimension {
+	return Dimension{
+		Header: header,
+		Value:  fn,
+	}
+}
+
+// Page caches complete HTTP responses keyed on the request route plus the
+// declared Vary dimensions. It complements fragment caching by skipping the
+// handler entirely on a hit.
+//
+// Only GET requests that produce a 200 response without a Set-Cookie header
+// are cached. HEAD requests are answered from a cached GET response when
+// there is one, and otherwise passed to the handler without being stored.
+// Responses marked Cache-Control private or no-store, or that Vary on "*",
+// are never stored, and requests carrying an Authorization header bypass the
+// cache entirely. When the handler's Vary header names request headers
+// beyond the dimensions, their values become part of the key.
+//
+// The query string is part of the key, so each distinct query is a separate
+// entry; the Store's entry limit (see Store.SetMaxEntries) bounds how many
+// are kept.
+//
+// Usage:
+//
+//	pages := cache.NewPage(5*time.Minute, cache.Locale(), cache.Cookie("theme"))
+//	mux.Handle("/", pages.Handler(site))
+//
+//	// After publishing new content
+//	pages.Purge("/blog")
+type Page struct {
+	store *Store
+	ttl   time.Duration
+	dims  []Dimension
+	vary  string
+
+	mu    sync.RWMutex
+	hooks []func(route string)
+}
+
+// NewPage creates a page cache using a dedicated Store.
+func NewPage(ttl time.Duration, dims ...Dimension) *Page {
+	return NewPageStore(New(), ttl, dims...)
+}
+
+// NewPageStore creates a page cache backed by the given Store.
+func NewPageStore(store *Store, ttl time.Duration, dims ...Dimension) *Page {
+	p := &Page{
+		store: store,
+		ttl:   ttl,
+		dims:  dims,
+	}
+	seen := map[string]bool{}
+	var vary []string
+	for _, d := range dims {
+		h := http.CanonicalHeaderKey(d.Header)
+		if h == "" || seen[h] {
+			continue
+		}
+		seen[h] = true
+		vary = append(vary, h)
+	}
+	p.vary = strings.Join(vary, ", ")
+	return p
+}
+
+// Handler wraps next with full-page caching.
+func (p *Page) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := p.key(r)
+		if e, ok := p.lookup(key, r); ok {
+			p.write(w, r, e, "HIT")
+			return
+		}
+
+		if r.Method == http.MethodHead {
+			p.addVary(w.Header())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		e := &entry{body: rec.body.Bytes(), header: rec.header, status: rec.status}
+		if extra, ok := p.cacheable(rec); ok {
+			if len(extra) > 0 {
+				p.store.store(key, &entry{vary: extra}, p.ttl)
+				key = variant(key, extra, r)
+			}
+			p.store.store(key, e, p.ttl)
+		}
+		p.write(w, r, e, "MISS")
+	})
+}
+
+// lookup returns the entry for key, following an entry that stands in for
+// a page varying on further request headers to the variant for r.
+func (p *Page) lookup(key string, r *http.Request) (*entry, bool) {
+	e, ok := p.store.lookup(key)
+	if !ok || e.vary == nil {
+		return e, ok
+	}
+	return p.store.lookup(variant(key, e.vary, r))
+}
+
+// cacheable reports whether rec may be stored, and returns the request
+// headers its Vary header names beyond the page's dimensions.
+func (p *Page) cacheable(rec *recorder) ([]string, bool) {
+	if rec.status != http.StatusOK || rec.header.Get("Set-Cookie") != "" {
+		return nil, false
+	}
+	for _, v := range rec.header.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if strings.EqualFold(name, "private") || strings.EqualFold(name, "no-store") {
+				return nil, false
+			}
+		}
+	}
+	dims := map[string]bool{}
+	for _, name := range strings.Split(p.vary, ", ") {
+		dims[name] = true
+	}
+	var extra []string
+	for _, v := range rec.header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			switch {
+			case name == "*":
+				return nil, false
+			case name == "" || dims[name] || slices.Contains(extra, name):
+			default:
+				extra = append(extra, name)
+			}
+		}
+	}
+	return extra, true
+}
+
+// variant extends key with the values r has for the named headers.
+func variant(key string, headers []string, r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(key)
+	b.WriteByte(1)
+	for _, h := range headers {
+		b.WriteByte(0)
+		b.WriteString(strings.Join(r.Header.Values(h), ","))
+	}
+	return b.String()
+}
+
+// Purge removes every cached variant of route and runs the registered purge hooks.
+// It returns the number of entries removed.
+func (p *Page) Purge(route string) int {
+	n := p.store.DeletePrefix(route + "\x00")
+	p.notify(route)
+	return n
+}
+
+// PurgeAll removes every cached page and runs the registered purge hooks with
+// an empty route.
+func (p *Page) PurgeAll() {
+	p.store.Clear()
+	p.notify("")
+}
+
+// OnPurge registers fn to be called after each purge, for example to forward
+// the purge to a CDN or to peer instances. PurgeAll calls fn with an empty route.
+func (p *Page) OnPurge(fn func(route string)) {
+	p.mu.Lock()
+	p.hooks = append(p.hooks, fn)
+	p.mu.Unlock()
+}
+
+// notify runs the purge hooks for route.
+func (p *Page) notify(route string) {
+	p.mu.RLock()
+	hooks := p.hooks
+	p.mu.RUnlock()
+	for _, fn := range hooks {
+		fn(route)
+	}
+}
+
+// key builds the cache key from the route, query and dimension values.
+// The route comes first so that Purge can match on prefix.
+func (p *Page) key(r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(r.URL.Path)
+	b.WriteByte(0)
+	b.WriteString(r.URL.RawQuery)
+	for _, d := range p.dims {
+		b.WriteByte(0)
+		if d.Value != nil {
+			b.WriteString(d.Value(r))
+		}
+	}
+	return b.String()
+}
+
+// write sends a cached or freshly rendered entry to the client.
+func (p *Page) write(w http.ResponseWriter, r *http.Request, e *entry, status string) {
+	h := w.Header()
+	for k, v := range e.header {
+		h[k] = slices.Clone(v)
+	}
+	p.addVary(h)
+	h.Set("X-Cache", status)
+	w.WriteHeader(e.status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(e.body)
+	}
+}
+
+// addVary adds the dimensions' headers to the Vary values already in h.
+func (p *Page) addVary(h http.Header) {
+	if p.vary == "" {
+		return
+	}
+	have := map[string]bool{}
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			have[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	if have["*"] {
+		return
+	}
+	var add []string
+	for _, name := range strings.Split(p.vary, ", ") {
+		if !have[name] {
+			add = append(add, name)
+		}
+	}
+	if len(add) > 0 {
+		h.Add("Vary", strings.Join(add, ", "))
+	}
+}
+
+// recorder captures a handler's response so it can be cached.
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+	wrote  bool
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wrote {
+		return
+	}
+	r.wrote = true
+	r.status = status
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.body.Write(b)
+}
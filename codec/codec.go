@@ -0,0 +1,438 @@
+// Package codec encodes node trees as JSON and decodes them back into nodes,
+// so a tree can be cached in Redis, sent between services or stored as CMS
+// content and rendered later.
+//
+// Elements - any node with RenderOpen and RenderClose, as every html5
+// element has - are encoded structurally as a tag, attributes and children,
+// and decode to a generic element that renders the same markup. Named
+// components keep their name. Nodes of a type registered with Register
+// encode themselves through MarshalNode and are rebuilt by their decoder, so
+// a component can round-trip its props rather than its output. Any other
+// node, including text and dynamic nodes such as node.Func, is encoded as a
+// snapshot of its rendered output.
+//
+// Usage:
+//
+//	codec.Register("card", Card{}, func(data []byte) (node.Node, error) {
+//	    var c Card
+//	    err := json.Unmarshal(data, &c)
+//	    return c, err
+//	})
+//
+//	b, err := codec.Marshal(page)
+//	// store b
+//	n, err := codec.Unmarshal(b)
+//	n.Render(w)
+//
+// Decoded data must be trusted. Tag and attribute names are checked, but
+// snapshots are written exactly as stored, so data that an attacker could
+// have written can inject any markup into the page.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Marshaler is implemented by node types registered with Register.
+type Marshaler interface {
+	node.Node
+	// MarshalNode returns the data the type's decoder rebuilds it from.
+	MarshalNode() ([]byte, error)
+}
+
+// Decoder rebuilds a node from the data returned by its MarshalNode.
+type Decoder func(data []byte) (node.Node, error)
+
+// UnknownKindError is returned when decoding a node of a kind that was never
+// registered.
+type UnknownKindError struct {
+	Kind string
+}
+
+func (e *UnknownKindError) Error() string {
+	return fmt.Sprintf("codec: unknown node kind %q", e.Kind)
+}
+
+// NameError is returned when decoding an element whose tag, attribute name
+// or doctype is not valid, as it would otherwise be written verbatim.
+type NameError struct {
+	// What is "tag", "attribute" or "doctype".
+	What string
+	Name string
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("codec: invalid %s %q", e.What, e.Name)
+}
+
+// validTag reports whether s is a tag name: an ASCII letter followed by
+// characters allowed in an attribute name.
+func validTag(s string) bool {
+	return s != "" && ('a' <= s[0] && s[0] <= 'z' || 'A' <= s[0] && s[0] <= 'Z') && validAttr(s)
+}
+
+// validAttr reports whether s is an attribute name under the HTML syntax,
+// which allows names such as hx-on:click, xlink:href and @click but not
+// whitespace, quotes, '>', '/', '=' or control characters.
+func validAttr(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c <= ' ', c == 0x7f, 0x80 <= c && c <= 0x9f:
+			return false
+		case c == '"', c == '\'', c == '>', c == '/', c == '=':
+			return false
+		}
+	}
+	return true
+}
+
+// validDoctype reports whether s is a single markup declaration.
+func validDoctype(s string) bool {
+	return s == "" || strings.HasPrefix(s, "<!") && strings.IndexAny(s[2:], "<>") == len(s)-3
+}
+
+// Built-in kinds. Registered kinds may not use these names.
+const (
+	kindElement   = "element"
+	kindComponent = "component"
+	kindHTML      = "html"
+)
+
+// Registry maps node types to kinds. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	decoders map[string]Decoder
+	kinds    map[reflect.Type]string
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{decoders: map[string]Decoder{}, kinds: map[reflect.Type]string{}}
+}
+
+// Default is the registry used by the package-level functions.
+var Default = NewRegistry()
+
+// Register encodes nodes with the concrete type of sample under kind, and
+// decodes that kind with decode. It panics if kind is a built-in kind or is
+// already registered to another type.
+func (r *Registry) Register(kind string, sample Marshaler, decode Decoder) {
+	switch kind {
+	case kindElement, kindComponent, kindHTML:
+		panic("codec: " + kind + " is a built-in kind")
+	}
+	t := reflect.TypeOf(sample)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for other, k := range r.kinds {
+		if k == kind && other != t {
+			panic(fmt.Sprintf("codec: kind %q registered to both %v and %v", kind, other, t))
+		}
+	}
+	r.kinds[t] = kind
+	r.decoders[kind] = decode
+}
+
+// Marshal encodes n as JSON. A nil node encodes as null.
+func (r *Registry) Marshal(n node.Node) ([]byte, error) {
+	w, err := r.encode(n)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(w)
+}
+
+// Unmarshal decodes JSON written by Marshal. Null decodes as a nil node.
+func (r *Registry) Unmarshal(data []byte) (node.Node, error) {
+	var w *wire
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	return r.decode(w)
+}
+
+// Register registers a node type with the Default registry.
+func Register(kind string, sample Marshaler, decode Decoder) {
+	Default.Register(kind, sample, decode)
+}
+
+// Marshal encodes n with the Default registry.
+func Marshal(n node.Node) ([]byte, error) {
+	return Default.Marshal(n)
+}
+
+// Unmarshal decodes a node with the Default registry.
+func Unmarshal(data []byte) (node.Node, error) {
+	return Default.Unmarshal(data)
+}
+
+// wire is the JSON form of a node.
+type wire struct {
+	Kind string `json:"kind"`
+	// Doctype is a doctype written before an element's start tag.
+	Doctype  string      `json:"doctype,omitempty"`
+	Tag      string      `json:"tag,omitempty"`
+	Attrs    []attribute `json:"attrs,omitempty"`
+	Void     bool        `json:"void,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	HTML     string      `json:"html,omitempty"`
+	Data     []byte      `json:"data,omitempty"`
+	Children []*wire     `json:"children,omitempty"`
+}
+
+// attribute is an element attribute with its value unescaped.
+type attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// opener is implemented by elements.
+type opener interface {
+	RenderOpen(buf *bytes.Buffer)
+	RenderClose(buf *bytes.Buffer)
+}
+
+func (r *Registry) encode(n node.Node) (*wire, error) {
+	if n == nil {
+		return nil, nil
+	}
+	r.mu.RLock()
+	kind, ok := r.kinds[reflect.TypeOf(n)]
+	r.mu.RUnlock()
+	if ok {
+		data, err := n.(Marshaler).MarshalNode()
+		if err != nil {
+			return nil, fmt.Errorf("codec: encoding %s: %w", kind, err)
+		}
+		return &wire{Kind: kind, Data: data}, nil
+	}
+
+	switch c := n.(type) {
+	case *node.NamedComponent:
+		child, err := r.encode(c.Unwrap())
+		if err != nil {
+			return nil, err
+		}
+		w := &wire{Kind: kindComponent, Name: c.Name()}
+		if child != nil {
+			w.Children = []*wire{child}
+		}
+		return w, nil
+	case opener:
+		return r.element(n)
+	}
+	return &wire{Kind: kindHTML, HTML: string(n.Render())}, nil
+}
+
+// element encodes an element from its start tag and children. Elements whose
+// start tag cannot be read back are encoded as a snapshot.
+func (r *Registry) element(n node.Node) (*wire, error) {
+	e := n.(opener)
+	var open, end bytes.Buffer
+	e.RenderOpen(&open)
+	e.RenderClose(&end)
+
+	w := &wire{Kind: kindElement}
+	var start *markup.Token
+	for _, tok := range markup.Tokenize(open.String()) {
+		switch {
+		case tok.Type == markup.DoctypeToken && start == nil:
+			w.Doctype = tok.Raw
+		case (tok.Type == markup.StartTagToken || tok.Type == markup.SelfClosingTagToken) && start == nil:
+			start = &tok
+		default:
+			start = nil
+		}
+	}
+	if start == nil {
+		return &wire{Kind: kindHTML, HTML: string(n.Render())}, nil
+	}
+	// Data is lowercased; keep the source case for SVG's camelCase tags.
+	w.Tag = start.Raw[1 : 1+len(start.Data)]
+	for _, a := range start.Attrs {
+		w.Attrs = append(w.Attrs, attribute{Key: a.Key, Value: a.Val})
+	}
+	w.Void = end.Len() == 0
+	for _, c := range n.Nodes() {
+		cw, err := r.encode(c)
+		if err != nil {
+			return nil, err
+		}
+		if cw != nil {
+			w.Children = append(w.Children, cw)
+		}
+	}
+	return w, nil
+}
+
+func (r *Registry) decode(w *wire) (node.Node, error) {
+	if w == nil {
+		return nil, nil
+	}
+	children := make([]node.Node, 0, len(w.Children))
+	for _, cw := range w.Children {
+		c, err := r.decode(cw)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+	}
+
+	switch w.Kind {
+	case kindElement:
+		if !validTag(w.Tag) {
+			return nil, &NameError{What: "tag", Name: w.Tag}
+		}
+		if !validDoctype(w.Doctype) {
+			return nil, &NameError{What: "doctype", Name: w.Doctype}
+		}
+		for _, a := range w.Attrs {
+			if !validAttr(a.Key) {
+				return nil, &NameError{What: "attribute", Name: a.Key}
+			}
+		}
+		// Decoded trees repeat the same names on every element, so share
+		// one copy of each rather than one per element.
+		e := &Element{Tag: intern.Tag(w.Tag), Doctype: w.Doctype, Void: w.Void, Children: children}
+		for _, a := range w.Attrs {
+			v := a.Value
+			if a.Key == "class" {
+				v = intern.String(v)
+			}
+			e.Attrs = append(e.Attrs, node.Attribute{Key: intern.Attr(a.Key), Value: v})
+		}
+		return e, nil
+	case kindComponent:
+		var child node.Node
+		if len(children) > 0 {
+			child = children[0]
+		}
+		return node.Component(w.Name, child), nil
+	case kindHTML:
+		return snapshot(w.HTML), nil
+	}
+
+	r.mu.RLock()
+	decode, ok := r.decoders[w.Kind]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &UnknownKindError{Kind: w.Kind}
+	}
+	n, err := decode(w.Data)
+	if err != nil {
+		return nil, fmt.Errorf("codec: decoding %s: %w", w.Kind, err)
+	}
+	return n, nil
+}
+
+// Element is a decoded element. It renders its tag with the attributes
+// escaped, followed by its children and end tag.
+type Element struct {
+	Tag string
+	// Doctype, if set, is written before the start tag.
+	Doctype string
+	// Attrs holds the attributes with their values unescaped.
+	Attrs    []node.Attribute
+	Void     bool
+	Children []node.Node
+}
+
+func (e *Element) Render(w ...io.Writer) []byte {
+	return node.Render(e, w...)
+}
+
+func (e *Element) RenderBuilder(buf *bytes.Buffer) {
+	e.RenderOpen(buf)
+	for _, c := range e.Children {
+		if c != nil {
+			c.RenderBuilder(buf)
+		}
+	}
+	e.RenderClose(buf)
+}
+
+// RenderOpen writes the doctype, if any, and the start tag.
+func (e *Element) RenderOpen(buf *bytes.Buffer) {
+	buf.WriteString(e.Doctype)
+	buf.WriteString("<" + e.Tag)
+	for _, a := range e.Attrs {
+		buf.WriteString(" " + a.Key + `="`)
+		escape.WriteString(buf, a.Value)
+		buf.WriteByte('"')
+	}
+	if e.Void {
+		buf.WriteString(" />")
+		return
+	}
+	buf.WriteByte('>')
+}
+
+// RenderClose writes the end tag, or nothing for a void element.
+func (e *Element) RenderClose(buf *bytes.Buffer) {
+	if !e.Void {
+		buf.WriteString("</" + e.Tag + ">")
+	}
+}
+
+// Clone returns a copy of the element with its attributes copied and its
+// children cloned.
+func (e *Element) Clone() node.Node {
+	cp := *e
+	cp.Attrs = slices.Clone(e.Attrs)
+	cp.Children = make([]node.Node, len(e.Children))
+	for i, c := range e.Children {
+		cp.Children[i] = node.Clone(c)
+	}
+	return &cp
+}
+
+func (e *Element) Nodes() []node.Node {
+	return e.Children
+}
+
+// SetAttribute sets the attribute key, replacing any existing value. As
+// with other nodes the value is escaped when written.
+func (e *Element) SetAttribute(key string, value string) {
+	for i := range e.Attrs {
+		if e.Attrs[i].Key == key {
+			e.Attrs[i].Value = value
+			return
+		}
+	}
+	e.Attrs = append(e.Attrs, node.Attribute{Key: key, Value: value})
+}
+
+// snapshot is the recorded output of a node.
+type snapshot string
+
+func (s snapshot) Render(w ...io.Writer) []byte {
+	return node.Render(s, w...)
+}
+
+func (s snapshot) RenderBuilder(buf *bytes.Buffer) {
+	buf.WriteString(string(s))
+}
+
+func (s snapshot) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+func (s snapshot) SetAttribute(_ string, _ string) {
+	// snapshot does not support attributes
+}
@@ -0,0 +1,174 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/br"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// card is a registered component encoding its props.
+type card struct {
+	Title string `json:"title"`
+}
+
+func (c card) Render(w ...io.Writer) []byte    { return node.Render(c, w...) }
+func (c card) RenderBuilder(buf *bytes.Buffer) { buf.WriteString("<article>" + c.Title + "</article>") }
+func (c card) Nodes() []node.Node              { return []node.Node{} }
+func (c card) SetAttribute(_ string, _ string) {}
+func (c card) MarshalNode() ([]byte, error)    { return json.Marshal(c) }
+
+func decodeCard(data []byte) (node.Node, error) {
+	var c card
+	err := json.Unmarshal(data, &c)
+	return c, err
+}
+
+func registry() *Registry {
+	r := NewRegistry()
+	r.Register("card", card{}, decodeCard)
+	return r
+}
+
+// attrs sets each of names on n.
+func attrs(n node.Node, names ...string) node.Node {
+	for _, name := range names {
+		n.SetAttribute(name, "v")
+	}
+	return n
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+	}{
+		{"element", div.New(p.Text("Fish & chips")).Class("menu").ID("m")},
+		{"void", div.New(img.New().Src("/a.png").Alt("A"), br.New())},
+		{"doctype", html.New(body.New(p.Static("hi")))},
+		{"raw text", div.New(text.RawText("<b>bold</b>"))},
+		{"component", node.Component("Greeting", p.Text("Hello"))},
+		{"registered", div.New(card{Title: "News"})},
+		{"func", node.Func(func() node.Node { return p.Text("computed") })},
+		{"attribute names", attrs(div.New(), "hx-on:click", "xlink:href", "xmlns:xlink", "@click", "data-foo_bar", ":class")},
+	}
+	r := registry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := r.Marshal(tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := r.Unmarshal(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(n.Render()), string(tt.n.Render()); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestStructure(t *testing.T) {
+	r := registry()
+	b, err := r.Marshal(node.Component("Page", div.New(card{Title: "A"}, p.Text("x")).Class("c")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := r.Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := n.(*node.NamedComponent)
+	if !ok || c.Name() != "Page" {
+		t.Fatalf("got %T, want the named component", n)
+	}
+	e, ok := c.Unwrap().(*Element)
+	if !ok || e.Tag != "div" || len(e.Attrs) != 1 || e.Attrs[0] != (node.Attribute{Key: "class", Value: "c"}) {
+		t.Fatalf("got %#v, want the div element", c.Unwrap())
+	}
+	if got, ok := e.Children[0].(card); !ok || got.Title != "A" {
+		t.Errorf("got %#v, want the decoded card", e.Children[0])
+	}
+}
+
+func TestNil(t *testing.T) {
+	b, err := Marshal(nil)
+	if err != nil || string(b) != "null" {
+		t.Fatalf("got %q, %v, want null", b, err)
+	}
+	n, err := Unmarshal(b)
+	if err != nil || n != nil {
+		t.Errorf("got %v, %v, want a nil node", n, err)
+	}
+}
+
+func TestUnknownKind(t *testing.T) {
+	b, err := registry().Marshal(card{Title: "A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewRegistry().Unmarshal(b)
+	var unknown *UnknownKindError
+	if !errors.As(err, &unknown) || unknown.Kind != "card" {
+		t.Errorf("got %v, want an UnknownKindError for card", err)
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	for _, data := range []string{
+		`{"kind":"element","tag":"script><script"}`,
+		`{"kind":"element","tag":"p","attrs":[{"key":"onclick=x","value":""}]}`,
+		`{"kind":"element","tag":"p","attrs":[{"key":"a b","value":""}]}`,
+		`{"kind":"element","tag":"p","attrs":[{"key":"a/b","value":""}]}`,
+		`{"kind":"element","tag":"p","attrs":[{"key":"a'b","value":""}]}`,
+		`{"kind":"element","tag":"p","attrs":[{"key":"a\u0000","value":""}]}`,
+		`{"kind":"element","tag":"1p"}`,
+		`{"kind":"element","doctype":"<!doctype html><script>","tag":"html"}`,
+	} {
+		var invalid *NameError
+		if _, err := Unmarshal([]byte(data)); !errors.As(err, &invalid) {
+			t.Errorf("%s: got %v, want a NameError", data, err)
+		}
+	}
+	n, err := Unmarshal([]byte(`{"kind":"element","doctype":"<!DOCTYPE html>","tag":"html","attrs":[{"key":"data-x","value":"1"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(n.Render()), `<!DOCTYPE html><html data-x="1"></html>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRegisterConflict(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering a built-in kind")
+		}
+	}()
+	NewRegistry().Register("element", card{}, decodeCard)
+}
+
+func TestElementSetAttribute(t *testing.T) {
+	e := &Element{Tag: "a", Attrs: []node.Attribute{{Key: "href", Value: "/"}}}
+	e.SetAttribute("href", "/?a=1&b=2")
+	e.SetAttribute("title", "Home")
+	if got, want := string(e.Render()), `<a href="/?a=1&amp;b=2" title="Home"></a>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !strings.Contains(e.Attrs[0].Value, "&b") {
+		t.Errorf("got %q, want the value as set", e.Attrs[0].Value)
+	}
+}
@@ -0,0 +1,87 @@
+// Package echo adapts fluent to the Echo web framework
+// (github.com/labstack/echo), rendering nodes as Echo responses.
+//
+// The package does not import Echo. Its types are generic over the context
+// type, and instantiated with echo.Context they satisfy Echo's interfaces,
+// so fluent carries no dependency on any Echo version.
+//
+// Usage:
+//
+//	import (
+//	    "github.com/labstack/echo/v4"
+//	    fluentecho "github.com/jpl-au/fluent/compat/echo"
+//	)
+//
+//	e := echo.New()
+//	e.Renderer = fluentecho.Renderer[echo.Context]{}
+//
+//	e.GET("/", func(c echo.Context) error {
+//	    return c.Render(http.StatusOK, "", HomePage()) // through the Renderer
+//	})
+//	e.GET("/about", func(c echo.Context) error {
+//	    return fluentecho.HTML(c, http.StatusOK, AboutPage()) // directly
+//	})
+package echo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Context is the part of echo.Context the renderer uses.
+type Context interface {
+	Request() *http.Request
+}
+
+// Page builds the node for a named page from the data passed to Render.
+type Page func(data any) node.Node
+
+// Renderer renders nodes for Echo's Context.Render. Renderer[echo.Context]
+// implements echo.Renderer.
+//
+// Data that is a node.Node is rendered as it is, and the name is ignored.
+// Otherwise the name selects a page from Pages, which is built from the
+// data. Nodes render through fluent.RenderContext with the request's
+// context, so middleware, render config and deadlines apply. Echo writes
+// the output with its own status and HTML content type.
+type Renderer[C Context] struct {
+	// Pages maps template names to page constructors, for handlers that
+	// pass view data rather than nodes to Context.Render.
+	Pages map[string]Page
+}
+
+// Render implements echo.Renderer.
+func (r Renderer[C]) Render(w io.Writer, name string, data any, c C) error {
+	n, ok := data.(node.Node)
+	if !ok {
+		page, found := r.Pages[name]
+		if !found {
+			return fmt.Errorf("echo: no page named %q for data of type %T", name, data)
+		}
+		n = page(data)
+	}
+	return fluent.RenderContext(c.Request().Context(), n, w)
+}
+
+// HTMLContext is the part of echo.Context HTML uses.
+type HTMLContext interface {
+	Context
+	HTMLBlob(code int, b []byte) error
+}
+
+// HTML renders n with the request's context into a pooled buffer and
+// writes it as the response with status code and an HTML content type.
+// Nothing is written if the render fails, so the handler can return the
+// error to Echo's error handler.
+func HTML[C HTMLContext](c C, code int, n node.Node) error {
+	buf := fluent.NewBuffer()
+	defer fluent.PutBuffer(buf)
+	if err := fluent.RenderContext(c.Request().Context(), n, buf); err != nil {
+		return err
+	}
+	return c.HTMLBlob(code, buf.Bytes())
+}
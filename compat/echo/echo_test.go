@@ -0,0 +1,91 @@
+package echo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fluentecho "github.com/jpl-au/fluent/compat/echo"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+// renderer is echo.Renderer with echo.Context replaced by the fake.
+type renderer interface {
+	Render(w io.Writer, name string, data any, c *fakeContext) error
+}
+
+// fakeContext implements the methods of echo.Context the package uses.
+type fakeContext struct {
+	req  *http.Request
+	code int
+	body []byte
+}
+
+func (c *fakeContext) Request() *http.Request { return c.req }
+
+func (c *fakeContext) HTMLBlob(code int, b []byte) error {
+	c.code = code
+	c.body = append([]byte(nil), b...)
+	return nil
+}
+
+func newContext(ctx context.Context) *fakeContext {
+	return &fakeContext{req: httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)}
+}
+
+func TestRenderer(t *testing.T) {
+	var r renderer = fluentecho.Renderer[*fakeContext]{
+		Pages: map[string]fluentecho.Page{
+			"greeting": func(data any) node.Node { return p.Textf("Hello, %s", data) },
+		},
+	}
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{"node", "", p.Text("direct"), "<p>direct</p>"},
+		{"page", "greeting", "Ann", "<p>Hello, Ann</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.Render(&buf, tt.tmpl, tt.data, newContext(context.Background())); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := r.Render(io.Discard, "missing", 1, newContext(context.Background())); err == nil {
+		t.Error("rendering an unknown page returned no error")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	c := newContext(context.Background())
+	if err := fluentecho.HTML(c, http.StatusCreated, p.Text("made")); err != nil {
+		t.Fatal(err)
+	}
+	if c.code != http.StatusCreated || string(c.body) != "<p>made</p>" {
+		t.Errorf("got %d %q, want 201 %q", c.code, c.body, "<p>made</p>")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = newContext(ctx)
+	if err := fluentecho.HTML(c, http.StatusOK, p.Text("late")); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if c.body != nil {
+		t.Errorf("wrote %q for a failed render", c.body)
+	}
+}
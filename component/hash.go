@@ -0,0 +1,160 @@
+package component
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"hash"
+	"math"
+	"reflect"
+	"slices"
+)
+
+var (
+	hashableType  = reflect.TypeFor[Hashable]()
+	marshalerType = reflect.TypeFor[encoding.BinaryMarshaler]()
+)
+
+// hasher writes a deterministic encoding of a value to a hash. Every value
+// is prefixed by its kind, so nil and zero values encode differently, and
+// map entries are written in sorted order.
+type hasher struct {
+	h hash.Hash
+	// path holds the pointers being followed, to detect cycles.
+	path map[uintptr]bool
+	buf  []byte
+}
+
+// hashProps writes props to h. It reports false for props holding funcs,
+// channels or cyclic pointers, which have no stable encoding.
+func hashProps(h hash.Hash, props any) bool {
+	w := &hasher{h: h, path: map[uintptr]bool{}}
+	return w.value(reflect.ValueOf(props))
+}
+
+func (w *hasher) uint(u uint64) {
+	w.buf = binary.AppendUvarint(w.buf[:0], u)
+	w.h.Write(w.buf)
+}
+
+func (w *hasher) string(s string) {
+	w.uint(uint64(len(s)))
+	w.h.Write([]byte(s))
+}
+
+func (w *hasher) value(v reflect.Value) bool {
+	if !v.IsValid() {
+		w.uint(0)
+		return true
+	}
+	w.uint(uint64(v.Kind()))
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			w.uint(0)
+			return true
+		}
+		w.uint(1)
+		p := v.Pointer()
+		if w.path[p] {
+			return false
+		}
+		w.path[p] = true
+		defer delete(w.path, p)
+	case reflect.Interface:
+		if v.IsNil() {
+			w.uint(0)
+			return true
+		}
+		w.string(v.Elem().Type().String())
+		return w.value(v.Elem())
+	}
+
+	if v.CanInterface() {
+		switch {
+		case v.Type().Implements(hashableType):
+			w.string(v.Interface().(Hashable).Hash())
+			return true
+		case v.Type().Implements(marshalerType):
+			b, err := v.Interface().(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				return false
+			}
+			w.string(string(b))
+			return true
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			w.uint(1)
+		} else {
+			w.uint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.uint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.uint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		w.uint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		w.uint(math.Float64bits(real(c)))
+		w.uint(math.Float64bits(imag(c)))
+	case reflect.String:
+		w.string(v.String())
+	case reflect.Pointer:
+		return w.value(v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if !w.value(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			w.uint(0)
+			return true
+		}
+		w.uint(uint64(v.Len()) + 1)
+		fallthrough
+	case reflect.Array:
+		for i := range v.Len() {
+			if !w.value(v.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			w.uint(0)
+			return true
+		}
+		w.uint(uint64(v.Len()) + 1)
+		return w.entries(v)
+	default:
+		return false
+	}
+	return true
+}
+
+// entries writes the entries of map v, each hashed on its own so they can
+// be written in sorted order.
+func (w *hasher) entries(v reflect.Value) bool {
+	sums := make([][]byte, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		e := &hasher{h: sha256.New(), path: w.path}
+		if !e.value(iter.Key()) || !e.value(iter.Value()) {
+			return false
+		}
+		sums = append(sums, e.h.Sum(nil))
+	}
+	slices.SortFunc(sums, bytes.Compare)
+	for _, s := range sums {
+		w.h.Write(s)
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+// Package component provides helpers for building reusable components.
+//
+// Memo wraps a component constructor so that calls with unchanged props reuse
+// the previously rendered output instead of building and rendering the tree
+// again. It suits components rendered identically many times over, such as
+// product cards on catalogue pages.
+//
+// Usage:
+//
+//	type CardProps struct {
+//	    Name  string
+//	    Price int
+//	}
+//
+//	var ProductCard = component.Memo(func(c CardProps) node.Node {
+//	    return div.New(h3.Text(c.Name), p.Textf("$%d", c.Price)).Class("card")
+//	})
+//
+//	ul.New(node.Map(products, func(p Product) node.Node {
+//	    return li.New(ProductCard(CardProps{Name: p.Name, Price: p.Price}))
+//	}))
+package component
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Hashable is implemented by props that provide their own memoisation key.
+// Props with equal hashes must render identically.
+type Hashable interface {
+	Hash() string
+}
+
+// MemoSize is the number of distinct props a memoised component keeps the
+// output of. When it is reached the memo is cleared and refilled, bounding
+// memory for components rendered with unbounded distinct props.
+var MemoSize = 4096
+
+// Memo returns a constructor that renders ctor once per distinct props value
+// and reuses the rendered bytes whenever it is called with equal props.
+//
+// Props are compared by their Hash when they implement Hashable, and by
+// value otherwise: every field is compared, pointers are followed, a nil
+// pointer, slice or map differs from a zero one, and maps compare by their
+// entries whatever their order. Values implementing Hashable or
+// encoding.BinaryMarshaler, such as time.Time, are compared by that
+// encoding. Props holding funcs, channels or cyclic pointers are not
+// memoised and render ctor on every use.
+//
+// The memoised output is rendered on its own, outside the render of the page
+// that contains it, so it must depend only on props: it cannot read render
+// context values or declare assets.
+func Memo[P any](ctor func(P) node.Node) func(P) node.Node {
+	m := &memo[P]{ctor: ctor, entries: map[[sha256.Size]byte][]byte{}}
+	return func(props P) node.Node {
+		return &memoised[P]{memo: m, props: props}
+	}
+}
+
+type memo[P any] struct {
+	ctor    func(P) node.Node
+	mu      sync.RWMutex
+	entries map[[sha256.Size]byte][]byte
+}
+
+// key returns the memoisation key for props, or false if it has none.
+func (m *memo[P]) key(props P) ([sha256.Size]byte, bool) {
+	h := sha256.New()
+	if p, ok := any(props).(Hashable); ok {
+		io.WriteString(h, p.Hash())
+	} else if !hashProps(h, props) {
+		return [sha256.Size]byte{}, false
+	}
+	return [sha256.Size]byte(h.Sum(nil)), true
+}
+
+func (m *memo[P]) render(buf *bytes.Buffer, props P) {
+	k, ok := m.key(props)
+	if !ok {
+		if n := m.ctor(props); n != nil {
+			n.RenderBuilder(buf)
+		}
+		return
+	}
+
+	m.mu.RLock()
+	b, ok := m.entries[k]
+	m.mu.RUnlock()
+	if !ok {
+		if n := m.ctor(props); n != nil {
+			b = n.Render()
+		}
+		m.mu.Lock()
+		if len(m.entries) >= MemoSize {
+			clear(m.entries)
+		}
+		m.entries[k] = b
+		m.mu.Unlock()
+	}
+	buf.Write(b)
+}
+
+type memoised[P any] struct {
+	memo  *memo[P]
+	props P
+}
+
+func (n *memoised[P]) Render(w ...io.Writer) []byte {
+	return node.Render(n, w...)
+}
+
+// RenderBuilder writes the memoised output for the props, rendering it first
+// if the props have not been seen.
+func (n *memoised[P]) RenderBuilder(buf *bytes.Buffer) {
+	n.memo.render(buf, n.props)
+}
+
+// Nodes returns an empty slice as the component is only built on a miss.
+func (n *memoised[P]) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output is looked up when it renders.
+func (n *memoised[P]) Dynamic() bool {
+	return true
+}
+
+func (n *memoised[P]) SetAttribute(_ string, _ string) {
+	// memoised does not support attributes
+}
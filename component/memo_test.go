@@ -0,0 +1,155 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+type card struct {
+	Name  string
+	Price int
+	Tags  []string
+}
+
+type keyed struct {
+	id    int
+	label string
+}
+
+func (k keyed) Hash() string {
+	return string(rune('0' + k.id))
+}
+
+func TestMemo(t *testing.T) {
+	builds := 0
+	Card := Memo(func(c card) node.Node {
+		builds++
+		return div.New(text.Textf("%s $%d", c.Name, c.Price))
+	})
+
+	tests := []struct {
+		props  card
+		want   string
+		builds int
+	}{
+		{card{Name: "Mug", Price: 5}, "<div>Mug $5</div>", 1},
+		{card{Name: "Mug", Price: 5}, "<div>Mug $5</div>", 1},
+		{card{Name: "Mug", Price: 6}, "<div>Mug $6</div>", 2},
+		{card{Name: "Mug", Price: 5, Tags: []string{"sale"}}, "<div>Mug $5</div>", 3},
+		{card{Name: "Mug", Price: 5}, "<div>Mug $5</div>", 3},
+	}
+	for i, tt := range tests {
+		if got := string(Card(tt.props).Render()); got != tt.want {
+			t.Errorf("render %d: got %q, want %q", i, got, tt.want)
+		}
+		if builds != tt.builds {
+			t.Errorf("render %d: %d builds, want %d", i, builds, tt.builds)
+		}
+	}
+}
+
+func TestMemoHashable(t *testing.T) {
+	builds := 0
+	Label := Memo(func(k keyed) node.Node {
+		builds++
+		return text.Text(k.label)
+	})
+	// Equal hashes share output even though the labels differ.
+	Label(keyed{id: 1, label: "a"}).Render()
+	if got := string(Label(keyed{id: 1, label: "b"}).Render()); got != "a" || builds != 1 {
+		t.Errorf("got %q after %d builds, want %q after 1", got, builds, "a")
+	}
+}
+
+func TestMemoUnencodable(t *testing.T) {
+	builds := 0
+	F := Memo(func(f func() string) node.Node {
+		builds++
+		return text.Text(f())
+	})
+	for range 2 {
+		if got := string(F(func() string { return "x" }).Render()); got != "x" {
+			t.Errorf("got %q, want %q", got, "x")
+		}
+	}
+	if builds != 2 {
+		t.Errorf("unencodable props memoised: %d builds, want 2", builds)
+	}
+}
+
+func TestMemoSize(t *testing.T) {
+	defer func(n int) { MemoSize = n }(MemoSize)
+	MemoSize = 2
+
+	builds := 0
+	C := Memo(func(n int) node.Node {
+		builds++
+		return text.Textf("%d", n)
+	})
+	for _, n := range []int{1, 2, 3, 1} {
+		C(n).Render()
+	}
+	if builds != 4 {
+		t.Errorf("got %d builds, want 4 after the memo was cleared", builds)
+	}
+}
+
+func TestMemoNilPointer(t *testing.T) {
+	type props struct{ N *int }
+	builds := 0
+	C := Memo(func(p props) node.Node {
+		builds++
+		if p.N == nil {
+			return text.Text("none")
+		}
+		return text.Textf("%d", *p.N)
+	})
+	zero := 0
+	if got := string(C(props{}).Render()); got != "none" {
+		t.Errorf("nil: got %q", got)
+	}
+	if got := string(C(props{N: &zero}).Render()); got != "0" {
+		t.Errorf("&0: got %q, want 0", got)
+	}
+	if builds != 2 {
+		t.Errorf("got %d builds, want 2", builds)
+	}
+}
+
+func TestMemoMap(t *testing.T) {
+	builds := 0
+	C := Memo(func(m map[string]int) node.Node {
+		builds++
+		return text.Textf("%d", len(m))
+	})
+	for range 20 {
+		C(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8}).Render()
+	}
+	if builds != 1 {
+		t.Errorf("got %d builds, want 1", builds)
+	}
+	C(map[string]int{"a": 1}).Render()
+	C(map[string]int(nil)).Render()
+	if builds != 3 {
+		t.Errorf("got %d builds, want 3", builds)
+	}
+}
+
+func TestMemoCycle(t *testing.T) {
+	type list struct{ Next *list }
+	builds := 0
+	C := Memo(func(l *list) node.Node {
+		builds++
+		return text.Text("x")
+	})
+	l := &list{}
+	l.Next = l
+	C(l).Render()
+	C(l).Render()
+	if builds != 2 {
+		t.Errorf("cyclic props memoised: %d builds, want 2", builds)
+	}
+}
@@ -0,0 +1,266 @@
+// Package compress provides response compression with support for shared
+// compression dictionaries.
+//
+// Fragment-heavy applications (htmx, Turbo) send many small responses made
+// mostly of the same markup. A dictionary built from that common markup lets
+// a dictionary-aware encoder reference it instead of resending it, which is
+// far more effective than compressing each fragment on its own.
+//
+// Negotiation follows Compression Dictionary Transport (RFC 9842): the
+// dictionary is served with a Use-As-Dictionary header, the browser advertises
+// it back with Available-Dictionary, and the response is encoded with a
+// dictionary encoder when both sides agree. Otherwise the handler falls back
+// to a standard encoding such as gzip.
+//
+// Only gzip is built in. Dictionary encodings (dcb, dcz) and brotli are
+// supplied as Encoder values so this package stays free of dependencies.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Dictionary is a pre-shared compression dictionary.
+type Dictionary struct {
+	data  []byte
+	hash  string // structured-field byte sequence of the SHA-256, e.g. :base64:
+	match string
+}
+
+// NewDictionary creates a dictionary from raw segments, typically the static
+// boilerplate shared by many responses. match is the URL pattern the browser
+// should use the dictionary for, e.g. "/fragments/*".
+func NewDictionary(match string, segments ...[]byte) *Dictionary {
+	data := bytes.Join(segments, nil)
+	sum := sha256.Sum256(data)
+	return &Dictionary{
+		data:  data,
+		hash:  ":" + base64.StdEncoding.EncodeToString(sum[:]) + ":",
+		match: match,
+	}
+}
+
+// FromNodes creates a dictionary from the rendered output of nodes.
+// Pass the static parts of your layouts and components, not request-specific content.
+//
+// Usage:
+//
+//	dict := compress.FromNodes("/app/*", layout.Shell(), components.Card(placeholder))
+func FromNodes(match string, nodes ...node.Node) *Dictionary {
+	segments := make([][]byte, 0, len(nodes))
+	for _, n := range nodes {
+		if n != nil {
+			segments = append(segments, n.Render())
+		}
+	}
+	return NewDictionary(match, segments...)
+}
+
+// Bytes returns the dictionary content.
+func (d *Dictionary) Bytes() []byte {
+	return d.data
+}
+
+// Hash returns the SHA-256 of the dictionary in the structured-field form used
+// by the Available-Dictionary header.
+func (d *Dictionary) Hash() string {
+	return d.hash
+}
+
+// Handler serves the dictionary with the headers that register it in the browser.
+func (d *Dictionary) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		h := w.Header()
+		h.Set("Content-Type", "text/plain; charset=utf-8")
+		h.Set("Use-As-Dictionary", `match="`+d.match+`"`)
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+		h.Set("Content-Length", strconv.Itoa(len(d.data)))
+		_, _ = w.Write(d.data)
+	})
+}
+
+// Encoder produces a single Content-Encoding.
+type Encoder struct {
+	// Name is the Content-Encoding token, such as "gzip", "br" or "dcz".
+	Name string
+
+	// Dictionary marks encoders that require the shared dictionary. They are
+	// only selected when the client advertises the matching dictionary.
+	Dictionary bool
+
+	// New wraps w with a compressing writer. dict is nil for encoders that do
+	// not use a dictionary.
+	// A writer with a Flush() error method is flushed when the handler
+	// flushes the response.
+	New func(w io.Writer, dict *Dictionary) (io.WriteCloser, error)
+}
+
+// Gzip is the standard gzip encoder.
+var Gzip = Encoder{
+	Name: "gzip",
+	New: func(w io.Writer, _ *Dictionary) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+}
+
+// Compressor is HTTP middleware that compresses responses, preferring a
+// dictionary encoder when the client holds the dictionary.
+//
+// Usage:
+//
+//	dict := compress.FromNodes("/fragments/*", shell)
+//	c := compress.New(dict, zstdDictEncoder, compress.Gzip)
+//	mux.Handle("/dictionary.txt", dict.Handler())
+//	mux.Handle("/fragments/", c.Handler(fragments))
+type Compressor struct {
+	dict     *Dictionary
+	encoders []Encoder
+}
+
+// New creates a Compressor. Encoders are tried in order. Gzip is appended as
+// a fallback when no non-dictionary encoder is given. dict may be nil.
+func New(dict *Dictionary, encoders ...Encoder) *Compressor {
+	fallback := false
+	for _, e := range encoders {
+		if !e.Dictionary {
+			fallback = true
+		}
+	}
+	if !fallback {
+		encoders = append(encoders, Gzip)
+	}
+	return &Compressor{dict: dict, encoders: encoders}
+}
+
+// Negotiate selects the encoder for r, or returns false when the response
+// should be sent uncompressed.
+func (c *Compressor) Negotiate(r *http.Request) (Encoder, bool) {
+	accepted := acceptEncodings(r.Header.Get("Accept-Encoding"))
+	hasDict := c.dict != nil && r.Header.Get("Available-Dictionary") == c.dict.hash
+	for _, e := range c.encoders {
+		if e.Dictionary && !hasDict {
+			continue
+		}
+		if accepted[e.Name] {
+			return e, true
+		}
+	}
+	return Encoder{}, false
+}
+
+// Handler wraps next with response compression.
+func (c *Compressor) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if c.dict != nil {
+			w.Header().Add("Vary", "Available-Dictionary")
+		}
+		enc, ok := c.Negotiate(r)
+		if !ok || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &writer{ResponseWriter: w, enc: enc, dict: c.dict}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptEncodings parses an Accept-Encoding header into the set of tokens
+// with a non-zero quality.
+func acceptEncodings(header string) map[string]bool {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name == "" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(name)] = true
+	}
+	return accepted
+}
+
+// writer compresses the response body on first write.
+type writer struct {
+	http.ResponseWriter
+	enc     Encoder
+	dict    *Dictionary
+	zw      io.WriteCloser
+	started bool
+}
+
+func (w *writer) WriteHeader(status int) {
+	w.start(status)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if !w.started {
+		w.start(http.StatusOK)
+	}
+	if w.zw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.zw.Write(b)
+}
+
+// start decides whether to compress and writes the response headers.
+func (w *writer) start(status int) {
+	if w.started {
+		return
+	}
+	w.started = true
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	var dict *Dictionary
+	if w.enc.Dictionary {
+		dict = w.dict
+	}
+	zw, err := w.enc.New(w.ResponseWriter, dict)
+	if err != nil {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.zw = zw
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", w.enc.Name)
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush writes the data compressed so far, flushing the encoder when it
+// supports it and then the underlying writer, so streamed responses reach
+// the client as they are written.
+func (w *writer) Flush() {
+	if !w.started {
+		w.start(http.StatusOK)
+	}
+	if f, ok := w.zw.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close flushes the compressed stream.
+func (w *writer) close() {
+	if w.zw != nil {
+		_ = w.zw.Close()
+	}
+}
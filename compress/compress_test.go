@@ -0,0 +1,126 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+)
+
+// deflateDict is a test-only dictionary encoder using raw deflate with a preset dictionary.
+var deflateDict = Encoder{
+	Name:       "x-deflate-dict",
+	Dictionary: true,
+	New: func(w io.Writer, dict *Dictionary) (io.WriteCloser, error) {
+		return flate.NewWriterDict(w, flate.BestCompression, dict.Bytes())
+	},
+}
+
+const fragment = `<div class="card"><div class="card-body">hello</div></div>`
+
+func handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, fragment)
+	})
+}
+
+func TestDictionaryHandler(t *testing.T) {
+	dict := FromNodes("/fragments/*", div.New(div.New().Class("card-body")).Class("card"))
+	rec := httptest.NewRecorder()
+	dict.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dict", nil))
+
+	if got := rec.Header().Get("Use-As-Dictionary"); got != `match="/fragments/*"` {
+		t.Errorf("Use-As-Dictionary = %q", got)
+	}
+	if rec.Body.String() != `<div class="card"><div class="card-body"></div></div>` {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+	if !strings.HasPrefix(dict.Hash(), ":") || !strings.HasSuffix(dict.Hash(), ":") {
+		t.Errorf("Hash() = %q, want structured-field byte sequence", dict.Hash())
+	}
+}
+
+func TestGzipFallback(t *testing.T) {
+	dict := NewDictionary("/*", []byte(fragment))
+	c := New(dict, deflateDict)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, x-deflate-dict")
+	rec := httptest.NewRecorder()
+	c.Handler(handler()).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != fragment {
+		t.Errorf("body = %q, want %q", body, fragment)
+	}
+}
+
+func TestDictionaryEncoding(t *testing.T) {
+	dict := NewDictionary("/*", []byte(fragment))
+	c := New(dict, deflateDict)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, x-deflate-dict")
+	req.Header.Set("Available-Dictionary", dict.Hash())
+	rec := httptest.NewRecorder()
+	c.Handler(handler()).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "x-deflate-dict" {
+		t.Fatalf("Content-Encoding = %q, want x-deflate-dict", got)
+	}
+	body, _ := io.ReadAll(flate.NewReaderDict(rec.Body, dict.Bytes()))
+	if string(body) != fragment {
+		t.Errorf("body = %q, want %q", body, fragment)
+	}
+}
+
+func TestNoCompression(t *testing.T) {
+	c := New(nil)
+	for _, accept := range []string{"", "identity", "gzip;q=0"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", accept)
+		rec := httptest.NewRecorder()
+		c.Handler(handler()).ServeHTTP(rec, req)
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != fragment {
+			t.Errorf("Accept-Encoding %q: got encoding %q body %q", accept, rec.Header().Get("Content-Encoding"), rec.Body.String())
+		}
+	}
+}
+
+func TestFlush(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	var flushed string
+	New(nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, fragment)
+		w.(http.Flusher).Flush()
+		zr, err := gzip.NewReader(strings.NewReader(rec.Body.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := make([]byte, len(fragment))
+		_, _ = io.ReadFull(zr, b)
+		flushed = string(b)
+	})).ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("underlying writer was not flushed")
+	}
+	if flushed != fragment {
+		t.Errorf("flushed %q, want %q", flushed, fragment)
+	}
+}
@@ -0,0 +1,184 @@
+package fluent
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/internal/pretty"
+	"github.com/jpl-au/fluent/internal/xmlconv"
+	"github.com/jpl-au/fluent/minify"
+)
+
+// EscapePolicy selects how characters outside ASCII are written.
+type EscapePolicy int
+
+const (
+	// EscapeDefault writes text as the nodes escape it, replacing only the
+	// characters HTML requires.
+	EscapeDefault EscapePolicy = iota
+	// EscapeASCII also writes each non-ASCII character in text and attribute
+	// values as a numeric character reference, for transports and systems
+	// that are not UTF-8 clean. Script and style content is left as it is.
+	EscapeASCII
+)
+
+// Config selects how RenderContext writes its output. The zero Config writes
+// the output exactly as the nodes render it.
+type Config struct {
+	// Minify removes insignificant whitespace and comments (see minify.HTML).
+	// It is ignored when Pretty is set.
+	Minify bool
+	// Pretty puts each block-level element on its own indented line, for
+	// reading output during development.
+	Pretty bool
+	// XHTML rewrites the output as well-formed XML (see the xhtml package).
+	XHTML bool
+	// Escape selects the escaping applied on top of the nodes' own.
+	Escape EscapePolicy
+	// DebugComments writes an HTML comment naming each named component
+	// before and after its output, as node.WithDebug does. Minify removes
+	// them.
+	DebugComments bool
+	// DeterministicAttributes sorts the attributes of each start tag by
+	// name, so output compares equal whatever order they were set in. Each
+	// attribute is otherwise written as the node rendered it.
+	DeterministicAttributes bool
+}
+
+var defaultConfig atomic.Pointer[Config]
+
+// SetDefaultConfig sets the Config used by renders whose context carries
+// none (see WithConfig). It is safe to call while renders run.
+//
+// Usage:
+//
+//	fluent.SetDefaultConfig(fluent.Config{Minify: true})
+func SetDefaultConfig(c Config) {
+	defaultConfig.Store(&c)
+}
+
+// DefaultConfig returns the Config set with SetDefaultConfig.
+func DefaultConfig() Config {
+	if c := defaultConfig.Load(); c != nil {
+		return *c
+	}
+	return Config{}
+}
+
+type configKey struct{}
+
+// WithConfig returns a context whose renders use c in place of the default
+// Config.
+//
+// Usage:
+//
+//	ctx := fluent.WithConfig(r.Context(), fluent.Config{Pretty: true, DebugComments: true})
+//	err := fluent.RenderContext(ctx, page, w)
+func WithConfig(ctx context.Context, c Config) context.Context {
+	return context.WithValue(ctx, configKey{}, c)
+}
+
+// ConfigFrom returns the Config of renders using ctx: the one set with
+// WithConfig, or the default.
+func ConfigFrom(ctx context.Context) Config {
+	if c, ok := ctx.Value(configKey{}).(Config); ok {
+		return c
+	}
+	return DefaultConfig()
+}
+
+// transforms reports whether c changes the rendered output.
+func (c Config) transforms() bool {
+	return c.Minify || c.Pretty || c.XHTML || c.Escape != EscapeDefault || c.DeterministicAttributes
+}
+
+// apply returns out with c's transformations applied.
+func (c Config) apply(out []byte) []byte {
+	if c.DeterministicAttributes {
+		out = sortAttributes(out)
+	}
+	if c.XHTML {
+		out = []byte(xmlconv.Convert(string(out)))
+	}
+	switch {
+	case c.Pretty:
+		out = pretty.HTML(out)
+	case c.Minify:
+		out = minify.HTML(out)
+	}
+	if c.Escape == EscapeASCII {
+		out = escapeASCII(out)
+	}
+	return out
+}
+
+// sortAttributes rewrites each start tag whose attributes are out of order
+// with them sorted by name.
+func sortAttributes(src []byte) []byte {
+	var out []byte
+	last := 0
+	for _, tok := range markup.Tokenize(string(src)) {
+		if tok.Type != markup.StartTagToken && tok.Type != markup.SelfClosingTagToken {
+			continue
+		}
+		byKey := func(a, b markup.Attr) int { return strings.Compare(a.Key, b.Key) }
+		if slices.IsSortedFunc(tok.Attrs, byKey) {
+			continue
+		}
+		attrs := slices.Clone(tok.Attrs)
+		slices.SortStableFunc(attrs, byKey)
+
+		// The tag's end, such as " />", follows its last attribute.
+		lastAttr := tok.Attrs[len(tok.Attrs)-1].Raw
+		end := strings.LastIndex(tok.Raw, lastAttr) + len(lastAttr)
+
+		out = append(out, src[last:tok.Offset]...)
+		out = append(out, tok.Raw[:1+len(tok.Data)]...)
+		for _, a := range attrs {
+			out = append(out, ' ')
+			out = append(out, a.Raw...)
+		}
+		out = append(out, tok.Raw[end:]...)
+		last = tok.Offset + len(tok.Raw)
+	}
+	if out == nil {
+		return src
+	}
+	return append(out, src[last:]...)
+}
+
+// escapeASCII writes each non-ASCII character outside script and style
+// content as a numeric character reference.
+func escapeASCII(src []byte) []byte {
+	if !slices.ContainsFunc(src, func(b byte) bool { return b >= utf8.RuneSelf }) {
+		return src
+	}
+	out := make([]byte, 0, len(src)+len(src)/8)
+	raw := false // inside script or style
+	for _, tok := range markup.Tokenize(string(src)) {
+		switch {
+		case tok.Type == markup.StartTagToken:
+			raw = tok.Data == "script" || tok.Data == "style"
+		case tok.Type == markup.EndTagToken:
+			raw = false
+		case tok.Type == markup.CommentToken || tok.Type == markup.TextToken && raw:
+			out = append(out, tok.Raw...)
+			continue
+		}
+		for _, r := range tok.Raw {
+			if r < utf8.RuneSelf {
+				out = append(out, byte(r))
+				continue
+			}
+			out = append(out, "&#"...)
+			out = strconv.AppendInt(out, int64(r), 10)
+			out = append(out, ';')
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/br"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestConfig(t *testing.T) {
+	page := func() node.Node {
+		return div.New(
+			node.Component("Greeting", p.New(span.Text("caf\u00e9"), br.New())),
+			text.RawText("<!-- note -->"),
+			script.New().Text("x = '\u00e9'"),
+		).ID("main").Class("box")
+	}
+	tests := []struct {
+		name string
+		cfg  fluent.Config
+		want string
+	}{
+		{"zero", fluent.Config{},
+			"<div class=\"box\" id=\"main\"><p><span>caf\u00e9</span><br /></p><!-- note --><script>x = &#39;\u00e9&#39;</script></div>"},
+		{"minify", fluent.Config{Minify: true},
+			"<div class=\"box\" id=\"main\"><p><span>caf\u00e9</span><br /></p><script>x = &#39;\u00e9&#39;</script></div>"},
+		{"pretty", fluent.Config{Pretty: true, Minify: true},
+			"<div class=\"box\" id=\"main\">\n  <p><span>caf\u00e9</span><br /></p>\n  <!-- note -->\n  <script>x = &#39;\u00e9&#39;</script>\n</div>"},
+		{"xhtml", fluent.Config{XHTML: true, Minify: true},
+			"<div class=\"box\" id=\"main\"><p><span>caf\u00e9</span><br /></p><script><![CDATA[x = &#39;\u00e9&#39;]]></script></div>"},
+		{"ascii", fluent.Config{Escape: fluent.EscapeASCII},
+			"<div class=\"box\" id=\"main\"><p><span>caf&#233;</span><br /></p><!-- note --><script>x = &#39;\u00e9&#39;</script></div>"},
+		{"debug comments", fluent.Config{DebugComments: true},
+			"<div class=\"box\" id=\"main\"><!-- Greeting --><p><span>caf\u00e9</span><br /></p><!-- /Greeting --><!-- note --><script>x = &#39;\u00e9&#39;</script></div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := fluent.WithConfig(context.Background(), tt.cfg)
+			if err := fluent.RenderContext(ctx, page(), &buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeterministicAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := fluent.WithConfig(context.Background(), fluent.Config{DeterministicAttributes: true})
+	n := div.New(br.New().Class("x").ID("b")).ID("a").Class("c").Title("t & u")
+	n.SetAttribute("data-z", "1")
+	n.SetAttribute("aria-hidden", "true")
+	n.SetAttribute("data-a", "")
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		t.Fatal(err)
+	}
+	// Attributes are written as the nodes rendered them, only reordered.
+	want := `<div aria-hidden="true" class="c" data-a="" data-z="1" id="a" title="t &amp; u"><br class="x" id="b" /></div>`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	defer fluent.SetDefaultConfig(fluent.Config{})
+	fluent.SetDefaultConfig(fluent.Config{Pretty: true})
+	if !fluent.DefaultConfig().Pretty || !fluent.ConfigFrom(context.Background()).Pretty {
+		t.Fatal("expected the default config to be used")
+	}
+
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), div.New(p.Text("a")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<div>\n  <p>a</p>\n</div>"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// A context config overrides the default.
+	buf.Reset()
+	ctx := fluent.WithConfig(context.Background(), fluent.Config{})
+	if err := fluent.RenderContext(ctx, div.New(p.Text("a")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<div><p>a</p></div>"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,340 @@
+// Package contentmodel checks that elements are nested as HTML allows: no
+// block elements inside <p>, <li> only inside lists, <tr> only inside table
+// sections and so on. Browsers silently repair invalid nesting while
+// parsing, so the DOM ends up a different shape from the markup - a <div>
+// inside a <p> closes the paragraph early - which breaks styling, scripts
+// and patches that assume the shape that was rendered.
+//
+// Check walks a tree and returns every violation. Must is for development:
+// while Enforce is on it checks the tree it is given and panics, naming the
+// file and line that called it, so invalid nesting fails where it is built.
+// With Enforce off, as by default, Must returns its argument untouched.
+//
+// Violations name the nearest enclosing node.Component and, when
+// node.TrackSources is enabled, the file and line that built it.
+//
+// Usage:
+//
+//	contentmodel.Enforce(os.Getenv("APP_ENV") == "development")
+//
+//	func Intro(text string) node.Node {
+//	    return contentmodel.Must(p.New(div.Text(text))) // panics: <div> is not allowed inside <p>
+//	}
+package contentmodel
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Violation is an element nested where HTML does not allow it.
+type Violation struct {
+	// Path lists the tags from the top of the checked tree to the element,
+	// e.g. "div > p > div".
+	Path    string
+	Message string
+	// Component is the name of the nearest enclosing named component, and
+	// File and Line locate where it was built when sources are tracked.
+	Component string
+	File      string
+	Line      int
+}
+
+func (v *Violation) Error() string {
+	msg := "contentmodel: " + v.Path + ": " + v.Message
+	if v.Component != "" {
+		msg += " (in " + v.Component
+		if v.File != "" {
+			msg += fmt.Sprintf(" at %s:%d", v.File, v.Line)
+		}
+		msg += ")"
+	}
+	return msg
+}
+
+// Error is the panic value of Must. File and Line locate the call to Must.
+type Error struct {
+	Violations []*Violation
+	File       string
+	Line       int
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "contentmodel: invalid nesting built at %s:%d", e.File, e.Line)
+	for _, v := range e.Violations {
+		b.WriteString("\n\t" + v.Error())
+	}
+	return b.String()
+}
+
+var enforcing atomic.Bool
+
+// Enforce sets whether Must checks the trees passed to it. It is off by
+// default as checking walks the whole tree each time; enable it at start up
+// in development and tests.
+func Enforce(enabled bool) {
+	enforcing.Store(enabled)
+}
+
+// Must returns n. While Enforce is on it first checks n and panics with an
+// *Error listing the violations and the location of the call.
+func Must[T node.Node](n T) T {
+	if !enforcing.Load() {
+		return n
+	}
+	if vs := Check(n); len(vs) > 0 {
+		err := &Error{Violations: vs}
+		_, err.File, err.Line, _ = runtime.Caller(1)
+		panic(err)
+	}
+	return n
+}
+
+// Check returns the nesting violations in n in document order. Elements are
+// checked from the tree's structure; the output of other leaf nodes, such as
+// node.Func, is rendered and checked in place. The elements at the top of
+// the tree have no known parent, so a component may return <li> or <tr>
+// elements for its caller to place.
+func Check(n node.Node) []*Violation {
+	c := &checker{}
+	c.walk(n, nil, nil)
+	return c.violations
+}
+
+// elem is an element and its ancestors.
+type elem struct {
+	tag    string
+	parent *elem
+}
+
+type checker struct {
+	violations []*Violation
+}
+
+// opener is implemented by elements.
+type opener interface {
+	RenderOpen(buf *bytes.Buffer)
+}
+
+func (c *checker) walk(n node.Node, parent *elem, comp *node.NamedComponent) {
+	switch v := n.(type) {
+	case nil:
+		return
+	case *node.NamedComponent:
+		c.walk(v.Unwrap(), parent, v)
+		return
+	case opener:
+		if tag := tagOf(v); tag != "" {
+			e := &elem{tag: tag, parent: parent}
+			c.check(e, comp)
+			parent = e
+		}
+	}
+	children := n.Nodes()
+	if len(children) == 0 {
+		if _, ok := n.(opener); !ok {
+			c.output(n, parent, comp)
+		}
+		return
+	}
+	for _, child := range children {
+		c.walk(child, parent, comp)
+	}
+}
+
+// output checks the elements rendered by a leaf node.
+func (c *checker) output(n node.Node, parent *elem, comp *node.NamedComponent) {
+	out := n.Render()
+	if bytes.IndexByte(out, '<') < 0 {
+		return
+	}
+	var walk func(m *markup.Node, parent *elem)
+	walk = func(m *markup.Node, parent *elem) {
+		for _, child := range m.Children {
+			if child.Type != markup.ElementNode {
+				continue
+			}
+			e := &elem{tag: child.Tag, parent: parent}
+			c.check(e, comp)
+			walk(child, e)
+		}
+	}
+	walk(markup.Parse(string(out)), parent)
+}
+
+// tagOf returns the lowercase name of the element's start tag.
+func tagOf(o opener) string {
+	var buf bytes.Buffer
+	o.RenderOpen(&buf)
+	for _, tok := range markup.Tokenize(buf.String()) {
+		if tok.Type == markup.StartTagToken || tok.Type == markup.SelfClosingTagToken {
+			return tok.Data
+		}
+	}
+	return ""
+}
+
+func (c *checker) check(e *elem, comp *node.NamedComponent) {
+	report := func(msg string) {
+		v := &Violation{Path: e.path(), Message: msg}
+		if comp != nil {
+			v.Component = comp.Name()
+			v.File, v.Line = comp.Source()
+		}
+		c.violations = append(c.violations, v)
+	}
+
+	if allowed, ok := parents[e.tag]; ok && e.parent != nil && !contains(allowed, e.parent.tag) {
+		report(fmt.Sprintf("<%s> must be inside %s", e.tag, list(allowed)))
+	}
+	if allowed, ok := children[tagOrEmpty(e.parent)]; ok && !contains(allowed, e.tag) {
+		report(fmt.Sprintf("<%s> is not allowed inside <%s>", e.tag, e.parent.tag))
+	}
+	if container := e.container(); container != nil && phrasingOnly[container.tag] && !phrasing[e.tag] {
+		report(fmt.Sprintf("<%s> is not allowed inside <%s>, which only holds phrasing content", e.tag, container.tag))
+	}
+	if interactive[e.tag] {
+		for a := e.parent; a != nil; a = a.parent {
+			if a.tag == "a" || a.tag == "button" {
+				report(fmt.Sprintf("<%s> is not allowed inside <%s>", e.tag, a.tag))
+				break
+			}
+		}
+	}
+	if e.tag == "form" {
+		for a := e.parent; a != nil; a = a.parent {
+			if a.tag == "form" {
+				report("<form> is not allowed inside another <form>")
+				break
+			}
+		}
+	}
+}
+
+// container returns the ancestor whose content model applies to e, looking
+// through transparent elements such as <a>, which take their parent's.
+func (e *elem) container() *elem {
+	p := e.parent
+	for p != nil && transparent[p.tag] {
+		p = p.parent
+	}
+	return p
+}
+
+func (e *elem) path() string {
+	var tags []string
+	for a := e; a != nil; a = a.parent {
+		tags = append(tags, a.tag)
+	}
+	for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+		tags[i], tags[j] = tags[j], tags[i]
+	}
+	return strings.Join(tags, " > ")
+}
+
+func tagOrEmpty(e *elem) string {
+	if e == nil {
+		return ""
+	}
+	return e.tag
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// list formats tags as "<a>, <b> or <c>".
+func list(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, t := range tags {
+		parts[i] = "<" + t + ">"
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
+
+// parents lists the only elements some elements may appear in. <tr> is
+// held to a table section even though HTML allows it directly in <table>,
+// as browsers then insert a <tbody> the markup does not have.
+var parents = map[string][]string{
+	"li":         {"ul", "ol", "menu"},
+	"dt":         {"dl", "div"},
+	"dd":         {"dl", "div"},
+	"tr":         {"thead", "tbody", "tfoot"},
+	"td":         {"tr"},
+	"th":         {"tr"},
+	"thead":      {"table"},
+	"tbody":      {"table"},
+	"tfoot":      {"table"},
+	"caption":    {"table"},
+	"colgroup":   {"table"},
+	"col":        {"colgroup"},
+	"option":     {"select", "datalist", "optgroup"},
+	"optgroup":   {"select"},
+	"figcaption": {"figure"},
+	"legend":     {"fieldset"},
+	"summary":    {"details"},
+	"track":      {"audio", "video"},
+	"source":     {"audio", "video", "picture"},
+	"param":      {"object"},
+	"area":       {"map"},
+	"head":       {"html"},
+	"body":       {"html"},
+}
+
+// children lists the only elements some elements may contain.
+var children = map[string][]string{
+	"ul":       {"li", "script", "template"},
+	"ol":       {"li", "script", "template"},
+	"menu":     {"li", "script", "template"},
+	"dl":       {"dt", "dd", "div", "script", "template"},
+	"table":    {"caption", "colgroup", "thead", "tbody", "tfoot", "tr", "script", "template"},
+	"thead":    {"tr", "script", "template"},
+	"tbody":    {"tr", "script", "template"},
+	"tfoot":    {"tr", "script", "template"},
+	"tr":       {"td", "th", "script", "template"},
+	"select":   {"option", "optgroup", "hr", "script", "template"},
+	"optgroup": {"option", "script", "template"},
+	"colgroup": {"col", "template"},
+}
+
+// phrasing lists the elements that are phrasing content.
+var phrasing = set(`a abbr area audio b bdi bdo br button canvas cite code data
+	datalist del dfn em embed i iframe img input ins kbd label link map mark
+	math meta meter noscript object output picture progress q ruby s samp
+	script select slot small span strong sub sup svg template textarea time u
+	var video wbr`)
+
+// phrasingOnly lists the elements that may only hold phrasing content.
+var phrasingOnly = set(`p h1 h2 h3 h4 h5 h6 pre span em strong b i u s small
+	mark abbr cite code q sub sup kbd samp var dfn time data label legend
+	button output bdi bdo ruby`)
+
+// transparent lists the elements whose content model is their parent's.
+var transparent = set(`a ins del map object canvas audio video slot noscript`)
+
+// interactive lists the interactive elements, which may not be nested in
+// <a> or <button>.
+var interactive = set(`a button details embed iframe label select textarea`)
+
+func set(fields string) map[string]bool {
+	m := map[string]bool{}
+	for _, f := range strings.Fields(fields) {
+		m[f] = true
+	}
+	return m
+}
@@ -0,0 +1,104 @@
+package contentmodel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/form"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/table"
+	"github.com/jpl-au/fluent/html5/tbody"
+	"github.com/jpl-au/fluent/html5/td"
+	"github.com/jpl-au/fluent/html5/tr"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+		want []string // "path: message" for each violation
+	}{
+		{"valid", div.New(p.New(span.Text("a"), a.Text("b")), ul.New(li.Text("c"))), nil},
+		{"div in p", p.New(div.Text("x")),
+			[]string{"p > div: <div> is not allowed inside <p>, which only holds phrasing content"}},
+		{"through transparent a", p.New(a.New(div.Text("x"))),
+			[]string{"p > a > div: <div> is not allowed inside <p>, which only holds phrasing content"}},
+		{"div in a in div", div.New(a.New(div.Text("card"))), nil},
+		{"li outside list", div.New(li.Text("x")),
+			[]string{"div > li: <li> must be inside <ul>, <ol> or <menu>"}},
+		{"top level li", li.Text("x"), nil},
+		{"tr in table", table.New(tr.New(td.Text("x"))),
+			[]string{"table > tr: <tr> must be inside <thead>, <tbody> or <tfoot>"}},
+		{"tr in tbody", table.New(tbody.New(tr.New(td.Text("x")))), nil},
+		{"nested links", a.New(span.New(a.Text("x"))),
+			[]string{"a > span > a: <a> is not allowed inside <a>"}},
+		{"nested forms", form.New(div.New(form.New())),
+			[]string{"form > div > form: <form> is not allowed inside another <form>"}},
+		{"func output", p.New(node.Func(func() node.Node { return div.New() })),
+			[]string{"p > div: <div> is not allowed inside <p>, which only holds phrasing content"}},
+		{"raw output", ul.New(text.RawText("<li>a</li><div>b</div>")),
+			[]string{"ul > div: <div> is not allowed inside <ul>"}},
+		{"component", node.Component("Menu", div.New(li.Text("x"))),
+			[]string{"div > li: <li> must be inside <ul>, <ol> or <menu>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Check(tt.n) {
+				got = append(got, v.Path+": "+v.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMust(t *testing.T) {
+	invalid := func() node.Node { return p.New(div.New()) }
+
+	// Off by default: nothing is checked.
+	Must(invalid())
+
+	Enforce(true)
+	defer Enforce(false)
+	defer func() {
+		var err *Error
+		if !errors.As(recoverError(recover()), &err) {
+			t.Fatal("expected Must to panic with an *Error")
+		}
+		if !strings.HasSuffix(err.File, "contentmodel_test.go") || err.Line == 0 || len(err.Violations) != 1 {
+			t.Errorf("got %+v, want one violation located in this file", err)
+		}
+	}()
+	Must(invalid())
+}
+
+func recoverError(v any) error {
+	err, _ := v.(error)
+	return err
+}
+
+func TestViolationSource(t *testing.T) {
+	node.TrackSources(true)
+	defer node.TrackSources(false)
+
+	vs := Check(node.Component("Intro", p.New(div.New())))
+	if len(vs) != 1 {
+		t.Fatalf("got %d violations, want 1", len(vs))
+	}
+	if v := vs[0]; v.Component != "Intro" || !strings.HasSuffix(v.File, "contentmodel_test.go") {
+		t.Errorf("got %+v, want the Intro component built in this file", v)
+	}
+	if msg := vs[0].Error(); !strings.Contains(msg, "(in Intro at ") {
+		t.Errorf("got %q, want the component and its source", msg)
+	}
+}
@@ -0,0 +1,231 @@
+// Package css scopes component styles to generated class names, so a
+// component's rules never leak onto the rest of the page.
+//
+// Scoped takes the body of a rule, with nested rules written using &, and
+// returns a class name derived from a hash of the source together with the
+// compiled stylesheet. The stylesheet is declared with the assets style
+// collector, so it is written once in the head however many times the
+// component renders.
+//
+// Usage:
+//
+//	var primary = css.Scoped(`
+//	    padding: .5rem 1rem;
+//	    &:hover { background: var(--accent); }
+//	    svg { width: 1em; }
+//	    @media (max-width: 40rem) { width: 100%; }
+//	`)
+//
+//	func Button(label string) node.Node {
+//	    return div.New(primary.Style(), button.Text(label).Class(primary.Class))
+//	}
+package css
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Prefix starts every generated class name.
+const Prefix = "css-"
+
+// Scope is a compiled scoped stylesheet.
+type Scope struct {
+	// Class is the generated class name, without a leading dot.
+	Class string
+	// CSS is the compiled stylesheet with every rule scoped to Class.
+	CSS string
+}
+
+// Scoped compiles src, the body of a rule, into a stylesheet scoped to a
+// class named after its content hash. Identical sources share a class.
+//
+// Nested rules are resolved against the scope: & stands for the scoped
+// selector, and a nested selector without & matches descendants. Conditional
+// at-rules (@media, @supports, @container, @layer) may contain declarations
+// and nested rules; other at-rules such as @keyframes and @font-face are
+// copied unchanged and are therefore global.
+func Scoped(src string) Scope {
+	sum := sha256.Sum256([]byte(src))
+	class := Prefix + hex.EncodeToString(sum[:4])
+	var b strings.Builder
+	compile(&b, []string{"." + class}, src)
+	return Scope{Class: class, CSS: b.String()}
+}
+
+// Style declares the stylesheet with the assets style collector of the
+// render. It renders nothing in place.
+func (s Scope) Style() node.Node {
+	return assets.CSS(s.Class, s.CSS)
+}
+
+// conditional lists the at-rules whose blocks hold rules for the same scope.
+var conditional = []string{"@media", "@supports", "@container", "@layer"}
+
+// compile writes the declarations of body under the selectors, then its
+// nested rules.
+func compile(b *strings.Builder, selectors []string, body string) {
+	decls, rules := split(body)
+	if len(decls) > 0 {
+		b.WriteString(strings.Join(selectors, ","))
+		b.WriteByte('{')
+		b.WriteString(strings.Join(decls, ";"))
+		b.WriteByte('}')
+	}
+	for _, r := range rules {
+		if !strings.HasPrefix(r.prelude, "@") {
+			compile(b, resolve(selectors, r.prelude), r.body)
+			continue
+		}
+		b.WriteString(r.prelude)
+		b.WriteByte('{')
+		if isConditional(r.prelude) {
+			compile(b, selectors, r.body)
+		} else {
+			b.WriteString(strings.TrimSpace(r.body))
+		}
+		b.WriteByte('}')
+	}
+}
+
+func isConditional(prelude string) bool {
+	for _, c := range conditional {
+		if strings.HasPrefix(prelude, c) && (len(prelude) == len(c) || prelude[len(c)] == ' ' || prelude[len(c)] == '(') {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve combines every parent selector with every selector in the
+// comma-separated list sel.
+func resolve(parents []string, sel string) []string {
+	var out []string
+	for _, p := range parents {
+		for _, s := range splitTop(sel, ',') {
+			s = strings.TrimSpace(s)
+			if strings.Contains(s, "&") {
+				out = append(out, strings.ReplaceAll(s, "&", p))
+			} else {
+				out = append(out, p+" "+s)
+			}
+		}
+	}
+	return out
+}
+
+// rule is a nested block.
+type rule struct {
+	prelude string
+	body    string
+}
+
+// split separates body into its declarations and nested blocks, skipping
+// comments and respecting quotes and parentheses.
+func split(body string) (decls []string, rules []rule) {
+	var cur strings.Builder
+	parens := 0
+	flush := func() {
+		if d := strings.TrimSpace(cur.String()); d != "" {
+			decls = append(decls, d)
+		}
+		cur.Reset()
+	}
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '/' && i+1 < len(body) && body[i+1] == '*':
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				i = len(body)
+			} else {
+				i += end + 3
+			}
+		case c == '"' || c == '\'':
+			j := skipString(body, i)
+			cur.WriteString(body[i:j])
+			i = j - 1
+		case c == '(':
+			parens++
+			cur.WriteByte(c)
+		case c == ')':
+			parens--
+			cur.WriteByte(c)
+		case c == ';' && parens == 0:
+			flush()
+		case c == '{':
+			j := matching(body, i)
+			rules = append(rules, rule{prelude: collapse(cur.String()), body: body[i+1 : j]})
+			cur.Reset()
+			i = j
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	flush()
+	return decls, rules
+}
+
+// skipString returns the index just past the quoted string starting at i.
+func skipString(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// matching returns the index of the brace closing the one at i, or the end of
+// s when it is unbalanced.
+func matching(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '"', '\'':
+			j = skipString(s, j) - 1
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(s)
+}
+
+// splitTop splits s on sep outside parentheses and brackets, so selectors
+// like :is(a, b) stay whole.
+func splitTop(s string, sep byte) []string {
+	var out []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// collapse trims s and reduces internal runs of whitespace to single spaces.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,65 @@
+package css
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/span"
+)
+
+func TestScoped(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"declarations", "color: red; padding: 0 1rem;", ".S{color: red;padding: 0 1rem}"},
+		{"ampersand", "color: red; &:hover, &.active { color: blue }", ".S{color: red}.S:hover,.S.active{color: blue}"},
+		{"descendant", "svg, :is(a, b) { width: 1em }", ".S svg,.S :is(a, b){width: 1em}"},
+		{"nested", "ul { li { & + & { margin: 0 } } }", ".S ul li + .S ul li{margin: 0}"},
+		{"media", "@media (max-width: 40rem) { width: 100%; & > p { margin: 0 } }", "@media (max-width: 40rem){.S{width: 100%}.S > p{margin: 0}}"},
+		{"keyframes", "@keyframes spin { to { transform: rotate(1turn) } }", "@keyframes spin{to { transform: rotate(1turn) }}"},
+		{"strings and urls", `content: "a;b}"; background: url(data:image/png;base64,AA) /* c; */;`, `.S{content: "a;b}";background: url(data:image/png;base64,AA)}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scoped(tt.src)
+			if !strings.HasPrefix(s.Class, Prefix) || len(s.Class) != len(Prefix)+8 {
+				t.Fatalf("class: got %q", s.Class)
+			}
+			want := strings.ReplaceAll(tt.want, ".S", "."+s.Class)
+			if s.CSS != want {
+				t.Errorf("got %q, want %q", s.CSS, want)
+			}
+		})
+	}
+}
+
+func TestScopedClass(t *testing.T) {
+	if Scoped("color: red").Class != Scoped("color: red").Class {
+		t.Error("identical sources should share a class")
+	}
+	if Scoped("color: red").Class == Scoped("color: blue").Class {
+		t.Error("different sources should not share a class")
+	}
+}
+
+func TestStyle(t *testing.T) {
+	s := Scoped("color: red")
+	badge := func() *span.Element { return span.Text("new").Class(s.Class) }
+	page := html.New(head.New(assets.HeadStyles()), body.New(s.Style(), badge(), s.Style(), badge()))
+
+	var buf bytes.Buffer
+	if err := assets.Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "<style>"); n != 1 {
+		t.Errorf("got %d style elements, want 1: %s", n, buf.String())
+	}
+}
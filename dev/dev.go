@@ -0,0 +1,292 @@
+// Package dev provides live reload for iterating on fluent pages.
+//
+// A Reloader serves registered page constructors, injects a small script into
+// each page and pushes a reload event over Server-Sent Events whenever a
+// watched file changes. The script also reloads the page when the server
+// restarts, so it works alongside tools that rebuild the Go binary on save.
+//
+// The watcher polls modification times rather than using OS notifications,
+// which keeps the package free of dependencies and works on every platform.
+// It is intended for development only.
+//
+// Usage:
+//
+//	r := dev.New()
+//	r.Watch("content", "static")
+//	r.Page("/", pages.Home)
+//	r.Page("/about", pages.About)
+//	go r.Start(ctx)
+//	http.ListenAndServe(":8080", r)
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// DefaultEndpoint is the path of the Server-Sent Events endpoint.
+const DefaultEndpoint = "/_fluent/reload"
+
+// DefaultInterval is how often watched paths are polled for changes.
+const DefaultInterval = 500 * time.Millisecond
+
+// Reloader serves pages with live reload. Create one with New.
+type Reloader struct {
+	// Endpoint is the path of the reload event stream.
+	Endpoint string
+	// Interval is the polling interval used by Start.
+	Interval time.Duration
+	// Logger receives constructor panics and watcher errors. Defaults to log.Default().
+	Logger *log.Logger
+
+	boot string
+
+	mu       sync.RWMutex
+	pages    map[string]func() node.Node
+	rendered map[string][]byte
+	paths    []string
+	snapshot map[string]stamp
+	clients  map[chan struct{}]struct{}
+}
+
+// stamp identifies a version of a file.
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// New creates a Reloader with the default endpoint and interval.
+func New() *Reloader {
+	return &Reloader{
+		Endpoint: DefaultEndpoint,
+		Interval: DefaultInterval,
+		Logger:   log.Default(),
+		boot:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		pages:    map[string]func() node.Node{},
+		rendered: map[string][]byte{},
+		clients:  map[chan struct{}]struct{}{},
+	}
+}
+
+// Watch adds files or directories (watched recursively) to the watch list.
+func (r *Reloader) Watch(paths ...string) {
+	r.mu.Lock()
+	r.paths = append(r.paths, paths...)
+	r.mu.Unlock()
+}
+
+// Page registers a page constructor at path. The constructor is re-run
+// whenever a watched file changes.
+func (r *Reloader) Page(path string, ctor func() node.Node) {
+	r.mu.Lock()
+	r.pages[path] = ctor
+	delete(r.rendered, path)
+	r.mu.Unlock()
+}
+
+// Script returns the live-reload script node. Registered pages have it
+// injected automatically; add it yourself to pages served by other handlers.
+func (r *Reloader) Script() node.Node {
+	return text.RawText(fmt.Sprintf(script, strconv.Quote(r.Endpoint)))
+}
+
+// script connects to the event stream, reloading on a reload event or when a
+// reconnect reports a different boot id (the server restarted).
+const script = `<script>(function(){var boot=null;var es=new EventSource(%s);` +
+	`es.addEventListener("hello",function(e){if(boot!==null&&boot!==e.data){location.reload()}boot=e.data});` +
+	`es.addEventListener("reload",function(){location.reload()})})()</script>`
+
+// ServeHTTP serves the event stream and registered pages.
+func (r *Reloader) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == r.Endpoint {
+		r.events(w, req)
+		return
+	}
+	body, ok := r.render(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(body)
+}
+
+// render returns the output of the page at path, building it if needed.
+func (r *Reloader) render(path string) ([]byte, bool) {
+	r.mu.RLock()
+	body, cached := r.rendered[path]
+	ctor, ok := r.pages[path]
+	r.mu.RUnlock()
+	if cached {
+		return body, true
+	}
+	if !ok {
+		return nil, false
+	}
+	body = r.build(path, ctor)
+	r.mu.Lock()
+	r.rendered[path] = body
+	r.mu.Unlock()
+	return body, true
+}
+
+// build runs ctor and injects the reload script, turning a panic into an
+// error page so a broken constructor doesn't take the dev server down.
+func (r *Reloader) build(path string, ctor func() node.Node) (out []byte) {
+	defer func() {
+		if p := recover(); p != nil {
+			r.Logger.Printf("dev: page %s panicked: %v", path, p)
+			msg := text.Textf("page %s panicked: %v", path, p).String()
+			out = inject([]byte("<!DOCTYPE html><pre>"+msg+"</pre>"), r.Script().Render())
+		}
+	}()
+	var page []byte
+	if n := ctor(); n != nil {
+		page = n.Render()
+	}
+	return inject(page, r.Script().Render())
+}
+
+// inject inserts script before the closing body tag, or appends it when the
+// page has none.
+func inject(page []byte, script []byte) []byte {
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, script...)
+	}
+	out := make([]byte, 0, len(page)+len(script))
+	out = append(out, page[:i]...)
+	out = append(out, script...)
+	return append(out, page[i:]...)
+}
+
+// events streams reload notifications to a browser.
+func (r *Reloader) events(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+
+	ch := make(chan struct{}, 1)
+	r.mu.Lock()
+	r.clients[ch] = struct{}{}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.clients, ch)
+		r.mu.Unlock()
+	}()
+
+	fmt.Fprintf(w, "event: hello\ndata: %s\n\n", r.boot)
+	flusher.Flush()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: \n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// Reload rebuilds every registered page and tells connected browsers to reload.
+func (r *Reloader) Reload() {
+	r.mu.Lock()
+	clear(r.rendered)
+	pages := make(map[string]func() node.Node, len(r.pages))
+	for path, ctor := range r.pages {
+		pages[path] = ctor
+	}
+	r.mu.Unlock()
+
+	for path, ctor := range pages {
+		body := r.build(path, ctor)
+		r.mu.Lock()
+		r.rendered[path] = body
+		r.mu.Unlock()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for ch := range r.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start polls the watched paths until ctx is cancelled, calling Reload when
+// anything changes.
+func (r *Reloader) Start(ctx context.Context) {
+	r.Changed()
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.Changed() {
+				r.Reload()
+			}
+		}
+	}
+}
+
+// Changed scans the watched paths and reports whether any file was added,
+// removed or modified since the previous scan. The first scan only records
+// the initial state and returns false.
+func (r *Reloader) Changed() bool {
+	r.mu.RLock()
+	paths := append([]string(nil), r.paths...)
+	r.mu.RUnlock()
+
+	current := map[string]stamp{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			current[path] = stamp{mod: info.ModTime(), size: info.Size()}
+			return nil
+		})
+		if err != nil {
+			r.Logger.Printf("dev: watching %s: %v", root, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.snapshot
+	r.snapshot = current
+	if previous == nil || len(previous) != len(current) {
+		return previous != nil
+	}
+	for path, s := range current {
+		if previous[path] != s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package dev
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestPageInjectsScript(t *testing.T) {
+	r := New()
+	r.Page("/", func() node.Node { return html.New(body.New(p.Text("hi"))) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	got := rec.Body.String()
+	if !strings.Contains(got, `<p>hi</p><script>`) || !strings.HasSuffix(got, `</script></body></html>`) {
+		t.Errorf("script not injected before </body>: %q", got)
+	}
+	if !strings.Contains(got, `"/_fluent/reload"`) {
+		t.Errorf("script missing endpoint: %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing page status = %d, want 404", rec.Code)
+	}
+}
+
+func TestPagePanic(t *testing.T) {
+	r := New()
+	r.Logger.SetOutput(new(strings.Builder))
+	r.Page("/", func() node.Node { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Body.String(); !strings.Contains(got, "boom") || !strings.Contains(got, "<script>") {
+		t.Errorf("panic page = %q", got)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "content.md")
+	os.WriteFile(file, []byte("one"), 0o644)
+
+	r := New()
+	r.Watch(dir)
+	if r.Changed() {
+		t.Error("first scan reported a change")
+	}
+	if r.Changed() {
+		t.Error("unchanged scan reported a change")
+	}
+	os.WriteFile(file, []byte("three"), 0o644)
+	if !r.Changed() {
+		t.Error("modified file not detected")
+	}
+	os.WriteFile(filepath.Join(dir, "new.md"), []byte("x"), 0o644)
+	if !r.Changed() {
+		t.Error("added file not detected")
+	}
+}
+
+func TestReloadRebuildsAndNotifies(t *testing.T) {
+	r := New()
+	version := "v1"
+	r.Page("/", func() node.Node { return p.Text(version) })
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+DefaultEndpoint, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+	if line, _ := events.ReadString('\n'); line != "event: hello\n" {
+		t.Fatalf("first event = %q, want hello", line)
+	}
+
+	version = "v2"
+	r.Reload()
+
+	for {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading events: %v", err)
+		}
+		if line == "event: reload\n" {
+			break
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.HasPrefix(rec.Body.String(), "<p>v2</p>") {
+		t.Errorf("page not rebuilt: %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,191 @@
+// Package diff compares two renders and describes the difference as a list
+// of patch operations - replace a node, set an attribute, remove a child -
+// that turn the old DOM into the new one.
+//
+// The operations are the basis for sending live updates to a browser as
+// small patches rather than whole pages, and a test can assert a refactor
+// leaves a render unchanged by checking the list is empty.
+//
+// Both sides are rendered and parsed, so any two nodes can be compared,
+// whatever their Go types. Children are matched with a shortest edit script
+// over their tag names, and elements with an id attribute only match an
+// element with the same id, so one keyed item is never patched into another.
+//
+// Usage:
+//
+//	for _, op := range diff.Nodes(before, after) {
+//	    fmt.Println(op)
+//	}
+//	// set-attr 0/1 class="active"
+//	// set-text 0/2/0 "3 items"
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/internal/myers"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Kind is the kind of a patch operation.
+type Kind int
+
+const (
+	// Replace replaces the node at Path with the markup in Value.
+	Replace Kind = iota
+	// Insert inserts the markup in Value so that it becomes the child at Path.
+	Insert
+	// Remove removes the node at Path.
+	Remove
+	// SetAttr sets the attribute Key of the element at Path to Value.
+	SetAttr
+	// RemoveAttr removes the attribute Key from the element at Path.
+	RemoveAttr
+	// SetText sets the content of the text node at Path to Value.
+	SetText
+)
+
+var kinds = [...]string{"replace", "insert", "remove", "set-attr", "remove-attr", "set-text"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kinds) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kinds[k]
+}
+
+// Op is a single patch operation. Operations apply in order: each Path is
+// valid in the tree as left by the operations before it.
+type Op struct {
+	Kind Kind
+	// Path locates the node by child index from the top level of the
+	// output, counting every child node, text and comments included.
+	Path []int
+	// Key is the attribute name for SetAttr and RemoveAttr.
+	Key string
+	// Value is the markup for Replace and Insert, the attribute value for
+	// SetAttr and the unescaped text for SetText.
+	Value string
+}
+
+// String returns the operation in a compact, readable form.
+func (o Op) String() string {
+	path := make([]string, len(o.Path))
+	for i, p := range o.Path {
+		path[i] = strconv.Itoa(p)
+	}
+	s := o.Kind.String() + " " + strings.Join(path, "/")
+	switch o.Kind {
+	case Replace, Insert:
+		s += " " + o.Value
+	case SetAttr:
+		s += fmt.Sprintf(" %s=%q", o.Key, o.Value)
+	case RemoveAttr:
+		s += " " + o.Key
+	case SetText:
+		s += fmt.Sprintf(" %q", o.Value)
+	}
+	return s
+}
+
+// Nodes renders a and b and returns the operations turning the output of a
+// into the output of b. A nil node renders nothing.
+func Nodes(a, b node.Node) []Op {
+	return HTML(render(a), render(b))
+}
+
+// HTML returns the operations turning the markup a into the markup b, such
+// as two rendered snapshots of a page.
+func HTML(a, b string) []Op {
+	var ops []Op
+	children(&ops, nil, markup.Parse(a), markup.Parse(b))
+	return ops
+}
+
+func render(n node.Node) string {
+	if n == nil {
+		return ""
+	}
+	return string(n.Render())
+}
+
+// children appends the operations turning the children of a into those of b.
+func children(ops *[]Op, path []int, a, b *markup.Node) {
+	edits := myers.Diff(keys(a.Children), keys(b.Children))
+	i := 0
+	for _, e := range edits {
+		switch e.Op {
+		case myers.Equal:
+			compare(ops, at(path, i), a.Children[e.A], b.Children[e.B])
+			i++
+		case myers.Delete:
+			*ops = append(*ops, Op{Kind: Remove, Path: at(path, i)})
+		case myers.Insert:
+			*ops = append(*ops, Op{Kind: Insert, Path: at(path, i), Value: b.Children[e.B].HTML()})
+			i++
+		}
+	}
+}
+
+// compare appends the operations turning a into b, which have the same key.
+func compare(ops *[]Op, path []int, a, b *markup.Node) {
+	switch a.Type {
+	case markup.TextNode:
+		if a.Data != b.Data {
+			*ops = append(*ops, Op{Kind: SetText, Path: path, Value: b.Data})
+		}
+	case markup.ElementNode:
+		attributes(ops, path, a, b)
+		children(ops, path, a, b)
+	default:
+		if a.Data != b.Data {
+			*ops = append(*ops, Op{Kind: Replace, Path: path, Value: b.HTML()})
+		}
+	}
+}
+
+// attributes appends the operations turning the attributes of a into those
+// of b: changed and added attributes in b's order, then removed ones.
+func attributes(ops *[]Op, path []int, a, b *markup.Node) {
+	for _, attr := range b.Attrs {
+		if v, ok := a.Attr(attr.Key); !ok || v != attr.Val {
+			*ops = append(*ops, Op{Kind: SetAttr, Path: path, Key: attr.Key, Value: attr.Val})
+		}
+	}
+	for _, attr := range a.Attrs {
+		if _, ok := b.Attr(attr.Key); !ok {
+			*ops = append(*ops, Op{Kind: RemoveAttr, Path: path, Key: attr.Key})
+		}
+	}
+}
+
+// keys returns the matching key of each node: its type, its tag for
+// elements and its id when it has one. Nodes only match nodes with the same
+// key; any other difference is patched in place.
+func keys(nodes []*markup.Node) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		switch n.Type {
+		case markup.TextNode:
+			out[i] = "#text"
+		case markup.CommentNode:
+			out[i] = "#comment"
+		case markup.DoctypeNode:
+			out[i] = "#doctype"
+		default:
+			out[i] = n.Tag
+			if id, ok := n.Attr("id"); ok {
+				out[i] += "#" + id
+			}
+		}
+	}
+	return out
+}
+
+// at returns path extended with i, without sharing path's backing array.
+func at(path []int, i int) []int {
+	return append(path[:len(path):len(path)], i)
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// apply applies ops to the parsed markup src and returns the result.
+func apply(t *testing.T, src string, ops []Op) string {
+	t.Helper()
+	doc := markup.Parse(src)
+	for _, op := range ops {
+		parent := doc
+		for _, i := range op.Path[:len(op.Path)-1] {
+			parent = parent.Children[i]
+		}
+		i := op.Path[len(op.Path)-1]
+		switch op.Kind {
+		case Insert, Replace:
+			nodes := markup.Parse(op.Value).Children
+			for _, n := range nodes {
+				n.Parent = parent
+			}
+			if op.Kind == Replace {
+				parent.Children = slices.Delete(parent.Children, i, i+1)
+			}
+			parent.Children = slices.Insert(parent.Children, i, nodes...)
+		case Remove:
+			parent.Children = slices.Delete(parent.Children, i, i+1)
+		case SetText:
+			parent.Children[i].Data = op.Value
+		case SetAttr:
+			n := parent.Children[i]
+			j := slices.IndexFunc(n.Attrs, func(a markup.Attr) bool { return a.Key == op.Key })
+			if j < 0 {
+				n.Attrs = append(n.Attrs, markup.Attr{Key: op.Key, Val: op.Value})
+			} else {
+				n.Attrs[j].Val = op.Value
+			}
+		case RemoveAttr:
+			n := parent.Children[i]
+			n.Attrs = slices.DeleteFunc(n.Attrs, func(a markup.Attr) bool { return a.Key == op.Key })
+		}
+	}
+	return doc.HTML()
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"equal", `<p class="x">a</p>`, `<p class="x">a</p>`, nil},
+		{"text", `<p>a</p>`, `<p>b &amp; c</p>`, []string{`set-text 0/0 "b & c"`}},
+		{"attributes", `<a href="/" class="x" title="t">a</a>`, `<a href="/docs" class="x" rel="next">a</a>`,
+			[]string{`set-attr 0 href="/docs"`, `set-attr 0 rel="next"`, `remove-attr 0 title`}},
+		{"tag change", `<div><p>a</p></div>`, `<div><h2>a</h2></div>`, []string{"remove 0/0", "insert 0/0 <h2>a</h2>"}},
+		{"append", `<ul><li>1</li></ul>`, `<ul><li>1</li><li>2</li></ul>`, []string{"insert 0/1 <li>2</li>"}},
+		{"remove middle", `<ul><li>1</li><b>x</b><li>2</li></ul>`, `<ul><li>1</li><li>2</li></ul>`, []string{"remove 0/1"}},
+		{"keyed", `<ul><li id="a">A</li><li id="b">B</li></ul>`, `<ul><li id="b">B</li><li id="a">A</li></ul>`,
+			[]string{"remove 0/0", `insert 0/1 <li id="a">A</li>`}},
+		{"comment", `<!-- a --><p></p>`, `<!-- b --><p></p>`, []string{"replace 0 <!-- b -->"}},
+		{"top level", ``, `<p>x</p>`, []string{"insert 0 <p>x</p>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := HTML(tt.a, tt.b)
+			got := make([]string, len(ops))
+			for i, op := range ops {
+				got[i] = op.String()
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if out, want := apply(t, tt.a, ops), markup.Parse(tt.b).HTML(); out != want {
+				t.Errorf("applying ops gave %q, want %q", out, want)
+			}
+		})
+	}
+}
+
+func TestNodes(t *testing.T) {
+	list := func(items ...string) node.Node {
+		var lis []node.Node
+		for i, item := range items {
+			l := li.Text(item)
+			if i == 0 {
+				l.Class("first")
+			}
+			lis = append(lis, l)
+		}
+		return div.New(span.Textf("%d items", len(items)), ul.New(lis...))
+	}
+	a, b := list("x", "y"), list("w", "x", "y", "z")
+	ops := Nodes(a, b)
+	if out, want := apply(t, string(a.Render()), ops), string(b.Render()); out != want {
+		t.Errorf("applying ops gave %q, want %q (ops %v)", out, want, ops)
+	}
+	if len(Nodes(a, list("x", "y"))) != 0 {
+		t.Error("identical trees produced operations")
+	}
+	if ops := Nodes(nil, a); len(ops) != 1 || ops[0].Kind != Insert {
+		t.Errorf("from nil: %v", ops)
+	}
+}
+
+func TestKindString(t *testing.T) {
+	if SetAttr.String() != "set-attr" || Kind(42).String() != "Kind(42)" {
+		t.Errorf("got %q and %q", SetAttr, Kind(42))
+	}
+}
@@ -0,0 +1,92 @@
+package fluent
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// previewLen is the number of characters of content Dump shows for a leaf.
+const previewLen = 40
+
+// Dump returns an indented outline of the tree rooted at n for debugging and
+// logging. Each line shows a node's Go type followed by, where they apply,
+// an element's start tag with its attributes, a component's name, a dynamic
+// flag and a preview of a leaf's content.
+//
+// An element's start tag is written on its own, so its children are not
+// rendered. A leaf without a name or String method is rendered for its
+// preview, so a node.Func leaf is evaluated once; its output is not
+// expanded into the outline.
+//
+// Usage:
+//
+//	log.Print(fluent.Dump(page))
+//
+// Output:
+//
+//	div.element <div class="card">
+//	  node.NamedComponent "Badge"
+//	    span.element <span>
+//	      text.Node "new"
+//	  text.Node "Hello, Ada" dynamic
+func Dump(n node.Node) string {
+	var b strings.Builder
+	tag := NewBuffer()
+	defer PutBuffer(tag)
+	dump(&b, tag, n, 0)
+	return b.String()
+}
+
+// dump writes the outline of n to b, using tag as scratch space for start
+// tags.
+func dump(b *strings.Builder, tag *bytes.Buffer, n node.Node, depth int) {
+	for range depth {
+		b.WriteString("  ")
+	}
+	if n == nil {
+		b.WriteString("nil\n")
+		return
+	}
+	b.WriteString(strings.TrimPrefix(fmt.Sprintf("%T", n), "*"))
+
+	children := n.Nodes()
+	switch v := n.(type) {
+	case interface{ Name() string }:
+		fmt.Fprintf(b, " %q", v.Name())
+	case fmt.Stringer:
+		if len(children) == 0 {
+			fmt.Fprintf(b, " %q", preview(v.String()))
+		}
+	default:
+		if e, ok := n.(node.Element); ok {
+			tag.Reset()
+			e.RenderOpen(tag)
+			b.WriteByte(' ')
+			b.Write(tag.Bytes())
+		} else if len(children) == 0 {
+			if out := n.Render(); len(out) > 0 {
+				fmt.Fprintf(b, " %q", preview(string(out)))
+			}
+		}
+	}
+	if d, ok := n.(node.Dynamic); ok && d.Dynamic() {
+		b.WriteString(" dynamic")
+	}
+	b.WriteByte('\n')
+
+	for _, child := range children {
+		dump(b, tag, child, depth+1)
+	}
+}
+
+// preview shortens s to previewLen characters.
+func preview(s string) string {
+	if utf8.RuneCountInString(s) <= previewLen {
+		return s
+	}
+	return string([]rune(s)[:previewLen]) + "…"
+}
@@ -0,0 +1,45 @@
+package fluent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestDump(t *testing.T) {
+	evaluated := 0
+	tree := div.New(
+		node.Component("Badge", span.Static("new").Class("badge")),
+		input.Text("q", "go"),
+		text.Textf("%s", strings.Repeat("x", 50)),
+		node.Func(func() node.Node {
+			evaluated++
+			return span.Static("late")
+		}),
+		nil,
+	).ID("card")
+
+	want := `div.element <div id="card">
+  node.NamedComponent "Badge"
+    span.element <span class="badge">
+      text.Node "new"
+  input.element <input name="q" value="go" type="text" />
+  text.Node "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx…" dynamic
+  node.FunctionComponent "<span>late</span>"
+  nil
+`
+	if got := fluent.Dump(tree); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	// Only the leaf preview evaluates the Func; the div's start tag does not
+	// render its children.
+	if evaluated != 1 {
+		t.Errorf("Func evaluated %d times, want 1", evaluated)
+	}
+}
@@ -0,0 +1,35 @@
+package fluent
+
+import (
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/pool"
+)
+
+// IsEmpty reports whether rendering n would produce no output, so wrappers
+// can omit a container around empty content, such as a <ul> with no items.
+// Nil nodes and node.Empty are empty, elements never are, as their tags are
+// always written, and prerendered nodes are checked without rendering. Any
+// other node is rendered to find out, so content evaluated at render time,
+// such as node.Func, is evaluated.
+//
+// Usage:
+//
+//	items := node.FuncNodes(listItems)
+//	node.Unless(fluent.IsEmpty(items), ul.New(items))
+func IsEmpty(n node.Node) bool {
+	if n == nil {
+		return true
+	}
+	switch v := n.(type) {
+	case node.Element:
+		return false
+	case node.Prerendered:
+		if out := v.Prerendered(); out != nil {
+			return len(out) == 0
+		}
+	}
+	buf := pool.Get(0)
+	defer pool.Put(buf)
+	n.RenderBuilder(buf)
+	return buf.Len() == 0
+}
@@ -0,0 +1,37 @@
+package fluent_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+		want bool
+	}{
+		{"nil", nil, true},
+		{"empty", node.Empty(), true},
+		{"empty element", div.New(), false},
+		{"empty text", text.Text(""), true},
+		{"text", text.Text("x"), false},
+		{"empty static", text.Static(""), true},
+		{"false condition", node.When(false, div.New()), true},
+		{"no items", node.FuncNodes(func() []node.Node { return nil }), true},
+		{"items", node.FuncNodes(func() []node.Node { return []node.Node{li.Text("a")} }), false},
+		{"prerendered", node.Prerender(node.Empty()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fluent.IsEmpty(tt.n); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package escape_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+)
+
+func TestAttributesEscaped(t *testing.T) {
+	el := a.Text("x").Href(`/?q="><script>&p=1`).Class(`c"d`)
+	el.SetAttribute("data-x", "<y>")
+	want := `<a href="/?q=&#34;&gt;&lt;script&gt;&amp;p=1" class="c&#34;d" data-x="&lt;y&gt;">x</a>`
+	if got := string(el.Render()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
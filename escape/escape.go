@@ -0,0 +1,114 @@
+// Package escape escapes text for HTML in a single pass. It produces the
+// same output as html.EscapeString - &lt; &gt; &amp; &#39; and &#34; - but
+// writes straight into the destination and returns clean input untouched,
+// so the common case of text with nothing to escape costs no allocation.
+//
+// Text nodes and the attribute values of elements escape with it as they
+// render, so values are set unescaped.
+//
+// Usage:
+//
+//	escape.WriteString(buf, userName)          // into a render buffer
+//	s := escape.String(label)                  // s itself when already clean
+package escape
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// special lists the bytes that are escaped.
+const special = `<>&'"`
+
+// entity returns the replacement for a special byte.
+func entity(c byte) string {
+	switch c {
+	case '<':
+		return "&lt;"
+	case '>':
+		return "&gt;"
+	case '&':
+		return "&amp;"
+	case '\'':
+		return "&#39;"
+	default:
+		return "&#34;"
+	}
+}
+
+// Needed reports whether s contains a byte that must be escaped.
+func Needed(s string) bool {
+	return strings.ContainsAny(s, special)
+}
+
+// Len returns the length of s once escaped, for sizing buffers up front.
+func Len(s string) int {
+	n := len(s)
+	for {
+		i := strings.IndexAny(s, special)
+		if i < 0 {
+			return n
+		}
+		n += len(entity(s[i])) - 1
+		s = s[i+1:]
+	}
+}
+
+// String returns s escaped for HTML. Clean input is returned as is, without
+// allocating.
+func String(s string) string {
+	i := strings.IndexAny(s, special)
+	if i < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 16)
+	for i >= 0 {
+		b.WriteString(s[:i])
+		b.WriteString(entity(s[i]))
+		s = s[i+1:]
+		i = strings.IndexAny(s, special)
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// WriteString writes s escaped for HTML to buf.
+func WriteString(buf *bytes.Buffer, s string) {
+	for {
+		i := strings.IndexAny(s, special)
+		if i < 0 {
+			buf.WriteString(s)
+			return
+		}
+		buf.WriteString(s[:i])
+		buf.WriteString(entity(s[i]))
+		s = s[i+1:]
+	}
+}
+
+// Write writes s escaped for HTML to w, in runs between the escaped bytes,
+// and returns the number of bytes written. Writers implementing
+// io.StringWriter receive the runs without allocation.
+func Write(w io.Writer, s string) (int, error) {
+	total := 0
+	for {
+		i := strings.IndexAny(s, special)
+		if i < 0 {
+			n, err := io.WriteString(w, s)
+			return total + n, err
+		}
+		n, err := io.WriteString(w, s[:i])
+		total += n
+		if err != nil {
+			return total, err
+		}
+		n, err = io.WriteString(w, entity(s[i]))
+		total += n
+		if err != nil {
+			return total, err
+		}
+		s = s[i+1:]
+	}
+}
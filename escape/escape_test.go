@@ -0,0 +1,75 @@
+package escape
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"strings"
+	"testing"
+)
+
+var inputs = []string{
+	"",
+	"plain text",
+	"<script>alert('x')</script>",
+	`a & b "quoted"`,
+	"&&&",
+	"trailing <",
+	"caf\u00e9 \u2013 <b>",
+}
+
+func TestMatchesStdlib(t *testing.T) {
+	for _, in := range inputs {
+		want := html.EscapeString(in)
+		if got := String(in); got != want {
+			t.Errorf("String(%q) = %q, want %q", in, got, want)
+		}
+		var buf bytes.Buffer
+		WriteString(&buf, in)
+		if buf.String() != want {
+			t.Errorf("WriteString(%q) = %q, want %q", in, buf.String(), want)
+		}
+		var sb strings.Builder
+		n, err := Write(&sb, in)
+		if err != nil || sb.String() != want || n != len(want) {
+			t.Errorf("Write(%q) = %q, %d, %v; want %q", in, sb.String(), n, err, want)
+		}
+		if got := Len(in); got != len(want) {
+			t.Errorf("Len(%q) = %d, want %d", in, got, len(want))
+		}
+		if Needed(in) != (want != in) {
+			t.Errorf("Needed(%q) = %v", in, Needed(in))
+		}
+	}
+}
+
+func TestNoAllocation(t *testing.T) {
+	clean := "nothing to escape here"
+	if allocs := testing.AllocsPerRun(100, func() { _ = String(clean) }); allocs != 0 {
+		t.Errorf("String allocated %v times for clean input, want 0", allocs)
+	}
+	var buf bytes.Buffer
+	buf.Grow(256)
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		WriteString(&buf, "<b>Tom & Jerry</b>")
+	}); allocs != 0 {
+		t.Errorf("WriteString allocated %v times, want 0", allocs)
+	}
+}
+
+type failWriter struct{ n int }
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("closed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestWriteError(t *testing.T) {
+	if _, err := Write(&failWriter{n: 1}, "a<b"); err == nil {
+		t.Error("expected the writer's error")
+	}
+}
@@ -0,0 +1,149 @@
+package feed
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ContentTypeAtom is the media type for Atom feeds.
+const ContentTypeAtom = "application/atom+xml; charset=utf-8"
+
+// AtomFeed is an Atom 1.0 document. Create one with Atom.
+type AtomFeed struct {
+	title    string
+	id       string
+	link     string
+	self     string
+	subtitle string
+	author   string
+	updated  time.Time
+	items    []Item
+}
+
+// Atom creates an Atom feed. id is a permanent, unique identifier for the
+// feed (commonly the site URL) and link is the site the feed describes.
+func Atom(title, id, link string) *AtomFeed {
+	return &AtomFeed{title: title, id: id, link: link}
+}
+
+// Self sets the URL the feed itself is served from.
+func (f *AtomFeed) Self(url string) *AtomFeed {
+	f.self = url
+	return f
+}
+
+// Subtitle sets the feed subtitle.
+func (f *AtomFeed) Subtitle(s string) *AtomFeed {
+	f.subtitle = s
+	return f
+}
+
+// Author sets the feed-level author, used for entries without their own.
+func (f *AtomFeed) Author(name string) *AtomFeed {
+	f.author = name
+	return f
+}
+
+// Updated sets the feed updated time. Defaults to the latest entry date.
+func (f *AtomFeed) Updated(t time.Time) *AtomFeed {
+	f.updated = t
+	return f
+}
+
+// Add appends items to the feed.
+func (f *AtomFeed) Add(items ...Item) *AtomFeed {
+	f.items = append(f.items, items...)
+	return f
+}
+
+// Render generates the XML document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (f *AtomFeed) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+// RenderBuilder writes the XML document directly to a buffer.
+func (f *AtomFeed) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(xmlDecl)
+	buf.WriteString(`<feed xmlns="http://www.w3.org/2005/Atom">`)
+	element(buf, "title", f.title)
+	element(buf, "subtitle", f.subtitle)
+	element(buf, "id", f.id)
+	link(buf, "alternate", f.link)
+	link(buf, "self", f.self)
+	element(buf, "updated", f.latest().Format(time.RFC3339))
+	author(buf, f.author)
+	for _, it := range f.items {
+		buf.WriteString("<entry>")
+		element(buf, "title", it.Title)
+		element(buf, "id", it.guid())
+		link(buf, "alternate", it.Link)
+		element(buf, "updated", it.updated().Format(time.RFC3339))
+		if !it.Published.IsZero() {
+			element(buf, "published", it.Published.Format(time.RFC3339))
+		}
+		author(buf, it.Author)
+		for _, c := range it.Categories {
+			buf.WriteString(`<category term="`)
+			escape.WriteString(buf, c)
+			buf.WriteString(`"/>`)
+		}
+		element(buf, "summary", it.Summary)
+		if it.Content != "" {
+			buf.WriteString(`<content type="html">`)
+			node.CDATA(it.Content).RenderBuilder(buf)
+			buf.WriteString(`</content>`)
+		}
+		buf.WriteString("</entry>")
+	}
+	buf.WriteString("</feed>")
+}
+
+// latest returns the explicit update time or the latest entry date.
+func (f *AtomFeed) latest() time.Time {
+	if !f.updated.IsZero() {
+		return f.updated
+	}
+	var latest time.Time
+	for _, it := range f.items {
+		if u := it.updated(); u.After(latest) {
+			latest = u
+		}
+	}
+	return latest
+}
+
+// Nodes returns an empty slice as feed entries are not nodes.
+func (f *AtomFeed) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op for AtomFeed as it does not have attributes.
+func (f *AtomFeed) SetAttribute(_ string, _ string) {
+	// AtomFeed does not support attributes
+}
+
+func link(buf *bytes.Buffer, rel, href string) {
+	if href == "" {
+		return
+	}
+	buf.WriteString(`<link rel="`)
+	buf.WriteString(rel)
+	buf.WriteString(`" href="`)
+	escape.WriteString(buf, href)
+	buf.WriteString(`"/>`)
+}
+
+func author(buf *bytes.Buffer, name string) {
+	if name == "" {
+		return
+	}
+	buf.WriteString("<author>")
+	element(buf, "name", name)
+	buf.WriteString("</author>")
+}
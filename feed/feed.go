@@ -0,0 +1,94 @@
+// Package feed builds RSS 2.0 and Atom 1.0 feeds that render through the
+// node system, so a blog's feed is produced by the same pipeline as its pages.
+//
+// Usage:
+//
+//	f := feed.RSS("Acme Blog", "https://acme.example/blog", "News from Acme")
+//	for _, post := range posts {
+//	    f.Add(feed.Item{
+//	        Title:     post.Title,
+//	        Link:      "https://acme.example/blog/" + post.Slug,
+//	        Published: post.Date,
+//	        Content:   string(post.Body.Render()),
+//	    })
+//	}
+//	f.Render(w)
+package feed
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Item is a single feed entry.
+type Item struct {
+	Title string
+	// Link is the absolute URL of the item.
+	Link string
+	// GUID uniquely identifies the item. Defaults to Link.
+	GUID string
+	// Summary is a plain-text description.
+	Summary string
+	// Content is the full HTML body. It is wrapped in CDATA, so it does not
+	// need escaping.
+	Content    string
+	Author     string
+	Categories []string
+	Published  time.Time
+	// Updated defaults to Published.
+	Updated time.Time
+}
+
+func (it Item) guid() string {
+	if it.GUID != "" {
+		return it.GUID
+	}
+	return it.Link
+}
+
+func (it Item) updated() time.Time {
+	if !it.Updated.IsZero() {
+		return it.Updated
+	}
+	return it.Published
+}
+
+var xmlDecl = []byte(`<?xml version="1.0" encoding="UTF-8"?>`)
+
+// element writes <name>escaped value</name>, omitting empty values.
+func element(buf *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	buf.WriteByte('<')
+	buf.WriteString(name)
+	buf.WriteByte('>')
+	escape.WriteString(buf, value)
+	buf.WriteString("</")
+	buf.WriteString(name)
+	buf.WriteByte('>')
+}
+
+// cdata writes <name><![CDATA[value]]></name>, splitting any "]]>" in value
+// across two sections so it cannot terminate the CDATA block early.
+func cdata(buf *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	buf.WriteByte('<')
+	buf.WriteString(name)
+	buf.WriteByte('>')
+	node.CDATA(value).RenderBuilder(buf)
+	buf.WriteString("</")
+	buf.WriteString(name)
+	buf.WriteByte('>')
+}
+
+// render implements Render for the feeds in this package.
+func render(n node.Node, w []io.Writer) []byte {
+	return node.Render(n, w...)
+}
@@ -0,0 +1,133 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	published = time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	edited    = time.Date(2026, 3, 15, 12, 30, 0, 0, time.UTC)
+)
+
+func items() []Item {
+	return []Item{
+		{
+			Title:      "Fish & Chips",
+			Link:       "https://acme.example/blog/fish",
+			Summary:    "A <short> summary",
+			Content:    "<p>Body with ]]> inside</p>",
+			Author:     "jo@acme.example (Jo)",
+			Categories: []string{"food"},
+			Published:  published,
+			Updated:    edited,
+		},
+		{
+			Title:     "Second",
+			Link:      "https://acme.example/blog/second",
+			GUID:      "urn:uuid:1234",
+			Published: published,
+		},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	out := string(RSS("Acme", "https://acme.example/", "News").Language("en-au").Add(items()...).Render())
+
+	var doc struct {
+		Channel struct {
+			Title     string `xml:"title"`
+			LastBuild string `xml:"lastBuildDate"`
+			Items     []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+
+	if got := doc.Channel.LastBuild; got != "Sun, 15 Mar 2026 12:30:00 +0000" {
+		t.Errorf("lastBuildDate: got %q", got)
+	}
+	if len(doc.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(doc.Channel.Items))
+	}
+	first := doc.Channel.Items[0]
+	if first.Title != "Fish & Chips" {
+		t.Errorf("title: got %q", first.Title)
+	}
+	if first.Content != "<p>Body with ]]> inside</p>" {
+		t.Errorf("content: got %q", first.Content)
+	}
+	if first.PubDate != "Sat, 14 Mar 2026 09:00:00 +0000" {
+		t.Errorf("pubDate: got %q", first.PubDate)
+	}
+	if !strings.Contains(out, `<guid isPermaLink="true">https://acme.example/blog/fish</guid>`) {
+		t.Errorf("permalink guid missing:\n%s", out)
+	}
+	if !strings.Contains(out, `<guid isPermaLink="false">urn:uuid:1234</guid>`) {
+		t.Errorf("opaque guid missing:\n%s", out)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	out := string(Atom("Acme", "https://acme.example/", "https://acme.example/").
+		Self("https://acme.example/feed.atom").
+		Author("Acme").
+		Add(items()...).
+		Render())
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+
+	if doc.Updated != "2026-03-15T12:30:00Z" {
+		t.Errorf("updated: got %q", doc.Updated)
+	}
+	if len(doc.Links) != 2 || doc.Links[1].Rel != "self" {
+		t.Errorf("links: got %+v", doc.Links)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(doc.Entries))
+	}
+	if got := doc.Entries[0].Content; got.Type != "html" || got.Body != "<p>Body with ]]> inside</p>" {
+		t.Errorf("content: got %+v", got)
+	}
+	if got := doc.Entries[1].ID; got != "urn:uuid:1234" {
+		t.Errorf("id: got %q", got)
+	}
+	if got := doc.Entries[1].Updated; got != "2026-03-14T09:00:00Z" {
+		t.Errorf("entry updated: got %q", got)
+	}
+}
+
+func TestCDATASafe(t *testing.T) {
+	var buf bytes.Buffer
+	cdata(&buf, "x", "a]]>b]]>")
+	if got := buf.String(); got != "<x><![CDATA[a]]]]><![CDATA[>b]]]]><![CDATA[>]]></x>" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package feed
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ContentTypeRSS is the media type for RSS feeds.
+const ContentTypeRSS = "application/rss+xml; charset=utf-8"
+
+// RSSFeed is an RSS 2.0 document. Create one with RSS.
+type RSSFeed struct {
+	title       string
+	link        string
+	description string
+	language    string
+	updated     time.Time
+	items       []Item
+}
+
+// RSS creates an RSS 2.0 feed for the site at link.
+func RSS(title, link, description string) *RSSFeed {
+	return &RSSFeed{title: title, link: link, description: description}
+}
+
+// Language sets the channel language, e.g. "en-au".
+func (f *RSSFeed) Language(lang string) *RSSFeed {
+	f.language = lang
+	return f
+}
+
+// Updated sets the channel lastBuildDate. Defaults to the latest item date.
+func (f *RSSFeed) Updated(t time.Time) *RSSFeed {
+	f.updated = t
+	return f
+}
+
+// Add appends items to the feed.
+func (f *RSSFeed) Add(items ...Item) *RSSFeed {
+	f.items = append(f.items, items...)
+	return f
+}
+
+// Render generates the XML document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (f *RSSFeed) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+// RenderBuilder writes the XML document directly to a buffer.
+func (f *RSSFeed) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(xmlDecl)
+	buf.WriteString(`<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel>`)
+	element(buf, "title", f.title)
+	element(buf, "link", f.link)
+	element(buf, "description", f.description)
+	element(buf, "language", f.language)
+	if updated := f.lastBuild(); !updated.IsZero() {
+		element(buf, "lastBuildDate", updated.Format(time.RFC1123Z))
+	}
+	for _, it := range f.items {
+		buf.WriteString("<item>")
+		element(buf, "title", it.Title)
+		element(buf, "link", it.Link)
+		if guid := it.guid(); guid != "" {
+			if guid == it.Link {
+				buf.WriteString(`<guid isPermaLink="true">`)
+			} else {
+				buf.WriteString(`<guid isPermaLink="false">`)
+			}
+			escape.WriteString(buf, guid)
+			buf.WriteString("</guid>")
+		}
+		if !it.Published.IsZero() {
+			element(buf, "pubDate", it.Published.Format(time.RFC1123Z))
+		}
+		element(buf, "author", it.Author)
+		for _, c := range it.Categories {
+			element(buf, "category", c)
+		}
+		element(buf, "description", it.Summary)
+		cdata(buf, "content:encoded", it.Content)
+		buf.WriteString("</item>")
+	}
+	buf.WriteString("</channel></rss>")
+}
+
+// lastBuild returns the explicit update time or the latest item date.
+func (f *RSSFeed) lastBuild() time.Time {
+	if !f.updated.IsZero() {
+		return f.updated
+	}
+	var latest time.Time
+	for _, it := range f.items {
+		if u := it.updated(); u.After(latest) {
+			latest = u
+		}
+	}
+	return latest
+}
+
+// Nodes returns an empty slice as feed items are not nodes.
+func (f *RSSFeed) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op for RSSFeed as it does not have attributes.
+func (f *RSSFeed) SetAttribute(_ string, _ string) {
+	// RSSFeed does not support attributes
+}
@@ -0,0 +1,119 @@
+package fluenttest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// AssertContainsElement fails t unless the output of n contains an element
+// matched by selector, and returns the matched elements. The failure shows
+// the normalised output searched.
+//
+// Usage:
+//
+//	fluenttest.AssertContainsElement(t, form, `input[name="email"][required]`)
+func AssertContainsElement(t testing.TB, n node.Node, selector string) []*Element {
+	t.Helper()
+	out := render(n)
+	found := FindHTML(out, selector)
+	if len(found) == 0 {
+		t.Errorf("fluenttest: no element matches %q in:\n%s", selector, indent(Normalise(out)))
+	}
+	return found
+}
+
+// AssertAttr fails t unless the first element matched by selector has the
+// attribute key with the value want.
+//
+// Usage:
+//
+//	fluenttest.AssertAttr(t, link, "a", "rel", "noopener")
+func AssertAttr(t testing.TB, n node.Node, selector, key, want string) {
+	t.Helper()
+	e := first(t, n, selector)
+	if e == nil {
+		return
+	}
+	got, ok := e.Attr(key)
+	switch {
+	case !ok:
+		t.Errorf("fluenttest: %s has no %s attribute, want %q:\n%s", e.Path(), key, want, indent(e.String()))
+	case got != want:
+		t.Errorf("fluenttest: %s has %s=%q, want %q:\n%s", e.Path(), key, got, want, indent(e.String()))
+	}
+}
+
+// AssertText fails t unless the visible text of the first element matched by
+// selector, with runs of whitespace collapsed, is want.
+//
+// Usage:
+//
+//	fluenttest.AssertText(t, card, "h3", "Sourdough loaf")
+func AssertText(t testing.TB, n node.Node, selector, want string) {
+	t.Helper()
+	e := first(t, n, selector)
+	if e == nil {
+		return
+	}
+	if got := e.Text(); got != want {
+		t.Errorf("fluenttest: %s has text %q, want %q:\n%s", e.Path(), got, want, indent(e.String()))
+	}
+}
+
+// AssertNoRaw fails t if the output of n contains a script element, a
+// sign that content meant to be escaped was rendered raw. Render the
+// component with hostile input, such as `<script>alert(1)</script>`, and
+// assert it stays inert.
+//
+// Usage:
+//
+//	fluenttest.AssertNoRaw(t, span.Text("<script>alert(1)</script>"))
+func AssertNoRaw(t testing.TB, n node.Node) {
+	t.Helper()
+	s, _ := markup.Compile("script")
+	for _, m := range markup.Parse(render(n)).Query(s) {
+		e := &Element{n: m}
+		t.Errorf("fluenttest: unescaped script element at %s:\n%s", e.Path(), indent(e.String()))
+	}
+}
+
+// first returns the first element of n's output matched by selector, failing
+// t when there is none.
+func first(t testing.TB, n node.Node, selector string) *Element {
+	t.Helper()
+	out := render(n)
+	found := FindHTML(out, selector)
+	if len(found) == 0 {
+		t.Errorf("fluenttest: no element matches %q in:\n%s", selector, indent(Normalise(out)))
+		return nil
+	}
+	return found[0]
+}
+
+// indent prefixes each line of s with a tab so it stands apart from the
+// failure message in test output.
+func indent(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = "\t" + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EqualHTML fails t unless want and got are equivalent HTML: they are
+// compared after Normalise, so attribute order and insignificant whitespace
+// do not matter. The failure shows a line diff of the normalised forms.
+//
+// Usage:
+//
+//	fluenttest.EqualHTML(t, `<a href="/" class="active">Home</a>`, string(link.Render()))
+func EqualHTML(t testing.TB, want, got string) {
+	t.Helper()
+	w, g := Normalise(want), Normalise(got)
+	if w != g {
+		t.Errorf("fluenttest: HTML differs (- want, + got):\n%s", Diff(w, g))
+	}
+}
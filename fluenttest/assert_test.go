@@ -0,0 +1,77 @@
+package fluenttest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestAssertions(t *testing.T) {
+	c := card("Bread")
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		want   string // substring of the single failure, or "" for a pass
+	}{
+		{"element", func(t testing.TB) { AssertContainsElement(t, c, "div.card > a.button") }, ""},
+		{"missing element", func(t testing.TB) { AssertContainsElement(t, c, "img") }, "no element matches \"img\" in:\n\t<div class=\"card\">\n\t  <h3>Bread</h3>"},
+		{"attr", func(t testing.TB) { AssertAttr(t, c, "a", "href", "/buy") }, ""},
+		{"wrong attr", func(t testing.TB) { AssertAttr(t, c, "a", "href", "/sell") }, `div > a has href="/buy", want "/sell":` + "\n\t" + `<a class="button" href="/buy">Buy</a>`},
+		{"absent attr", func(t testing.TB) { AssertAttr(t, c, "a", "rel", "nofollow") }, "div > a has no rel attribute"},
+		{"attr no match", func(t testing.TB) { AssertAttr(t, c, "button", "type", "submit") }, `no element matches "button"`},
+		{"text", func(t testing.TB) { AssertText(t, c, "p", "Fresh from the oven") }, ""},
+		{"wrong text", func(t testing.TB) { AssertText(t, c, "h3", "Cake") }, `div > h3 has text "Bread", want "Cake"`},
+		{"escaped", func(t testing.TB) { AssertNoRaw(t, p.Text("<script>alert(1)</script>")) }, ""},
+		{"raw", func(t testing.TB) {
+			AssertNoRaw(t, div.New(text.RawText("<script>alert(1)</script>")))
+		}, "unescaped script element at div > script:\n\t<script>alert(1)</script>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(tt.assert)
+			switch {
+			case tt.want == "" && len(r.errors) > 0:
+				t.Errorf("unexpected failure: %q", r.errors)
+			case tt.want != "" && (len(r.errors) != 1 || !strings.Contains(r.errors[0], tt.want)):
+				t.Errorf("got failures %q, want one containing %q", r.errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertContainsElementReturns(t *testing.T) {
+	var n node.Node = div.New(p.Text("a"), p.Text("b"))
+	if got := AssertContainsElement(t, n, "p"); len(got) != 2 || got[1].Text() != "b" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestEqualHTML(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		diff      string
+	}{
+		{"attribute order", `<a href="/" class="x">Go</a>`, `<a class="x" href="/">Go</a>`, ""},
+		{"whitespace", "<ul>\n  <li>One  item</li>\n</ul>", "<ul><li>One item</li></ul>", ""},
+		{"escaping", `<p title="a&amp;b">&lt;</p>`, `<p title="a&#38;b">&#60;</p>`, ""},
+		{"text", "<p>a</p>", "<p>b</p>", "- <p>a</p>\n+ <p>b</p>\n"},
+		{"attribute", `<div><i class="x"></i></div>`, `<div><i class="y"></i></div>`, "  <div>\n-   <i class=\"x\"></i>\n+   <i class=\"y\"></i>\n  </div>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := run(func(t testing.TB) { EqualHTML(t, tt.want, tt.got) })
+			switch {
+			case tt.diff == "" && len(r.errors) > 0:
+				t.Errorf("unexpected failure: %q", r.errors)
+			case tt.diff != "" && (len(r.errors) != 1 || !strings.HasSuffix(r.errors[0], tt.diff)):
+				t.Errorf("got failures %q, want diff %q", r.errors, tt.diff)
+			}
+		})
+	}
+}
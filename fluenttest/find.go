@@ -0,0 +1,89 @@
+package fluenttest
+
+import (
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Element is an element of rendered output found by Find.
+type Element struct {
+	n *markup.Node
+}
+
+// Find renders n and returns the elements matched by a CSS selector in
+// document order, so tests can assert on structure rather than substrings.
+// It panics if the selector is invalid.
+//
+// Selectors may use type, universal, #id, .class and [attribute] selectors
+// with the =, ~=, |=, ^=, $= and *= operators, the descendant, >, + and ~
+// combinators, selector lists, and the :first-child, :last-child,
+// :only-child, :nth-child(), :nth-last-child(), :nth-of-type(),
+// :first-of-type, :last-of-type, :empty and :not() pseudo-classes.
+//
+// Usage:
+//
+//	items := fluenttest.Find(menu, "ul.menu > li")
+//	if len(items) != 3 || !items[0].HasClass("active") {
+//	    t.Errorf("menu items: %v", items)
+//	}
+func Find(n node.Node, selector string) []*Element {
+	return query(markup.Parse(render(n)), selector)
+}
+
+// FindHTML is Find for output that has already been rendered.
+func FindHTML(src, selector string) []*Element {
+	return query(markup.Parse(src), selector)
+}
+
+// query returns the descendants of root matched by selector.
+func query(root *markup.Node, selector string) []*Element {
+	s, err := markup.Compile(selector)
+	if err != nil {
+		panic("fluenttest: " + err.Error())
+	}
+	var out []*Element
+	for _, m := range root.Query(s) {
+		out = append(out, &Element{n: m})
+	}
+	return out
+}
+
+// Tag returns the lowercase element name.
+func (e *Element) Tag() string {
+	return e.n.Tag
+}
+
+// Attr returns the unescaped value of the named attribute.
+func (e *Element) Attr(key string) (string, bool) {
+	return e.n.Attr(key)
+}
+
+// HasClass reports whether the element's class attribute contains name.
+func (e *Element) HasClass(name string) bool {
+	return e.n.HasClass(name)
+}
+
+// Text returns the element's visible text with runs of whitespace collapsed.
+func (e *Element) Text() string {
+	return strings.Join(strings.Fields(e.n.Text()), " ")
+}
+
+// Path returns a CSS-like path locating the element in the output, such as
+// "div#main > ul > li:nth-of-type(2)".
+func (e *Element) Path() string {
+	return e.n.Path()
+}
+
+// Find returns the descendants of the element matched by selector.
+func (e *Element) Find(selector string) []*Element {
+	return query(e.n, selector)
+}
+
+// String returns the element's normalised HTML (see Normalise).
+func (e *Element) String() string {
+	var b strings.Builder
+	write(&b, e.n, 0, false)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,61 @@
+package fluenttest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/nav"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+func menu() node.Node {
+	return ul.New(
+		li.New(a.New().Href("/").Text("Home")).Class("active"),
+		li.New(a.New().Href("/docs").Text("Docs  &  guides")),
+		li.New(a.New().Text("Soon")),
+	).Class("menu")
+}
+
+func TestFind(t *testing.T) {
+	page := nav.New(menu())
+
+	items := Find(page, "ul.menu > li")
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+	if !items[0].HasClass("active") || items[1].HasClass("active") {
+		t.Error("active class not on the first item only")
+	}
+	if got := items[1].Text(); got != "Docs & guides" {
+		t.Errorf("Text() = %q", got)
+	}
+	if got := items[2].Path(); got != "nav > ul > li:nth-of-type(3)" {
+		t.Errorf("Path() = %q", got)
+	}
+
+	links := Find(page, "li > a[href]")
+	if len(links) != 2 {
+		t.Fatalf("got %d links, want 2", len(links))
+	}
+	if href, _ := links[1].Attr("href"); href != "/docs" || links[1].Tag() != "a" {
+		t.Errorf("second link = %s", links[1])
+	}
+	if got := items[0].Find("a"); len(got) != 1 || got[0].String() != `<a href="/">Home</a>` {
+		t.Errorf("Element.Find = %v", got)
+	}
+	if got := FindHTML(`<p>x</p><p class="y">z</p>`, "p.y"); len(got) != 1 {
+		t.Errorf("FindHTML matched %d", len(got))
+	}
+}
+
+func TestFindInvalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "fluenttest: ") {
+			t.Errorf("recovered %v, want a fluenttest panic", r)
+		}
+	}()
+	Find(menu(), "li[")
+}
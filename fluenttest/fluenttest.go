@@ -0,0 +1,71 @@
+// Package fluenttest provides helpers for testing components built with
+// fluent.
+//
+// Snapshot compares a component's output against a golden file, so
+// regression tests don't need hand-maintained expected strings. Output is
+// normalised before it is compared - one element per line, attributes
+// sorted, insignificant whitespace collapsed - so golden files read well in
+// review and failures show a line diff of what changed.
+//
+// Usage:
+//
+//	func TestProductCard(t *testing.T) {
+//	    fluenttest.Snapshot(t, "product_card", ProductCard(sample))
+//	}
+//
+// Regenerate golden files after an intended change with:
+//
+//	go test ./... -update
+package fluenttest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// update is the -update flag. Test packages using Snapshot must not define a
+// flag of the same name.
+var update = flag.Bool("update", false, "rewrite fluenttest golden files with the current output")
+
+// Snapshot renders n, normalises the output and compares it with the golden
+// file testdata/name.golden, failing t with a line diff when they differ.
+// With the -update flag the golden file is written instead. name may contain
+// slashes to group golden files in subdirectories.
+func Snapshot(t testing.TB, name string, n node.Node) {
+	t.Helper()
+	got := Normalise(render(n))
+	path := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("fluenttest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("fluenttest: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("fluenttest: golden file %s does not exist; run go test with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("fluenttest: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("fluenttest: output does not match %s (- want, + got):\n%s", path, Diff(string(want), got))
+	}
+}
+
+// render returns the output of n, or an empty string for a nil node.
+func render(n node.Node) string {
+	if n == nil {
+		return ""
+	}
+	return string(n.Render())
+}
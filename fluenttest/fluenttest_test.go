@@ -0,0 +1,133 @@
+package fluenttest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/h3"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+// recorder is a testing.TB capturing failures, so tests can check the
+// messages helpers report.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+	runtime.Goexit()
+}
+
+// run calls fn with a recorder and returns it once fn has finished.
+func run(fn func(t testing.TB)) *recorder {
+	r := &recorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r
+}
+
+func card(title string) node.Node {
+	return div.New(
+		h3.Text(title),
+		p.Text("Fresh  from\nthe oven"),
+		a.New().Href("/buy").Class("button").Text("Buy"),
+	).Class("card")
+}
+
+func TestSnapshot(t *testing.T) {
+	Snapshot(t, "cards/bread", card("Bread"))
+
+	r := run(func(t testing.TB) { Snapshot(t, "cards/bread", card("Cake")) })
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "-   <h3>Bread</h3>\n+   <h3>Cake</h3>") {
+		t.Errorf("mismatch reported as %q", r.errors)
+	}
+
+	r = run(func(t testing.TB) { Snapshot(t, "cards/missing", card("Bread")) })
+	if !r.fatal || !strings.Contains(r.errors[0], "-update") {
+		t.Errorf("missing golden file reported as %q", r.errors)
+	}
+}
+
+func TestSnapshotUpdate(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	*update = true
+	defer func() { *update = false }()
+
+	Snapshot(t, "new/card", card("Pie"))
+	got, err := os.ReadFile(filepath.Join(dir, "testdata", "new", "card.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "<h3>Pie</h3>") {
+		t.Errorf("golden file not written: %q", got)
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			"attributes sorted",
+			`<a class="x" href="/?a=1&amp;b=2" id="y">Go</a>`,
+			"<a class=\"x\" href=\"/?a=1&amp;b=2\" id=\"y\">Go</a>\n",
+		},
+		{
+			"nesting and whitespace",
+			"<ul>\n  <li> one   two </li><li><b>x</b> y</li>\n</ul>",
+			"<ul>\n  <li>one two</li>\n  <li>\n    <b>x</b>\n    y\n  </li>\n</ul>\n",
+		},
+		{
+			"void and empty",
+			`<div><input type="text" name="q"><br/><span></span></div>`,
+			"<div>\n  <input name=\"q\" type=\"text\" />\n  <br />\n  <span></span>\n</div>\n",
+		},
+		{
+			"preserved",
+			"<pre>  a\n  b</pre><script>if (a < b) {}</script>",
+			"<pre>  a\n  b</pre>\n<script>if (a < b) {}</script>\n",
+		},
+		{
+			"doctype and comment",
+			"<!DOCTYPE html><!-- note --><p>&lt;hi&gt;</p>",
+			"<!DOCTYPE html>\n<!-- note -->\n<p>&lt;hi&gt;</p>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalise(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	want := "a\nb\nc\nd\ne\nf\ng\n"
+	got := "a\nb\nc\nd\nE\nf\ng\n"
+	if d, exp := Diff(want, got), "...\n  c\n  d\n- e\n+ E\n  f\n  g\n"; d != exp {
+		t.Errorf("got %q, want %q", d, exp)
+	}
+}
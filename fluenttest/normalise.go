@@ -0,0 +1,143 @@
+package fluenttest
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/internal/myers"
+)
+
+// preserved lists elements whose text is kept exactly as written.
+var preserved = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// Normalise returns a canonical form of an HTML fragment: each element and
+// text run on its own line, indented by depth, attributes sorted by name and
+// escaped consistently, and runs of whitespace in text collapsed to a single
+// space, with text that is only whitespace dropped. Text inside pre,
+// textarea, script and style is kept as written. An element containing only
+// text is written on one line.
+//
+// Two fragments that differ only in attribute order or insignificant
+// whitespace normalise to the same string.
+func Normalise(src string) string {
+	var b strings.Builder
+	for _, c := range markup.Parse(src).Children {
+		write(&b, c, 0, false)
+	}
+	return b.String()
+}
+
+func write(b *strings.Builder, n *markup.Node, depth int, keep bool) {
+	indent := strings.Repeat("  ", depth)
+	switch n.Type {
+	case markup.TextNode:
+		text := n.Data
+		if !keep {
+			text = strings.Join(strings.Fields(text), " ")
+			if text == "" {
+				return
+			}
+		}
+		b.WriteString(indent + escapeText(n, text) + "\n")
+	case markup.CommentNode:
+		b.WriteString(indent + "<!--" + n.Data + "-->\n")
+	case markup.DoctypeNode:
+		b.WriteString(indent + "<!" + n.Data + ">\n")
+	case markup.ElementNode:
+		keep = keep || preserved[n.Tag]
+		if markup.IsVoid(n.Tag) {
+			b.WriteString(indent + startTag(n, " />") + "\n")
+			return
+		}
+		if len(n.Children) == 0 {
+			b.WriteString(indent + startTag(n, ">") + "</" + n.Tag + ">\n")
+			return
+		}
+		if len(n.Children) == 1 && n.Children[0].Type == markup.TextNode {
+			text := n.Children[0].Data
+			if !keep {
+				text = strings.Join(strings.Fields(text), " ")
+			}
+			b.WriteString(indent + startTag(n, ">") + escapeText(n.Children[0], text) + "</" + n.Tag + ">\n")
+			return
+		}
+		b.WriteString(indent + startTag(n, ">") + "\n")
+		for _, c := range n.Children {
+			write(b, c, depth+1, keep)
+		}
+		b.WriteString(indent + "</" + n.Tag + ">\n")
+	}
+}
+
+// startTag writes an element's start tag with its attributes sorted by name.
+func startTag(n *markup.Node, end string) string {
+	attrs := slices.Clone(n.Attrs)
+	slices.SortStableFunc(attrs, func(a, b markup.Attr) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	var b strings.Builder
+	b.WriteString("<" + n.Tag)
+	for _, a := range attrs {
+		fmt.Fprintf(&b, ` %s="%s"`, a.Key, html.EscapeString(a.Val))
+	}
+	b.WriteString(end)
+	return b.String()
+}
+
+// escapeText escapes text unless its parent is a raw-text element, whose
+// content is not unescaped when parsed.
+func escapeText(n *markup.Node, text string) string {
+	if n.Parent != nil && (n.Parent.Tag == "script" || n.Parent.Tag == "style") {
+		return text
+	}
+	return html.EscapeString(text)
+}
+
+// context is the number of unchanged lines Diff shows around each change.
+const context = 2
+
+// Diff returns a line diff of want and got, prefixing removed lines with
+// "- ", added lines with "+ " and unchanged lines around each change with
+// "  ". Runs of unchanged lines further from a change are elided with "...".
+func Diff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	edits := myers.Diff(a, b)
+
+	near := make([]bool, len(edits))
+	for i, e := range edits {
+		if e.Op == myers.Equal {
+			continue
+		}
+		for j := max(i-context, 0); j <= min(i+context, len(edits)-1); j++ {
+			near[j] = true
+		}
+	}
+
+	var out strings.Builder
+	elided := false
+	for i, e := range edits {
+		if !near[i] {
+			if !elided {
+				out.WriteString("...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		switch e.Op {
+		case myers.Equal:
+			out.WriteString("  " + a[e.A] + "\n")
+		case myers.Delete:
+			out.WriteString("- " + a[e.A] + "\n")
+		case myers.Insert:
+			out.WriteString("+ " + b[e.B] + "\n")
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,566 @@
+package form
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/dropdown"
+	"github.com/jpl-au/fluent/html5/fieldset"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/label"
+	"github.com/jpl-au/fluent/html5/legend"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/small"
+	"github.com/jpl-au/fluent/html5/textarea"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// Field is a single form field. Its exported fields may be adjusted before
+// rendering. It is a node rendering the label, control and help text wrapped
+// in <div class="field">.
+type Field struct {
+	// Name is the input name.
+	Name string
+	// ID overrides the generated ID of the control.
+	ID          string
+	Label       string
+	Widget      string
+	Placeholder string
+	Help        string
+	// Value is the current value as submitted, and Values the values of a
+	// Multiple select.
+	Value    string
+	Values   []string
+	Checked  bool
+	Multiple bool
+	Options  []Option
+
+	Required             bool
+	Min, Max, Step       string
+	MinLength, MaxLength int
+	Pattern              string
+
+	// Err is the validation error shown with the field, set by Form.Errors.
+	Err string
+
+	form *Form
+}
+
+// InputID returns the ID of the field's control.
+func (fl *Field) InputID() string {
+	if fl.ID != "" {
+		return fl.ID
+	}
+	prefix := "form"
+	if fl.form != nil {
+		prefix = fl.form.prefix
+	}
+	return prefix + "-" + strings.NewReplacer(".", "-", "[", "-", "]", "").Replace(fl.Name)
+}
+
+// helpID returns the ID of the help text.
+func (fl *Field) helpID() string {
+	return fl.InputID() + "-help"
+}
+
+// errorID returns the ID of the error message.
+func (fl *Field) errorID() string {
+	return fl.InputID() + "-error"
+}
+
+// Error returns the field's error message as a <p class="error-message">
+// linked to the control, or an empty node when the field has no error. The
+// field renders it after the control; call Error to place it elsewhere.
+func (fl *Field) Error() node.Node {
+	if fl.Err == "" {
+		return text.Static("")
+	}
+	return p.Text(fl.Err).ID(fl.errorID()).Class("error-message")
+}
+
+// LabelNode returns the field's <label>.
+func (fl *Field) LabelNode() node.Node {
+	return label.For(fl.InputID(), fl.Label)
+}
+
+// Control returns the field's input, textarea, select or radio group.
+func (fl *Field) Control() node.Node {
+	name := fl.Name
+	id := fl.InputID()
+	switch fl.Widget {
+	case "textarea":
+		el := textarea.Text(fl.Value).Name(name).ID(id)
+		if fl.Placeholder != "" {
+			el.Placeholder(fl.Placeholder)
+		}
+		if fl.Required {
+			el.Required()
+		}
+		if fl.MinLength > 0 {
+			el.MinLength(fl.MinLength)
+		}
+		if fl.MaxLength > 0 {
+			el.MaxLength(fl.MaxLength)
+		}
+		fl.describe(el)
+		return el
+	case "select":
+		el := dropdown.New(optionNodes(fl.Options, fl.selected)...).Name(name).ID(id)
+		if fl.Multiple {
+			el.Multiple()
+		}
+		if fl.Required {
+			el.Required()
+		}
+		fl.describe(el)
+		return el
+	case "radio":
+		return fl.radios()
+	}
+
+	el := widgets[fl.Widget](name).ID(id)
+	switch fl.Widget {
+	case "checkbox":
+		el.Value("true")
+		if fl.Checked {
+			el.Checked()
+		}
+	case "password":
+		// Passwords are never redisplayed.
+	default:
+		if fl.Value != "" {
+			el.Value(fl.Value)
+		}
+	}
+	if fl.Placeholder != "" {
+		el.Placeholder(fl.Placeholder)
+	}
+	if fl.Required {
+		el.Required()
+	}
+	if fl.Min != "" {
+		el.Min(fl.Min)
+	}
+	if fl.Max != "" {
+		el.Max(fl.Max)
+	}
+	if fl.Step != "" {
+		el.Step(fl.Step)
+	}
+	if fl.MinLength > 0 {
+		el.MinLength(fl.MinLength)
+	}
+	if fl.MaxLength > 0 {
+		el.MaxLength(fl.MaxLength)
+	}
+	if fl.Pattern != "" {
+		el.Pattern(fl.Pattern)
+	}
+	fl.describe(el)
+	return el
+}
+
+// describe links the control to the error message and help text, and marks
+// it invalid when the field has an error.
+func (fl *Field) describe(n node.Node) {
+	var ids []string
+	if fl.Err != "" {
+		n.SetAttribute("aria-invalid", "true")
+		ids = append(ids, fl.errorID())
+	}
+	if fl.Help != "" {
+		ids = append(ids, fl.helpID())
+	}
+	if len(ids) > 0 {
+		n.SetAttribute("aria-describedby", strings.Join(ids, " "))
+	}
+}
+
+func (fl *Field) selected(value string) bool {
+	if fl.Multiple {
+		for _, v := range fl.Values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	return value == fl.Value
+}
+
+// radios returns a radio group in a fieldset, with the field label as legend.
+func (fl *Field) radios() node.Node {
+	set := fieldset.New(legend.Text(fl.Label)).ID(fl.InputID())
+	fl.describe(set)
+	name := fl.Name
+	for i, o := range fl.Options {
+		id := fl.InputID() + "-" + strconv.Itoa(i)
+		el := input.Radio(name, o.Value).ID(id)
+		if o.Value == fl.Value {
+			el.Checked()
+		}
+		if fl.Required {
+			el.Required()
+		}
+		if o.Disabled {
+			el.Disabled()
+		}
+		if fl.Err != "" {
+			el.SetAttribute("aria-invalid", "true")
+		}
+		set.Add(div.New(el, label.For(id, o.Label)).Class("option"))
+	}
+	return set
+}
+
+// Render renders the field.
+func (fl *Field) Render(w ...io.Writer) []byte {
+	return node.Render(fl, w...)
+}
+
+// RenderBuilder writes the field wrapper with its label, control, error and
+// help. The wrapper of a field with an error also has the form's error class.
+func (fl *Field) RenderBuilder(buf *bytes.Buffer) {
+	if fl.Widget == "hidden" {
+		fl.Control().RenderBuilder(buf)
+		return
+	}
+	class := "field field-" + fl.Widget
+	if fl.Err != "" {
+		class += " " + fl.errorClass()
+	}
+	div.New(fl.Nodes()...).Class(class).RenderBuilder(buf)
+}
+
+func (fl *Field) errorClass() string {
+	if fl.form != nil {
+		return fl.form.errorClass
+	}
+	return DefaultErrorClass
+}
+
+// Nodes returns the label, control, error and help text in display order.
+func (fl *Field) Nodes() []node.Node {
+	var nodes []node.Node
+	switch fl.Widget {
+	case "hidden":
+		return []node.Node{fl.Control()}
+	case "radio":
+		nodes = []node.Node{fl.Control()}
+	case "checkbox":
+		nodes = []node.Node{fl.Control(), fl.LabelNode()}
+	default:
+		nodes = []node.Node{fl.LabelNode(), fl.Control()}
+	}
+	if fl.Err != "" {
+		nodes = append(nodes, fl.Error())
+	}
+	if fl.Help != "" {
+		nodes = append(nodes, small.Text(fl.Help).ID(fl.helpID()).Class("help"))
+	}
+	return nodes
+}
+
+// SetAttribute is a no-op; configure the field through its exported fields.
+func (fl *Field) SetAttribute(_ string, _ string) {
+	// Field does not support attributes
+}
+
+// widgets maps input widgets to their constructors.
+var widgets = map[string]func(name string) *input.Element{
+	"text":           func(n string) *input.Element { return input.Text(n, "") },
+	"checkbox":       func(n string) *input.Element { return input.Checkbox(n, "") },
+	"hidden":         func(n string) *input.Element { return input.Hidden(n, "") },
+	"email":          input.Email,
+	"password":       input.Password,
+	"number":         input.Number,
+	"range":          input.Range,
+	"date":           input.Date,
+	"time":           input.Time,
+	"datetime-local": input.DateTimeLocal,
+	"month":          input.Month,
+	"week":           input.Week,
+	"tel":            input.Tel,
+	"url":            input.URL,
+	"search":         input.Search,
+	"color":          input.Color,
+}
+
+// timeLayouts maps time widgets to their value formats.
+var timeLayouts = map[string]string{
+	"date":           "2006-01-02",
+	"time":           "15:04",
+	"datetime-local": "2006-01-02T15:04",
+	"month":          "2006-01",
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	enumType = reflect.TypeFor[Enum]()
+	textType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// fields reflects over v and builds its fields.
+func fields(v any, f *Form) []*Field {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("form: New requires a struct, got %T", v))
+	}
+	return appendFields(nil, rv, f)
+}
+
+func appendFields(out []*Field, rv reflect.Value, f *Form) []*Field {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("form")
+		if tag == "-" || !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		fv := rv.Field(i)
+		if sf.Anonymous && tag == "" && indirect(sf.Type).Kind() == reflect.Struct && indirect(sf.Type) != timeType {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			out = appendFields(out, fv, f)
+			continue
+		}
+		if fl := field(sf, fv, f); fl != nil {
+			out = append(out, fl)
+		}
+	}
+	return out
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// field builds the field for a struct field, or returns nil for types that
+// have no form representation.
+func field(sf reflect.StructField, fv reflect.Value, f *Form) *Field {
+	fl := &Field{
+		Name:        sf.Tag.Get("form"),
+		Label:       sf.Tag.Get("label"),
+		Widget:      sf.Tag.Get("widget"),
+		Placeholder: sf.Tag.Get("placeholder"),
+		Help:        sf.Tag.Get("help"),
+		form:        f,
+	}
+	if fl.Name == "" {
+		fl.Name = snake(sf.Name)
+	}
+	if fl.Label == "" {
+		fl.Label = words(sf.Name)
+	}
+	if opts := sf.Tag.Get("options"); opts != "" {
+		fl.Options = parseOptions(opts)
+	} else if sf.Type.Implements(enumType) {
+		fl.Options = reflect.Zero(sf.Type).Interface().(Enum).Options() //nolint:forcetypeassert // checked by Implements
+	}
+
+	t := indirect(sf.Type)
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv = reflect.Value{}
+		} else {
+			fv = fv.Elem()
+		}
+	}
+
+	switch {
+	case t == timeType:
+		if fl.Widget == "" {
+			fl.Widget = "datetime-local"
+		}
+		if fv.IsValid() {
+			if tm := fv.Interface().(time.Time); !tm.IsZero() { //nolint:forcetypeassert // type checked above
+				layout, ok := timeLayouts[fl.Widget]
+				if !ok {
+					layout = time.RFC3339
+				}
+				fl.Value = tm.Format(layout)
+			}
+		}
+	case t.Kind() == reflect.Bool:
+		if fl.Widget == "" {
+			fl.Widget = "checkbox"
+		}
+		fl.Checked = fv.IsValid() && fv.Bool()
+		fl.Value = strconv.FormatBool(fl.Checked)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		if fl.Options == nil {
+			return nil
+		}
+		fl.Multiple = true
+		if fv.IsValid() {
+			for j := range fv.Len() {
+				fl.Values = append(fl.Values, fv.Index(j).String())
+			}
+		}
+	default:
+		if !scalar(t) {
+			return nil
+		}
+		if fv.IsValid() {
+			fl.Value = format(fv)
+		}
+		switch {
+		case fl.Widget != "":
+		case fl.Options != nil:
+		case isInt(t.Kind()):
+			fl.Widget, fl.Step = "number", "1"
+		case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+			fl.Widget, fl.Step = "number", "any"
+		}
+	}
+	if fl.Widget == "" {
+		if fl.Options != nil {
+			fl.Widget = "select"
+		} else {
+			fl.Widget = "text"
+		}
+	}
+	if _, ok := widgets[fl.Widget]; !ok && fl.Widget != "textarea" && fl.Widget != "select" && fl.Widget != "radio" {
+		panic(fmt.Sprintf("form: unknown widget %q on field %s", fl.Widget, sf.Name))
+	}
+	validate(fl, sf.Tag.Get("validate"))
+	return fl
+}
+
+func scalar(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textType) || t.Implements(textType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isInt(t.Kind())
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
+
+// format returns the form value of a scalar.
+func format(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	}
+	return strconv.FormatUint(v.Uint(), 10)
+}
+
+// validate applies a validate tag.
+func validate(fl *Field, tag string) {
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "pattern=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+		key, val, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			fl.Required = true
+		case "min":
+			fl.Min = val
+		case "max":
+			fl.Max = val
+		case "step":
+			fl.Step = val
+		case "minlen":
+			fl.MinLength, _ = strconv.Atoi(val)
+		case "maxlen":
+			fl.MaxLength, _ = strconv.Atoi(val)
+		case "pattern":
+			fl.Pattern = val
+		}
+	}
+}
+
+// parseOptions parses "value,value:Label" option lists.
+func parseOptions(s string) []Option {
+	var out []Option
+	for _, item := range strings.Split(s, ",") {
+		value, lbl, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			lbl = value
+		}
+		out = append(out, Option{Value: value, Label: lbl})
+	}
+	return out
+}
+
+// snake converts a Go field name to snake_case: "FirstName" -> "first_name",
+// "HTTPPort" -> "http_port".
+func snake(s string) string {
+	return strings.ReplaceAll(strings.ToLower(split(s)), " ", "_")
+}
+
+// words converts a Go field name to a label: "FirstName" -> "First name".
+func words(s string) string {
+	w := strings.ToLower(split(s))
+	for i, part := range strings.Fields(w) {
+		// Keep acronyms such as URL upper case.
+		if orig := acronym(s, part); orig != "" {
+			w = strings.Replace(w, part, orig, 1)
+		} else if i == 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return w
+}
+
+// acronym returns part in its original upper case when it was an acronym in s.
+func acronym(s, part string) string {
+	up := strings.ToUpper(part)
+	if len(part) > 1 && strings.Contains(s, up) && !strings.Contains(s, strings.ToUpper(part[:1])+part[1:]) {
+		return up
+	}
+	return ""
+}
+
+// split separates the words of a camel case identifier with spaces.
+func split(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,209 @@
+// Package form generates HTML forms from Go structs.
+//
+// New reflects over a struct and builds a field for each exported field:
+// labels are wired to inputs through generated IDs, the input type follows
+// the Go type (checkbox for bool, number for integers and floats, a date or
+// time input for time.Time) and fields with options render as a select.
+// Current values are bound into the inputs, so a form rebuilt from a struct,
+// or given the submitted values with Values, redisplays what the user typed.
+//
+// Fields are configured with struct tags:
+//
+//	form:"email"             input name; "-" skips the field
+//	label:"Email address"    label text, derived from the field name by default
+//	widget:"textarea"        input type: text, email, password, textarea, select,
+//	                         radio, checkbox, hidden, number, range, date, time,
+//	                         datetime-local, month, week, tel, url, search, color
+//	placeholder:"you@example.com"
+//	help:"We never share it" description linked with aria-describedby
+//	options:"free,pro:Pro plan"  select options as value or value:label
+//	validate:"required,min=1,max=10,minlen=2,maxlen=50,pattern=[a-z]+"
+//
+// Validation rules become the matching HTML attributes; pattern must come
+// last as it may contain commas. A field whose type implements Enum takes its
+// options from the type.
+//
+// Usage:
+//
+//	type Signup struct {
+//	    Email    string    `validate:"required" widget:"email"`
+//	    Plan     string    `options:"free:Free,pro:Pro"`
+//	    Birthday time.Time `widget:"date"`
+//	    Agree    bool      `label:"I accept the terms" validate:"required"`
+//	}
+//
+//	form.New(&signup).Action("/signup").Submit("Create account")
+//
+// After a failed submission, Values redisplays the submitted input and Errors
+// shows each field's message next to it:
+//
+//	form.New(&signup).Values(r.PostForm).Errors(map[string]string{
+//	    "email": "Enter a valid email address",
+//	})
+//
+// Rendered with fluent.RenderContext under a context carrying a CSRF token
+// (see security.CSRF), the form includes the token as a hidden field.
+//
+// Redirect carries a return address, such as the page that sent the user to
+// a login form, validated and signed by a security.Redirects.
+//
+// Options, Grouped and MapOptions build <option> lists from application data
+// for hand-written selects, and Choices sets a field's options from the same.
+package form
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+
+	"github.com/jpl-au/fluent/html5/attr/method"
+	"github.com/jpl-au/fluent/html5/button"
+	elem "github.com/jpl-au/fluent/html5/form"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/security"
+)
+
+// DefaultErrorClass is the class added to the wrapper of a field with an error.
+const DefaultErrorClass = "has-error"
+
+// Form is a form generated from a struct. It is a node.
+type Form struct {
+	action     string
+	method     method.Method
+	prefix     string
+	submit     string
+	errorClass string
+	fields     []*Field
+	redirects  *security.Redirects
+	next       string
+}
+
+// New builds a form from v, a struct or pointer to a struct. It panics if v
+// is not a struct, as that is a programming error.
+func New(v any) *Form {
+	f := &Form{method: method.Post, prefix: "form", errorClass: DefaultErrorClass}
+	f.fields = fields(v, f)
+	return f
+}
+
+// Action sets the URL the form submits to.
+func (f *Form) Action(url string) *Form {
+	f.action = url
+	return f
+}
+
+// Redirect carries target through the form as the hidden, signed fields of
+// r, and checks the form's action against r. An unsafe action is dropped, so
+// the form submits to the page it is on; an unsafe target is left out.
+func (f *Form) Redirect(r *security.Redirects, target string) *Form {
+	f.redirects = r
+	f.next = target
+	return f
+}
+
+// Method sets the submission method. The default is method.Post.
+func (f *Form) Method(m method.Method) *Form {
+	f.method = m
+	return f
+}
+
+// Prefix sets the prefix of generated IDs, so several forms can share a page.
+// The default is "form", giving IDs such as "form-email".
+func (f *Form) Prefix(prefix string) *Form {
+	f.prefix = prefix
+	return f
+}
+
+// Submit adds a submit button with the given label.
+func (f *Form) Submit(label string) *Form {
+	f.submit = label
+	return f
+}
+
+// Values binds submitted values, such as r.PostForm, over the struct's values,
+// so input that could not be decoded into the struct is still redisplayed.
+func (f *Form) Values(values url.Values) *Form {
+	for _, fl := range f.fields {
+		vals, ok := values[fl.Name]
+		switch {
+		case fl.Widget == "checkbox":
+			fl.Checked = ok && len(vals) > 0 && vals[0] != ""
+		case !ok:
+		case fl.Multiple:
+			fl.Values = vals
+		default:
+			fl.Value = vals[0]
+		}
+	}
+	return f
+}
+
+// Errors binds validation errors, keyed by input name, to the fields. A field
+// with an error renders the message after its control, is marked with
+// aria-invalid and aria-describedby, and its wrapper gets the error class.
+// Errors for unknown names are ignored.
+func (f *Form) Errors(errs map[string]string) *Form {
+	for _, fl := range f.fields {
+		fl.Err = errs[fl.Name]
+	}
+	return f
+}
+
+// ErrorClass sets the class added to the wrapper of a field with an error,
+// such as "is-invalid". The default is DefaultErrorClass.
+func (f *Form) ErrorClass(class string) *Form {
+	f.errorClass = class
+	return f
+}
+
+// Field returns the field with the given input name, or nil.
+func (f *Form) Field(name string) *Field {
+	for _, fl := range f.fields {
+		if fl.Name == name {
+			return fl
+		}
+	}
+	return nil
+}
+
+// Fields returns the fields in struct order.
+func (f *Form) Fields() []*Field {
+	return f.fields
+}
+
+// Render renders the form.
+func (f *Form) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+// RenderBuilder writes the form element, its fields and the submit button.
+func (f *Form) RenderBuilder(buf *bytes.Buffer) {
+	el := elem.New(f.Nodes()...).Method(f.method)
+	if f.action != "" && (f.redirects == nil || f.redirects.Check(f.action) == nil) {
+		el.Action(f.action)
+	}
+	el.RenderBuilder(buf)
+}
+
+// Nodes returns the CSRF field, the redirect fields, the fields and the
+// submit button. The CSRF field renders the token carried by the render
+// context, if any.
+func (f *Form) Nodes() []node.Node {
+	nodes := make([]node.Node, 0, len(f.fields)+3)
+	nodes = append(nodes, security.CSRFField())
+	if f.redirects != nil {
+		nodes = append(nodes, f.redirects.Hidden(f.next))
+	}
+	for _, fl := range f.fields {
+		nodes = append(nodes, fl)
+	}
+	if f.submit != "" {
+		nodes = append(nodes, button.Submit(f.submit))
+	}
+	return nodes
+}
+
+// SetAttribute is a no-op; configure the form through its methods.
+func (f *Form) SetAttribute(_ string, _ string) {
+	// Form does not support attributes
+}
@@ -0,0 +1,254 @@
+package form
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/attr/method"
+	"github.com/jpl-au/fluent/security"
+)
+
+type plan string
+
+func (plan) Options() []Option {
+	return []Option{
+		{Value: "free", Label: "Free", Group: "Personal"},
+		{Value: "pro", Label: "Pro", Group: "Personal"},
+		{Value: "team", Label: "Team"},
+	}
+}
+
+type Base struct {
+	ID int `widget:"hidden"`
+}
+
+type signup struct {
+	Base
+	FirstName string `validate:"required,minlen=2,pattern=[a-z,]+"`
+	Email     string `widget:"email" placeholder:"you@example.com" help:"We never share it"`
+	Password  string `widget:"password"`
+	Age       int    `validate:"min=18"`
+	Score     float64
+	Agree     bool      `label:"I accept the terms"`
+	Birthday  time.Time `widget:"date"`
+	Plan      plan
+	Tags      []string `options:"go,js:JavaScript"`
+	Size      string   `widget:"radio" options:"s:Small,m:Medium"`
+	Bio       string   `widget:"textarea"`
+	Internal  string   `form:"-"`
+	Homepage  *string  `form:"site" label:"Website" widget:"url"`
+	secret    string
+}
+
+func TestFieldDefaults(t *testing.T) {
+	f := New(signup{})
+	tests := []struct {
+		name, label, widget string
+	}{
+		{"id", "ID", "hidden"},
+		{"first_name", "First name", "text"},
+		{"email", "Email", "email"},
+		{"password", "Password", "password"},
+		{"age", "Age", "number"},
+		{"score", "Score", "number"},
+		{"agree", "I accept the terms", "checkbox"},
+		{"birthday", "Birthday", "date"},
+		{"plan", "Plan", "select"},
+		{"tags", "Tags", "select"},
+		{"size", "Size", "radio"},
+		{"bio", "Bio", "textarea"},
+		{"site", "Website", "url"},
+	}
+	if got := len(f.Fields()); got != len(tests) {
+		t.Fatalf("got %d fields, want %d", got, len(tests))
+	}
+	for i, tt := range tests {
+		fl := f.Fields()[i]
+		if fl.Name != tt.name || fl.Label != tt.label || fl.Widget != tt.widget {
+			t.Errorf("field %d: got %q/%q/%q, want %q/%q/%q", i, fl.Name, fl.Label, fl.Widget, tt.name, tt.label, tt.widget)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	site := "https://example.com/?a=1&b=2"
+	f := New(&signup{
+		Base:      Base{ID: 7},
+		FirstName: `<b>"x"</b>`,
+		Password:  "hunter2",
+		Age:       30,
+		Agree:     true,
+		Birthday:  time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC),
+		Plan:      "pro",
+		Tags:      []string{"js"},
+		Size:      "m",
+		Homepage:  &site,
+	}).Action("/signup?next=/a&b").Submit("Create account")
+	out := string(f.Render())
+
+	tests := []struct {
+		name, want string
+	}{
+		{"form", `<form action="/signup?next=/a&amp;b" method="post">`},
+		{"hidden", `<input name="id" value="7" type="hidden" id="form-id" />`},
+		{"label", `<label for="form-first_name">First name</label>`},
+		{"escaped value", `value="&lt;b&gt;&#34;x&#34;&lt;/b&gt;"`},
+		{"validation", `required="required" id="form-first_name" minlength="2" pattern="[a-z,]+"`},
+		{"placeholder", `placeholder="you@example.com"`},
+		{"help", `aria-describedby="form-email-help" />` + `<small class="help" id="form-email-help">We never share it</small>`},
+		{"password", `<input name="password" type="password" id="form-password" />`},
+		{"number", `<input name="age" value="30" type="number" id="form-age" min="18" step="1" />`},
+		{"float", `step="any"`},
+		{"checkbox", `<input name="agree" value="true" type="checkbox" checked="checked" id="form-agree" /><label for="form-agree">I accept the terms</label>`},
+		{"date", `value="1990-04-01" type="date"`},
+		{"optgroup", `<optgroup label="Personal"><option value="free">Free</option><option value="pro" selected="selected">Pro</option></optgroup><option value="team">Team</option>`},
+		{"multiple", `<select name="tags" multiple="multiple" id="form-tags"><option value="go">go</option><option value="js" selected="selected">JavaScript</option></select>`},
+		{"radio", `<input name="size" value="m" type="radio" checked="checked" id="form-size-1" /><label for="form-size-1">Medium</label>`},
+		{"legend", `<fieldset id="form-size"><legend>Size</legend>`},
+		{"pointer", `value="https://example.com/?a=1&amp;b=2"`},
+		{"submit", `<button type="submit">Create account</button></form>`},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s: %q not found in %q", tt.name, tt.want, out)
+		}
+	}
+	for _, bad := range []string{"hunter2", "internal", "secret"} {
+		if strings.Contains(out, bad) {
+			t.Errorf("output contains %q", bad)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	f := New(signup{Age: 30, Agree: true}).Values(url.Values{
+		"age":   {"abc"},
+		"tags":  {"go", "js"},
+		"email": {"a@b"},
+	})
+	if got := f.Field("age").Value; got != "abc" {
+		t.Errorf("age: got %q, want %q", got, "abc")
+	}
+	if got := f.Field("email").Value; got != "a@b" {
+		t.Errorf("email: got %q, want %q", got, "a@b")
+	}
+	if got := f.Field("tags").Values; len(got) != 2 {
+		t.Errorf("tags: got %q, want 2 values", got)
+	}
+	if f.Field("agree").Checked {
+		t.Error("agree: unchecked box was not cleared")
+	}
+	if f.Field("missing") != nil {
+		t.Error("Field returned a field for an unknown name")
+	}
+}
+
+func TestPrefixAndMethod(t *testing.T) {
+	out := string(New(struct{ Query string }{}).Prefix("search").Method(method.Get).Render())
+	want := `<form method="get"><div class="field field-text"><label for="search-query">Query</label><input name="query" type="text" id="search-query" /></div></form>`
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		in, label, name string
+	}{
+		{"FirstName", "First name", "first_name"},
+		{"HTTPPort", "HTTP port", "http_port"},
+		{"UserID", "User ID", "user_id"},
+		{"Email", "Email", "email"},
+	}
+	for _, tt := range tests {
+		if got := words(tt.in); got != tt.label {
+			t.Errorf("words(%q): got %q, want %q", tt.in, got, tt.label)
+		}
+		if got := snake(tt.in); got != tt.name {
+			t.Errorf("snake(%q): got %q, want %q", tt.in, got, tt.name)
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New did not panic on a non-struct")
+		}
+	}()
+	New("nope")
+}
+
+func TestCSRFField(t *testing.T) {
+	ctx := security.WithCSRFToken(context.Background(), "tok")
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, New(struct{}{}), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<form method="post"><input name="csrf_token" value="tok" type="hidden" /></form>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	r := security.NewRedirects(nil)
+	got := string(New(struct{}{}).Action("/login").Redirect(r, "/account").Render())
+	if want := `<form action="/login" method="post"><input name="next" value="/account" type="hidden" /></form>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = string(New(struct{}{}).Action("https://evil.example/").Redirect(r, "//evil.example").Render())
+	if want := `<form method="post"></form>`; got != want {
+		t.Errorf("unsafe: got %q, want %q", got, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	type account struct {
+		Email string `widget:"email" help:"Work address"`
+		Name  string
+		Size  string `widget:"radio" options:"s,m"`
+	}
+	f := New(account{}).Errors(map[string]string{
+		"email": "Enter a <valid> email",
+		"size":  "Pick a size",
+		"other": "ignored",
+	})
+
+	out := string(f.Field("email").Render())
+	want := `<div class="field field-email has-error"><label for="form-email">Email</label>` +
+		`<input name="email" type="email" id="form-email" aria-invalid="true" aria-describedby="form-email-error form-email-help" />` +
+		`<p class="error-message" id="form-email-error">Enter a &lt;valid&gt; email</p>` +
+		`<small class="help" id="form-email-help">Work address</small></div>`
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	if out := string(f.Field("name").Render()); strings.Contains(out, "aria-invalid") || strings.Contains(out, "has-error") {
+		t.Errorf("field without error marked invalid: %q", out)
+	}
+	if got := string(f.Field("name").Error().Render()); got != "" {
+		t.Errorf("Error() without error: got %q", got)
+	}
+
+	out = string(f.ErrorClass("is-invalid").Field("size").Render())
+	for _, want := range []string{
+		`class="field field-radio is-invalid"`,
+		`<fieldset id="form-size" aria-invalid="true" aria-describedby="form-size-error">`,
+		`type="radio" id="form-size-0" aria-invalid="true"`,
+		`<p class="error-message" id="form-size-error">Pick a size</p>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q not found in %q", want, out)
+		}
+	}
+
+	f.Errors(nil)
+	if f.Field("email").Err != "" {
+		t.Error("Errors(nil) did not clear errors")
+	}
+}
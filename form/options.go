@@ -0,0 +1,101 @@
+package form
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/jpl-au/fluent/html5/optgroup"
+	"github.com/jpl-au/fluent/html5/option"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Option is a choice of a select or radio field.
+type Option struct {
+	Value string
+	Label string
+	// Group places the option in an <optgroup> of that label.
+	Group    string
+	Disabled bool
+}
+
+// Enum is implemented by types with a fixed set of values. Fields of such a
+// type render as a select of its options.
+type Enum interface {
+	Options() []Option
+}
+
+// Choices converts items to options, for setting a Field's Options from
+// application data.
+func Choices[T any](items []T, value func(T) string, label func(T) string) []Option {
+	out := make([]Option, len(items))
+	for i, item := range items {
+		out[i] = Option{Value: value(item), Label: label(item)}
+	}
+	return out
+}
+
+// Options returns the <option> elements for items, selecting the one whose
+// value is selected. Values and labels are escaped.
+//
+// Usage:
+//
+//	dropdown.New(form.Options(countries, Country.Code, Country.Name, user.Country)...).Name("country")
+func Options[T any](items []T, value func(T) string, label func(T) string, selected string) []node.Node {
+	return optionNodes(Choices(items, value, label), equal(selected))
+}
+
+// Grouped is Options with items placed in an <optgroup> labelled by group.
+// Groups appear in the order they are first seen; items with an empty group
+// are left ungrouped.
+func Grouped[T any](items []T, group func(T) string, value func(T) string, label func(T) string, selected string) []node.Node {
+	opts := Choices(items, value, label)
+	for i, item := range items {
+		opts[i].Group = group(item)
+	}
+	return optionNodes(opts, equal(selected))
+}
+
+// MapOptions returns the <option> elements for a map of values to labels,
+// ordered by label.
+func MapOptions(m map[string]string, selected string) []node.Node {
+	opts := make([]Option, 0, len(m))
+	for v, l := range m {
+		opts = append(opts, Option{Value: v, Label: l})
+	}
+	slices.SortFunc(opts, func(a, b Option) int {
+		return cmp.Or(cmp.Compare(a.Label, b.Label), cmp.Compare(a.Value, b.Value))
+	})
+	return optionNodes(opts, equal(selected))
+}
+
+func equal(selected string) func(string) bool {
+	return func(v string) bool { return v == selected }
+}
+
+// optionNodes builds <option> elements, gathering options that share a Group
+// into an <optgroup> placed where the group first appears.
+func optionNodes(opts []Option, selected func(string) bool) []node.Node {
+	out := make([]node.Node, 0, len(opts))
+	groups := map[string]*optgroup.Element{}
+	for _, o := range opts {
+		el := option.Option(o.Value, o.Label)
+		if selected(o.Value) {
+			el.Selected()
+		}
+		if o.Disabled {
+			el.Disabled()
+		}
+		if o.Group == "" {
+			out = append(out, el)
+			continue
+		}
+		if g, ok := groups[o.Group]; ok {
+			g.Add(el)
+			continue
+		}
+		g := optgroup.New(el).Label(o.Group)
+		groups[o.Group] = g
+		out = append(out, g)
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/dropdown"
+	"github.com/jpl-au/fluent/node"
+)
+
+type country struct {
+	Code, Name, Region string
+}
+
+func (c country) code() string   { return c.Code }
+func (c country) name() string   { return c.Name }
+func (c country) region() string { return c.Region }
+
+var countries = []country{
+	{"au", "Australia", "Oceania"},
+	{"fr", "France", "Europe"},
+	{"nz", "New Zealand", "Oceania"},
+	{"x\"y", "<Other>", ""},
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []node.Node
+		want  string
+	}{
+		{
+			"slice",
+			Options(countries[:2], country.code, country.name, "fr"),
+			`<select><option value="au">Australia</option><option value="fr" selected="selected">France</option></select>`,
+		},
+		{
+			"escaped",
+			Options(countries[3:], country.code, country.name, `x"y`),
+			`<select><option value="x&#34;y" selected="selected">&lt;Other&gt;</option></select>`,
+		},
+		{
+			"grouped",
+			Grouped(countries, country.region, country.code, country.name, "nz"),
+			`<select><optgroup label="Oceania"><option value="au">Australia</option>` +
+				`<option value="nz" selected="selected">New Zealand</option></optgroup>` +
+				`<optgroup label="Europe"><option value="fr">France</option></optgroup>` +
+				`<option value="x&#34;y">&lt;Other&gt;</option></select>`,
+		},
+		{
+			"map",
+			MapOptions(map[string]string{"b": "Beta", "a": "Alpha", "c": "Gamma"}, "c"),
+			`<select><option value="a">Alpha</option><option value="b">Beta</option><option value="c" selected="selected">Gamma</option></select>`,
+		},
+		{
+			"none selected",
+			Options([]string{"one", "two"}, func(s string) string { return s }, func(s string) string { return s }, ""),
+			`<select><option value="one">one</option><option value="two">two</option></select>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(dropdown.New(tt.nodes...).Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChoices(t *testing.T) {
+	type profile struct {
+		Country string
+	}
+	f := New(profile{Country: "nz"})
+	f.Field("country").Widget = "select"
+	f.Field("country").Options = Choices(countries[:3], country.code, country.name)
+	want := `<select name="country" id="form-country"><option value="au">Australia</option>` +
+		`<option value="fr">France</option><option value="nz" selected="selected">New Zealand</option></select>`
+	if got := string(f.Field("country").Control().Render()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
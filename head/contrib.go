@@ -0,0 +1,132 @@
+package head
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jpl-au/fluent/html5/attr/rel"
+	"github.com/jpl-au/fluent/html5/link"
+	"github.com/jpl-au/fluent/html5/meta"
+	"github.com/jpl-au/fluent/html5/title"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/seo"
+)
+
+// Contribution adds entries to the Manager of the render it is part of and
+// renders nothing itself. Without a Manager in the context it is a no-op.
+type Contribution struct {
+	entries []Entry
+}
+
+// Add returns a contribution of n under key with the given priority.
+func Add(key string, priority int, n node.Node) *Contribution {
+	return &Contribution{entries: []Entry{{Key: key, Priority: priority, Node: n}}}
+}
+
+// Title contributes the document <title>.
+func Title(s string) *Contribution {
+	return Add("title", High, title.Text(s))
+}
+
+// Meta contributes <meta name content>, keyed by name.
+func Meta(name, content string) *Contribution {
+	return Add("meta:"+name, Normal, meta.New().Name(name).Content(content))
+}
+
+// Canonical contributes <link rel="canonical">. Only one is kept per page.
+func Canonical(href string) *Contribution {
+	return Add("link:canonical", High, link.New().Rel(rel.Canonical).Href(href))
+}
+
+// Link contributes <link rel href>, keyed by both so distinct links with the
+// same relation are all kept.
+func Link(r rel.Rel, href string) *Contribution {
+	return Add("link:"+string(r)+":"+href, Low, link.New().Rel(r).Href(href))
+}
+
+// Social contributes the tags of an Open Graph or Twitter card builder, keyed
+// by property so a page's tags replace the layout's defaults one for one.
+func Social(m *seo.Meta) *Contribution {
+	c := &Contribution{}
+	for _, t := range m.Tags() {
+		c.entries = append(c.entries, Entry{Key: "meta:" + t.Key, Priority: Normal, Node: t.Node})
+	}
+	return c
+}
+
+// Priority overrides the priority of every entry in the contribution.
+func (c *Contribution) Priority(p int) *Contribution {
+	for i := range c.entries {
+		c.entries[i].Priority = p
+	}
+	return c
+}
+
+// Render registers the contribution. It produces no output.
+func (c *Contribution) Render(w ...io.Writer) []byte {
+	return node.Render(c, w...)
+}
+
+// RenderBuilder registers the entries with the Manager in the render context.
+func (c *Contribution) RenderBuilder(buf *bytes.Buffer) {
+	m := From(node.Context(buf))
+	if m == nil {
+		return
+	}
+	for _, e := range c.entries {
+		m.Add(e.Key, e.Priority, e.Node)
+	}
+}
+
+// Nodes returns an empty slice as the entries render in the head, not in place.
+func (c *Contribution) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the contribution depends on the render context.
+func (c *Contribution) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for Contribution as it does not have attributes.
+func (c *Contribution) SetAttribute(_ string, _ string) {
+	// Contribution does not support attributes
+}
+
+// outlet marks where the collected head is written.
+type outlet struct{}
+
+// Outlet marks where the collected entries are written, normally at the end
+// of the layout's <head>. Without a Manager in the context it renders nothing.
+func Outlet() node.Node {
+	return outlet{}
+}
+
+// Render renders the outlet on its own. Entries are only collected during a
+// render with a Manager, so outside one it produces no output.
+func (o outlet) Render(w ...io.Writer) []byte {
+	return node.Render(o, w...)
+}
+
+// RenderBuilder defers writing the Manager's entries until the render
+// completes, so contributions made after the outlet are included.
+func (o outlet) RenderBuilder(buf *bytes.Buffer) {
+	if m := From(node.Context(buf)); m != nil {
+		node.Defer(buf, m.RenderBuilder)
+	}
+}
+
+// Nodes returns an empty slice as the outlet has no children.
+func (o outlet) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the output depends on the render context.
+func (o outlet) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for outlet as it does not have attributes.
+func (o outlet) SetAttribute(_ string, _ string) {
+	// outlet does not support attributes
+}
@@ -0,0 +1,158 @@
+// Package head lets components anywhere in a tree contribute <title>, <meta>
+// and <link> tags to the document head.
+//
+// A Manager carried in the render context collects contributions while the
+// page renders. Each contribution has a key, and only one entry per key is
+// kept, so a page component can override the layout's title or canonical
+// link. Entries are ordered by priority, then by first contribution. Outlet
+// writes them once the render completes (see node.Defer).
+//
+// Usage:
+//
+//	layout := html.New(
+//	    htmlhead.New(meta.UTF8(), head.Outlet()),
+//	    body.New(content),
+//	)
+//
+//	// deep inside content:
+//	head.Title("Invoice INV-0042")
+//	head.Canonical("https://acme.example/invoices/42")
+//	head.Alternate("fr", frURL)
+//	head.Social(seo.OpenGraph(title, summary, cover, url, seo.Article))
+//
+//	err := head.Render(r.Context(), layout, w)
+package head
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Priorities for common entries. Higher priorities render first.
+const (
+	Critical = 100 // charset, viewport, base
+	High     = 50  // title, canonical
+	Normal   = 0   // description, social tags
+	Low      = -50 // preloads, alternates
+)
+
+// Entry is a single head contribution.
+type Entry struct {
+	Key      string
+	Priority int
+	Node     node.Node
+	seq      int
+}
+
+// Manager collects head entries during a render. It is safe for concurrent use.
+type Manager struct {
+	mu      sync.Mutex
+	entries map[string]*Entry
+	seq     int
+}
+
+// New creates an empty Manager.
+func New() *Manager {
+	return &Manager{entries: make(map[string]*Entry)}
+}
+
+// Add contributes n under key. If key is already present, the entry with the
+// higher priority is kept; on a tie the later contribution replaces the
+// earlier one but keeps its position.
+func (m *Manager) Add(key string, priority int, n node.Node) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.entries[key]; ok {
+		if priority >= e.Priority {
+			e.Priority = priority
+			e.Node = n
+		}
+		return
+	}
+	m.seq++
+	m.entries[key] = &Entry{Key: key, Priority: priority, Node: n, seq: m.seq}
+}
+
+// Entries returns the entries in render order.
+func (m *Manager) Entries() []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		out = append(out, *e)
+	}
+	slices.SortFunc(out, func(a, b Entry) int {
+		if a.Priority != b.Priority {
+			return b.Priority - a.Priority
+		}
+		return a.seq - b.seq
+	})
+	return out
+}
+
+// Len returns the number of entries.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Render generates the HTML representation of the entries.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (m *Manager) Render(w ...io.Writer) []byte {
+	return node.Render(m, w...)
+}
+
+// RenderBuilder writes the entries, in order, directly to a buffer.
+func (m *Manager) RenderBuilder(buf *bytes.Buffer) {
+	for _, e := range m.Entries() {
+		if e.Node != nil {
+			e.Node.RenderBuilder(buf)
+		}
+	}
+}
+
+// Nodes returns the entry nodes in render order.
+func (m *Manager) Nodes() []node.Node {
+	entries := m.Entries()
+	nodes := make([]node.Node, len(entries))
+	for i, e := range entries {
+		nodes[i] = e.Node
+	}
+	return nodes
+}
+
+// SetAttribute is a no-op for Manager as it does not have attributes.
+func (m *Manager) SetAttribute(_ string, _ string) {
+	// Manager does not support attributes
+}
+
+type managerKey struct{}
+
+// WithManager returns a context whose renders contribute to m.
+func WithManager(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, managerKey{}, m)
+}
+
+// From returns the Manager carried by ctx, or nil.
+func From(ctx context.Context) *Manager {
+	m, _ := ctx.Value(managerKey{}).(*Manager)
+	return m
+}
+
+// Render renders n to w through fluent.RenderContext with a Manager in the
+// context, so Outlet writes the collected entries. If ctx already carries a
+// Manager it is used, so defaults can be seeded before rendering.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) error {
+	if From(ctx) == nil {
+		ctx = WithManager(ctx, New())
+	}
+	return fluent.RenderContext(ctx, n, w, policy...)
+}
@@ -0,0 +1,87 @@
+package head
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/attr/rel"
+	"github.com/jpl-au/fluent/html5/body"
+	htmlhead "github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/meta"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/seo"
+)
+
+func page(content ...node.Node) node.Node {
+	return html.New(
+		htmlhead.New(meta.UTF8(), Outlet()),
+		body.New(append([]node.Node{Title("Site"), Canonical("/")}, content...)...),
+	)
+}
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(context.Background(), page(
+		p.Text("hello"),
+		Link(rel.Alternate, "/feed.xml"),
+		Meta("description", `Say "hi"`),
+		Title("Post & Comments"),
+		Canonical("/post?id=1&x=2"),
+	), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<!DOCTYPE html><html><head><meta charset="UTF-8" />` +
+		`<title>Post &amp; Comments</title>` +
+		`<link rel="canonical" href="/post?id=1&amp;x=2" />` +
+		`<meta name="description" content="Say &#34;hi&#34;" />` +
+		`<link rel="alternate" href="/feed.xml" />` +
+		`</head><body><p>hello</p></body></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestManagerPriority(t *testing.T) {
+	m := New()
+	m.Add("title", Low, p.Static("low"))
+	m.Add("title", High, p.Static("high"))
+	m.Add("title", Normal, p.Static("ignored"))
+	m.Add("a", Normal, p.Static("a"))
+	m.Add("b", Normal, p.Static("b"))
+	m.Add("a", Normal, p.Static("a2"))
+
+	if got := string(m.Render()); got != "<p>high</p><p>a2</p><p>b</p>" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSocial(t *testing.T) {
+	m := New()
+	ctx := WithManager(context.Background(), m)
+	var buf bytes.Buffer
+	err := Render(ctx, page(
+		Social(seo.OpenGraph("Default", "", "", "", seo.Website)),
+		Social(seo.OpenGraph("Post", "", "", "", seo.Article)),
+	), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 4 {
+		t.Errorf("got %d entries, want 4", m.Len())
+	}
+	want := `<meta content="Post" property="og:title" /><meta content="article" property="og:type" />`
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("missing %s in\n%s", want, buf.String())
+	}
+}
+
+func TestWithoutManager(t *testing.T) {
+	if got := string(page(p.Text("x")).Render()); got != `<!DOCTYPE html><html><head><meta charset="UTF-8" /></head><body><p>x</p></body></html>` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package head
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jpl-au/fluent/html5/attr/rel"
+	"github.com/jpl-au/fluent/html5/link"
+)
+
+// TrackingParams lists the query parameters Normalize removes. A name
+// ending in "*" matches every parameter starting with the rest of it. Set
+// it during initialisation, before pages render.
+var TrackingParams = []string{"utm_*", "gclid", "dclid", "fbclid", "msclkid", "mc_cid", "mc_eid", "_ga", "_gl"}
+
+// Normalize returns u as a canonical URL string: the scheme and host are
+// lowercased, default ports, the fragment and the parameters in
+// TrackingParams are removed, the remaining parameters are sorted by name
+// and an empty path becomes "/". u itself is not modified.
+func Normalize(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	n := *u
+	n.User = nil
+	n.Fragment, n.RawFragment = "", ""
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	if port := n.Port(); port == "80" && n.Scheme == "http" || port == "443" && n.Scheme == "https" {
+		n.Host = strings.TrimSuffix(n.Host, ":"+port)
+	}
+	if n.Path == "" && n.Host != "" {
+		n.Path, n.RawPath = "/", ""
+	}
+	q := n.Query()
+	for name := range q {
+		if tracking(name) {
+			q.Del(name)
+		}
+	}
+	n.RawQuery = q.Encode()
+	n.ForceQuery = false
+	return n.String()
+}
+
+// tracking reports whether name is listed in TrackingParams.
+func tracking(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range TrackingParams {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalURL contributes <link rel="canonical"> for u, normalised with
+// Normalize. Only one canonical link is kept per page, as with Canonical.
+//
+// Usage:
+//
+//	head.CanonicalURL(r.URL)
+func CanonicalURL(u *url.URL) *Contribution {
+	return Canonical(Normalize(u))
+}
+
+// Alternate contributes <link rel="alternate" hreflang href> naming the
+// version of the page in lang, such as "en-GB" or "x-default". One link is
+// kept per language.
+func Alternate(lang string, u *url.URL) *Contribution {
+	return Add("link:alternate:"+strings.ToLower(lang), Low,
+		link.New().Rel(rel.Alternate).HrefLang(lang).Href(Normalize(u)))
+}
+
+// Prev contributes <link rel="prev"> to the previous page of a paginated
+// sequence. Only one is kept per page.
+func Prev(u *url.URL) *Contribution {
+	return Add("link:prev", Low, link.New().Rel(rel.Prev).Href(Normalize(u)))
+}
+
+// Next contributes <link rel="next"> to the next page of a paginated
+// sequence. Only one is kept per page.
+func Next(u *url.URL) *Contribution {
+	return Add("link:next", Low, link.New().Rel(rel.Next).Href(Normalize(u)))
+}
@@ -0,0 +1,86 @@
+package head
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	htmlhead "github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"HTTPS://Example.COM:443?b=2&a=1#top", "https://example.com/?a=1&b=2"},
+		{"http://example.com:80/p?utm_source=x&UTM_Medium=y&gclid=1&id=7", "http://example.com/p?id=7"},
+		{"http://example.com:8080/p?", "http://example.com:8080/p"},
+		{"https://user:pw@example.com/p", "https://example.com/p"},
+		{"/docs/a b?fbclid=1", "/docs/a%20b"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Normalize(u); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if u.String() == tt.want && tt.in != tt.want {
+			t.Errorf("Normalize(%q) modified its argument", tt.in)
+		}
+	}
+	if got := Normalize(nil); got != "" {
+		t.Errorf("Normalize(nil) = %q, want empty", got)
+	}
+}
+
+func TestNormalizeTrackingParams(t *testing.T) {
+	defer func(p []string) { TrackingParams = p }(TrackingParams)
+	TrackingParams = []string{"ref", "session*"}
+
+	u, _ := url.Parse("https://example.com/?ref=a&sessionid=b&utm_source=c")
+	if got, want := Normalize(u), "https://example.com/?utm_source=c"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestURLContributions(t *testing.T) {
+	parse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	doc := html.New(htmlhead.New(Outlet()), node.FuncNodes(func() []node.Node {
+		return []node.Node{
+			CanonicalURL(parse("https://example.com/list?page=1&utm_campaign=x")),
+			CanonicalURL(parse("https://example.com/list?page=2&utm_campaign=x")),
+			Alternate("en", parse("https://example.com/en/list")),
+			Alternate("fr", parse("https://example.com/fr/list?a=1&b=2")),
+			Alternate("EN", parse("https://example.com/en-gb/list")),
+			Prev(parse("https://example.com/list?page=1")),
+			Next(parse("https://example.com/list?page=3")),
+		}
+	}))
+
+	var buf bytes.Buffer
+	if err := Render(context.Background(), doc, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<!DOCTYPE html><html><head>` +
+		`<link rel="canonical" href="https://example.com/list?page=2" />` +
+		`<link rel="alternate" href="https://example.com/en-gb/list" hreflang="EN" />` +
+		`<link rel="alternate" href="https://example.com/fr/list?a=1&amp;b=2" hreflang="fr" />` +
+		`<link rel="prev" href="https://example.com/list?page=1" />` +
+		`<link rel="next" href="https://example.com/list?page=3" />` +
+		`</head></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package fluent
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// adaptive controls whether RenderContext sizes its buffer from the sizes
+// of earlier renders.
+var adaptive atomic.Bool
+
+// hints holds the decayed average output size of each render site, keyed
+// by hintKey.
+var hints sync.Map
+
+// hintKey identifies a render site: the name of a named component, or the
+// file and line RenderContext was called from.
+type hintKey struct {
+	name string
+	file string
+	line int
+}
+
+// hintDecay sets how quickly the average follows a change in size: each
+// render moves it 1/hintDecay of the way towards its own size.
+const hintDecay = 4
+
+// EnableAdaptiveHints makes RenderContext record the size of each render's
+// output, per named component or, for other nodes, per call site, and grow
+// the buffer of the next render from the same place to a decayed average of
+// those sizes. Pages whose size is stable then render without the buffer
+// growing part way through, which node.SizeHint alone cannot avoid for
+// output produced at render time.
+//
+// Usage:
+//
+//	fluent.EnableAdaptiveHints()
+func EnableAdaptiveHints() {
+	adaptive.Store(true)
+}
+
+// DisableAdaptiveHints turns adaptive hints off. The recorded sizes are
+// kept; use ResetAdaptiveHints to drop them.
+func DisableAdaptiveHints() {
+	adaptive.Store(false)
+}
+
+// AdaptiveHints reports whether adaptive hints are enabled.
+func AdaptiveHints() bool {
+	return adaptive.Load()
+}
+
+// ResetAdaptiveHints drops the recorded sizes.
+func ResetAdaptiveHints() {
+	hints.Clear()
+}
+
+// siteOf returns the key n's sizes are recorded under, or false when
+// adaptive hints are disabled.
+func siteOf(n node.Node) (hintKey, bool) {
+	if !adaptive.Load() {
+		return hintKey{}, false
+	}
+	if c, ok := n.(*node.NamedComponent); ok && c.Name() != "" {
+		return hintKey{name: c.Name()}, true
+	}
+	var pcs [8]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/jpl-au/fluent.") {
+			return hintKey{file: f.File, line: f.Line}, true
+		}
+		if !more {
+			return hintKey{}, false
+		}
+	}
+}
+
+// hintFor returns the recorded average size for k.
+func hintFor(k hintKey) int {
+	if v, ok := hints.Load(k); ok {
+		return int(v.(*atomic.Int64).Load()) //nolint:forcetypeassert // hints only holds *atomic.Int64
+	}
+	return 0
+}
+
+// record folds size into the average for k.
+func record(k hintKey, size int) {
+	v, loaded := hints.LoadOrStore(k, new(atomic.Int64))
+	avg := v.(*atomic.Int64) //nolint:forcetypeassert // hints only holds *atomic.Int64
+	if !loaded {
+		avg.Store(int64(size))
+		return
+	}
+	for {
+		old := avg.Load()
+		if avg.CompareAndSwap(old, old+(int64(size)-old)/hintDecay) {
+			return
+		}
+	}
+}
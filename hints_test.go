@@ -0,0 +1,56 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/pool"
+)
+
+func TestAdaptiveHints(t *testing.T) {
+	fluent.EnableAdaptiveHints()
+	defer fluent.DisableAdaptiveHints()
+	defer fluent.ResetAdaptiveHints()
+
+	large := strings.Repeat("x", 2*pool.Threshold())
+	page := node.Func(func() node.Node { return node.Bytes([]byte(large)) })
+
+	// The first render cannot know the size; the second starts from it.
+	var gets []uint64
+	for range 2 {
+		pool.ResetStats()
+		var out bytes.Buffer
+		if err := fluent.RenderContext(context.Background(), page, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != large {
+			t.Fatalf("rendered %d bytes, want %d", out.Len(), len(large))
+		}
+		gets = append(gets, pool.Stats().Large.Gets)
+	}
+	if gets[0] != 0 || gets[1] == 0 {
+		t.Errorf("large pool gets = %v, want the second render only", gets)
+	}
+}
+
+func TestAdaptiveHintsDisabled(t *testing.T) {
+	fluent.DisableAdaptiveHints()
+	defer fluent.ResetAdaptiveHints()
+
+	large := strings.Repeat("x", 2*pool.Threshold())
+	page := node.Func(func() node.Node { return node.Bytes([]byte(large)) })
+	for range 2 {
+		pool.ResetStats()
+		if err := fluent.RenderContext(context.Background(), page, io.Discard); err != nil {
+			t.Fatal(err)
+		}
+		if got := pool.Stats().Large.Gets; got != 0 {
+			t.Errorf("large pool gets = %d, want 0", got)
+		}
+	}
+}
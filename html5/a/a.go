@@ -3,6 +3,8 @@
 package a
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -349,9 +351,9 @@ func (e *element) Type(mime string) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1179,7 +1181,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1258,7 +1260,7 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.href != "" {
 		buf.Write(html5.AttrHref)
-		buf.WriteString(e.href)
+		escape.WriteString(buf, e.href)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.rel) > 0 {
@@ -1278,17 +1280,17 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.ping != "" {
 		buf.Write(html5.AttrPing)
-		buf.WriteString(e.ping)
+		escape.WriteString(buf, e.ping)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1318,7 +1320,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
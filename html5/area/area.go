@@ -3,6 +3,8 @@
 package area
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"strconv"
@@ -249,9 +251,9 @@ func (e *element) Type(mime string) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1079,7 +1081,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1143,17 +1145,17 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.coords != "" {
 		buf.Write(html5.AttrCoords)
-		buf.WriteString(e.coords)
+		escape.WriteString(buf, e.coords)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.href != "" {
 		buf.Write(html5.AttrHref)
-		buf.WriteString(e.href)
+		escape.WriteString(buf, e.href)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.alt != "" {
 		buf.Write(html5.AttrAlt)
-		buf.WriteString(e.alt)
+		escape.WriteString(buf, e.alt)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.rel) > 0 {
@@ -1163,22 +1165,22 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.ping != "" {
 		buf.Write(html5.AttrPing)
-		buf.WriteString(e.ping)
+		escape.WriteString(buf, e.ping)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.areaType != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.areaType)
+		escape.WriteString(buf, e.areaType)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1208,7 +1210,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
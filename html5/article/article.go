@@ -3,6 +3,8 @@
 package article
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -122,9 +124,9 @@ func RawTextf(format string, args ...any) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -952,7 +954,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1031,12 +1033,12 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1066,7 +1068,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
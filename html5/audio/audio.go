@@ -3,6 +3,8 @@
 package audio
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -190,9 +192,9 @@ func (e *element) Preload(value preload.Preload) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1020,7 +1022,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1099,7 +1101,7 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.src != "" {
 		buf.Write(html5.AttrSrc)
-		buf.WriteString(e.src)
+		escape.WriteString(buf, e.src)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.preload) > 0 {
@@ -1121,7 +1123,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.controlsList != "" {
 		buf.Write(html5.AttrControlsList)
-		buf.WriteString(e.controlsList)
+		escape.WriteString(buf, e.controlsList)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.crossorigin) > 0 {
@@ -1134,12 +1136,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1169,7 +1171,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package button
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -269,9 +271,9 @@ func (e *element) Value(value string) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1090,7 +1092,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1172,17 +1174,17 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.name != "" {
 		buf.Write(html5.AttrName)
-		buf.WriteString(e.name)
+		escape.WriteString(buf, e.name)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.buttonType != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.buttonType)
+		escape.WriteString(buf, e.buttonType)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.value != "" {
 		buf.Write(html5.AttrValue)
-		buf.WriteString(e.value)
+		escape.WriteString(buf, e.value)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.formnovalidate {
@@ -1210,12 +1212,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1245,7 +1247,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
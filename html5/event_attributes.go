@@ -3,6 +3,8 @@
 package html5
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"bytes"
 	"github.com/jpl-au/fluent/node"
 )
@@ -36,7 +38,7 @@ func (ea *EventAttributes) SetAttribute(key string, value string) {
 	}
 
 	// Add new attribute
-	*ea.attr = append(*ea.attr, node.Attribute{Key: key, Value: value})
+	*ea.attr = append(*ea.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Attributes returns the slice of attributes
@@ -48,42 +50,42 @@ func (ea *EventAttributes) Attributes() *[]node.Attribute {
 func (ea *EventAttributes) AttributeBuilder(buf *bytes.Buffer) {
 	if ea.OnClick != "" {
 		buf.Write(AttrOnClick)
-		buf.WriteString(ea.OnClick)
+		escape.WriteString(buf, ea.OnClick)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnChange != "" {
 		buf.Write(AttrOnChange)
-		buf.WriteString(ea.OnChange)
+		escape.WriteString(buf, ea.OnChange)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnInput != "" {
 		buf.Write(AttrOnInput)
-		buf.WriteString(ea.OnInput)
+		escape.WriteString(buf, ea.OnInput)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnFocus != "" {
 		buf.Write(AttrOnFocus)
-		buf.WriteString(ea.OnFocus)
+		escape.WriteString(buf, ea.OnFocus)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnBlur != "" {
 		buf.Write(AttrOnBlur)
-		buf.WriteString(ea.OnBlur)
+		escape.WriteString(buf, ea.OnBlur)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnSubmit != "" {
 		buf.Write(AttrOnSubmit)
-		buf.WriteString(ea.OnSubmit)
+		escape.WriteString(buf, ea.OnSubmit)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnKeyDown != "" {
 		buf.Write(AttrOnKeyDown)
-		buf.WriteString(ea.OnKeyDown)
+		escape.WriteString(buf, ea.OnKeyDown)
 		buf.Write(MarkupQuote)
 	}
 	if ea.OnKeyUp != "" {
 		buf.Write(AttrOnKeyUp)
-		buf.WriteString(ea.OnKeyUp)
+		escape.WriteString(buf, ea.OnKeyUp)
 		buf.Write(MarkupQuote)
 	}
 
@@ -93,7 +95,7 @@ func (ea *EventAttributes) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(MarkupEquals)
 			buf.Write(MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(MarkupQuote)
 		}
 	}
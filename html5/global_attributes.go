@@ -3,6 +3,8 @@
 package html5
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"bytes"
 	"github.com/jpl-au/fluent/node"
 	"github.com/jpl-au/fluent/html5/attr/autocapitalize"
@@ -60,7 +62,7 @@ func (ga *GlobalAttributes) SetAttribute(key string, value string) {
 	}
 
 	// Add new attribute
-	*ga.attr = append(*ga.attr, node.Attribute{Key: key, Value: value})
+	*ga.attr = append(*ga.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Attributes returns the slice of attributes
@@ -72,32 +74,32 @@ func (ga *GlobalAttributes) Attributes() *[]node.Attribute {
 func (ga *GlobalAttributes) AttributeBuilder(buf *bytes.Buffer) {
 	if ga.Style != "" {
 		buf.Write(AttrStyle)
-		buf.WriteString(ga.Style)
+		escape.WriteString(buf, ga.Style)
 		buf.Write(MarkupQuote)
 	}
 	if ga.Title != "" {
 		buf.Write(AttrTitle)
-		buf.WriteString(ga.Title)
+		escape.WriteString(buf, ga.Title)
 		buf.Write(MarkupQuote)
 	}
 	if ga.Role != "" {
 		buf.Write(AttrRole)
-		buf.WriteString(ga.Role)
+		escape.WriteString(buf, ga.Role)
 		buf.Write(MarkupQuote)
 	}
 	if ga.Lang != "" {
 		buf.Write(AttrLang)
-		buf.WriteString(ga.Lang)
+		escape.WriteString(buf, ga.Lang)
 		buf.Write(MarkupQuote)
 	}
 	if ga.AccessKey != "" {
 		buf.Write(AttrAccessKey)
-		buf.WriteString(ga.AccessKey)
+		escape.WriteString(buf, ga.AccessKey)
 		buf.Write(MarkupQuote)
 	}
 	if ga.AriaLabel != "" {
 		buf.Write(AttrAriaLabel)
-		buf.WriteString(ga.AriaLabel)
+		escape.WriteString(buf, ga.AriaLabel)
 		buf.Write(MarkupQuote)
 	}
 	if len(ga.AutoCapitalize) > 0 {
@@ -127,7 +129,7 @@ func (ga *GlobalAttributes) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if ga.ExportParts != "" {
 		buf.Write(AttrExportParts)
-		buf.WriteString(ga.ExportParts)
+		escape.WriteString(buf, ga.ExportParts)
 		buf.Write(MarkupQuote)
 	}
 	if len(ga.InputMode) > 0 {
@@ -137,17 +139,17 @@ func (ga *GlobalAttributes) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if ga.ItemProp != "" {
 		buf.Write(AttrItemProp)
-		buf.WriteString(ga.ItemProp)
+		escape.WriteString(buf, ga.ItemProp)
 		buf.Write(MarkupQuote)
 	}
 	if ga.ItemRef != "" {
 		buf.Write(AttrItemRef)
-		buf.WriteString(ga.ItemRef)
+		escape.WriteString(buf, ga.ItemRef)
 		buf.Write(MarkupQuote)
 	}
 	if ga.Part != "" {
 		buf.Write(AttrPart)
-		buf.WriteString(ga.Part)
+		escape.WriteString(buf, ga.Part)
 		buf.Write(MarkupQuote)
 	}
 	if len(ga.Popover) > 0 {
@@ -182,7 +184,7 @@ func (ga *GlobalAttributes) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(MarkupEquals)
 			buf.Write(MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package input
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strconv"
 	"strings"
@@ -541,9 +543,9 @@ func (e *element) Width(width int) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1371,7 +1373,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1420,12 +1422,12 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.name != "" {
 		buf.Write(html5.AttrName)
-		buf.WriteString(e.name)
+		escape.WriteString(buf, e.name)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.value != "" {
 		buf.Write(html5.AttrValue)
-		buf.WriteString(e.value)
+		escape.WriteString(buf, e.value)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.inputType) > 0 {
@@ -1435,7 +1437,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.src != "" {
 		buf.Write(html5.AttrSrc)
-		buf.WriteString(e.src)
+		escape.WriteString(buf, e.src)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.accept) > 0 {
@@ -1461,7 +1463,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.placeholder != "" {
 		buf.Write(html5.AttrPlaceholder)
-		buf.WriteString(e.placeholder)
+		escape.WriteString(buf, e.placeholder)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.readonly {
@@ -1503,12 +1505,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1538,7 +1540,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package link
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"strconv"
@@ -231,9 +233,9 @@ func (e *element) Type(mimeType string) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1054,7 +1056,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1108,7 +1110,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.href != "" {
 		buf.Write(html5.AttrHref)
-		buf.WriteString(e.href)
+		escape.WriteString(buf, e.href)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.as) > 0 {
@@ -1133,7 +1135,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.mimeType != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.mimeType)
+		escape.WriteString(buf, e.mimeType)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.disabled {
@@ -1141,12 +1143,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1176,7 +1178,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package object
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -205,9 +207,9 @@ func (e *element) Width(pixels int) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1035,7 +1037,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1114,17 +1116,17 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.data != "" {
 		buf.Write(html5.AttrData)
-		buf.WriteString(e.data)
+		escape.WriteString(buf, e.data)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.objectType != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.objectType)
+		escape.WriteString(buf, e.objectType)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.form != "" {
 		buf.Write(html5.AttrForm)
-		buf.WriteString(e.form)
+		escape.WriteString(buf, e.form)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.height != 0 {
@@ -1134,12 +1136,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.name != "" {
 		buf.Write(html5.AttrName)
-		buf.WriteString(e.name)
+		escape.WriteString(buf, e.name)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.useMap != "" {
 		buf.Write(html5.AttrUseMap)
-		buf.WriteString(e.useMap)
+		escape.WriteString(buf, e.useMap)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.width != 0 {
@@ -1149,12 +1151,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1184,7 +1186,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package script
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"github.com/jpl-au/fluent/text"
@@ -217,9 +219,9 @@ func (e *element) ReferrerPolicy(value referrerpolicy.ReferrerPolicy) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1040,7 +1042,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1119,12 +1121,12 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.src != "" {
 		buf.Write(html5.AttrSrc)
-		buf.WriteString(e.src)
+		escape.WriteString(buf, e.src)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.scriptType != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.scriptType)
+		escape.WriteString(buf, e.scriptType)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.async {
@@ -1140,7 +1142,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.integrity != "" {
 		buf.Write(html5.AttrIntegrity)
-		buf.WriteString(e.integrity)
+		escape.WriteString(buf, e.integrity)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.noModule {
@@ -1148,7 +1150,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.nonce != "" {
 		buf.Write(html5.AttrNonce)
-		buf.WriteString(e.nonce)
+		escape.WriteString(buf, e.nonce)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.referrerpolicy) > 0 {
@@ -1158,12 +1160,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1193,7 +1195,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -3,6 +3,8 @@
 package source
 
 import (
+	"github.com/jpl-au/fluent/intern"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/html5"
 	"strings"
 	"strconv"
@@ -242,9 +244,9 @@ func (e *element) Height(pixels int) *element {
 // component-based architectures and maintainable CSS.
 func (e *element) Class(class string) *element {
 	if e.class == "" {
-		e.class = class
+		e.class = intern.Class(class)
 	} else {
-		e.class = strings.Join([]string{e.class, class}, " ")
+		e.class = intern.Class(e.class, class)
 	}
 	return e
 }
@@ -1072,7 +1074,7 @@ func (e *element) SetAttribute(key string, value string) {
 			return
 		}
 	}
-	*e.attr = append(*e.attr, node.Attribute{Key: key, Value: value})
+	*e.attr = append(*e.attr, node.Attribute{Key: intern.Attr(key), Value: value})
 }
 
 // Add appends child nodes to the element
@@ -1121,17 +1123,17 @@ func (e *element) Render(w ...io.Writer) []byte {
 func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	if e.src != "" {
 		buf.Write(html5.AttrSrc)
-		buf.WriteString(e.src)
+		escape.WriteString(buf, e.src)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.mime != "" {
 		buf.Write(html5.AttrType)
-		buf.WriteString(e.mime)
+		escape.WriteString(buf, e.mime)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.srcset != "" {
 		buf.Write(html5.AttrSrcset)
-		buf.WriteString(e.srcset)
+		escape.WriteString(buf, e.srcset)
 		buf.Write(html5.MarkupQuote)
 	}
 	if len(e.sizes) > 0 {
@@ -1141,7 +1143,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.media != "" {
 		buf.Write(html5.AttrMedia)
-		buf.WriteString(e.media)
+		escape.WriteString(buf, e.media)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.width != 0 {
@@ -1156,12 +1158,12 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 	}
 	if e.class != "" {
 		buf.Write(html5.AttrClass)
-		buf.WriteString(e.class)
+		escape.WriteString(buf, e.class)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.id != "" {
 		buf.Write(html5.AttrID)
-		buf.WriteString(e.id)
+		escape.WriteString(buf, e.id)
 		buf.Write(html5.MarkupQuote)
 	}
 	if e.hidden {
@@ -1191,7 +1193,7 @@ func (e *element) AttributeBuilder(buf *bytes.Buffer) {
 			buf.WriteString(attr.Key)
 			buf.Write(html5.MarkupEquals)
 			buf.Write(html5.MarkupQuote)
-			buf.WriteString(attr.Value)
+			escape.WriteString(buf, attr.Value)
 			buf.Write(html5.MarkupQuote)
 		}
 	}
@@ -0,0 +1,223 @@
+// Package hydrate marks server-rendered markup as client components, so a
+// frontend runtime can attach behaviour to it without a separate template.
+//
+// Component labels an element with data-component, naming the client
+// component, and data-props, holding its props as JSON. Each component
+// rendered is recorded with the manifest in the render context, and the
+// Manifest outlet writes the names as a JSON script element so the runtime
+// knows which modules to load before it hydrates.
+//
+// Usage:
+//
+//	func Counter(start int) node.Node {
+//	    return hydrate.Component("counter", map[string]int{"start": start},
+//	        div.New(button.Text("+1"), span.Textf("%d", start)),
+//	    )
+//	}
+//
+//	layout := html.New(
+//	    head.New(meta.UTF8()),
+//	    body.New(content, hydrate.Manifest(), script.Module("/js/hydrate.js")),
+//	)
+//	err := hydrate.Render(r.Context(), layout, w)
+//
+// renders
+//
+//	<div data-component="counter" data-props="{&#34;start&#34;:3}">...</div>
+//	<script type="application/json" id="fluent-hydrate">["counter"]</script>
+//
+// and the runtime reads them back with
+//
+//	const names = JSON.parse(document.getElementById("fluent-hydrate").text)
+//	for (const el of document.querySelectorAll("[data-component]")) {
+//	    components[el.dataset.component].hydrate(el, JSON.parse(el.dataset.props || "null"))
+//	}
+package hydrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ManifestID is the id of the script element written by Manifest.
+const ManifestID = "fluent-hydrate"
+
+// SafeJSON encodes v as JSON that is safe to place inside a script element:
+// <, > and & are written as \u escapes, so the output can never close the
+// element or open a comment, as are U+2028 and U+2029, which older
+// JavaScript parsers reject in strings. Set as an attribute value it is
+// escaped when the element renders.
+func SafeJSON(v any) (string, error) {
+	// json.Marshal applies exactly these escapes; SafeJSON names the
+	// guarantee so callers need not rely on an encoder default.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Component marks n, which should be an element, as the server render of the
+// client component name. props is encoded with SafeJSON into data-props;
+// nil props omit the attribute. Props that cannot be encoded are reported to
+// renders with assertions enabled (see node.WithAssertions) and omitted.
+func Component(name string, props any, n node.Node) node.Node {
+	c := &component{name: name, node: n}
+	if n == nil {
+		return c
+	}
+	n.SetAttribute("data-component", name)
+	if props != nil {
+		s, err := SafeJSON(props)
+		if err != nil {
+			c.err = err
+		} else {
+			n.SetAttribute("data-props", s)
+		}
+	}
+	return c
+}
+
+type component struct {
+	name string
+	node node.Node
+	err  error
+}
+
+func (c *component) Render(w ...io.Writer) []byte {
+	return node.Render(c, w...)
+}
+
+// RenderBuilder records the component with the manifest and renders it.
+func (c *component) RenderBuilder(buf *bytes.Buffer) {
+	if c.err != nil {
+		node.Fail(buf, c.err)
+	}
+	if m := From(node.Context(buf)); m != nil {
+		m.Add(c.name)
+	}
+	if c.node != nil {
+		c.node.RenderBuilder(buf)
+	}
+}
+
+func (c *component) Nodes() []node.Node {
+	if c.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{c.node}
+}
+
+// Dynamic returns true as the component registers with the manifest each
+// time it renders.
+func (c *component) Dynamic() bool {
+	return true
+}
+
+func (c *component) SetAttribute(key string, value string) {
+	if c.node != nil {
+		c.node.SetAttribute(key, value)
+	}
+}
+
+// Components collects the names of the components rendered on a page. It is
+// safe for concurrent use.
+type Components struct {
+	mu    sync.Mutex
+	names []string
+	seen  map[string]struct{}
+}
+
+// NewComponents creates an empty collector.
+func NewComponents() *Components {
+	return &Components{seen: map[string]struct{}{}}
+}
+
+// Add records name, ignoring names already recorded.
+func (c *Components) Add(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[name]; ok {
+		return
+	}
+	c.seen[name] = struct{}{}
+	c.names = append(c.names, name)
+}
+
+// Names returns the recorded names in the order they first rendered.
+func (c *Components) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.names...)
+}
+
+type componentsKey struct{}
+
+// With returns a context whose renders record components with c.
+func With(ctx context.Context, c *Components) context.Context {
+	return context.WithValue(ctx, componentsKey{}, c)
+}
+
+// From returns the collector carried by ctx, or nil.
+func From(ctx context.Context) *Components {
+	c, _ := ctx.Value(componentsKey{}).(*Components)
+	return c
+}
+
+// Render renders n to w through fluent.RenderContext with an empty collector
+// in the context, unless ctx already carries one.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) error {
+	if From(ctx) == nil {
+		ctx = With(ctx, NewComponents())
+	}
+	return fluent.RenderContext(ctx, n, w, policy...)
+}
+
+// Manifest writes the names of the components rendered on the page as a
+// JSON array in a script element with id ManifestID. It may be placed before
+// the components, such as in <head>, as it is written once the render
+// completes. Without a collector in the context it renders nothing.
+func Manifest() node.Node {
+	return manifest{}
+}
+
+type manifest struct{}
+
+func (m manifest) Render(w ...io.Writer) []byte {
+	return node.Render(m, w...)
+}
+
+func (m manifest) RenderBuilder(buf *bytes.Buffer) {
+	c := From(node.Context(buf))
+	if c == nil {
+		return
+	}
+	node.Defer(buf, func(b *bytes.Buffer) {
+		names := c.Names()
+		if names == nil {
+			names = []string{}
+		}
+		s, _ := SafeJSON(names)
+		b.WriteString(`<script type="application/json" id="` + ManifestID + `">`)
+		b.WriteString(s)
+		b.WriteString("</script>")
+	})
+}
+
+func (m manifest) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+func (m manifest) Dynamic() bool {
+	return true
+}
+
+func (m manifest) SetAttribute(_ string, _ string) {
+	// manifest does not support attributes
+}
@@ -0,0 +1,100 @@
+package hydrate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestSafeJSON(t *testing.T) {
+	got, err := SafeJSON(map[string]string{"html": "</script><!-- & \u2028"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"html":"\u003c/script\u003e\u003c!-- \u0026 \u2028"}`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, err := SafeJSON(func() {}); err == nil {
+		t.Error("expected an error for a value JSON cannot encode")
+	}
+}
+
+func TestComponent(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"props", Component("counter", map[string]any{"start": 3, "label": `"hi"`}, div.New()),
+			`<div data-component="counter" data-props="{&#34;label&#34;:&#34;\&#34;hi\&#34;&#34;,&#34;start&#34;:3}"></div>`},
+		{"nil props", Component("clock", nil, span.Text("12:00")),
+			`<span data-component="clock">12:00</span>`},
+		{"escaped name", Component(`a"b`, nil, div.New()),
+			`<div data-component="a&#34;b"></div>`},
+		{"nil node", Component("empty", 1, nil), ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	page := html.Fragment(
+		head.New(Manifest()),
+		body.New(
+			Component("chart", []int{1, 2}, div.New()),
+			Component("counter", nil, div.New()),
+			Component("chart", []int{3}, div.New()),
+		),
+	)
+	var buf bytes.Buffer
+	if err := Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<html><head><script type="application/json" id="fluent-hydrate">["chart","counter"]</script></head><body>` +
+		`<div data-component="chart" data-props="[1,2]"></div>` +
+		`<div data-component="counter"></div>` +
+		`<div data-component="chart" data-props="[3]"></div></body></html>`
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestManifestEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(context.Background(), Manifest(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<script type="application/json" id="fluent-hydrate">[]</script>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if got := Manifest().Render(); len(got) != 0 {
+		t.Errorf("got %q without a collector, want nothing", got)
+	}
+}
+
+func TestUnencodableProps(t *testing.T) {
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := Render(ctx, Component("bad", func() {}, div.New()), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div data-component="bad"></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+}
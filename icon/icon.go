@@ -0,0 +1,356 @@
+// Package icon renders SVG icons as references into a sprite sheet, so each
+// icon's paths are sent once per page however many times it is used.
+//
+// Icons are registered once, typically from an embedded directory, and
+// parsed at registration. Use renders <svg><use href="#icon-name"/></svg>
+// and declares the icon's <symbol> with the render's assets.Sprites
+// collector; Sprite writes a hidden sheet holding a symbol for each icon the
+// page used.
+//
+// Usage:
+//
+//	//go:embed icons/*.svg
+//	var icons embed.FS
+//
+//	func init() {
+//	    if err := icon.Load(icons, "icons/*.svg"); err != nil {
+//	        panic(err)
+//	    }
+//	}
+//
+//	layout := html.New(body.New(icon.Sprite(), content))
+//	button.New(icon.Use("trash", icon.Size(16)), text.Text("Delete"))
+//
+//	err := assets.Render(r.Context(), layout, w)
+package icon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Prefix is added to an icon's name to form the id of its symbol.
+const Prefix = "icon-"
+
+// Set holds registered icons. It is safe for concurrent use.
+type Set struct {
+	mu      sync.RWMutex
+	symbols map[string]string // name to rendered <symbol>
+	sheet   []byte            // every symbol, built on first use
+}
+
+// NewSet creates an empty set.
+func NewSet() *Set {
+	return &Set{symbols: map[string]string{}}
+}
+
+// Default is the set used by the package-level functions.
+var Default = NewSet()
+
+// UnknownError is reported when Use names an icon that was never
+// registered.
+type UnknownError struct {
+	Name string
+}
+
+func (e *UnknownError) Error() string {
+	return fmt.Sprintf("icon: %q is not registered", e.Name)
+}
+
+// Register parses svg, the source of an SVG document, and adds it as the
+// icon name, replacing any icon of that name. The viewBox and the content
+// of the root <svg> become the icon's symbol.
+func (s *Set) Register(name string, svg []byte) error {
+	if name == "" || strings.ContainsAny(name, " \t\n\"'<>&#") {
+		return fmt.Errorf("icon: invalid name %q", name)
+	}
+	src := string(svg)
+	var root *markup.Token
+	tokens := markup.Tokenize(src)
+	for i := range tokens {
+		if tokens[i].Type == markup.StartTagToken && tokens[i].Data == "svg" {
+			root = &tokens[i]
+			break
+		}
+	}
+	end := strings.LastIndex(strings.ToLower(src), "</svg>")
+	if root == nil || end < root.Offset {
+		return fmt.Errorf("icon: %s: no <svg> element", name)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<symbol id="` + escape.String(Prefix+name) + `"`)
+	for _, a := range root.Attrs {
+		if a.Key == "viewbox" {
+			b.WriteString(` viewBox="` + escape.String(a.Val) + `"`)
+		}
+	}
+	b.WriteByte('>')
+	b.WriteString(strings.TrimSpace(src[root.Offset+len(root.Raw) : end]))
+	b.WriteString("</symbol>")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols[name] = b.String()
+	s.sheet = nil
+	return nil
+}
+
+// Load registers every file in fsys matching pattern (see fs.Glob), named
+// after the file without its extension.
+func (s *Set) Load(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(path.Base(f), path.Ext(f))
+		if err := s.Register(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Has reports whether the icon name is registered.
+func (s *Set) Has(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.symbols[name]
+	return ok
+}
+
+// Names returns the registered icon names in order.
+func (s *Set) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.namesLocked()
+}
+
+// symbol returns the <symbol> of the icon name.
+func (s *Set) symbol(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sym, ok := s.symbols[name]
+	return sym, ok
+}
+
+// write writes a hidden sprite sheet holding every icon of s.
+func (s *Set) write(buf *bytes.Buffer) {
+	s.mu.Lock()
+	if s.sheet == nil {
+		var b bytes.Buffer
+		for _, name := range s.namesLocked() {
+			b.WriteString(s.symbols[name])
+		}
+		s.sheet = b.Bytes()
+	}
+	sheet := s.sheet
+	s.mu.Unlock()
+	if len(sheet) == 0 {
+		return
+	}
+	buf.WriteString(sheetOpen)
+	buf.Write(sheet)
+	buf.WriteString("</svg>")
+}
+
+// namesLocked returns the registered names in order. s.mu must be held.
+func (s *Set) namesLocked() []string {
+	names := make([]string, 0, len(s.symbols))
+	for name := range s.symbols {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// sheetOpen starts a sprite sheet, hidden from view and from assistive
+// technology.
+const sheetOpen = `<svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">`
+
+// Register adds an icon to the Default set.
+func Register(name string, svg []byte) error {
+	return Default.Register(name, svg)
+}
+
+// Load registers icons from fsys with the Default set.
+func Load(fsys fs.FS, pattern string) error {
+	return Default.Load(fsys, pattern)
+}
+
+// Sprite writes the sprite sheet of the icons used by the page, once the
+// render completes (see assets.SpriteSheet), so it may be placed before
+// them. Without an assets.Sprites collector in the context it holds every
+// icon of s. Place it once, at the start of <body>.
+func (s *Set) Sprite() node.Node {
+	return sprite{set: s}
+}
+
+// Sprite writes the sprite sheet of the Default set.
+func Sprite() node.Node {
+	return Default.Sprite()
+}
+
+type sprite struct {
+	set *Set
+}
+
+func (sp sprite) Render(w ...io.Writer) []byte {
+	return node.Render(sp, w...)
+}
+
+func (sp sprite) RenderBuilder(buf *bytes.Buffer) {
+	if assets.SpritesFrom(node.Context(buf)) != nil {
+		assets.SpriteSheet().RenderBuilder(buf)
+		return
+	}
+	sp.set.write(buf)
+}
+
+func (sp sprite) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+func (sp sprite) Dynamic() bool {
+	return true
+}
+
+func (sp sprite) SetAttribute(_ string, _ string) {
+	// sprite does not support attributes
+}
+
+// Option adjusts an icon reference.
+type Option func(*Ref)
+
+// Size sets the width and height of the icon in pixels.
+func Size(px int) Option {
+	return func(r *Ref) {
+		r.width, r.height = px, px
+	}
+}
+
+// Class adds classes to the icon's <svg>.
+func Class(class string) Option {
+	return func(r *Ref) {
+		r.class = strings.TrimSpace(r.class + " " + class)
+	}
+}
+
+// Label names the icon for assistive technology, for icons that carry
+// meaning on their own, such as a button without text. Icons without a
+// label are hidden from assistive technology as decoration.
+func Label(label string) Option {
+	return func(r *Ref) {
+		r.label = label
+	}
+}
+
+// Ref is a reference to an icon of a Set. Create one with Use.
+type Ref struct {
+	set           *Set
+	name          string
+	class         string
+	label         string
+	width, height int
+	attrs         []node.Attribute
+}
+
+// Use returns a reference to the icon name, declaring its symbol with the
+// render's assets.Sprites collector. The <svg> has the class "icon", plus any added
+// with Class. Using an unregistered icon renders nothing and is reported as
+// an *UnknownError to renders with assertions enabled (see
+// node.WithAssertions).
+func (s *Set) Use(name string, opts ...Option) *Ref {
+	r := &Ref{set: s, name: name, class: "icon"}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Use returns a reference to an icon of the Default set.
+func Use(name string, opts ...Option) *Ref {
+	return Default.Use(name, opts...)
+}
+
+func (r *Ref) Render(w ...io.Writer) []byte {
+	return node.Render(r, w...)
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (r *Ref) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, r)
+}
+
+// RenderBuilder writes the <svg> referencing the icon.
+func (r *Ref) RenderBuilder(buf *bytes.Buffer) {
+	symbol, ok := r.set.symbol(r.name)
+	if !ok {
+		node.Fail(buf, &UnknownError{Name: r.name})
+		return
+	}
+	if sp := assets.SpritesFrom(node.Context(buf)); sp != nil {
+		sp.Add(assets.Symbol{ID: Prefix + r.name, Markup: symbol})
+	}
+
+	buf.WriteString(`<svg class="`)
+	escape.WriteString(buf, r.class)
+	buf.WriteByte('"')
+	if r.width > 0 {
+		buf.WriteString(` width="` + strconv.Itoa(r.width) + `" height="` + strconv.Itoa(r.height) + `"`)
+	}
+	if r.label != "" {
+		buf.WriteString(` role="img" aria-label="`)
+		escape.WriteString(buf, r.label)
+		buf.WriteByte('"')
+	} else {
+		buf.WriteString(` aria-hidden="true"`)
+	}
+	buf.WriteString(` focusable="false"`)
+	for _, a := range r.attrs {
+		buf.WriteString(" " + a.Key + `="`)
+		escape.WriteString(buf, a.Value)
+		buf.WriteByte('"')
+	}
+	buf.WriteString(`><use href="#`)
+	escape.WriteString(buf, Prefix+r.name)
+	buf.WriteString(`"></use></svg>`)
+}
+
+// Nodes returns an empty slice as references have no children.
+func (r *Ref) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the reference declares the icon with the render.
+func (r *Ref) Dynamic() bool {
+	return true
+}
+
+// SetAttribute sets an attribute on the <svg>. A class is added to the
+// icon's classes.
+func (r *Ref) SetAttribute(key string, value string) {
+	if key == "class" {
+		Class(value)(r)
+		return
+	}
+	r.attrs = append(r.attrs, node.Attribute{Key: key, Value: value})
+}
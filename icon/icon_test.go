@@ -0,0 +1,108 @@
+package icon_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/icon"
+	"github.com/jpl-au/fluent/node"
+)
+
+var files = fstest.MapFS{
+	"icons/trash.svg": {Data: []byte(`<?xml version="1.0"?>
+<!-- trash -->
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" width="24">
+  <path d="M3 6h18"/>
+</svg>
+`)},
+	"icons/star.svg":   {Data: []byte(`<svg viewBox="0 0 16 16"><circle r="8"/></svg>`)},
+	"icons/readme.txt": {Data: []byte("not an icon")},
+}
+
+func newSet(t *testing.T) *icon.Set {
+	t.Helper()
+	s := icon.NewSet()
+	if err := s.Load(files, "icons/*.svg"); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+const (
+	trash = `<symbol id="icon-trash" viewBox="0 0 24 24"><path d="M3 6h18"/></symbol>`
+	star  = `<symbol id="icon-star" viewBox="0 0 16 16"><circle r="8"/></symbol>`
+	sheet = `<svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">`
+)
+
+func TestRegister(t *testing.T) {
+	s := newSet(t)
+	if got := s.Names(); len(got) != 2 || got[0] != "star" || got[1] != "trash" {
+		t.Errorf("Names() = %q", got)
+	}
+	if err := s.Register("bad name", []byte("<svg></svg>")); err == nil {
+		t.Error("Register accepted a name with a space")
+	}
+	if err := s.Register("empty", []byte("<p>no svg</p>")); err == nil {
+		t.Error("Register accepted a file without <svg>")
+	}
+}
+
+func TestUse(t *testing.T) {
+	s := newSet(t)
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"plain", s.Use("star"), `<svg class="icon" aria-hidden="true" focusable="false"><use href="#icon-star"></use></svg>`},
+		{"options", s.Use("star", icon.Size(16), icon.Class("text-red"), icon.Label("Favourite")),
+			`<svg class="icon text-red" width="16" height="16" role="img" aria-label="Favourite" focusable="false"><use href="#icon-star"></use></svg>`},
+		{"unknown", s.Use("missing"), ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.node.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprite(t *testing.T) {
+	s := newSet(t)
+	page := body.New(s.Sprite(), div.New(s.Use("trash"), s.Use("trash")))
+
+	var buf bytes.Buffer
+	if err := assets.Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	use := `<svg class="icon" aria-hidden="true" focusable="false"><use href="#icon-trash"></use></svg>`
+	want := `<body>` + sheet + trash + `</svg><div>` + use + use + `</div></body>`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// Without a sprites collector the sheet holds every icon.
+	if got, want := string(s.Sprite().Render()), sheet+star+trash+`</svg>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestUseUnknown(t *testing.T) {
+	var got error
+	ctx := node.WithAssertions(context.Background(), func(err error) { got = err })
+	var buf bytes.Buffer
+	if err := assets.Render(ctx, icon.NewSet().Use("nope"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var ue *icon.UnknownError
+	if !errors.As(got, &ue) || ue.Name != "nope" {
+		t.Errorf("got %v, want an *UnknownError for nope", got)
+	}
+}
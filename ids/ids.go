@@ -0,0 +1,153 @@
+// Package ids finds id attributes used more than once in rendered markup.
+// Duplicate ids are easy to produce when components are reused - two
+// instances of a form field each rendering id="email" - and browsers do not
+// complain: a <label for> names only the first element, getElementById and
+// htmx targets find only the first, and the second element silently loses
+// its label or its updates.
+//
+// Check renders a tree and returns each duplicated id with the paths of the
+// elements that share it, for use in tests. Assert wraps a node so that
+// renders with assertions enabled through node.WithAssertions report each
+// duplicate as an error while other renders pass through untouched. Wrap the
+// whole page: ids only need to be unique within a document, and a component
+// checked on its own cannot see the ids around it.
+//
+// Usage:
+//
+//	for _, d := range ids.Check(page) {
+//	    t.Error(d)
+//	}
+//
+//	ctx := node.WithAssertions(r.Context(), func(err error) { log.Print(err) })
+//	fluent.RenderContext(ctx, ids.Assert(page), w)
+package ids
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Duplicate is an id carried by more than one element.
+type Duplicate struct {
+	ID string
+	// Paths locate the elements with the id in document order, e.g.
+	// "form > div:nth-of-type(2) > input".
+	Paths []string
+}
+
+// Error formats the duplicate so it can be reported through node.Fail.
+func (d *Duplicate) Error() string {
+	return "ids: duplicate id " + strconv.Quote(d.ID) + " on " + strings.Join(d.Paths, ", ")
+}
+
+// Check renders n and returns its duplicated ids in order of first use.
+func Check(n node.Node) []*Duplicate {
+	if n == nil {
+		return nil
+	}
+	return CheckHTML(string(n.Render()))
+}
+
+// CheckHTML returns the duplicated ids in rendered markup.
+func CheckHTML(src string) []*Duplicate {
+	var order []string
+	seen := map[string][]*markup.Node{}
+	markup.Parse(src).Walk(func(n *markup.Node) bool {
+		if n.Type != markup.ElementNode {
+			return true
+		}
+		if id, ok := n.Attr("id"); ok && id != "" {
+			if seen[id] == nil {
+				order = append(order, id)
+			}
+			seen[id] = append(seen[id], n)
+		}
+		return true
+	})
+	var dups []*Duplicate
+	for _, id := range order {
+		if len(seen[id]) < 2 {
+			continue
+		}
+		d := &Duplicate{ID: id}
+		for _, n := range seen[id] {
+			d.Paths = append(d.Paths, path(n))
+		}
+		dups = append(dups, d)
+	}
+	return dups
+}
+
+// path locates n by position rather than by its id, which the elements
+// being reported share.
+func path(n *markup.Node) string {
+	part := n.Tag
+	if p := n.Parent; p != nil {
+		same, index := 0, 0
+		for _, s := range p.Elements() {
+			if s.Tag == n.Tag {
+				same++
+			}
+			if s == n {
+				index = same
+			}
+		}
+		if same > 1 {
+			part += ":nth-of-type(" + strconv.Itoa(index) + ")"
+		}
+		if p.Type == markup.ElementNode {
+			return p.Path() + " > " + part
+		}
+	}
+	return part
+}
+
+// Assert wraps n so that renders with assertions enabled check its output
+// and report each Duplicate through node.Fail.
+func Assert(n node.Node) node.Node {
+	return &asserted{node: n}
+}
+
+type asserted struct {
+	node node.Node
+}
+
+func (a *asserted) Render(w ...io.Writer) []byte {
+	return node.Render(a, w...)
+}
+
+// RenderBuilder writes the wrapped node and, when the render has assertions
+// enabled, checks the output for duplicate ids.
+func (a *asserted) RenderBuilder(buf *bytes.Buffer) {
+	if a.node == nil {
+		return
+	}
+	if !node.Checking(buf) {
+		a.node.RenderBuilder(buf)
+		return
+	}
+	start := buf.Len()
+	a.node.RenderBuilder(buf)
+	for _, d := range CheckHTML(string(buf.Bytes()[start:])) {
+		node.Fail(buf, d)
+	}
+}
+
+func (a *asserted) Nodes() []node.Node {
+	if a.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{a.node}
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (a *asserted) SetAttribute(key string, value string) {
+	if a.node != nil {
+		a.node.SetAttribute(key, value)
+	}
+}
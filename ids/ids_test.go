@@ -0,0 +1,81 @@
+package ids
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestCheckHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // "id: path, path" for each duplicate
+	}{
+		{"unique", `<div id="a"><p id="b"></p></div>`, nil},
+		{"empty ids ignored", `<p id=""></p><p id=""></p>`, nil},
+		{"siblings", `<form><input id="q"><input id="q"></form>`,
+			[]string{"q: form > input:nth-of-type(1), form > input:nth-of-type(2)"}},
+		{"nested", `<main><section id="s"><h2 id="t"></h2></section><aside><h2 id="t"></h2></aside></main>`,
+			[]string{"t: main > section#s > h2, main > aside > h2"}},
+		{"three and order", `<b id="y"></b><i id="x"></i><b id="y"></b><i id="x"></i><u id="y"></u>`,
+			[]string{"y: b:nth-of-type(1), b:nth-of-type(2), u", "x: i:nth-of-type(1), i:nth-of-type(2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range CheckHTML(tt.src) {
+				got = append(got, d.ID+": "+strings.Join(d.Paths, ", "))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	field := func() node.Node { return div.New(input.Text("email", "").ID("email")) }
+	dups := Check(div.New(field(), field()))
+	if len(dups) != 1 {
+		t.Fatalf("got %d duplicates, want 1", len(dups))
+	}
+	if got, want := dups[0].Error(), `ids: duplicate id "email" on div > div:nth-of-type(1) > input, div > div:nth-of-type(2) > input`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if Check(nil) != nil {
+		t.Error("expected no duplicates for a nil node")
+	}
+}
+
+func TestAssert(t *testing.T) {
+	page := Assert(div.New(div.New().ID("x"), div.New().ID("x")))
+
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div><div id="x"></div><div id="x"></div></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+
+	errs = nil
+	buf.Reset()
+	if err := fluent.RenderContext(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("got %d errors without assertions, want 0", len(errs))
+	}
+}
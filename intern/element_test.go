@@ -0,0 +1,26 @@
+package intern_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/intern"
+)
+
+func TestElementsIntern(t *testing.T) {
+	key := strings.ToLower("DATA-ROW")
+	a := div.New().Class("row").Class(strings.ToLower("ODD"))
+	a.SetAttribute(key, "1")
+	b := div.New().Class("row").Class("odd")
+	b.SetAttribute("data-row", "2")
+
+	ka, kb := (*a.Attributes())[0].Key, (*b.Attributes())[0].Key
+	if unsafe.StringData(ka) != unsafe.StringData(kb) || unsafe.StringData(ka) != unsafe.StringData(intern.Attr("data-row")) {
+		t.Error("expected attribute keys to share one copy")
+	}
+	if got := string(a.Render()); got != `<div class="row odd" data-row="1"></div>` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package intern deduplicates strings that repeat across large trees: tag
+// names, attribute keys and class lists. Trees built from data - decoded
+// from JSON, parsed from markup or assembled per row of a table - otherwise
+// hold a separate copy of "class" or "btn btn-primary" for every element,
+// each kept alive as long as the tree.
+//
+// The names of HTML elements and common attributes are interned in advance,
+// so looking them up neither allocates nor touches the shared table. Code
+// generators add the names their elements use with Register at init, and
+// build constants from Tag and Attr; the generated html5 elements intern the
+// attribute keys they are given. The codec package interns the names and
+// values of the trees it decodes.
+//
+// Usage:
+//
+//	key := intern.Bytes(raw)                        // no allocation when already interned
+//	cls := intern.Class("btn", "btn-" + variant)    // one copy of each class list
+package intern
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unique"
+)
+
+// known maps pre-interned names to themselves. It is replaced as a whole by
+// Register so lookups need no lock.
+var (
+	known      atomic.Pointer[map[string]string]
+	registerMu sync.Mutex
+)
+
+func init() {
+	m := make(map[string]string, len(tags)+len(attrs))
+	for _, s := range strings.Fields(tags + " " + attrs) {
+		m[s] = s
+	}
+	known.Store(&m)
+}
+
+// String returns the canonical copy of s. Equal strings passed to String
+// share one backing array, and the caller's copy can be freed.
+func String(s string) string {
+	if v, ok := (*known.Load())[s]; ok {
+		return v
+	}
+	return unique.Make(s).Value()
+}
+
+// Bytes returns the canonical string for b. It does not allocate when the
+// string is pre-interned, so it suits names sliced from a larger input.
+func Bytes(b []byte) string {
+	if v, ok := (*known.Load())[string(b)]; ok {
+		return v
+	}
+	return unique.Make(string(b)).Value()
+}
+
+// Class joins class names with spaces, as an element's class attribute
+// holds them, and returns the canonical copy of the result.
+func Class(names ...string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return String(names[0])
+	}
+	return String(strings.Join(names, " "))
+}
+
+// Register pre-interns names so later lookups of them are free. It is meant
+// for init functions of packages with fixed vocabularies, such as
+// generated elements; strings interned on demand need no registration.
+func Register(names ...string) {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	old := *known.Load()
+	m := make(map[string]string, len(old)+len(names))
+	maps.Copy(m, old)
+	for _, s := range names {
+		if _, ok := m[s]; !ok {
+			m[s] = s
+		}
+	}
+	known.Store(&m)
+}
+
+// Tag returns the canonical copy of an element name, for generated code to
+// hold in a constant: var tagDiv = intern.Tag("div").
+func Tag(name string) string {
+	return String(name)
+}
+
+// Attr returns the canonical copy of an attribute key.
+func Attr(key string) string {
+	return String(key)
+}
+
+// tags lists the HTML elements.
+const tags = `a abbr address area article aside audio b base bdi bdo blockquote
+	body br button canvas caption cite code col colgroup data datalist dd del
+	details dfn dialog div dl dt em embed fieldset figcaption figure footer
+	form h1 h2 h3 h4 h5 h6 head header hgroup hr html i iframe img input ins
+	kbd label legend li link main map mark menu meta meter nav noscript
+	object ol optgroup option output p param picture pre progress q rp rt
+	ruby s samp script search section select slot small source span strong
+	style sub summary sup svg table tbody td template textarea tfoot th
+	thead time title tr track u ul var video wbr`
+
+// attrs lists common attribute keys.
+const attrs = `accept accesskey action alt aria-controls aria-describedby
+	aria-expanded aria-hidden aria-label aria-labelledby aria-live
+	aria-current autocomplete autofocus charset checked class cols colspan
+	content contenteditable crossorigin datetime defer dir disabled download
+	draggable enctype for form height hidden href hreflang http-equiv id
+	inputmode integrity lang list loading max maxlength media method min
+	minlength multiple name nonce pattern placeholder readonly rel required
+	role rows rowspan sandbox scope selected sizes slot span src srcset start
+	step style tabindex target title translate type value width
+	hx-get hx-post hx-put hx-patch hx-delete hx-target hx-swap hx-trigger
+	hx-select hx-vals hx-boost hx-push-url hx-indicator hx-confirm`
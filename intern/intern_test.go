@@ -0,0 +1,76 @@
+package intern
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// same reports whether a and b share a backing array.
+func same(a, b string) bool {
+	return unsafe.StringData(a) == unsafe.StringData(b)
+}
+
+func TestString(t *testing.T) {
+	a := String(strings.Repeat("x", 3) + "-widget")
+	b := String("xxx" + strings.ToLower("-WIDGET"))
+	if a != "xxx-widget" || !same(a, b) {
+		t.Errorf("got %q and %q sharing %v, want one shared copy", a, b, same(a, b))
+	}
+	if got := String(""); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	src := []byte(`<div class="card">`)
+	if got := Bytes(src[1:4]); got != "div" || !same(got, String("div")) {
+		t.Errorf("got %q, want the interned div", got)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { Bytes(src[5:10]) }); allocs != 0 {
+		t.Errorf("Bytes allocated %v times for a known name, want 0", allocs)
+	}
+	if got := Bytes([]byte("data-row")); !same(got, String("data-row")) {
+		t.Error("expected unknown names to be interned on demand")
+	}
+}
+
+func TestClass(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"btn"}, "btn"},
+		{[]string{"btn", "btn-primary"}, "btn btn-primary"},
+	}
+	for _, tt := range tests {
+		if got := Class(tt.in...); got != tt.want {
+			t.Errorf("Class(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if a, b := Class("btn", "btn-primary"), Class("btn", "btn-"+"primary"); !same(a, b) {
+		t.Error("expected equal class lists to share a copy")
+	}
+}
+
+func TestPreinterned(t *testing.T) {
+	b := []byte("hx-target")
+	if allocs := testing.AllocsPerRun(100, func() { Bytes(b) }); allocs != 0 {
+		t.Errorf("Bytes allocated %v times for a pre-interned name, want 0", allocs)
+	}
+	if !same(Attr("href"), Attr(string([]byte("href")))) {
+		t.Error("expected attribute keys to be pre-interned")
+	}
+	if !same(Tag("div"), String("div")) {
+		t.Error("expected Tag to return the interned name")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("x-custom-tag")
+	b := []byte("x-custom-tag")
+	if allocs := testing.AllocsPerRun(100, func() { Bytes(b) }); allocs != 0 {
+		t.Errorf("Bytes allocated %v times for a registered name, want 0", allocs)
+	}
+}
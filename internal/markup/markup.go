@@ -0,0 +1,251 @@
+// Package markup is a small, forgiving HTML tokenizer and tree builder for
+// inspecting rendered fluent output.
+//
+// It is not a spec-complete HTML5 parser: it understands tags, attributes,
+// void elements, raw-text elements (script, style, textarea, title),
+// comments, doctypes and processing instructions, which is everything fluent
+// itself produces. Unmatched end tags are ignored and unclosed elements are
+// closed at the end of input.
+package markup
+
+import (
+	"html"
+	"strings"
+)
+
+// TokenType identifies the kind of token.
+type TokenType int
+
+const (
+	TextToken TokenType = iota
+	StartTagToken
+	EndTagToken
+	SelfClosingTagToken
+	CommentToken
+	DoctypeToken
+)
+
+// Attr is a single attribute. Key is lowercase and Val is unescaped.
+type Attr struct {
+	Key string
+	Val string
+	// Name is the key as written in the source, such as SVG's viewBox.
+	Name string
+	// Raw is the attribute exactly as written in the source, value included.
+	Raw string
+}
+
+// Token is a lexical unit of HTML.
+type Token struct {
+	Type TokenType
+	// Data is the lowercase tag name for tags, the unescaped text for text
+	// tokens, and the inner content for comments and doctypes.
+	Data  string
+	Attrs []Attr
+	// Raw is the exact source of the token.
+	Raw string
+	// Offset is the byte offset of the token in the source.
+	Offset int
+}
+
+// voids lists elements that never have content or an end tag.
+var voids = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// rawText lists elements whose content is not parsed as markup.
+var rawText = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// inline lists elements around which whitespace is significant, because it
+// renders as a space between words.
+var inline = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "button": true,
+	"cite": true, "code": true, "data": true, "del": true, "dfn": true, "em": true,
+	"i": true, "img": true, "input": true, "ins": true, "kbd": true, "label": true,
+	"mark": true, "meter": true, "output": true, "progress": true, "q": true,
+	"s": true, "samp": true, "select": true, "small": true, "span": true,
+	"strong": true, "sub": true, "sup": true, "svg": true, "time": true,
+	"u": true, "var": true, "wbr": true, "br": true,
+}
+
+// IsInline reports whether tag is an inline element.
+func IsInline(tag string) bool {
+	return inline[tag]
+}
+
+// IsVoid reports whether tag is a void element.
+func IsVoid(tag string) bool {
+	return voids[tag]
+}
+
+// Tokenize splits src into tokens.
+func Tokenize(src string) []Token {
+	var tokens []Token
+	i := 0
+	text := func(end int) {
+		if end > i {
+			raw := src[i:end]
+			tokens = append(tokens, Token{Type: TextToken, Data: html.UnescapeString(raw), Raw: raw, Offset: i})
+		}
+	}
+	for i < len(src) {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			text(len(src))
+			break
+		}
+		start := i + lt
+		text(start)
+		i = start
+		rest := src[start:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				end = len(rest) - 4
+				i = len(src)
+			} else {
+				i = start + 4 + end + 3
+			}
+			tokens = append(tokens, Token{Type: CommentToken, Data: rest[4 : 4+end], Raw: src[start:i], Offset: start})
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			i = start + end + 1
+			inner := strings.TrimSpace(rest[2:end])
+			tokens = append(tokens, Token{Type: DoctypeToken, Data: inner, Raw: src[start:i], Offset: start})
+		case len(rest) > 1 && (isLetter(rest[1]) || rest[1] == '/'):
+			tok, n := tag(rest)
+			tok.Offset = start
+			i = start + n
+			tokens = append(tokens, tok)
+			if tok.Type == StartTagToken && rawText[tok.Data] {
+				closing := "</" + tok.Data
+				end := indexFold(src[i:], closing)
+				if end < 0 {
+					end = len(src) - i
+				}
+				if end > 0 {
+					raw := src[i : i+end]
+					data := raw
+					if tok.Data == "textarea" || tok.Data == "title" {
+						data = html.UnescapeString(raw)
+					}
+					tokens = append(tokens, Token{Type: TextToken, Data: data, Raw: raw, Offset: i})
+				}
+				i += end
+			}
+		default:
+			// A lone '<' is text.
+			tokens = append(tokens, Token{Type: TextToken, Data: "<", Raw: "<", Offset: start})
+			i = start + 1
+		}
+	}
+	return tokens
+}
+
+// tag parses a start or end tag at the beginning of s.
+func tag(s string) (Token, int) {
+	tok := Token{Type: StartTagToken}
+	i := 1
+	if s[i] == '/' {
+		tok.Type = EndTagToken
+		i++
+	}
+	start := i
+	for i < len(s) && !isSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	tok.Data = strings.ToLower(s[start:i])
+
+	for i < len(s) {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			i++
+			break
+		}
+		if s[i] == '/' {
+			if i+1 < len(s) && s[i+1] == '>' {
+				if tok.Type == StartTagToken {
+					tok.Type = SelfClosingTagToken
+				}
+				i += 2
+				break
+			}
+			i++
+			continue
+		}
+		kstart := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := s[kstart:i]
+		val := ""
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				q := s[i]
+				i++
+				vstart := i
+				for i < len(s) && s[i] != q {
+					i++
+				}
+				val = s[vstart:i]
+				if i < len(s) {
+					i++
+				}
+			} else {
+				vstart := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				val = s[vstart:i]
+			}
+		}
+		if name != "" && tok.Type != EndTagToken {
+			tok.Attrs = append(tok.Attrs, Attr{Key: strings.ToLower(name), Val: html.UnescapeString(val), Name: name, Raw: s[kstart:i]})
+		}
+	}
+	if tok.Type == StartTagToken && voids[tok.Data] {
+		tok.Type = SelfClosingTagToken
+	}
+	tok.Raw = s[:i]
+	return tok, i
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// indexFold is a case-insensitive strings.Index for an ASCII needle.
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
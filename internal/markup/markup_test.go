@@ -0,0 +1,61 @@
+package markup
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	doc := Parse(`<!DOCTYPE html><div class="a b" id=main><p>Hello &amp; <b>bye</b></p><br><img src="x.png" alt='pic' /><script>if (a < b) {}</script><!-- note --></div>`)
+
+	div := doc.Elements()[0]
+	if div.Tag != "div" || !div.HasClass("b") {
+		t.Fatalf("root element = %+v", div)
+	}
+	if id, _ := div.Attr("id"); id != "main" {
+		t.Errorf("unquoted id = %q", id)
+	}
+	kids := div.Elements()
+	if len(kids) != 4 || kids[0].Tag != "p" || kids[1].Tag != "br" || kids[2].Tag != "img" || kids[3].Tag != "script" {
+		t.Fatalf("children = %+v", kids)
+	}
+	if alt, _ := kids[2].Attr("alt"); alt != "pic" {
+		t.Errorf("alt = %q", alt)
+	}
+	if got := div.Text(); got != "Hello & bye" {
+		t.Errorf("Text() = %q, want script excluded", got)
+	}
+	if got := kids[3].Children[0].Data; got != "if (a < b) {}" {
+		t.Errorf("script content = %q", got)
+	}
+	if got := kids[0].Elements()[0].Path(); got != "div#main > p > b" {
+		t.Errorf("Path() = %q", got)
+	}
+}
+
+func TestParseUnbalanced(t *testing.T) {
+	doc := Parse(`<ul><li>one<li>two</span></ul>text`)
+	ul := doc.Elements()[0]
+	if ul.Tag != "ul" || len(doc.Children) != 2 {
+		t.Fatalf("document = %+v", doc.Children)
+	}
+	if doc.Children[1].Data != "text" {
+		t.Errorf("trailing text = %q", doc.Children[1].Data)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	src := `<!DOCTYPE html><div class="a" title="x &amp; &quot;y&quot;"><p>1 &lt; 2</p><br /><script>if (a < b) {}</script><!-- c --></div>`
+	want := `<!DOCTYPE html><div class="a" title="x &amp; &#34;y&#34;"><p>1 &lt; 2</p><br /><script>if (a < b) {}</script><!-- c --></div>`
+	if got := Parse(src).HTML(); got != want {
+		t.Errorf("HTML() = %q, want %q", got, want)
+	}
+	if got := Parse(want).HTML(); got != want {
+		t.Errorf("HTML() does not round trip: %q", got)
+	}
+}
+
+func TestAttrName(t *testing.T) {
+	toks := Tokenize(`<svg viewBox="0 0 1 1">`)
+	a := toks[0].Attrs[0]
+	if a.Key != "viewbox" || a.Name != "viewBox" || a.Val != "0 0 1 1" {
+		t.Errorf("got %+v, want key viewbox named viewBox", a)
+	}
+}
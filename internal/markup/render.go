@@ -0,0 +1,49 @@
+package markup
+
+import (
+	"html"
+	"strings"
+)
+
+// HTML returns the markup of n and its descendants. Attribute values and
+// text are escaped, except the content of script and style elements, so
+// the result parses back to an equivalent tree. A DocumentNode renders its
+// children.
+func (n *Node) HTML() string {
+	var b strings.Builder
+	n.render(&b)
+	return b.String()
+}
+
+func (n *Node) render(b *strings.Builder) {
+	switch n.Type {
+	case DocumentNode:
+		for _, c := range n.Children {
+			c.render(b)
+		}
+	case TextNode:
+		if n.Parent != nil && (n.Parent.Tag == "script" || n.Parent.Tag == "style") {
+			b.WriteString(n.Data)
+		} else {
+			b.WriteString(html.EscapeString(n.Data))
+		}
+	case CommentNode:
+		b.WriteString("<!--" + n.Data + "-->")
+	case DoctypeNode:
+		b.WriteString("<!" + n.Data + ">")
+	case ElementNode:
+		b.WriteString("<" + n.Tag)
+		for _, a := range n.Attrs {
+			b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+		}
+		if IsVoid(n.Tag) {
+			b.WriteString(" />")
+			return
+		}
+		b.WriteByte('>')
+		for _, c := range n.Children {
+			c.render(b)
+		}
+		b.WriteString("</" + n.Tag + ">")
+	}
+}
@@ -0,0 +1,500 @@
+package markup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Selector is a compiled CSS selector. It supports type, universal, #id,
+// .class and [attribute] selectors with the =, ~=, |=, ^=, $= and *=
+// operators, the descendant, >, + and ~ combinators, selector lists, and the
+// pseudo-classes :first-child, :last-child, :only-child, :nth-child(),
+// :nth-last-child(), :nth-of-type(), :first-of-type, :last-of-type, :empty
+// and :not().
+type Selector struct {
+	list []chain
+}
+
+// chain is a complex selector: compounds joined by combinators, where
+// combinators[i] joins compounds[i] and compounds[i+1].
+type chain struct {
+	compounds   []compound
+	combinators []byte
+}
+
+type compound struct {
+	tag     string
+	ids     []string
+	classes []string
+	attrs   []attrSelector
+	pseudos []pseudo
+}
+
+type attrSelector struct {
+	key, op, val string
+}
+
+type pseudo struct {
+	name string
+	a, b int       // an+b for the nth pseudo-classes
+	not  *Selector // argument of :not
+}
+
+// Compile parses a selector.
+func Compile(src string) (*Selector, error) {
+	p := &selectorParser{src: src}
+	s, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return s, nil
+}
+
+// Match reports whether n is an element matched by s.
+func (s *Selector) Match(n *Node) bool {
+	if n == nil || n.Type != ElementNode {
+		return false
+	}
+	for _, c := range s.list {
+		if c.match(n, len(c.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns the descendants of n matched by s in document order.
+func (n *Node) Query(s *Selector) []*Node {
+	var out []*Node
+	for _, c := range n.Children {
+		c.Walk(func(d *Node) bool {
+			if s.Match(d) {
+				out = append(out, d)
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// match reports whether n matches the chain up to compound i, trying every
+// candidate for the combinator to its left.
+func (c chain) match(n *Node, i int) bool {
+	if !c.compounds[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch c.combinators[i-1] {
+	case '>':
+		p := parentElement(n)
+		return p != nil && c.match(p, i-1)
+	case '+':
+		p := previousElement(n)
+		return p != nil && c.match(p, i-1)
+	case '~':
+		for p := previousElement(n); p != nil; p = previousElement(p) {
+			if c.match(p, i-1) {
+				return true
+			}
+		}
+	default:
+		for p := parentElement(n); p != nil; p = parentElement(p) {
+			if c.match(p, i-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *compound) match(n *Node) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != n.Tag {
+		return false
+	}
+	for _, id := range c.ids {
+		if v, _ := n.Attr("id"); v != id {
+			return false
+		}
+	}
+	for _, class := range c.classes {
+		if !n.HasClass(class) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.match(n) {
+			return false
+		}
+	}
+	for _, p := range c.pseudos {
+		if !p.match(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(n *Node) bool {
+	v, ok := n.Attr(a.key)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == a.val
+	case "~=":
+		for _, f := range strings.Fields(v) {
+			if f == a.val {
+				return true
+			}
+		}
+		return false
+	case "|=":
+		return v == a.val || strings.HasPrefix(v, a.val+"-")
+	case "^=":
+		return a.val != "" && strings.HasPrefix(v, a.val)
+	case "$=":
+		return a.val != "" && strings.HasSuffix(v, a.val)
+	case "*=":
+		return a.val != "" && strings.Contains(v, a.val)
+	}
+	return false
+}
+
+func (p pseudo) match(n *Node) bool {
+	switch p.name {
+	case "not":
+		return !p.not.Match(n)
+	case "empty":
+		for _, c := range n.Children {
+			if c.Type == ElementNode || c.Type == TextNode && c.Data != "" {
+				return false
+			}
+		}
+		return true
+	case "first-child":
+		return position(n, false, false) == 1
+	case "last-child":
+		return position(n, true, false) == 1
+	case "only-child":
+		return position(n, false, false) == 1 && position(n, true, false) == 1
+	case "first-of-type":
+		return position(n, false, true) == 1
+	case "last-of-type":
+		return position(n, true, true) == 1
+	case "nth-child":
+		return nth(p.a, p.b, position(n, false, false))
+	case "nth-last-child":
+		return nth(p.a, p.b, position(n, true, false))
+	case "nth-of-type":
+		return nth(p.a, p.b, position(n, false, true))
+	}
+	return false
+}
+
+// nth reports whether the 1-based position i is an+b for some n >= 0.
+func nth(a, b, i int) bool {
+	if a == 0 {
+		return i == b
+	}
+	return (i-b)%a == 0 && (i-b)/a >= 0
+}
+
+// position returns the 1-based index of n among its element siblings,
+// counting from the end when last is set and only siblings with the same tag
+// when ofType is set.
+func position(n *Node, last, ofType bool) int {
+	if n.Parent == nil {
+		return 1
+	}
+	siblings := n.Parent.Elements()
+	i := 0
+	for j := range siblings {
+		s := siblings[j]
+		if last {
+			s = siblings[len(siblings)-1-j]
+		}
+		if !ofType || s.Tag == n.Tag {
+			i++
+		}
+		if s == n {
+			return i
+		}
+	}
+	return 0
+}
+
+func parentElement(n *Node) *Node {
+	if n.Parent == nil || n.Parent.Type != ElementNode {
+		return nil
+	}
+	return n.Parent
+}
+
+func previousElement(n *Node) *Node {
+	if n.Parent == nil {
+		return nil
+	}
+	var prev *Node
+	for _, c := range n.Parent.Children {
+		if c == n {
+			return prev
+		}
+		if c.Type == ElementNode {
+			prev = c
+		}
+	}
+	return nil
+}
+
+// selectorParser is a recursive descent parser for selectors.
+type selectorParser struct {
+	src string
+	pos int
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("markup: invalid selector %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *selectorParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// list parses a comma-separated selector list, stopping at the end of input
+// or a closing parenthesis.
+func (p *selectorParser) list() (*Selector, error) {
+	s := &Selector{}
+	for {
+		p.skipSpace()
+		c, err := p.chain()
+		if err != nil {
+			return nil, err
+		}
+		s.list = append(s.list, c)
+		if p.peek() != ',' {
+			return s, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) chain() (chain, error) {
+	var c chain
+	for {
+		comp, err := p.compound()
+		if err != nil {
+			return c, err
+		}
+		c.compounds = append(c.compounds, comp)
+
+		space := p.skipSpace()
+		switch ch := p.peek(); ch {
+		case '>', '+', '~':
+			p.pos++
+			p.skipSpace()
+			c.combinators = append(c.combinators, ch)
+		case 0, ',', ')':
+			return c, nil
+		default:
+			if !space {
+				return c, p.errorf("unexpected %q", ch)
+			}
+			c.combinators = append(c.combinators, ' ')
+		}
+	}
+}
+
+func (p *selectorParser) compound() (compound, error) {
+	var c compound
+	start := p.pos
+	if p.peek() == '*' {
+		p.pos++
+		c.tag = "*"
+	} else if name := p.ident(); name != "" {
+		c.tag = strings.ToLower(name)
+	}
+	for {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			id := p.ident()
+			if id == "" {
+				return c, p.errorf("expected an id")
+			}
+			c.ids = append(c.ids, id)
+		case '.':
+			p.pos++
+			class := p.ident()
+			if class == "" {
+				return c, p.errorf("expected a class name")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			a, err := p.attribute()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			ps, err := p.pseudo()
+			if err != nil {
+				return c, err
+			}
+			c.pseudos = append(c.pseudos, ps)
+		default:
+			if p.pos == start {
+				return c, p.errorf("expected a selector")
+			}
+			return c, nil
+		}
+	}
+}
+
+func (p *selectorParser) attribute() (attrSelector, error) {
+	p.pos++ // [
+	p.skipSpace()
+	a := attrSelector{key: strings.ToLower(p.ident())}
+	if a.key == "" {
+		return a, p.errorf("expected an attribute name")
+	}
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return a, nil
+	}
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			a.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if a.op == "" {
+		return a, p.errorf("expected an attribute operator")
+	}
+	p.skipSpace()
+	if q := p.peek(); q == '"' || q == '\'' {
+		end := strings.IndexByte(p.src[p.pos+1:], q)
+		if end < 0 {
+			return a, p.errorf("unterminated string")
+		}
+		a.val = p.src[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	} else {
+		a.val = p.ident()
+	}
+	p.skipSpace()
+	if p.peek() != ']' {
+		return a, p.errorf("expected ]")
+	}
+	p.pos++
+	return a, nil
+}
+
+func (p *selectorParser) pseudo() (pseudo, error) {
+	p.pos++ // :
+	ps := pseudo{name: strings.ToLower(p.ident())}
+	switch ps.name {
+	case "first-child", "last-child", "only-child", "first-of-type", "last-of-type", "empty":
+		return ps, nil
+	case "not", "nth-child", "nth-last-child", "nth-of-type":
+	default:
+		return ps, p.errorf("unsupported pseudo-class %q", ps.name)
+	}
+	if p.peek() != '(' {
+		return ps, p.errorf("expected (")
+	}
+	p.pos++
+	if ps.name == "not" {
+		s, err := p.list()
+		if err != nil {
+			return ps, err
+		}
+		ps.not = s
+	} else {
+		end := strings.IndexByte(p.src[p.pos:], ')')
+		if end < 0 {
+			return ps, p.errorf("expected )")
+		}
+		a, b, ok := parseNth(p.src[p.pos : p.pos+end])
+		if !ok {
+			return ps, p.errorf("invalid argument to :%s", ps.name)
+		}
+		ps.a, ps.b = a, b
+		p.pos += end
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		return ps, p.errorf("expected )")
+	}
+	p.pos++
+	return ps, nil
+}
+
+// parseNth parses the an+b argument of the nth pseudo-classes, including the
+// odd and even keywords.
+func parseNth(s string) (a, b int, ok bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	switch s {
+	case "odd":
+		return 2, 1, true
+	case "even":
+		return 2, 0, true
+	}
+	i := strings.IndexByte(s, 'n')
+	if i < 0 {
+		b, err := strconv.Atoi(s)
+		return 0, b, err == nil
+	}
+	switch coef := s[:i]; coef {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(coef); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rest := s[i+1:]; rest != "" {
+		var err error
+		if b, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, false
+		}
+	}
+	return a, b, true
+}
+
+// ident reads a name made of letters, digits, hyphens and underscores.
+func (p *selectorParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !isLetter(c) && !(c >= '0' && c <= '9') && c != '-' && c != '_' && c < 0x80 {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
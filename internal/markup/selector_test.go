@@ -0,0 +1,74 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+const page = `<div id="main" class="list">` +
+	`<h2 lang="en-GB">Items</h2>` +
+	`<ul><li class="item active"><a href="/a">A</a></li><li class="item"><a>B</a></li><li class="item sale"><a href="https://x.test/c">C</a></li></ul>` +
+	`<p></p><p>end</p>` +
+	`</div>`
+
+func TestSelector(t *testing.T) {
+	doc := Parse(page)
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"li", "li li li"},
+		{"*", "div h2 ul li a li a li a p p"},
+		{"#main > h2", "h2"},
+		{"div.list a", "a a a"},
+		{"ul > a", ""},
+		{"li.item.active", "li"},
+		{"a[href]", "a a"},
+		{`a[href^="https:"]`, "a"},
+		{"a[href$=c]", "a"},
+		{"a[href*='x.test']", "a"},
+		{"li[class~=sale]", "li"},
+		{"h2[lang|=en]", "h2"},
+		{"li:first-child a", "a"},
+		{"li:last-child", "li"},
+		{"li:nth-child(2)", "li"},
+		{"li:nth-child(odd)", "li li"},
+		{"li:nth-child(-n+2)", "li li"},
+		{"li:nth-last-child(1)", "li"},
+		{"p:empty", "p"},
+		{"p:first-of-type", "p"},
+		{"p:last-of-type", "p"},
+		{"h2 + ul", "ul"},
+		{"h2 ~ p", "p p"},
+		{"li:not(.active, .sale)", "li"},
+		{"a:only-child", "a a a"},
+		{"h2, p", "h2 p p"},
+	}
+	for _, tt := range tests {
+		s, err := Compile(tt.selector)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.selector, err)
+			continue
+		}
+		var tags []string
+		for _, n := range doc.Query(s) {
+			tags = append(tags, n.Tag)
+		}
+		if got := strings.Join(tags, " "); got != tt.want {
+			t.Errorf("%q matched %q, want %q", tt.selector, got, tt.want)
+		}
+	}
+
+	s, _ := Compile("li:nth-child(2) a")
+	if got := doc.Query(s); len(got) != 1 || got[0].Text() != "B" {
+		t.Errorf("nth-child(2) descendant = %v", got)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, sel := range []string{"", "div >", "#", "a[href", "a[href=", "a:hover", "li:nth-child(x)", "a b)", "[=x]", "a,"} {
+		if _, err := Compile(sel); err == nil {
+			t.Errorf("Compile(%q) succeeded", sel)
+		}
+	}
+}
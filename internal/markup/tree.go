@@ -0,0 +1,157 @@
+package markup
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NodeType identifies the kind of tree node.
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	TextNode
+	CommentNode
+	DoctypeNode
+)
+
+// Node is a node in a parsed document.
+type Node struct {
+	Type NodeType
+	// Tag is the lowercase element name for element nodes.
+	Tag   string
+	Attrs []Attr
+	// Data is the unescaped text for text nodes and the content of comments and doctypes.
+	Data     string
+	Parent   *Node
+	Children []*Node
+	// Offset is the byte offset of the node in the source.
+	Offset int
+}
+
+// Parse builds a tree from src. The returned node is a DocumentNode.
+func Parse(src string) *Node {
+	doc := &Node{Type: DocumentNode}
+	stack := []*Node{doc}
+	top := func() *Node { return stack[len(stack)-1] }
+	appendChild := func(n *Node) {
+		p := top()
+		n.Parent = p
+		p.Children = append(p.Children, n)
+	}
+
+	for _, tok := range Tokenize(src) {
+		switch tok.Type {
+		case TextToken:
+			appendChild(&Node{Type: TextNode, Data: tok.Data, Offset: tok.Offset})
+		case CommentToken:
+			appendChild(&Node{Type: CommentNode, Data: tok.Data, Offset: tok.Offset})
+		case DoctypeToken:
+			appendChild(&Node{Type: DoctypeNode, Data: tok.Data, Offset: tok.Offset})
+		case SelfClosingTagToken:
+			appendChild(&Node{Type: ElementNode, Tag: tok.Data, Attrs: tok.Attrs, Offset: tok.Offset})
+		case StartTagToken:
+			n := &Node{Type: ElementNode, Tag: tok.Data, Attrs: tok.Attrs, Offset: tok.Offset}
+			appendChild(n)
+			stack = append(stack, n)
+		case EndTagToken:
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Tag == tok.Data {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+	return doc
+}
+
+// Attr returns the value of the named attribute.
+func (n *Node) Attr(key string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// HasClass reports whether the element's class attribute contains name.
+func (n *Node) HasClass(name string) bool {
+	cls, _ := n.Attr("class")
+	for _, c := range strings.Fields(cls) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Elements returns the element children of n.
+func (n *Node) Elements() []*Node {
+	var out []*Node
+	for _, c := range n.Children {
+		if c.Type == ElementNode {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Walk calls fn for n and every descendant in document order. Returning false
+// from fn skips the node's descendants.
+func (n *Node) Walk(fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// Text returns the concatenated text of n and its descendants, excluding
+// script and style content.
+func (n *Node) Text() string {
+	var b strings.Builder
+	n.Walk(func(c *Node) bool {
+		if c.Type == ElementNode && (c.Tag == "script" || c.Tag == "style") {
+			return false
+		}
+		if c.Type == TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+// Path returns a CSS-like path from the document root to n, e.g.
+// "html > body > div:nth-of-type(2) > h2".
+func (n *Node) Path() string {
+	var parts []string
+	for c := n; c != nil && c.Type == ElementNode; c = c.Parent {
+		part := c.Tag
+		if id, ok := c.Attr("id"); ok && id != "" {
+			part += "#" + id
+		} else if c.Parent != nil {
+			same, index := 0, 0
+			for _, s := range c.Parent.Elements() {
+				if s.Tag == c.Tag {
+					same++
+				}
+				if s == c {
+					index = same
+				}
+			}
+			if same > 1 {
+				part += ":nth-of-type(" + strconv.Itoa(index) + ")"
+			}
+		}
+		parts = append(parts, part)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
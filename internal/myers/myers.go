@@ -0,0 +1,185 @@
+// Package myers implements the Myers O(ND) difference algorithm over slices.
+package myers
+
+// Op is the kind of an edit.
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is a single step in the edit script. A is the index in the old slice
+// for Equal and Delete; B is the index in the new slice for Equal and Insert.
+type Edit struct {
+	Op Op
+	A  int
+	B  int
+}
+
+// Diff returns the shortest edit script transforming a into b. Within each
+// run of changes, deletions come before insertions.
+//
+// It uses the linear-space refinement of the algorithm: each step finds the
+// middle of the shortest path and recurses on either side of it, so memory
+// grows with len(a)+len(b) rather than with its product by the number of
+// changes.
+func Diff[T comparable](a, b []T) []Edit {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	var ops []Op
+	diff(a, b, &ops)
+	return script(ops)
+}
+
+// diff appends the operations transforming a into b to ops.
+func diff[T comparable](a, b []T, ops *[]Op) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	repeat(ops, Equal, prefix)
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		repeat(ops, Insert, len(b))
+	case len(b) == 0:
+		repeat(ops, Delete, len(a))
+	default:
+		if x, y, ok := middle(a, b); ok {
+			diff(a[:x], b[:y], ops)
+			diff(a[x:], b[y:], ops)
+		} else {
+			repeat(ops, Delete, len(a))
+			repeat(ops, Insert, len(b))
+		}
+	}
+	repeat(ops, Equal, suffix)
+}
+
+// middle searches from both ends of the edit graph at once and returns the
+// point where the forward and backward paths meet, which lies on a shortest
+// path. It reports false when a and b have nothing in common. Both a and b
+// must be non-empty.
+func middle[T comparable](a, b []T) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// When delta is odd the paths overlap during a forward step,
+	// otherwise during a backward one.
+	odd := delta%2 != 0
+	// Diagonals that have left the graph are trimmed from later steps.
+	var fstart, fend, bstart, bend int
+
+	for d := range maxD {
+		for k := -d + fstart; k <= d-fend; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fend += 2
+			case y > m:
+				fstart += 2
+			case odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x >= n-backward[j] {
+					return x, y, true
+				}
+			}
+		}
+		for k := -d + bstart; k <= d-bend; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+			switch {
+			case x > n:
+				bend += 2
+			case y > m:
+				bstart += 2
+			case !odd:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 {
+					fx := forward[j]
+					if fx >= n-x {
+						return fx, fx - (j - offset), true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// repeat appends count copies of op to ops.
+func repeat(ops *[]Op, op Op, count int) {
+	for range count {
+		*ops = append(*ops, op)
+	}
+}
+
+// script numbers ops into edits, moving the deletions of each run of changes
+// ahead of its insertions.
+func script(ops []Op) []Edit {
+	edits := make([]Edit, 0, len(ops))
+	x, y := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i] == Equal {
+			edits = append(edits, Edit{Op: Equal, A: x, B: y})
+			x, y, i = x+1, y+1, i+1
+			continue
+		}
+		deletes, inserts := 0, 0
+		for ; i < len(ops) && ops[i] != Equal; i++ {
+			if ops[i] == Delete {
+				deletes++
+			} else {
+				inserts++
+			}
+		}
+		for range deletes {
+			edits = append(edits, Edit{Op: Delete, A: x, B: y})
+			x++
+		}
+		for range inserts {
+			edits = append(edits, Edit{Op: Insert, A: x, B: y})
+			y++
+		}
+	}
+	return edits
+}
@@ -0,0 +1,71 @@
+package myers
+
+import (
+	"strings"
+	"testing"
+)
+
+// apply rebuilds b from a and the edit script.
+func apply(a, b []string, edits []Edit) []string {
+	var out []string
+	for _, e := range edits {
+		switch e.Op {
+		case Equal:
+			out = append(out, a[e.A])
+		case Insert:
+			out = append(out, b[e.B])
+		}
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"a b c", "a b c", 0},
+		{"", "a b", 2},
+		{"a b", "", 2},
+		{"the quick brown fox", "the slow brown dog", 4},
+		{"a b c a b b a", "c b a b a c", 5},
+	}
+	for _, tt := range tests {
+		a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+		edits := Diff(a, b)
+		if got := strings.Join(apply(a, b, edits), " "); got != tt.b {
+			t.Errorf("Diff(%q, %q) rebuilds %q", tt.a, tt.b, got)
+		}
+		changes := 0
+		for _, e := range edits {
+			if e.Op != Equal {
+				changes++
+			}
+		}
+		if changes != tt.edits {
+			t.Errorf("Diff(%q, %q) has %d changes, want %d", tt.a, tt.b, changes, tt.edits)
+		}
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	// Every other element differs, so the script has 10000 changes. Keeping
+	// a copy of the frontier per change would need gigabytes.
+	a, b := make([]int, 10000), make([]int, 10000)
+	for i := range a {
+		a[i], b[i] = i, i
+		if i%2 == 0 {
+			b[i] = -i - 1
+		}
+	}
+	changes := 0
+	for _, e := range Diff(a, b) {
+		if e.Op != Equal {
+			changes++
+		}
+	}
+	if changes != 10000 {
+		t.Errorf("got %d changes, want 10000", changes)
+	}
+}
@@ -0,0 +1,149 @@
+// Package pretty indents rendered HTML for reading during development.
+package pretty
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+)
+
+// indent is written once per level of nesting.
+const indent = "  "
+
+// preserved lists elements whose content is written as it is.
+var preserved = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// HTML returns src with each block-level element on its own line, indented
+// by its depth. Inline elements and text stay on the line of the content
+// around them, an element holding only inline content is kept on one line,
+// and whitespace-only text between block-level tags is dropped. The content
+// of <pre>, <textarea>, <script> and <style> is left untouched.
+func HTML(src []byte) []byte {
+	tokens := markup.Tokenize(string(src))
+	p := &printer{out: bytes.NewBuffer(make([]byte, 0, len(src)+len(src)/4))}
+
+	for i, tok := range tokens {
+		if p.keep != "" {
+			if tok.Type == markup.EndTagToken && tok.Data == p.keep {
+				p.keep = ""
+				p.close(tok)
+				continue
+			}
+			p.out.WriteString(tok.Raw)
+			continue
+		}
+		switch tok.Type {
+		case markup.StartTagToken, markup.SelfClosingTagToken:
+			if markup.IsInline(tok.Data) {
+				p.inline(tok.Raw)
+				continue
+			}
+			p.line()
+			p.out.WriteString(tok.Raw)
+			p.markParent()
+			if tok.Type == markup.SelfClosingTagToken || markup.IsVoid(tok.Data) {
+				p.ended = true
+				continue
+			}
+			p.ended = false
+			p.stack = append(p.stack, false)
+			if preserved[tok.Data] {
+				p.keep = tok.Data
+			}
+		case markup.EndTagToken:
+			if markup.IsInline(tok.Data) {
+				p.inline(tok.Raw)
+				continue
+			}
+			p.close(tok)
+		case markup.TextToken:
+			if strings.TrimSpace(tok.Raw) == "" && p.boundary(tokens, i) {
+				continue
+			}
+			p.inline(tok.Raw)
+		default:
+			p.line()
+			p.out.WriteString(tok.Raw)
+			p.ended = true
+			p.markParent()
+		}
+	}
+	return p.out.Bytes()
+}
+
+type printer struct {
+	out *bytes.Buffer
+	// stack records, for each open block-level element, whether it holds
+	// block-level content and so ends on a line of its own.
+	stack []bool
+	// ended is set after the end of a block-level element, a void element
+	// or a comment, so following inline content starts a new line.
+	ended bool
+	// keep is the preserved element whose content is being copied.
+	keep string
+}
+
+// line starts a new line at the current depth, unless nothing has been
+// written yet.
+func (p *printer) line() {
+	if p.out.Len() == 0 {
+		return
+	}
+	p.out.WriteByte('\n')
+	p.out.WriteString(strings.Repeat(indent, len(p.stack)))
+}
+
+// markParent records that the enclosing element holds block-level content.
+func (p *printer) markParent() {
+	if n := len(p.stack); n > 0 {
+		p.stack[n-1] = true
+	}
+}
+
+// inline writes inline content, on a new line if it follows the end of a
+// block-level element.
+func (p *printer) inline(raw string) {
+	if p.ended {
+		p.line()
+		p.markParent()
+		p.ended = false
+	}
+	p.out.WriteString(raw)
+}
+
+// close writes the end tag of a block-level element, on its own line when
+// the element holds block-level content.
+func (p *printer) close(tok markup.Token) {
+	nested := false
+	if n := len(p.stack); n > 0 {
+		nested = p.stack[n-1]
+		p.stack = p.stack[:n-1]
+	}
+	if nested {
+		p.line()
+	}
+	p.out.WriteString(tok.Raw)
+	p.ended = true
+	p.markParent()
+}
+
+// boundary reports whether the whitespace-only text token at i borders a
+// block-level tag, where it is not significant.
+func (p *printer) boundary(tokens []markup.Token, i int) bool {
+	blockAt := func(j int) bool {
+		if j < 0 || j >= len(tokens) {
+			return true
+		}
+		switch tokens[j].Type {
+		case markup.TextToken:
+			return false
+		case markup.StartTagToken, markup.EndTagToken, markup.SelfClosingTagToken:
+			return !markup.IsInline(tokens[j].Data)
+		}
+		return true
+	}
+	return blockAt(i-1) || blockAt(i+1)
+}
@@ -0,0 +1,32 @@
+package pretty
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"inline only", `<p>Hello <b>world</b></p>`, `<p>Hello <b>world</b></p>`},
+		{"nested blocks", `<div><h1>Title</h1><p>Body</p></div>`,
+			"<div>\n  <h1>Title</h1>\n  <p>Body</p>\n</div>"},
+		{"text after block", `<div><p>a</p>tail</div>`,
+			"<div>\n  <p>a</p>\n  tail\n</div>"},
+		{"void", `<div><hr /><br />x</div>`,
+			"<div>\n  <hr />\n  <br />x\n</div>"},
+		{"document", `<!DOCTYPE html><html><head><title>T</title></head><body></body></html>`,
+			"<!DOCTYPE html>\n<html>\n  <head>\n    <title>T</title>\n  </head>\n  <body></body>\n</html>"},
+		{"preserved", "<div><pre>  a\n<b>b</b></pre><script>if (a<b) {}</script></div>",
+			"<div>\n  <pre>  a\n<b>b</b></pre>\n  <script>if (a<b) {}</script>\n</div>"},
+		{"whitespace", "<ul>\n <li>a</li>\n <li>b <i>c</i></li>\n</ul>",
+			"<ul>\n  <li>a</li>\n  <li>b <i>c</i></li>\n</ul>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(HTML([]byte(tt.src))); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package websocket
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+	// The example from RFC 6455 section 1.3.
+	if got, want := accept("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func echo(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msg, err := conn.Read()
+			if err != nil {
+				return
+			}
+			if err := conn.Write(msg); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEcho(t *testing.T) {
+	srv := echo(t)
+	conn, err := Dial("ws" + strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, msg := range []string{"hello", "", strings.Repeat("x", 200), strings.Repeat("y", 70000)} {
+		if err := conn.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+		got, err := conn.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != msg {
+			t.Errorf("got %d bytes, want %d", len(got), len(msg))
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := echo(t)
+	conn, err := Dial("ws" + strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.write(opPing, []byte("are you there")); err != nil {
+		t.Fatal(err)
+	}
+	fin, op, payload, err := conn.frame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fin || op != opPong || string(payload) != "are you there" {
+		t.Errorf("got fin=%v op=%#x payload=%q, want a pong echoing the ping", fin, op, payload)
+	}
+}
+
+func TestClose(t *testing.T) {
+	srv := echo(t)
+	conn, err := Dial("ws" + strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.write(opClose, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Read(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
+
+func TestRejectsUnmasked(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	c := &Conn{conn: server, r: bufio.NewReader(server)}
+	go func() {
+		// An unmasked text frame, then drain the close frame sent back.
+		_, _ = client.Write([]byte{0x81, 0x02, 'h', 'i'})
+		_, _ = io.Copy(io.Discard, client)
+	}()
+	if _, err := c.Read(); !errors.Is(err, ErrProtocol) {
+		t.Errorf("got %v, want ErrProtocol", err)
+	}
+}
+
+func TestUpgradeRejectsPlainRequest(t *testing.T) {
+	srv := echo(t)
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestUpgradeChecksOrigin(t *testing.T) {
+	srv := echo(t)
+	for origin, want := range map[string]int{
+		"https://evil.example":                   http.StatusForbidden,
+		"null":                                   http.StatusForbidden,
+		"http://" + srv.Listener.Addr().String(): http.StatusSwitchingProtocols,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("origin %q: got %d, want %d", origin, resp.StatusCode, want)
+		}
+	}
+}
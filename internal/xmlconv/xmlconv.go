@@ -0,0 +1,117 @@
+// Package xmlconv rewrites rendered HTML as well-formed XML. It backs the
+// xhtml package and fluent's XHTML render setting.
+package xmlconv
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/internal/markup"
+)
+
+// Namespace is the XHTML namespace added to an <html> root.
+const Namespace = "http://www.w3.org/1999/xhtml"
+
+// Convert rewrites rendered HTML as XML.
+func Convert(src string) string {
+	var b strings.Builder
+	b.Grow(len(src) + len(src)/8)
+	root := true
+	raw := false // inside script or style
+	for _, tok := range markup.Tokenize(src) {
+		switch tok.Type {
+		case markup.StartTagToken, markup.SelfClosingTagToken:
+			b.WriteString("<" + tagName(tok, 1))
+			for _, a := range tok.Attrs {
+				b.WriteString(" " + a.Name + `="` + escapeAttr(clean(a.Val)) + `"`)
+			}
+			if root && tok.Data == "html" {
+				if _, ok := attr(tok, "xmlns"); !ok {
+					b.WriteString(` xmlns="` + Namespace + `"`)
+				}
+			}
+			root = false
+			if tok.Type == markup.SelfClosingTagToken {
+				b.WriteString(" />")
+				continue
+			}
+			b.WriteByte('>')
+			raw = tok.Data == "script" || tok.Data == "style"
+		case markup.EndTagToken:
+			raw = false
+			if markup.IsVoid(tok.Data) {
+				continue
+			}
+			b.WriteString("</" + tagName(tok, 2) + ">")
+		case markup.TextToken:
+			text := clean(tok.Data)
+			if raw && strings.ContainsAny(text, "<&") {
+				b.WriteString("<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>")
+			} else {
+				b.WriteString(escapeText(text))
+			}
+		case markup.CommentToken:
+			// XML forbids -- inside a comment and a - before its end.
+			data := strings.ReplaceAll(clean(tok.Data), "--", "- -")
+			if strings.HasSuffix(data, "-") {
+				data += " "
+			}
+			b.WriteString("<!--" + data + "-->")
+		default:
+			b.WriteString(tok.Raw)
+		}
+	}
+	return b.String()
+}
+
+// tagName returns the tag's name as written in the source, so that SVG's
+// camelCase names such as linearGradient survive; offset skips "<" or "</".
+func tagName(tok markup.Token, offset int) string {
+	if len(tok.Raw) >= offset+len(tok.Data) {
+		return tok.Raw[offset : offset+len(tok.Data)]
+	}
+	return tok.Data
+}
+
+func attr(tok markup.Token, key string) (string, bool) {
+	for _, a := range tok.Attrs {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+var (
+	escapeText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	escapeAttr = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace
+)
+
+// clean replaces characters XML does not allow, including invalid UTF-8,
+// with U+FFFD.
+func clean(s string) string {
+	valid := func(r rune) bool {
+		return r == '\t' || r == '\n' || r == '\r' ||
+			r >= 0x20 && r <= 0xD7FF || r >= 0xE000 && r <= 0xFFFD || r >= 0x10000 && r <= 0x10FFFF
+	}
+	ok := utf8.ValidString(s)
+	if ok {
+		for _, r := range s {
+			if !valid(r) {
+				ok = false
+				break
+			}
+		}
+	}
+	if ok {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if !valid(r) {
+			r = utf8.RuneError
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
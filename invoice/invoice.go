@@ -0,0 +1,280 @@
+// Package invoice provides invoice and receipt document components that
+// render for the web, for print, for HTML-to-PDF conversion and for email
+// from a single definition.
+//
+// Amounts are integers in the currency's minor unit (cents), so totals never
+// accumulate floating-point error. Tax and total computation is delegated to a
+// Calculator, which can be replaced to apply discounts, tax-inclusive pricing
+// or jurisdiction-specific rounding.
+//
+// Usage:
+//
+//	inv := invoice.New(invoice.Invoice, "INV-0042").
+//	    Issued(time.Now()).
+//	    Due(time.Now().AddDate(0, 0, 14)).
+//	    From(invoice.Address{Name: "Acme Pty Ltd", Lines: []string{"1 Example St", "Sydney NSW 2000"}}).
+//	    To(invoice.Address{Name: "Jo Bloggs"}).
+//	    Currency("AUD").
+//	    Add(
+//	        invoice.Item{Description: "Consulting", Quantity: 3, UnitPrice: 15000, TaxRate: 0.1},
+//	        invoice.Item{Description: "Travel", Quantity: 1, UnitPrice: 4250},
+//	    ).
+//	    Payment(invoice.Payment{Reference: "INV-0042", Instructions: []string{"BSB 062-000", "Account 1234 5678"}})
+//
+//	inv.Render(w)                          // web page fragment
+//	inv.View(invoice.Email).Render(mail)   // inline-styled email body
+//	inv.View(invoice.PDF).Render(renderer) // standalone document for an HTML-to-PDF converter
+package invoice
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Kind is the type of document.
+type Kind string
+
+const (
+	Invoice Kind = "Invoice"
+	Receipt Kind = "Receipt"
+)
+
+// Mode selects how a document is rendered.
+type Mode int
+
+const (
+	// Web renders an <article> with class names for the site's stylesheet.
+	Web Mode = iota
+	// Print renders the Web markup with an embedded stylesheet that lays the
+	// document out for paper when printed from the browser.
+	Print
+	// PDF renders a standalone HTML document with @page rules, ready for an
+	// HTML-to-PDF converter.
+	PDF
+	// Email renders with inline styles only, as most mail clients strip
+	// <style> blocks and ignore class names.
+	Email
+)
+
+// Address is a party to the document.
+type Address struct {
+	Name  string
+	Lines []string
+}
+
+// Item is a line on the document.
+type Item struct {
+	Description string
+	Quantity    float64
+	// UnitPrice is in the currency's minor unit.
+	UnitPrice int64
+	// TaxRate is a fraction, e.g. 0.1 for 10%.
+	TaxRate float64
+}
+
+// Amount returns Quantity × UnitPrice, rounded to the nearest minor unit.
+func (it Item) Amount() int64 {
+	return int64(math.Round(it.Quantity * float64(it.UnitPrice)))
+}
+
+// Tax returns the tax on Amount, rounded to the nearest minor unit.
+func (it Item) Tax() int64 {
+	return int64(math.Round(float64(it.Amount()) * it.TaxRate))
+}
+
+// Adjustment is an extra line in the totals, such as a discount (negative)
+// or a surcharge.
+type Adjustment struct {
+	Label  string
+	Amount int64
+}
+
+// Totals is the result of a calculation.
+type Totals struct {
+	Subtotal    int64
+	Tax         int64
+	Adjustments []Adjustment
+	Total       int64
+}
+
+// Calculator computes the totals for a document's items.
+type Calculator interface {
+	Totals(items []Item) Totals
+}
+
+// CalculatorFunc adapts a function to the Calculator interface.
+type CalculatorFunc func(items []Item) Totals
+
+// Totals returns f(items).
+func (f CalculatorFunc) Totals(items []Item) Totals {
+	return f(items)
+}
+
+// Standard sums line amounts and per-line tax, rounding each line
+// independently. It is the default Calculator.
+var Standard Calculator = CalculatorFunc(func(items []Item) Totals {
+	var t Totals
+	for _, it := range items {
+		t.Subtotal += it.Amount()
+		t.Tax += it.Tax()
+	}
+	t.Total = t.Subtotal + t.Tax
+	return t
+})
+
+// Payment describes how to pay an invoice, shown on the payment stub.
+type Payment struct {
+	Reference    string
+	Instructions []string
+}
+
+// Document is an invoice or receipt. Create one with New.
+type Document struct {
+	kind     Kind
+	number   string
+	issued   time.Time
+	due      time.Time
+	from     Address
+	to       Address
+	items    []Item
+	notes    string
+	payment  *Payment
+	calc     Calculator
+	currency string
+	money    func(minor int64) string
+}
+
+// New creates a document of the given kind and number.
+func New(kind Kind, number string) *Document {
+	return &Document{kind: kind, number: number, calc: Standard}
+}
+
+// Issued sets the issue date.
+func (d *Document) Issued(t time.Time) *Document {
+	d.issued = t
+	return d
+}
+
+// Due sets the payment due date.
+func (d *Document) Due(t time.Time) *Document {
+	d.due = t
+	return d
+}
+
+// From sets the issuing party.
+func (d *Document) From(a Address) *Document {
+	d.from = a
+	return d
+}
+
+// To sets the billed party.
+func (d *Document) To(a Address) *Document {
+	d.to = a
+	return d
+}
+
+// Add appends line items.
+func (d *Document) Add(items ...Item) *Document {
+	d.items = append(d.items, items...)
+	return d
+}
+
+// Notes sets free-text notes shown below the totals.
+func (d *Document) Notes(s string) *Document {
+	d.notes = s
+	return d
+}
+
+// Payment adds a payment stub.
+func (d *Document) Payment(p Payment) *Document {
+	d.payment = &p
+	return d
+}
+
+// Calculator replaces the Standard calculator.
+func (d *Document) Calculator(c Calculator) *Document {
+	d.calc = c
+	return d
+}
+
+// Currency sets the currency code shown with the total, e.g. "AUD".
+func (d *Document) Currency(code string) *Document {
+	d.currency = code
+	return d
+}
+
+// Money replaces the amount formatter. The default formats 123456 as "1,234.56".
+func (d *Document) Money(format func(minor int64) string) *Document {
+	d.money = format
+	return d
+}
+
+// Totals returns the document totals as computed by its Calculator.
+func (d *Document) Totals() Totals {
+	return d.calc.Totals(d.items)
+}
+
+// View returns the document rendered in mode m.
+func (d *Document) View(m Mode) node.Node {
+	return &view{doc: d, mode: m}
+}
+
+// Render generates the Web mode HTML representation of the document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (d *Document) Render(w ...io.Writer) []byte {
+	return d.View(Web).Render(w...)
+}
+
+// RenderBuilder writes the Web mode document directly to a buffer.
+func (d *Document) RenderBuilder(buf *bytes.Buffer) {
+	d.View(Web).RenderBuilder(buf)
+}
+
+// Nodes returns an empty slice as the document is built at render time.
+func (d *Document) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op for Document as it does not have attributes.
+func (d *Document) SetAttribute(_ string, _ string) {
+	// Document does not support attributes
+}
+
+func (d *Document) format(minor int64) string {
+	if d.money != nil {
+		return d.money(minor)
+	}
+	return formatMoney(minor)
+}
+
+// formatMoney formats minor units with two decimals and thousands separators.
+func formatMoney(minor int64) string {
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	whole := strconv.FormatInt(minor/100, 10)
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('.')
+	cents := minor % 100
+	if cents < 10 {
+		b.WriteByte('0')
+	}
+	b.WriteString(strconv.FormatInt(cents, 10))
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package invoice
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func sample() *Document {
+	return New(Invoice, "INV-7").
+		Issued(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)).
+		Due(time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)).
+		From(Address{Name: "Acme & Co", Lines: []string{"1 Example St"}}).
+		To(Address{Name: "Jo <Bloggs>"}).
+		Currency("AUD").
+		Add(
+			Item{Description: "Consulting", Quantity: 2.5, UnitPrice: 150000, TaxRate: 0.1},
+			Item{Description: "Travel", Quantity: 1, UnitPrice: 4250},
+		).
+		Payment(Payment{Reference: "INV-7", Instructions: []string{"BSB 062-000"}})
+}
+
+func TestTotals(t *testing.T) {
+	got := sample().Totals()
+	if got.Subtotal != 379250 || got.Tax != 37500 || got.Total != 416750 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestCalculatorHook(t *testing.T) {
+	discount := CalculatorFunc(func(items []Item) Totals {
+		t := Standard.Totals(items)
+		t.Adjustments = []Adjustment{{Label: "Discount", Amount: -500}}
+		t.Total -= 500
+		return t
+	})
+	out := string(sample().Calculator(discount).Render())
+	for _, want := range []string{
+		`<th colspan="4" scope="row" class="invoice-td invoice-num">Discount</th><td class="invoice-td invoice-num">-5.00</td>`,
+		`<td class="invoice-td invoice-num invoice-total">4,162.50</td>`,
+		`Amount due: AUD 4,162.50`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestModes(t *testing.T) {
+	doc := sample()
+
+	web := string(doc.Render())
+	if !strings.HasPrefix(web, `<article class="invoice">`) || strings.Contains(web, "<style") {
+		t.Errorf("web: unexpected output\n%s", web)
+	}
+	for _, want := range []string{"Acme &amp; Co", "Jo &lt;Bloggs&gt;", "Issued 1 Apr 2026 · Due 15 Apr 2026", "3,750.00", "Total (AUD)"} {
+		if !strings.Contains(web, want) {
+			t.Errorf("web: missing %q", want)
+		}
+	}
+
+	print := string(doc.View(Print).Render())
+	if !strings.HasPrefix(print, "<style>") || !strings.Contains(print, "@media print") || strings.Contains(print, "@page") {
+		t.Errorf("print: unexpected output\n%s", print)
+	}
+
+	pdf := string(doc.View(PDF).Render())
+	if !strings.HasPrefix(pdf, "<!DOCTYPE html>") || !strings.Contains(pdf, "@page{size:A4") || !strings.Contains(pdf, "<title>Invoice INV-7</title>") {
+		t.Errorf("pdf: unexpected output\n%s", pdf)
+	}
+
+	email := string(doc.View(Email).Render())
+	if strings.Contains(email, "class=") || strings.Contains(email, "<style") {
+		t.Errorf("email: must use inline styles only\n%s", email)
+	}
+	if !strings.Contains(email, `<article style="font-family:`) {
+		t.Errorf("email: missing inline style\n%s", email)
+	}
+}
+
+func TestReceipt(t *testing.T) {
+	out := string(New(Receipt, "R-1").Due(time.Now()).Add(Item{Description: "x", Quantity: 1, UnitPrice: 100}).Payment(Payment{}).Render())
+	if !strings.Contains(out, "Amount paid: 1.00") || strings.Contains(out, "Due") {
+		t.Errorf("got %s", out)
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := map[int64]string{0: "0.00", 5: "0.05", 123456: "1,234.56", -99: "-0.99", 100000000: "1,000,000.00"}
+	for in, want := range tests {
+		if got := formatMoney(in); got != want {
+			t.Errorf("formatMoney(%d): got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package invoice
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/html5/address"
+	"github.com/jpl-au/fluent/html5/article"
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/br"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/h2"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/meta"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/section"
+	"github.com/jpl-au/fluent/html5/strong"
+	"github.com/jpl-au/fluent/html5/style"
+	"github.com/jpl-au/fluent/html5/table"
+	"github.com/jpl-au/fluent/html5/tbody"
+	"github.com/jpl-au/fluent/html5/td"
+	"github.com/jpl-au/fluent/html5/tfoot"
+	"github.com/jpl-au/fluent/html5/th"
+	"github.com/jpl-au/fluent/html5/thead"
+	"github.com/jpl-au/fluent/html5/title"
+	"github.com/jpl-au/fluent/html5/tr"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// dateLayout is the layout used for issue and due dates.
+const dateLayout = "2 Jan 2006"
+
+// styles maps each class used by the document to its declarations. Web mode
+// emits only the class names; Print and PDF embed them as a stylesheet; Email
+// inlines them on each element.
+var styles = []struct{ class, css string }{
+	{"invoice", "font-family:Helvetica,Arial,sans-serif;color:#222;max-width:720px;margin:0 auto"},
+	{"invoice-title", "font-size:24px;margin:0 0 4px"},
+	{"invoice-meta", "margin:0 0 16px;color:#555"},
+	{"invoice-parties", "width:100%;margin-bottom:24px;border-collapse:collapse"},
+	{"invoice-party", "vertical-align:top;width:50%;padding:0"},
+	{"invoice-address", "font-style:normal"},
+	{"invoice-items", "width:100%;border-collapse:collapse"},
+	{"invoice-th", "text-align:left;border-bottom:2px solid #222;padding:6px"},
+	{"invoice-td", "border-bottom:1px solid #ddd;padding:6px"},
+	{"invoice-num", "text-align:right"},
+	{"invoice-total", "font-weight:bold;border-top:2px solid #222"},
+	{"invoice-notes", "margin-top:16px;color:#555"},
+	{"invoice-stub", "margin-top:32px;padding-top:16px;border-top:2px dashed #999"},
+}
+
+// printCSS keeps rows and the payment stub from splitting across pages and
+// repeats the table header on each page.
+const printCSS = "@media print{.invoice{max-width:none}" +
+	".invoice-items tr,.invoice-stub{break-inside:avoid}" +
+	".invoice-items thead{display:table-header-group}}"
+
+// pageCSS sizes the page for HTML-to-PDF converters.
+const pageCSS = "@page{size:A4;margin:18mm}"
+
+// stylesheet returns the rules for every class plus extra.
+func stylesheet(extra ...string) string {
+	var b strings.Builder
+	for _, s := range styles {
+		b.WriteByte('.')
+		b.WriteString(s.class)
+		b.WriteByte('{')
+		b.WriteString(s.css)
+		b.WriteByte('}')
+	}
+	for _, e := range extra {
+		b.WriteString(e)
+	}
+	return b.String()
+}
+
+// view renders a Document in a particular Mode.
+type view struct {
+	doc  *Document
+	mode Mode
+}
+
+// Render generates the HTML representation of the document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (v *view) Render(w ...io.Writer) []byte {
+	return node.Render(v, w...)
+}
+
+// RenderBuilder writes the document directly to a buffer.
+func (v *view) RenderBuilder(buf *bytes.Buffer) {
+	v.tree().RenderBuilder(buf)
+}
+
+// Nodes returns the element tree for the document.
+func (v *view) Nodes() []node.Node {
+	return []node.Node{v.tree()}
+}
+
+// SetAttribute is a no-op for a document view as it does not have attributes.
+func (v *view) SetAttribute(_ string, _ string) {
+	// view does not support attributes
+}
+
+// styled applies classes to n: as class names, or as inline declarations in
+// Email mode.
+func (v *view) styled(n node.Node, classes ...string) node.Node {
+	if v.mode != Email {
+		n.SetAttribute("class", strings.Join(classes, " "))
+		return n
+	}
+	var decls []string
+	for _, c := range classes {
+		for _, s := range styles {
+			if s.class == c {
+				decls = append(decls, s.css)
+			}
+		}
+	}
+	n.SetAttribute("style", strings.Join(decls, ";"))
+	return n
+}
+
+func (v *view) tree() node.Node {
+	d := v.doc
+	totals := d.Totals()
+
+	doc := v.styled(article.New(
+		v.header(),
+		v.parties(),
+		v.items(totals),
+		v.notes(),
+		v.stub(totals),
+	), "invoice")
+
+	switch v.mode {
+	case Print:
+		return fragment{style.RawText(stylesheet(printCSS)), doc}
+	case PDF:
+		return html.New(
+			head.New(
+				meta.UTF8(),
+				title.Text(string(d.kind)+" "+d.number),
+				style.RawText(stylesheet(pageCSS, printCSS)),
+			),
+			body.New(doc),
+		)
+	}
+	return doc
+}
+
+func (v *view) header() node.Node {
+	d := v.doc
+	var meta []string
+	if !d.issued.IsZero() {
+		meta = append(meta, "Issued "+d.issued.Format(dateLayout))
+	}
+	if !d.due.IsZero() && d.kind == Invoice {
+		meta = append(meta, "Due "+d.due.Format(dateLayout))
+	}
+	nodes := []node.Node{v.styled(h1.Text(string(d.kind)+" "+d.number), "invoice-title")}
+	if len(meta) > 0 {
+		nodes = append(nodes, v.styled(p.Text(strings.Join(meta, " · ")), "invoice-meta"))
+	}
+	return fragment(nodes)
+}
+
+func (v *view) parties() node.Node {
+	return v.styled(table.New(tbody.New(tr.New(
+		v.styled(td.New(v.address("From", v.doc.from)), "invoice-party"),
+		v.styled(td.New(v.address("To", v.doc.to)), "invoice-party"),
+	))), "invoice-parties")
+}
+
+func (v *view) address(label string, a Address) node.Node {
+	nodes := []node.Node{strong.Text(label), br.New(), text.Text(a.Name)}
+	for _, l := range a.Lines {
+		nodes = append(nodes, br.New(), text.Text(l))
+	}
+	return v.styled(address.New(nodes...), "invoice-address")
+}
+
+func (v *view) items(totals Totals) node.Node {
+	d := v.doc
+	head := tr.New(
+		v.styled(th.Text("Description").Scope("col"), "invoice-th"),
+		v.styled(th.Text("Qty").Scope("col"), "invoice-th", "invoice-num"),
+		v.styled(th.Text("Unit price").Scope("col"), "invoice-th", "invoice-num"),
+		v.styled(th.Text("Tax").Scope("col"), "invoice-th", "invoice-num"),
+		v.styled(th.Text("Amount").Scope("col"), "invoice-th", "invoice-num"),
+	)
+
+	rows := make([]node.Node, len(d.items))
+	for i, it := range d.items {
+		rows[i] = tr.New(
+			v.styled(td.Text(it.Description), "invoice-td"),
+			v.styled(td.Text(strconv.FormatFloat(it.Quantity, 'f', -1, 64)), "invoice-td", "invoice-num"),
+			v.styled(td.Text(d.format(it.UnitPrice)), "invoice-td", "invoice-num"),
+			v.styled(td.Text(strconv.FormatFloat(it.TaxRate*100, 'f', -1, 64)+"%"), "invoice-td", "invoice-num"),
+			v.styled(td.Text(d.format(it.Amount())), "invoice-td", "invoice-num"),
+		)
+	}
+
+	foot := []node.Node{v.sum("Subtotal", totals.Subtotal), v.sum("Tax", totals.Tax)}
+	for _, a := range totals.Adjustments {
+		foot = append(foot, v.sum(a.Label, a.Amount))
+	}
+	label := "Total"
+	if d.currency != "" {
+		label += " (" + d.currency + ")"
+	}
+	foot = append(foot, v.sum(label, totals.Total, "invoice-total"))
+
+	return v.styled(table.New(thead.New(head), tbody.New(rows...), tfoot.New(foot...)), "invoice-items")
+}
+
+// sum renders a totals row spanning the item columns.
+func (v *view) sum(label string, amount int64, classes ...string) node.Node {
+	cell := append([]string{"invoice-td", "invoice-num"}, classes...)
+	return tr.New(
+		v.styled(th.Text(label).Scope("row").ColSpan(4), cell...),
+		v.styled(td.Text(v.doc.format(amount)), cell...),
+	)
+}
+
+func (v *view) notes() node.Node {
+	if v.doc.notes == "" {
+		return nil
+	}
+	return v.styled(p.Text(v.doc.notes), "invoice-notes")
+}
+
+func (v *view) stub(totals Totals) node.Node {
+	d := v.doc
+	if d.payment == nil {
+		return nil
+	}
+	amount := d.format(totals.Total)
+	if d.currency != "" {
+		amount = d.currency + " " + amount
+	}
+
+	nodes := []node.Node{h2.Text("Payment")}
+	if d.payment.Reference != "" {
+		nodes = append(nodes, p.Text("Reference: "+d.payment.Reference))
+	}
+	if d.kind == Receipt {
+		nodes = append(nodes, p.New(strong.Text("Amount paid: "+amount)))
+	} else {
+		nodes = append(nodes, p.New(strong.Text("Amount due: "+amount)))
+		if !d.due.IsZero() {
+			nodes = append(nodes, p.Text("Due: "+d.due.Format(dateLayout)))
+		}
+	}
+	for _, line := range d.payment.Instructions {
+		nodes = append(nodes, p.Text(line))
+	}
+	return v.styled(section.New(nodes...), "invoice-stub")
+}
+
+// fragment renders a sequence of nodes without a wrapping element.
+type fragment []node.Node
+
+func (f fragment) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+func (f fragment) RenderBuilder(buf *bytes.Buffer) {
+	for _, n := range f {
+		if n != nil {
+			n.RenderBuilder(buf)
+		}
+	}
+}
+
+func (f fragment) Nodes() []node.Node {
+	return f
+}
+
+func (f fragment) SetAttribute(_ string, _ string) {
+	// fragment does not support attributes
+}
@@ -0,0 +1,232 @@
+// Package legal provides footer components for copyright notices, policy
+// links and jurisdiction-specific disclosures.
+//
+// Compliance text tends to be copied into every layout and drift apart. These
+// components keep it in one place: the copyright year comes from the render
+// clock (see node.WithClock), and disclosures are chosen per request by a
+// Resolver carried in the render context.
+//
+// Usage:
+//
+//	ctx := legal.WithResolver(r.Context(), legal.ResolverFunc(func(ctx context.Context) legal.Jurisdiction {
+//	    return legal.Jurisdiction(geo.Region(r))
+//	}))
+//
+//	legal.Footer(
+//	    legal.Copyright("Acme Pty Ltd").Since(2019),
+//	    legal.Links(
+//	        legal.Link{Label: "Privacy", Href: "/privacy"},
+//	        legal.Link{Label: "Terms", Href: "/terms"},
+//	    ),
+//	    legal.Disclosure(map[legal.Jurisdiction]node.Node{
+//	        "EU":    p.Static("We use cookies with your consent."),
+//	        "US-CA": a.Link("/ccpa", "Do Not Sell or Share My Personal Information"),
+//	    }, nil),
+//	)
+//
+// Render with fluent.RenderContext(ctx, ...) so the resolver is visible.
+package legal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/footer"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/nav"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Footer wraps the given legal components in <footer class="legal">.
+func Footer(nodes ...node.Node) node.Node {
+	return footer.New(nodes...).Class("legal")
+}
+
+// CopyrightNotice renders a copyright line with the current year.
+// Create one with Copyright.
+type CopyrightNotice struct {
+	holder string
+	since  int
+}
+
+// Copyright creates a notice for holder, e.g. "© 2026 Acme Pty Ltd".
+// The year is taken from the render clock, so it never goes stale.
+func Copyright(holder string) *CopyrightNotice {
+	return &CopyrightNotice{holder: holder}
+}
+
+// Since sets the first year of publication, producing a range such as
+// "© 2019–2026 Acme Pty Ltd". It has no effect when year is the current year.
+func (c *CopyrightNotice) Since(year int) *CopyrightNotice {
+	c.since = year
+	return c
+}
+
+// Render generates the HTML representation of the notice.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (c *CopyrightNotice) Render(w ...io.Writer) []byte {
+	return node.Render(c, w...)
+}
+
+// RenderBuilder writes the notice directly to a buffer.
+func (c *CopyrightNotice) RenderBuilder(buf *bytes.Buffer) {
+	year := node.Now(buf).Year()
+	buf.WriteString(`<small class="copyright">© `)
+	if c.since > 0 && c.since < year {
+		buf.WriteString(strconv.Itoa(c.since))
+		buf.WriteString("–")
+	}
+	buf.WriteString(strconv.Itoa(year))
+	if c.holder != "" {
+		buf.WriteByte(' ')
+		escape.WriteString(buf, c.holder)
+	}
+	buf.WriteString(`</small>`)
+}
+
+// Nodes returns an empty slice as the notice has no children.
+func (c *CopyrightNotice) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the year depends on the render clock.
+func (c *CopyrightNotice) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for CopyrightNotice as it does not have attributes.
+func (c *CopyrightNotice) SetAttribute(_ string, _ string) {
+	// CopyrightNotice does not support attributes
+}
+
+// Link is a policy link.
+type Link struct {
+	Label string
+	Href  string
+}
+
+// Links renders policy links as <nav aria-label="Legal"><ul>...</ul></nav>.
+func Links(links ...Link) node.Node {
+	items := make([]node.Node, len(links))
+	for i, l := range links {
+		items[i] = li.New(a.Link(l.Href, l.Label))
+	}
+	return nav.New(ul.New(items...)).SetAria("label", "Legal")
+}
+
+// Jurisdiction identifies a legal jurisdiction. Use hyphen-separated codes
+// from broad to narrow, such as "US" and "US-CA"; a disclosure registered for
+// "US" applies to "US-CA" unless a more specific one exists.
+type Jurisdiction string
+
+// parent returns the next broader jurisdiction, or "" at the top level.
+func (j Jurisdiction) parent() Jurisdiction {
+	i := strings.LastIndexByte(string(j), '-')
+	if i < 0 {
+		return ""
+	}
+	return j[:i]
+}
+
+// Resolver determines the jurisdiction that applies to a render.
+type Resolver interface {
+	Jurisdiction(ctx context.Context) Jurisdiction
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(ctx context.Context) Jurisdiction
+
+// Jurisdiction returns f(ctx).
+func (f ResolverFunc) Jurisdiction(ctx context.Context) Jurisdiction {
+	return f(ctx)
+}
+
+// Fixed returns a Resolver that always reports j.
+func Fixed(j Jurisdiction) Resolver {
+	return ResolverFunc(func(context.Context) Jurisdiction { return j })
+}
+
+type resolverKey struct{}
+
+// WithResolver returns a context whose renders resolve jurisdictions with r.
+func WithResolver(ctx context.Context, r Resolver) context.Context {
+	return context.WithValue(ctx, resolverKey{}, r)
+}
+
+// Current returns the jurisdiction for ctx, or "" when no resolver is set.
+func Current(ctx context.Context) Jurisdiction {
+	if r, ok := ctx.Value(resolverKey{}).(Resolver); ok {
+		return r.Jurisdiction(ctx)
+	}
+	return ""
+}
+
+// Disclosures renders the variant matching the current jurisdiction.
+// Create one with Disclosure.
+type Disclosures struct {
+	variants map[Jurisdiction]node.Node
+	fallback node.Node
+}
+
+// Disclosure selects one of variants for the jurisdiction of the current
+// render, falling back through broader jurisdictions ("US-CA" then "US") and
+// finally to fallback, which may be nil.
+func Disclosure(variants map[Jurisdiction]node.Node, fallback node.Node) *Disclosures {
+	return &Disclosures{variants: variants, fallback: fallback}
+}
+
+// Select returns the variant for j.
+func (d *Disclosures) Select(j Jurisdiction) node.Node {
+	for ; j != ""; j = j.parent() {
+		if n, ok := d.variants[j]; ok {
+			return n
+		}
+	}
+	return d.fallback
+}
+
+// Render generates the HTML representation of the selected variant.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (d *Disclosures) Render(w ...io.Writer) []byte {
+	return node.Render(d, w...)
+}
+
+// RenderBuilder writes the variant for the current jurisdiction directly to a buffer.
+func (d *Disclosures) RenderBuilder(buf *bytes.Buffer) {
+	if n := d.Select(Current(node.Context(buf))); n != nil {
+		n.RenderBuilder(buf)
+	}
+}
+
+// Nodes returns every variant, ordered by jurisdiction, followed by the
+// fallback.
+func (d *Disclosures) Nodes() []node.Node {
+	children := make([]node.Node, 0, len(d.variants)+1)
+	for _, j := range slices.Sorted(maps.Keys(d.variants)) {
+		children = append(children, d.variants[j])
+	}
+	if d.fallback != nil {
+		children = append(children, d.fallback)
+	}
+	return children
+}
+
+// Dynamic returns true as the variant depends on the render context.
+func (d *Disclosures) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for Disclosures as it does not have attributes.
+func (d *Disclosures) SetAttribute(_ string, _ string) {
+	// Disclosures does not support attributes
+}
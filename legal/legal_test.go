@@ -0,0 +1,84 @@
+package legal
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+func renderAt(t *testing.T, ctx context.Context, n node.Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCopyright(t *testing.T) {
+	ctx := node.WithClock(context.Background(), node.FixedClock(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"year", Copyright("Acme & Co"), `<small class="copyright">© 2026 Acme &amp; Co</small>`},
+		{"range", Copyright("Acme").Since(2019), `<small class="copyright">© 2019–2026 Acme</small>`},
+		{"since this year", Copyright("Acme").Since(2026), `<small class="copyright">© 2026 Acme</small>`},
+	}
+	for _, tt := range tests {
+		if got := renderAt(t, ctx, tt.node); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinks(t *testing.T) {
+	got := string(Links(Link{Label: "Privacy", Href: "/privacy?a=1&b=2"}, Link{Label: "Terms", Href: "/terms"}).Render())
+	want := `<nav aria-label="Legal"><ul><li><a href="/privacy?a=1&amp;b=2">Privacy</a></li><li><a href="/terms">Terms</a></li></ul></nav>`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDisclosure(t *testing.T) {
+	d := Disclosure(map[Jurisdiction]node.Node{
+		"EU":    p.Static("gdpr"),
+		"US":    p.Static("us"),
+		"US-CA": p.Static("ccpa"),
+	}, p.Static("default"))
+
+	tests := map[Jurisdiction]string{
+		"EU":    "<p>gdpr</p>",
+		"EU-DE": "<p>gdpr</p>",
+		"US-CA": "<p>ccpa</p>",
+		"US-NY": "<p>us</p>",
+		"AU":    "<p>default</p>",
+		"":      "<p>default</p>",
+	}
+	for j, want := range tests {
+		ctx := WithResolver(context.Background(), Fixed(j))
+		if got := renderAt(t, ctx, Footer(d)); got != `<footer class="legal">`+want+`</footer>` {
+			t.Errorf("%q: got %q", j, got)
+		}
+	}
+
+	if got := string(d.Render()); got != "<p>default</p>" {
+		t.Errorf("without resolver: got %q", got)
+	}
+
+	var order []string
+	for _, n := range d.Nodes() {
+		order = append(order, string(n.Render()))
+	}
+	if got, want := strings.Join(order, ""), "<p>gdpr</p><p>us</p><p>ccpa</p><p>default</p>"; got != want {
+		t.Errorf("Nodes: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+// Package live serves server-held views that update in the browser as their
+// state changes, in the style of Phoenix LiveView.
+//
+// A Handler renders a view into the page like any other node, together with
+// a small client script. The script opens a WebSocket back to the same URL,
+// where the handler mounts a view for the connection and keeps it in memory.
+// Browser events on elements marked with Click, Change or Submit are sent to
+// the view's Event method; after each event, and whenever the view calls
+// Socket.Update, the view is re-rendered and the difference from its previous
+// render is pushed to the browser as patch operations from the diff package.
+// If the browser cannot apply a patch, because its DOM has drifted from the
+// server's copy, it asks for the whole region instead.
+//
+// View methods are only ever called from the connection's goroutine, so a
+// view needs no locking as long as other goroutines change its state inside
+// Socket.Update. Update never blocks, so it may also be called from Event.
+//
+// Usage:
+//
+//	type counter struct{ n int }
+//
+//	func (c *counter) Render() node.Node {
+//	    return div.New(
+//	        p.Textf("Count: %d", c.n),
+//	        live.Click(button.Text("+1"), "inc"),
+//	    )
+//	}
+//
+//	func (c *counter) Event(e live.Event) {
+//	    if e.Name == "inc" {
+//	        c.n++
+//	    }
+//	}
+//
+//	http.Handle("/counter", live.New(func(r *http.Request, s *live.Socket) live.View {
+//	    return &counter{}
+//	}))
+package live
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/diff"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/internal/websocket"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// View is a server-held component.
+type View interface {
+	// Render builds the view from its current state.
+	Render() node.Node
+	// Event handles an event sent by the browser.
+	Event(e Event)
+}
+
+// Event is a browser event bound with Click, Change or Submit.
+type Event struct {
+	// Name is the event name given to the binding.
+	Name string
+	// Value is the element's Value binding, or the value of a form control.
+	Value string
+	// Form holds the fields of a submitted form.
+	Form url.Values
+}
+
+// Mount creates the view for a request. It is called once for the initial
+// page render, with a Socket that is not connected, and again when the
+// browser connects.
+type Mount func(r *http.Request, s *Socket) View
+
+// Handler serves a live view. Create one with New.
+type Handler struct {
+	// Layout wraps the view's region in a page. When nil the region is served
+	// on its own, for inclusion by other means such as an iframe or fetch.
+	Layout func(region node.Node) node.Node
+	// Logger receives view panics and protocol errors. Defaults to log.Default().
+	Logger *log.Logger
+	// Origins lists other origins, such as "https://app.example.com", whose
+	// pages may connect to the view. Pages served from the handler's own host
+	// are always allowed; connections from any other site are refused.
+	Origins []string
+
+	mount Mount
+}
+
+// New creates a Handler serving the views created by mount.
+func New(mount Mount) *Handler {
+	return &Handler{Logger: log.Default(), mount: mount}
+}
+
+// Socket is a view's connection to the browser.
+type Socket struct {
+	ctx context.Context
+	// wake signals the view's goroutine that queue has updates.
+	wake  chan struct{}
+	mu    sync.Mutex
+	queue []func()
+}
+
+// Connected reports whether the socket belongs to a WebSocket connection,
+// rather than the initial page render.
+func (s *Socket) Connected() bool {
+	return s.wake != nil
+}
+
+// Context returns a context that is cancelled when the connection closes.
+func (s *Socket) Context() context.Context {
+	return s.ctx
+}
+
+// Update queues fn to run on the view's goroutine, which then re-renders the
+// view and pushes the changes. fn may be nil to re-render only. Update never
+// blocks: called from the view's own goroutine, such as from Event, fn runs
+// after the current event has been handled. It does nothing once the
+// connection has closed or when the socket is not connected.
+func (s *Socket) Update(fn func()) {
+	if s.wake == nil || s.ctx.Err() != nil {
+		return
+	}
+	if fn == nil {
+		fn = func() {}
+	}
+	s.mu.Lock()
+	s.queue = append(s.queue, fn)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// updates returns and clears the queued updates.
+func (s *Socket) updates() []func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.queue
+	s.queue = nil
+	return q
+}
+
+// ServeHTTP serves the page for ordinary requests and the view's connection
+// for WebSocket upgrades.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsUpgrade(r) {
+		conn, err := websocket.Upgrade(w, r, h.Origins...)
+		if err != nil {
+			h.Logger.Printf("live: %s: %v", r.URL.Path, err)
+			return
+		}
+		h.serve(conn, r)
+		return
+	}
+	v := h.mount(r, &Socket{ctx: r.Context()})
+	var page node.Node = Region(r.URL.EscapedPath(), v)
+	if h.Layout != nil {
+		page = h.Layout(page)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := fluent.RenderContext(r.Context(), page, w); err != nil {
+		h.Logger.Printf("live: %s: %v", r.URL.Path, err)
+	}
+}
+
+// Region returns the markup for a view served at path: a div holding the
+// view's current render, followed by the client script. Handler uses it for
+// the initial page; use it directly to place a view inside a page served by
+// another handler. path is escaped, so it may come from the request.
+func Region(path string, v View) node.Node {
+	return fragment{div.New(v.Render()).SetData("live", path), Script()}
+}
+
+// fragment renders nodes one after another.
+type fragment []node.Node
+
+func (f fragment) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+func (f fragment) RenderBuilder(buf *bytes.Buffer) {
+	for _, n := range f {
+		if n != nil {
+			n.RenderBuilder(buf)
+		}
+	}
+}
+
+func (f fragment) Nodes() []node.Node {
+	return f
+}
+
+func (f fragment) SetAttribute(_ string, _ string) {
+	// fragment does not support attributes
+}
+
+// Script returns the client runtime, which connects every region on the page.
+func Script() node.Node {
+	return text.RawText(script)
+}
+
+// script connects each [data-live] region to its endpoint, applies patches
+// and forwards bound events. A patch that fails to apply asks for a resync.
+const script = `<script>(function(){document.querySelectorAll("[data-live]").forEach(function(root){` +
+	`if(root.liveSocket)return;` +
+	`var ws=root.liveSocket=new WebSocket((location.protocol=="https:"?"wss://":"ws://")+location.host+root.getAttribute("data-live"));` +
+	`function at(p){var n=root;for(var i=0;i<p.length;i++){n=n.childNodes[p[i]];if(!n)throw new Error("live: bad path")}return n}` +
+	`function frag(h){var t=document.createElement("template");t.innerHTML=h;return t.content}` +
+	`function patch(ops){ops.forEach(function(o){var p=o.p;switch(o.k){` +
+	`case"insert":var parent=at(p.slice(0,-1));parent.insertBefore(frag(o.v),parent.childNodes[p[p.length-1]]||null);break;` +
+	`case"replace":at(p).replaceWith(frag(o.v));break;` +
+	`case"remove":at(p).remove();break;` +
+	`case"set-attr":at(p).setAttribute(o.n,o.v);if(o.n=="value")at(p).value=o.v;break;` +
+	`case"remove-attr":at(p).removeAttribute(o.n);break;` +
+	`case"set-text":at(p).data=o.v;break}})}` +
+	`ws.onmessage=function(e){var m=JSON.parse(e.data);if(m.html!==undefined){root.innerHTML=m.html;return}` +
+	`try{patch(m.ops)}catch(x){ws.send(JSON.stringify({resync:true}))}};` +
+	`["click","change","submit"].forEach(function(t){root.addEventListener(t,function(ev){` +
+	`var el=ev.target.closest("[data-live-"+t+"]");if(!el||!root.contains(el))return;ev.preventDefault();` +
+	`var m={event:el.getAttribute("data-live-"+t),value:el.getAttribute("data-live-value")||el.value||""};` +
+	`if(el.tagName=="FORM"){m.form={};new FormData(el).forEach(function(v,k){(m.form[k]=m.form[k]||[]).push(String(v))})}` +
+	`ws.send(JSON.stringify(m))})})})})()</script>`
+
+// clientMessage is a message from the browser.
+type clientMessage struct {
+	Event  string     `json:"event"`
+	Value  string     `json:"value"`
+	Form   url.Values `json:"form"`
+	Resync bool       `json:"resync"`
+}
+
+// serverMessage is a message to the browser: the whole region or a patch.
+type serverMessage struct {
+	HTML *string `json:"html,omitempty"`
+	Ops  []op    `json:"ops,omitempty"`
+}
+
+// op is a diff.Op in the client's compact form.
+type op struct {
+	Kind  string `json:"k"`
+	Path  []int  `json:"p"`
+	Key   string `json:"n,omitempty"`
+	Value string `json:"v"`
+}
+
+// serve runs a view for the lifetime of a connection.
+func (h *Handler) serve(conn *websocket.Conn, r *http.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer conn.Close()
+
+	s := &Socket{ctx: ctx, wake: make(chan struct{}, 1)}
+	messages := make(chan clientMessage)
+	go func() {
+		defer cancel()
+		for {
+			raw, err := conn.Read()
+			if err != nil {
+				return
+			}
+			var m clientMessage
+			if err := json.Unmarshal([]byte(raw), &m); err != nil {
+				h.Logger.Printf("live: %s: bad message: %v", r.URL.Path, err)
+				continue
+			}
+			select {
+			case messages <- m:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			h.Logger.Printf("live: view %s panicked: %v", r.URL.Path, p)
+		}
+	}()
+	v := h.mount(r, s)
+	// The browser holds the initial page's render, which may differ from the
+	// state of this freshly mounted view, so start from a full sync.
+	last := render(v)
+	if err := send(conn, serverMessage{HTML: &last}); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			for _, fn := range s.updates() {
+				fn()
+			}
+		case m := <-messages:
+			if m.Resync {
+				if err := send(conn, serverMessage{HTML: &last}); err != nil {
+					return
+				}
+				continue
+			}
+			v.Event(Event{Name: m.Event, Value: m.Value, Form: m.Form})
+		}
+		next := render(v)
+		if ops := patch(last, next); len(ops) > 0 {
+			if err := send(conn, serverMessage{Ops: ops}); err != nil {
+				return
+			}
+		}
+		last = next
+	}
+}
+
+func render(v View) string {
+	n := v.Render()
+	if n == nil {
+		return ""
+	}
+	return string(n.Render())
+}
+
+// patch returns the operations turning the render a into b.
+func patch(a, b string) []op {
+	ops := diff.HTML(a, b)
+	out := make([]op, len(ops))
+	for i, o := range ops {
+		out[i] = op{Kind: o.Kind.String(), Path: o.Path, Key: o.Key, Value: o.Value}
+	}
+	return out
+}
+
+func send(conn *websocket.Conn, m serverMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return conn.Write(string(b))
+}
+
+// Click binds the element's click event to the view event name.
+func Click[T node.Node](n T, name string) T {
+	return bind(n, "click", name)
+}
+
+// Change binds the control's change event to the view event name. The event
+// carries the control's value.
+func Change[T node.Node](n T, name string) T {
+	return bind(n, "change", name)
+}
+
+// Submit binds the form's submit event to the view event name. The event
+// carries the form's fields, and the browser's own submission is prevented.
+func Submit[T node.Node](n T, name string) T {
+	return bind(n, "submit", name)
+}
+
+// Value sets the value sent with the element's events.
+func Value[T node.Node](n T, value string) T {
+	n.SetAttribute("data-live-value", value)
+	return n
+}
+
+func bind[T node.Node](n T, event, name string) T {
+	n.SetAttribute("data-live-"+event, name)
+	return n
+}
@@ -0,0 +1,239 @@
+package live
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/button"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/internal/websocket"
+	"github.com/jpl-au/fluent/node"
+)
+
+type counter struct {
+	n     int
+	label string
+}
+
+func (c *counter) Render() node.Node {
+	return div.New(
+		p.Textf("%s: %d", c.label, c.n),
+		Value(Click(button.Text("+"), "inc"), "1"),
+	)
+}
+
+func (c *counter) Event(e Event) {
+	switch e.Name {
+	case "inc":
+		c.n++
+	case "rename":
+		c.label = e.Form.Get("label")
+	}
+}
+
+func server(t *testing.T, mount Mount) (*httptest.Server, *Handler) {
+	h := New(mount)
+	h.Logger = log.New(io.Discard, "", 0)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv, h
+}
+
+func dial(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	conn, err := websocket.Dial("ws" + strings.TrimPrefix(srv.URL, "http") + "/counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) serverMessage {
+	t.Helper()
+	raw, err := conn.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m serverMessage
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func write(t *testing.T, conn *websocket.Conn, m clientMessage) {
+	t.Helper()
+	b, _ := json.Marshal(m)
+	if err := conn.Write(string(b)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPage(t *testing.T) {
+	srv, _ := server(t, func(r *http.Request, s *Socket) View {
+		if s.Connected() {
+			t.Error("socket connected during the page render")
+		}
+		return &counter{label: "Count"}
+	})
+	resp, err := http.Get(srv.URL + "/counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	want := `<div data-live="/counter"><div><p>Count: 0</p><button data-live-click="inc" data-live-value="1">+</button></div></div><script>`
+	if !strings.HasPrefix(string(body), want) {
+		t.Errorf("got %q, want prefix %q", body, want)
+	}
+}
+
+func TestRegionEscapesPath(t *testing.T) {
+	got := string(Region(`/live/x"><img src=x onerror=alert(1)>`, &counter{}).Render())
+	want := `<div data-live="/live/x&#34;&gt;&lt;img src=x onerror=alert(1)&gt;">`
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("got %q, want prefix %q", got, want)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	srv, h := server(t, func(r *http.Request, s *Socket) View {
+		return &counter{label: "Count"}
+	})
+	h.Layout = func(region node.Node) node.Node {
+		return div.New(region).ID("app")
+	}
+	resp, err := http.Get(srv.URL + "/counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !bytes.HasPrefix(body, []byte(`<div id="app"><div data-live="/counter">`)) || !bytes.HasSuffix(body, []byte("</script></div>")) {
+		t.Errorf("got %q, want the region inside the layout", body)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	srv, _ := server(t, func(r *http.Request, s *Socket) View {
+		return &counter{label: "Count"}
+	})
+	conn := dial(t, srv)
+
+	m := read(t, conn)
+	if m.HTML == nil || *m.HTML != `<div><p>Count: 0</p><button data-live-click="inc" data-live-value="1">+</button></div>` {
+		t.Fatalf("got %+v, want the initial render", m)
+	}
+
+	write(t, conn, clientMessage{Event: "inc", Value: "1"})
+	m = read(t, conn)
+	want := []op{{Kind: "set-text", Path: []int{0, 0, 0}, Value: "Count: 1"}}
+	if got, _ := json.Marshal(m.Ops); string(got) != mustJSON(want) {
+		t.Errorf("got %s, want %s", got, mustJSON(want))
+	}
+
+	write(t, conn, clientMessage{Event: "rename", Form: map[string][]string{"label": {"Total"}}})
+	m = read(t, conn)
+	want = []op{{Kind: "set-text", Path: []int{0, 0, 0}, Value: "Total: 1"}}
+	if got, _ := json.Marshal(m.Ops); string(got) != mustJSON(want) {
+		t.Errorf("got %s, want %s", got, mustJSON(want))
+	}
+}
+
+func TestResync(t *testing.T) {
+	srv, _ := server(t, func(r *http.Request, s *Socket) View {
+		return &counter{label: "Count", n: 3}
+	})
+	conn := dial(t, srv)
+	first := read(t, conn)
+
+	write(t, conn, clientMessage{Resync: true})
+	m := read(t, conn)
+	if m.HTML == nil || *m.HTML != *first.HTML {
+		t.Errorf("got %+v, want the full region %q", m, *first.HTML)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	sockets := make(chan *Socket, 1)
+	views := make(chan *counter, 1)
+	srv, _ := server(t, func(r *http.Request, s *Socket) View {
+		c := &counter{label: "Count"}
+		if s.Connected() {
+			sockets <- s
+			views <- c
+		}
+		return c
+	})
+	conn := dial(t, srv)
+	read(t, conn)
+
+	s, c := <-sockets, <-views
+	go s.Update(func() { c.n = 42 })
+	m := read(t, conn)
+	if len(m.Ops) != 1 || m.Ops[0].Value != "Count: 42" {
+		t.Errorf("got %+v, want the pushed update", m.Ops)
+	}
+
+	conn.Close()
+	<-s.Context().Done()
+	s.Update(nil) // must not block once closed
+}
+
+// echoView calls Socket.Update from its Event handler.
+type echoView struct {
+	s *Socket
+	n int
+}
+
+func (v *echoView) Render() node.Node {
+	return p.Textf("%d", v.n)
+}
+
+func (v *echoView) Event(e Event) {
+	v.s.Update(func() { v.n += 10 })
+	v.n++
+}
+
+func TestUpdateFromEvent(t *testing.T) {
+	srv, _ := server(t, func(r *http.Request, s *Socket) View {
+		return &echoView{s: s}
+	})
+	conn := dial(t, srv)
+	read(t, conn)
+
+	write(t, conn, clientMessage{Event: "inc"})
+	if m := read(t, conn); len(m.Ops) != 1 || m.Ops[0].Value != "1" {
+		t.Errorf("got %+v, want the event's change", m.Ops)
+	}
+	if m := read(t, conn); len(m.Ops) != 1 || m.Ops[0].Value != "11" {
+		t.Errorf("got %+v, want the queued update", m.Ops)
+	}
+}
+
+func TestBindings(t *testing.T) {
+	got := string(Submit(div.New(), `save "all"`).Render())
+	if want := `<div data-live-submit="save &#34;all&#34;"></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = string(Change(div.New(), "pick").Render())
+	if want := `<div data-live-change="pick"></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func mustJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,240 @@
+// Package media builds responsive images and audio and video players from
+// a description of the media, writing the <picture>, <source> and <track>
+// markup that is tedious and easy to get wrong by hand.
+//
+// Usage:
+//
+//	media.Image("/img/hero.jpg", "Harbour at dawn", 2400, 1600).
+//	    Widths(480, 960, 1600, 2400).
+//	    Formats("avif", "webp").
+//	    Sizes(sizes.FullWidth)
+package media
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/attr"
+	"github.com/jpl-au/fluent/html5/attr/decoding"
+	"github.com/jpl-au/fluent/html5/attr/fetchpriority"
+	"github.com/jpl-au/fluent/html5/attr/loading"
+	"github.com/jpl-au/fluent/html5/attr/sizes"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/html5/picture"
+	"github.com/jpl-au/fluent/html5/source"
+	"github.com/jpl-au/fluent/node"
+)
+
+// URLFunc returns the URL of the image src resized to width pixels and
+// converted to format, such as "webp". format is "" for the image's own
+// format. Set one with ImageBuilder.URL to address an image CDN.
+type URLFunc func(src string, width int, format string) string
+
+// QueryURL is the default URLFunc. It adds the width and format to src as
+// the w and fm query parameters, as many image services accept.
+func QueryURL(src string, width int, format string) string {
+	u := attr.URL(src).Query("w", strconv.Itoa(width))
+	if format != "" {
+		u.Query("fm", format)
+	}
+	return u.String()
+}
+
+// ImageBuilder is a responsive image. Create one with Image.
+type ImageBuilder struct {
+	src, alt      string
+	width, height int
+	widths        []int
+	formats       []string
+	sizes         []sizes.Size
+	loading       loading.Loading
+	decoding      decoding.Decoding
+	priority      bool
+	url           URLFunc
+	attrs         []node.Attribute
+}
+
+// Image returns a responsive image of src, whose intrinsic size is width
+// by height pixels. The size is written on the <img>, so the browser
+// reserves the image's space before it loads and the layout does not
+// shift. Images load lazily and decode asynchronously unless set otherwise.
+func Image(src, alt string, width, height int) *ImageBuilder {
+	return &ImageBuilder{
+		src: src, alt: alt, width: width, height: height,
+		loading: loading.Lazy, decoding: decoding.Async, url: QueryURL,
+	}
+}
+
+// Widths sets the widths, in pixels, offered in srcset. Widths above the
+// intrinsic width are dropped, as they would only upscale.
+func (i *ImageBuilder) Widths(widths ...int) *ImageBuilder {
+	i.widths = widths
+	return i
+}
+
+// Formats sets the formats, such as "avif" and "webp", offered ahead of
+// the image's own format. The image is then wrapped in a <picture> with a
+// <source> for each format, in the order given, so list the preferred
+// format first.
+func (i *ImageBuilder) Formats(formats ...string) *ImageBuilder {
+	i.formats = formats
+	return i
+}
+
+// Sizes sets the displayed width of the image at each viewport size, from
+// which the browser picks a width from srcset.
+func (i *ImageBuilder) Sizes(s ...sizes.Size) *ImageBuilder {
+	i.sizes = s
+	return i
+}
+
+// Loading sets when the image loads. The default is loading.Lazy.
+func (i *ImageBuilder) Loading(l loading.Loading) *ImageBuilder {
+	i.loading = l
+	return i
+}
+
+// Decoding sets how the image decodes. The default is decoding.Async.
+func (i *ImageBuilder) Decoding(d decoding.Decoding) *ImageBuilder {
+	i.decoding = d
+	return i
+}
+
+// Priority marks the image as important to the first paint, such as a hero
+// image: it loads eagerly with fetchpriority="high".
+func (i *ImageBuilder) Priority() *ImageBuilder {
+	i.priority = true
+	i.loading = loading.Eager
+	return i
+}
+
+// URL sets the function addressing each width and format of the image. The
+// default is QueryURL.
+func (i *ImageBuilder) URL(fn URLFunc) *ImageBuilder {
+	if fn != nil {
+		i.url = fn
+	}
+	return i
+}
+
+// Class sets the class of the <img>.
+func (i *ImageBuilder) Class(class string) *ImageBuilder {
+	i.SetAttribute("class", class)
+	return i
+}
+
+// Element returns the image's markup: an <img>, or a <picture> when formats
+// are set.
+func (i *ImageBuilder) Element() node.Node {
+	widths := i.candidates()
+	im := img.New().
+		Src(i.src).
+		Alt(i.alt).
+		Loading(i.loading).
+		Decoding(i.decoding)
+	if i.alt == "" {
+		// An empty alt marks the image as decorative, so keep it.
+		im.SetAttribute("alt", "")
+	}
+	if i.width > 0 && i.height > 0 {
+		im.Width(i.width).Height(i.height)
+	}
+	if len(widths) > 0 {
+		im.Srcset(i.srcset(widths, ""))
+		if len(i.sizes) > 0 {
+			im.Sizes(i.sizes...)
+		}
+	}
+	if i.priority {
+		im.FetchPriority(fetchpriority.High)
+	}
+	for _, a := range i.attrs {
+		im.SetAttribute(a.Key, a.Value)
+	}
+	if len(i.formats) == 0 {
+		return im
+	}
+
+	p := picture.New()
+	for _, f := range i.formats {
+		s := source.New().Type(MIMEType(f))
+		if len(widths) > 0 {
+			s.Srcset(i.srcset(widths, f))
+			if len(i.sizes) > 0 {
+				s.Sizes(i.sizes...)
+			}
+		} else {
+			s.Srcset(i.url(i.src, i.width, f))
+		}
+		p.Add(s)
+	}
+	return p.Add(im)
+}
+
+// candidates returns the srcset widths in ascending order, without those
+// above the intrinsic width.
+func (i *ImageBuilder) candidates() []int {
+	var ws []int
+	for _, w := range i.widths {
+		if w > 0 && (i.width <= 0 || w <= i.width) {
+			ws = append(ws, w)
+		}
+	}
+	slices.Sort(ws)
+	return slices.Compact(ws)
+}
+
+// srcset returns the srcset for widths in format.
+func (i *ImageBuilder) srcset(widths []int, format string) string {
+	parts := make([]string, len(widths))
+	for n, w := range widths {
+		parts[n] = i.url(i.src, w, format) + " " + strconv.Itoa(w) + "w"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MIMEType returns the media type of an image format or file extension,
+// such as "image/webp" for "webp" or ".webp".
+func MIMEType(format string) string {
+	f := strings.ToLower(strings.TrimPrefix(format, "."))
+	switch f {
+	case "jpg", "jpeg":
+		return "image/jpeg"
+	case "svg":
+		return "image/svg+xml"
+	case "ico":
+		return "image/x-icon"
+	case "tif":
+		return "image/tiff"
+	}
+	return "image/" + f
+}
+
+func (i *ImageBuilder) Render(w ...io.Writer) []byte {
+	return node.Render(i, w...)
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (i *ImageBuilder) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, i)
+}
+
+// RenderBuilder writes the image's markup.
+func (i *ImageBuilder) RenderBuilder(buf *bytes.Buffer) {
+	i.Element().RenderBuilder(buf)
+}
+
+// Nodes returns the image's markup as the only child.
+func (i *ImageBuilder) Nodes() []node.Node {
+	return []node.Node{i.Element()}
+}
+
+// SetAttribute sets an attribute on the <img>. The value is escaped when
+// written.
+func (i *ImageBuilder) SetAttribute(key string, value string) {
+	i.attrs = append(i.attrs, node.Attribute{Key: key, Value: value})
+}
@@ -0,0 +1,53 @@
+package media_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/attr/loading"
+	"github.com/jpl-au/fluent/html5/attr/sizes"
+	"github.com/jpl-au/fluent/media"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestImage(t *testing.T) {
+	cdn := func(src string, width int, format string) string {
+		return "https://cdn.example.com/" + strconv.Itoa(width) + "/" + format + src
+	}
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"plain", media.Image("/a.jpg", `A "cat"`, 800, 600),
+			`<img src="/a.jpg" alt="A &#34;cat&#34;" width="800" height="600" loading="lazy" decoding="async" />`},
+		{"widths", media.Image("/a.jpg", "A", 800, 600).Widths(1600, 400, 800).Sizes(sizes.HalfWidth),
+			`<img src="/a.jpg" alt="A" width="800" height="600" loading="lazy" sizes="50vw" decoding="async" srcset="/a.jpg?w=400 400w, /a.jpg?w=800 800w" />`},
+		{"formats", media.Image("/a.jpg", "A", 800, 600).Widths(400, 800).Formats("avif", "webp").URL(cdn).Loading(loading.Eager),
+			`<picture>` +
+				`<source type="image/avif" srcset="https://cdn.example.com/400/avif/a.jpg 400w, https://cdn.example.com/800/avif/a.jpg 800w" />` +
+				`<source type="image/webp" srcset="https://cdn.example.com/400/webp/a.jpg 400w, https://cdn.example.com/800/webp/a.jpg 800w" />` +
+				`<img src="/a.jpg" alt="A" width="800" height="600" loading="eager" decoding="async" srcset="https://cdn.example.com/400//a.jpg 400w, https://cdn.example.com/800//a.jpg 800w" />` +
+				`</picture>`},
+		{"formats without widths", media.Image("/a.png", "A", 10, 10).Formats("webp"),
+			`<picture><source type="image/webp" srcset="/a.png?w=10&amp;fm=webp" /><img src="/a.png" alt="A" width="10" height="10" loading="lazy" decoding="async" /></picture>`},
+		{"priority", media.Image("/hero.jpg", "", 1200, 600).Priority().Class("hero"),
+			`<img src="/hero.jpg" width="1200" height="600" loading="eager" decoding="async" fetchpriority="high" alt="" class="hero" />`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.node.Render()); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMIMEType(t *testing.T) {
+	tests := map[string]string{"webp": "image/webp", ".JPG": "image/jpeg", "svg": "image/svg+xml", "avif": "image/avif"}
+	for in, want := range tests {
+		if got := media.MIMEType(in); got != want {
+			t.Errorf("MIMEType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
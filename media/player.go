@@ -0,0 +1,317 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/jpl-au/fluent/html5/attr/preload"
+	"github.com/jpl-au/fluent/html5/audio"
+	"github.com/jpl-au/fluent/html5/source"
+	"github.com/jpl-au/fluent/html5/track"
+	"github.com/jpl-au/fluent/html5/video"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// Track is a timed text track of a video or audio player, in WebVTT.
+type Track struct {
+	// Kind is "subtitles", "captions", "descriptions", "chapters" or
+	// "metadata". Empty is subtitles.
+	Kind string
+	Src  string
+	// Lang is the language of the track, such as "en" or "pt-BR".
+	// Subtitles require one.
+	Lang string
+	// Label is the name of the track shown in the player's menu.
+	Label string
+	// Default enables the track unless the user's preferences select
+	// another.
+	Default bool
+}
+
+type mediaSource struct {
+	src, typ string
+}
+
+// PlayerBuilder is a <video> or <audio> player. Create one with Video or
+// Audio.
+type PlayerBuilder struct {
+	video         bool
+	sources       []mediaSource
+	tracks        []Track
+	poster        string
+	preload       preload.Preload
+	controls      bool
+	autoplay      bool
+	loop          bool
+	muted         bool
+	inline        bool
+	width, height int
+	fallback      string
+	attrs         []node.Attribute
+}
+
+// Video returns a video player for sources, in order of preference. The
+// type of each source is taken from its file extension (see MediaType).
+// Players show their controls unless Controls(false) is set.
+//
+// Usage:
+//
+//	media.Video("/v/intro.webm", "/v/intro.mp4").
+//	    Poster("/v/intro.jpg").
+//	    Size(1280, 720).
+//	    Captions("/v/intro.en.vtt", "en", "English")
+func Video(sources ...string) *PlayerBuilder {
+	return newPlayer(true, sources)
+}
+
+// Audio returns an audio player for sources, in order of preference.
+func Audio(sources ...string) *PlayerBuilder {
+	return newPlayer(false, sources)
+}
+
+func newPlayer(video bool, sources []string) *PlayerBuilder {
+	p := &PlayerBuilder{video: video, controls: true}
+	for _, src := range sources {
+		p.Source(src, "")
+	}
+	return p
+}
+
+// Source adds a source with the given media type. An empty type is taken
+// from the source's file extension.
+func (p *PlayerBuilder) Source(src, mediaType string) *PlayerBuilder {
+	if mediaType == "" {
+		mediaType = MediaType(src, p.video)
+	}
+	p.sources = append(p.sources, mediaSource{src: src, typ: mediaType})
+	return p
+}
+
+// Track adds a text track.
+func (p *PlayerBuilder) Track(t Track) *PlayerBuilder {
+	p.tracks = append(p.tracks, t)
+	return p
+}
+
+// Captions adds a captions track, transcribing dialogue and sounds for
+// viewers who cannot hear them.
+func (p *PlayerBuilder) Captions(src, lang, label string) *PlayerBuilder {
+	return p.Track(Track{Kind: "captions", Src: src, Lang: lang, Label: label})
+}
+
+// Subtitles adds a subtitles track, translating dialogue.
+func (p *PlayerBuilder) Subtitles(src, lang, label string) *PlayerBuilder {
+	return p.Track(Track{Kind: "subtitles", Src: src, Lang: lang, Label: label})
+}
+
+// Poster sets the image shown before a video plays. It is ignored for
+// audio.
+func (p *PlayerBuilder) Poster(url string) *PlayerBuilder {
+	p.poster = url
+	return p
+}
+
+// Preload sets how much of the media loads before it plays.
+func (p *PlayerBuilder) Preload(v preload.Preload) *PlayerBuilder {
+	p.preload = v
+	return p
+}
+
+// Controls sets whether the browser's playback controls are shown.
+func (p *PlayerBuilder) Controls(on bool) *PlayerBuilder {
+	p.controls = on
+	return p
+}
+
+// Autoplay starts playback once the media can play. Browsers only
+// autoplay muted media, so it also sets Muted.
+func (p *PlayerBuilder) Autoplay() *PlayerBuilder {
+	p.autoplay = true
+	p.muted = true
+	return p
+}
+
+// Loop restarts playback at the end.
+func (p *PlayerBuilder) Loop() *PlayerBuilder {
+	p.loop = true
+	return p
+}
+
+// Muted starts playback without sound.
+func (p *PlayerBuilder) Muted() *PlayerBuilder {
+	p.muted = true
+	return p
+}
+
+// PlaysInline plays a video in place on mobile browsers rather than full
+// screen. It is ignored for audio.
+func (p *PlayerBuilder) PlaysInline() *PlayerBuilder {
+	p.inline = true
+	return p
+}
+
+// Size sets the width and height of a video in pixels, reserving its space
+// before it loads. It is ignored for audio.
+func (p *PlayerBuilder) Size(width, height int) *PlayerBuilder {
+	p.width, p.height = width, height
+	return p
+}
+
+// Fallback sets the text shown by browsers that cannot play the media.
+func (p *PlayerBuilder) Fallback(text string) *PlayerBuilder {
+	p.fallback = text
+	return p
+}
+
+// Class sets the class of the player element.
+func (p *PlayerBuilder) Class(class string) *PlayerBuilder {
+	p.SetAttribute("class", class)
+	return p
+}
+
+// Element returns the player's markup.
+func (p *PlayerBuilder) Element() node.Node {
+	children := make([]node.Node, 0, len(p.sources)+len(p.tracks)+1)
+	for _, s := range p.sources {
+		el := source.New().Src(s.src)
+		if s.typ != "" {
+			el.Type(s.typ)
+		}
+		children = append(children, el)
+	}
+	for _, t := range p.tracks {
+		el := track.New().Src(t.Src)
+		if t.Kind != "" {
+			el.Kind(t.Kind)
+		}
+		if t.Lang != "" {
+			el.Srclang(t.Lang)
+		}
+		if t.Label != "" {
+			el.Label(t.Label)
+		}
+		if t.Default {
+			el.Default()
+		}
+		children = append(children, el)
+	}
+	if p.fallback != "" {
+		children = append(children, text.Text(p.fallback))
+	}
+
+	if !p.video {
+		el := audio.New(children...)
+		if p.controls {
+			el.Controls()
+		}
+		if p.autoplay {
+			el.Autoplay()
+		}
+		if p.loop {
+			el.Loop()
+		}
+		if p.muted {
+			el.Muted()
+		}
+		if p.preload != nil {
+			el.Preload(p.preload)
+		}
+		for _, a := range p.attrs {
+			el.SetAttribute(a.Key, a.Value)
+		}
+		return el
+	}
+
+	el := video.New(children...)
+	if p.controls {
+		el.Controls()
+	}
+	if p.autoplay {
+		el.Autoplay()
+	}
+	if p.loop {
+		el.Loop()
+	}
+	if p.muted {
+		el.Muted()
+	}
+	if p.preload != nil {
+		el.Preload(string(p.preload))
+	}
+	if p.poster != "" {
+		el.Poster(p.poster)
+	}
+	if p.width > 0 && p.height > 0 {
+		el.Width(p.width).Height(p.height)
+	}
+	if p.inline {
+		el.SetAttribute("playsinline", "playsinline")
+	}
+	for _, a := range p.attrs {
+		el.SetAttribute(a.Key, a.Value)
+	}
+	return el
+}
+
+// mediaTypes maps file extensions to media types. Extensions used for both
+// audio and video map to the video type; audioTypes overrides them for
+// audio players.
+var mediaTypes = map[string]string{
+	".mp4": "video/mp4", ".m4v": "video/mp4", ".webm": "video/webm",
+	".ogv": "video/ogg", ".ogg": "video/ogg", ".mov": "video/quicktime",
+	".mp3": "audio/mpeg", ".m4a": "audio/mp4", ".oga": "audio/ogg",
+	".opus": "audio/ogg", ".wav": "audio/wav", ".flac": "audio/flac",
+	".aac": "audio/aac", ".m3u8": "application/vnd.apple.mpegurl",
+	".mpd": "application/dash+xml",
+}
+
+var audioTypes = map[string]string{
+	".mp4": "audio/mp4", ".webm": "audio/webm", ".ogg": "audio/ogg",
+}
+
+// MediaType returns the media type of src from its file extension, for a
+// video player when video is true and an audio player otherwise, or "" if
+// the extension is not known. Query strings and fragments are ignored.
+func MediaType(src string, video bool) string {
+	p := src
+	if u, err := url.Parse(src); err == nil {
+		p = u.Path
+	}
+	ext := strings.ToLower(path.Ext(p))
+	if !video {
+		if t, ok := audioTypes[ext]; ok {
+			return t
+		}
+	}
+	return mediaTypes[ext]
+}
+
+func (p *PlayerBuilder) Render(w ...io.Writer) []byte {
+	return node.Render(p, w...)
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (p *PlayerBuilder) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, p)
+}
+
+// RenderBuilder writes the player's markup.
+func (p *PlayerBuilder) RenderBuilder(buf *bytes.Buffer) {
+	p.Element().RenderBuilder(buf)
+}
+
+// Nodes returns the player's markup as the only child.
+func (p *PlayerBuilder) Nodes() []node.Node {
+	return []node.Node{p.Element()}
+}
+
+// SetAttribute sets an attribute on the player element. The value is
+// escaped when written.
+func (p *PlayerBuilder) SetAttribute(key string, value string) {
+	p.attrs = append(p.attrs, node.Attribute{Key: key, Value: value})
+}
@@ -0,0 +1,60 @@
+package media_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/attr/preload"
+	"github.com/jpl-au/fluent/media"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestPlayer(t *testing.T) {
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"video", media.Video("/v/a.webm", "/v/a.mp4?v=2").Poster("/v/a.jpg").Size(640, 360).Captions("/v/a.en.vtt", "en", "English"),
+			`<video controls="controls" height="360" width="640" poster="/v/a.jpg">` +
+				`<source src="/v/a.webm" type="video/webm" />` +
+				`<source src="/v/a.mp4?v=2" type="video/mp4" />` +
+				`<track src="/v/a.en.vtt" kind="captions" label="English" srclang="en" />` +
+				`</video>`},
+		{"audio", media.Audio("/a/song.ogg", "/a/song.mp3").Preload(preload.None).Fallback("Your browser cannot play audio."),
+			`<audio preload="none" controls="controls">` +
+				`<source src="/a/song.ogg" type="audio/ogg" />` +
+				`<source src="/a/song.mp3" type="audio/mpeg" />` +
+				`Your browser cannot play audio.</audio>`},
+		{"background", media.Video("/bg.mp4").Controls(false).Autoplay().Loop().PlaysInline(),
+			`<video autoplay="autoplay" loop="loop" muted="muted" playsinline="playsinline"><source src="/bg.mp4" type="video/mp4" /></video>`},
+		{"explicit type", media.Audio().Source("/stream", "audio/aac").Track(media.Track{Kind: "chapters", Src: "/ch.vtt", Default: true}),
+			`<audio controls="controls"><source src="/stream" type="audio/aac" /><track src="/ch.vtt" kind="chapters" default="default" /></audio>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.node.Render()); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	tests := []struct {
+		src   string
+		video bool
+		want  string
+	}{
+		{"a.MP4", true, "video/mp4"},
+		{"a.mp4", false, "audio/mp4"},
+		{"a.webm#t=10", false, "audio/webm"},
+		{"https://cdn.example.com/a.m3u8?token=x", true, "application/vnd.apple.mpegurl"},
+		{"a.mp3", true, "audio/mpeg"},
+		{"a.unknown", true, ""},
+	}
+	for _, tt := range tests {
+		if got := media.MediaType(tt.src, tt.video); got != tt.want {
+			t.Errorf("MediaType(%q, %v) = %q, want %q", tt.src, tt.video, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package fluent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Renderer renders a node to a writer. Middleware receive the next Renderer
+// in the chain and return one that wraps it.
+type Renderer interface {
+	Render(ctx context.Context, n node.Node, w io.Writer) error
+}
+
+// RenderFunc adapts a function to the Renderer interface.
+type RenderFunc func(ctx context.Context, n node.Node, w io.Writer) error
+
+// Render calls f(ctx, n, w).
+func (f RenderFunc) Render(ctx context.Context, n node.Node, w io.Writer) error {
+	return f(ctx, n, w)
+}
+
+// Middleware wraps a Renderer to apply a cross-cutting change to every
+// render: it may replace the context, the node or the writer before calling
+// next, or write around or transform next's output.
+type Middleware func(next Renderer) Renderer
+
+var (
+	middleware atomic.Pointer[[]Middleware]
+	// using serialises Use so concurrent calls do not lose middleware.
+	using sync.Mutex
+)
+
+// Use registers middleware run around every RenderContext call. The first
+// middleware registered is the outermost, so it sees the output of all the
+// others. Use is safe to call while renders run; a render in flight keeps
+// the chain it started with.
+//
+// Middleware do not apply to Render or RenderBuilder called on a node
+// directly, or to StreamRender, whose output is written as it is produced.
+//
+// Usage:
+//
+//	fluent.Use(fluent.Transform(func(_ context.Context, out []byte) []byte {
+//	    return minify.HTML(out)
+//	}))
+func Use(mw ...Middleware) {
+	using.Lock()
+	defer using.Unlock()
+	var all []Middleware
+	if cur := middleware.Load(); cur != nil {
+		all = append(all, *cur...)
+	}
+	all = append(all, mw...)
+	middleware.Store(&all)
+}
+
+// ResetMiddleware removes all middleware registered with Use.
+func ResetMiddleware() {
+	using.Lock()
+	defer using.Unlock()
+	middleware.Store(nil)
+}
+
+// chain wraps r in the registered middleware.
+func chain(r Renderer) Renderer {
+	mws := middleware.Load()
+	if mws == nil {
+		return r
+	}
+	for i := len(*mws) - 1; i >= 0; i-- {
+		if mw := (*mws)[i]; mw != nil {
+			r = mw(r)
+		}
+	}
+	return r
+}
+
+// Transform returns middleware that renders into a buffer and writes
+// fn's result in its place, for changes made to the finished output such as
+// minifying or rewriting links. fn may modify out and return it. Nothing is
+// written if the render fails, other than with ErrPartial.
+//
+// Usage:
+//
+//	fluent.Use(fluent.Transform(func(_ context.Context, out []byte) []byte {
+//	    return bytes.ReplaceAll(out, []byte(`src="/static/`), []byte(`src="https://cdn.example.com/static/`))
+//	}))
+func Transform(fn func(ctx context.Context, out []byte) []byte) Middleware {
+	return func(next Renderer) Renderer {
+		return RenderFunc(func(ctx context.Context, n node.Node, w io.Writer) error {
+			buf := NewBuffer()
+			defer PutBuffer(buf)
+			err := next.Render(ctx, n, buf)
+			if err != nil && !errors.Is(err, ErrPartial) {
+				return err
+			}
+			if _, werr := w.Write(fn(ctx, buf.Bytes())); werr != nil {
+				return werr
+			}
+			return err
+		})
+	}
+}
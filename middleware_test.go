@@ -0,0 +1,65 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+// banner returns middleware writing s before the output of the render.
+func banner(s string) fluent.Middleware {
+	return func(next fluent.Renderer) fluent.Renderer {
+		return fluent.RenderFunc(func(ctx context.Context, n node.Node, w io.Writer) error {
+			_, _ = io.WriteString(w, s)
+			return next.Render(ctx, n, w)
+		})
+	}
+}
+
+func TestUse(t *testing.T) {
+	defer fluent.ResetMiddleware()
+	fluent.Use(banner("<!-- a -->"), fluent.Transform(func(_ context.Context, out []byte) []byte {
+		return bytes.ReplaceAll(out, []byte("div"), []byte("section"))
+	}))
+	fluent.Use(banner("<!-- b -->"))
+
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), div.New(span.Text("x")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	// The banner registered last is inside the transform.
+	if want := `<!-- a --><!-- b --><section><span>x</span></section>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	fluent.ResetMiddleware()
+	buf.Reset()
+	if err := fluent.RenderContext(context.Background(), div.New(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTransformError(t *testing.T) {
+	defer fluent.ResetMiddleware()
+	fluent.Use(fluent.Transform(func(_ context.Context, out []byte) []byte { return out }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, div.New(), &buf); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("got %q, want no output", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+// Package minify removes insignificant whitespace and comments from HTML.
+//
+// Fluent already renders compact markup, so most savings come from text
+// content: indented raw HTML, multi-line static strings and comments. The
+// content of <pre>, <textarea>, <script> and <style> is left untouched.
+package minify
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jpl-au/fluent/internal/markup"
+)
+
+// preserved lists elements whose content must not be altered.
+var preserved = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// HTML returns a minified copy of src.
+//
+// Runs of whitespace in text are collapsed to a single space, whitespace-only
+// text between block-level tags is removed, and comments are dropped except
+// for conditional comments (<!--[if ...]>).
+func HTML(src []byte) []byte {
+	tokens := markup.Tokenize(string(src))
+	out := bytes.NewBuffer(make([]byte, 0, len(src)))
+	var keep []string
+
+	for i, tok := range tokens {
+		switch tok.Type {
+		case markup.StartTagToken:
+			if preserved[tok.Data] {
+				keep = append(keep, tok.Data)
+			}
+			out.WriteString(tok.Raw)
+		case markup.EndTagToken:
+			if n := len(keep); n > 0 && keep[n-1] == tok.Data {
+				keep = keep[:n-1]
+			}
+			out.WriteString(tok.Raw)
+		case markup.CommentToken:
+			if len(keep) > 0 || strings.HasPrefix(tok.Data, "[if") {
+				out.WriteString(tok.Raw)
+			}
+		case markup.TextToken:
+			if len(keep) > 0 {
+				out.WriteString(tok.Raw)
+				continue
+			}
+			collapsed := collapse(tok.Raw)
+			if collapsed == " " && !significant(tokens, i) {
+				continue
+			}
+			out.WriteString(collapsed)
+		default:
+			out.WriteString(tok.Raw)
+		}
+	}
+	return out.Bytes()
+}
+
+// collapse replaces each run of whitespace in s with a single space.
+func collapse(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\n', '\r', '\f':
+			space = true
+		default:
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			b.WriteByte(s[i])
+		}
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// significant reports whether the whitespace-only text token at i sits next
+// to an inline element, where removing it would join adjacent words.
+func significant(tokens []markup.Token, i int) bool {
+	if i == 0 || i == len(tokens)-1 {
+		return false
+	}
+	return isInline(tokens[i-1]) && isInline(tokens[i+1])
+}
+
+func isInline(tok markup.Token) bool {
+	switch tok.Type {
+	case markup.TextToken:
+		return true
+	case markup.StartTagToken, markup.EndTagToken, markup.SelfClosingTagToken:
+		return markup.IsInline(tok.Data)
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package minify
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "block whitespace removed",
+			in:   "<ul>\n  <li>one</li>\n  <li>two</li>\n</ul>",
+			want: "<ul><li>one</li><li>two</li></ul>",
+		},
+		{
+			name: "text collapsed",
+			in:   "<p>Hello,\n\t   world</p>",
+			want: "<p>Hello, world</p>",
+		},
+		{
+			name: "inline spacing kept",
+			in:   "<p><b>bold</b>   <i>italic</i></p>",
+			want: "<p><b>bold</b> <i>italic</i></p>",
+		},
+		{
+			name: "comments dropped",
+			in:   "<div><!-- note --><!--[if IE]>x<![endif]--></div>",
+			want: "<div><!--[if IE]>x<![endif]--></div>",
+		},
+		{
+			name: "preserved content",
+			in:   "<pre>  a\n  b</pre><script>\nif (a  <  b) {}\n</script><textarea>\n x </textarea>",
+			want: "<pre>  a\n  b</pre><script>\nif (a  <  b) {}\n</script><textarea>\n x </textarea>",
+		},
+		{
+			name: "entities untouched",
+			in:   "<p>a &amp;  b</p>",
+			want: "<p>a &amp; b</p>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(HTML([]byte(tt.in))); got != tt.want {
+				t.Errorf("HTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
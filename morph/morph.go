@@ -0,0 +1,174 @@
+// Package morph annotates rendered elements with stable keys, so DOM morphing
+// libraries such as idiomorph and morphdom can match old and new elements
+// when a region is swapped, preserving focus, scroll position and state
+// without hand-maintained ids.
+//
+// Annotate gives every element in its output a data-morph-key attribute
+// derived from its position: the key of its parent and its index among its
+// element siblings. Elements with an id keep their id as their key. Items
+// whose position changes, such as rows of a sortable list, are marked with
+// Keyed so their key, and the keys of everything inside them, follow the item
+// rather than its index.
+//
+// Usage:
+//
+//	morph.Annotate(ul.New(node.Map(todos, func(t Todo) node.Node {
+//	    return morph.Keyed(t.ID, li.Text(t.Title))
+//	})...))
+//
+// With morphdom, match elements by the annotation:
+//
+//	morphdom(target, html, {getNodeKey: el => el.id || el.dataset?.morphKey})
+package morph
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// KeyAttribute is the attribute Annotate writes each element's key to.
+const KeyAttribute = "data-morph-key"
+
+// Keyed marks n with a key identifying it among its siblings, such as a
+// database id, so Annotate keys it by identity rather than position. Keys
+// must be unique among siblings.
+func Keyed[T node.Node](key string, n T) T {
+	n.SetAttribute(KeyAttribute, key)
+	return n
+}
+
+// Annotate returns a node rendering n with a KeyAttribute on each element.
+// Keys are scoped to the annotated node, so annotate the region that is
+// swapped as a whole.
+func Annotate(n node.Node) node.Node {
+	return &annotated{node: n}
+}
+
+type annotated struct {
+	node node.Node
+}
+
+func (a *annotated) Render(w ...io.Writer) []byte {
+	return node.Render(a, w...)
+}
+
+// RenderBuilder renders the wrapped node and writes its output with each
+// start tag annotated.
+func (a *annotated) RenderBuilder(buf *bytes.Buffer) {
+	if a.node == nil {
+		return
+	}
+	tmp := node.Scratch(buf)
+	defer node.PutScratch(buf, tmp)
+	if s := node.SessionOf(buf); s != nil {
+		release := node.Bind(tmp, s)
+		defer release()
+	}
+	a.node.RenderBuilder(tmp)
+	annotate(buf, tmp.String())
+}
+
+func (a *annotated) Nodes() []node.Node {
+	if a.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{a.node}
+}
+
+func (a *annotated) SetAttribute(key string, value string) {
+	if a.node != nil {
+		a.node.SetAttribute(key, value)
+	}
+}
+
+// frame is an open element: its tag, key and the number of element children
+// seen so far.
+type frame struct {
+	tag      string
+	key      string
+	children int
+}
+
+// annotate writes src to buf with keys added to its start tags.
+func annotate(buf *bytes.Buffer, src string) {
+	stack := []*frame{{}}
+	for _, tok := range markup.Tokenize(src) {
+		switch tok.Type {
+		case markup.StartTagToken, markup.SelfClosingTagToken:
+			parent := stack[len(stack)-1]
+			key := keyOf(tok, parent)
+			parent.children++
+			writeTag(buf, tok, key)
+			if tok.Type == markup.StartTagToken {
+				stack = append(stack, &frame{tag: tok.Data, key: key})
+			}
+		case markup.EndTagToken:
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == tok.Data {
+					stack = stack[:i]
+					break
+				}
+			}
+			buf.WriteString(tok.Raw)
+		default:
+			buf.WriteString(tok.Raw)
+		}
+	}
+}
+
+// keyOf returns the key of an element: "#id" for elements with an id, the
+// parent's key extended with the Keyed hint or the element's index
+// otherwise.
+func keyOf(tok markup.Token, parent *frame) string {
+	var hint string
+	for _, a := range tok.Attrs {
+		switch a.Key {
+		case "id":
+			if a.Val != "" {
+				return "#" + a.Val
+			}
+		case KeyAttribute:
+			hint = "/" + a.Val
+		}
+	}
+	if hint == "" {
+		hint = "." + strconv.Itoa(parent.children)
+	}
+	if parent.key == "" {
+		return hint[1:]
+	}
+	return parent.key + hint
+}
+
+// writeTag writes a start tag with its KeyAttribute set to key, or unchanged
+// for elements keyed by id.
+func writeTag(buf *bytes.Buffer, tok markup.Token, key string) {
+	if strings.HasPrefix(key, "#") {
+		buf.WriteString(tok.Raw)
+		return
+	}
+	// Keep the tag's original case, as markup lowercases Data.
+	buf.WriteString(tok.Raw[:1+len(tok.Data)])
+	for _, a := range tok.Attrs {
+		if a.Key == KeyAttribute {
+			continue
+		}
+		buf.WriteString(" " + a.Key + `="`)
+		escape.WriteString(buf, a.Val)
+		buf.WriteByte('"')
+	}
+	buf.WriteString(" " + KeyAttribute + `="`)
+	escape.WriteString(buf, key)
+	buf.WriteByte('"')
+	if strings.HasSuffix(tok.Raw, "/>") {
+		buf.WriteString(" />")
+	} else {
+		buf.WriteByte('>')
+	}
+}
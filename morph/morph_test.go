@@ -0,0 +1,60 @@
+package morph
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+func list(ids ...string) node.Node {
+	var items []node.Node
+	for _, id := range ids {
+		items = append(items, Keyed(id, li.New(span.Text(id), input.Checkbox("done", "1"))))
+	}
+	return div.New(p.Static("Todo"), ul.New(items...).Class("todos"), div.New().ID("footer").Class("f"))
+}
+
+func TestAnnotate(t *testing.T) {
+	got := string(Annotate(list("a", `b"`)).Render())
+	want := `<div data-morph-key="0">` +
+		`<p data-morph-key="0.0">Todo</p>` +
+		`<ul class="todos" data-morph-key="0.1">` +
+		`<li data-morph-key="0.1/a"><span data-morph-key="0.1/a.0">a</span><input name="done" value="1" type="checkbox" data-morph-key="0.1/a.1" /></li>` +
+		`<li data-morph-key="0.1/b&#34;"><span data-morph-key="0.1/b&#34;.0">b&#34;</span><input name="done" value="1" type="checkbox" data-morph-key="0.1/b&#34;.1" /></li>` +
+		`</ul>` +
+		`<div class="f" id="footer"></div>` +
+		`</div>`
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestAnnotateStable(t *testing.T) {
+	before := string(Annotate(list("a", "b", "c")).Render())
+	after := string(Annotate(list("c", "a")).Render())
+	for _, key := range []string{`data-morph-key="0.1/a.0"`, `data-morph-key="0.1/c.1"`} {
+		if !bytes.Contains([]byte(before), []byte(key)) || !bytes.Contains([]byte(after), []byte(key)) {
+			t.Errorf("key %s not stable across reorder:\n%s\n%s", key, before, after)
+		}
+	}
+}
+
+func TestAnnotateSession(t *testing.T) {
+	page := div.New(assets.HeadScripts(), Annotate(div.New(assets.Src("/app.js"))))
+	var buf bytes.Buffer
+	if err := assets.Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div><script src="/app.js" defer></script><div data-morph-key="0"></div></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
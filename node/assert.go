@@ -0,0 +1,168 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+)
+
+// AssertionError reports a failed Assert or Require marker.
+type AssertionError struct {
+	Message string
+	// File and Line locate the call that created the marker.
+	File string
+	Line int
+}
+
+func (e *AssertionError) Error() string {
+	if e.File == "" {
+		return "fluent: assertion failed: " + e.Message
+	}
+	return fmt.Sprintf("fluent: assertion failed at %s:%d: %s", e.File, e.Line, e.Message)
+}
+
+type assertKey struct{}
+
+// WithAssertions returns a context whose renders run invariant checks, such as
+// Assert and Require markers, calling fail with an error for each that does
+// not hold.
+// Tests pass t.Error; development servers pass a handler that panics or logs.
+// Without it markers render nothing and are never checked.
+//
+// Usage:
+//
+//	ctx := node.WithAssertions(context.Background(), func(err error) { t.Error(err) })
+//	fluent.RenderContext(ctx, page, &buf)
+func WithAssertions(ctx context.Context, fail func(error)) context.Context {
+	return context.WithValue(ctx, assertKey{}, fail)
+}
+
+// Checking reports whether the render writing into buf has assertions
+// enabled. Nodes whose checks are costly, such as measuring their output,
+// test it first so production renders skip the work.
+func Checking(buf *bytes.Buffer) bool {
+	fail, ok := Context(buf).Value(assertKey{}).(func(error))
+	return ok && fail != nil
+}
+
+// Fail reports err to the assertion handler of the render writing into buf.
+// It does nothing when the render does not have assertions enabled.
+func Fail(buf *bytes.Buffer, err error) {
+	FailContext(Context(buf), err)
+}
+
+// FailContext reports err to the assertion handler of ctx, for checks made
+// outside a node's render, such as by middleware given the render's
+// context. It does nothing when ctx does not have assertions enabled.
+func FailContext(ctx context.Context, err error) {
+	if fail, ok := ctx.Value(assertKey{}).(func(error)); ok && fail != nil {
+		fail(err)
+	}
+}
+
+// Assertion is a marker checking an invariant when rendered under
+// WithAssertions. It never produces output. Create one with Assert or Require.
+type Assertion struct {
+	check func() bool
+	msg   string
+	pc    uintptr
+}
+
+// Assert returns a marker that fails with msg when cond is false.
+//
+// Usage:
+//
+//	func Avatar(u *User) node.Node {
+//	    return node.FuncNodes(func() []node.Node {
+//	        return []node.Node{
+//	            node.Assert(u.Name != "", "Avatar: user has no name"),
+//	            img.New(u.Photo, u.Name),
+//	        }
+//	    })
+//	}
+func Assert(cond bool, msg string) *Assertion {
+	return &Assertion{check: func() bool { return cond }, msg: msg, pc: caller()}
+}
+
+// Require returns a marker that fails when value is missing: nil, the zero
+// value of its type, or an empty slice, map or string. It guards required
+// props and slots, such as a card rendered without a title.
+func Require(value any) *Assertion {
+	return &Assertion{
+		check: func() bool { return present(value) },
+		msg:   fmt.Sprintf("required %T value is missing", value),
+		pc:    caller(),
+	}
+}
+
+// caller returns the program counter of the function calling the marker
+// constructor. Resolving it to a file and line is left until a check fails.
+func caller() uintptr {
+	return callerAt(4)
+}
+
+// callerAt returns the program counter skip frames up the stack, counting
+// runtime.Callers as 0 and callerAt as 1.
+func callerAt(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// present reports whether v holds a usable value.
+func present(v any) bool {
+	if v == nil {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array, reflect.Chan:
+		return rv.Len() > 0
+	}
+	return !rv.IsZero()
+}
+
+// Err checks the marker and returns an *AssertionError if it does not hold.
+func (a *Assertion) Err() error {
+	if a.check() {
+		return nil
+	}
+	err := &AssertionError{Message: a.msg}
+	if a.pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{a.pc}).Next()
+		err.File, err.Line = frame.File, frame.Line
+	}
+	return err
+}
+
+// Render produces no output. Markers are only checked by context-aware
+// renders, as assertions are enabled through the render context.
+func (a *Assertion) Render(_ ...io.Writer) []byte {
+	return nil
+}
+
+// RenderBuilder checks the marker when the render has assertions enabled
+// and writes nothing.
+func (a *Assertion) RenderBuilder(buf *bytes.Buffer) {
+	if !Checking(buf) {
+		return
+	}
+	if err := a.Err(); err != nil {
+		Fail(buf, err)
+	}
+}
+
+// Nodes returns an empty slice as markers have no children.
+func (a *Assertion) Nodes() []Node {
+	return []Node{}
+}
+
+// SetAttribute is a no-op.
+func (a *Assertion) SetAttribute(_ string, _ string) {
+	// Assertion does not support attributes
+}
@@ -0,0 +1,73 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestAssertions(t *testing.T) {
+	var title string
+	var items []string
+	tests := []struct {
+		name   string
+		marker *node.Assertion
+		fails  bool
+	}{
+		{"assert true", node.Assert(true, "ok"), false},
+		{"assert false", node.Assert(false, "card needs a title"), true},
+		{"require string", node.Require("Hello"), false},
+		{"require empty string", node.Require(title), true},
+		{"require nil", node.Require(nil), true},
+		{"require nil node", node.Require(node.Node(nil)), true},
+		{"require empty slice", node.Require(items), true},
+		{"require zero int", node.Require(0), true},
+		{"require node", node.Require(div.Static("x")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+			var buf bytes.Buffer
+			if err := fluent.RenderContext(ctx, div.New(tt.marker, div.Static("body")), &buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != "<div><div>body</div></div>" {
+				t.Errorf("got %q", got)
+			}
+			if got := len(errs) > 0; got != tt.fails {
+				t.Fatalf("failed = %v, want %v (%v)", got, tt.fails, errs)
+			}
+			if !tt.fails {
+				return
+			}
+			var ae *node.AssertionError
+			if !errors.As(errs[0], &ae) {
+				t.Fatalf("got %T, want *node.AssertionError", errs[0])
+			}
+			if !strings.HasSuffix(ae.File, "assert_test.go") || ae.Line == 0 {
+				t.Errorf("location %s:%d does not point at the test", ae.File, ae.Line)
+			}
+		})
+	}
+}
+
+func TestAssertionsDisabled(t *testing.T) {
+	page := div.New(node.Assert(false, "broken"), node.Require(""))
+	if got := string(page.Render()); got != "<div></div>" {
+		t.Errorf("Render: got %q", got)
+	}
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<div></div>" {
+		t.Errorf("RenderContext: got %q", buf.String())
+	}
+}
@@ -5,3 +5,47 @@ type Attribute struct {
 	Key   string
 	Value string
 }
+
+// AttrIf returns the attribute key="value" when cond is true and the zero
+// Attribute, which SetAttributes skips, when it is false.
+func AttrIf(cond bool, key, value string) Attribute {
+	if !cond {
+		return Attribute{}
+	}
+	return Attribute{Key: key, Value: value}
+}
+
+// SetAttributes sets each attribute on n and returns n, so attributes that
+// depend on a condition can be added inline without wrapping the element in
+// a Condition or Func. Attributes with an empty key are skipped.
+//
+// Usage:
+//
+//	node.SetAttributes(a.New().Href("/docs").Text("Docs"),
+//	    node.AttrIf(active, "aria-current", "page"),
+//	    node.AttrIf(active, "data-active", ""),
+//	)
+func SetAttributes[T Node](n T, attrs ...Attribute) T {
+	if isNil(n) {
+		return n
+	}
+	for _, a := range attrs {
+		if a.Key != "" {
+			n.SetAttribute(a.Key, a.Value)
+		}
+	}
+	return n
+}
+
+// SetAttributeIf sets the attribute key="value" on n when cond is true, and
+// returns n either way.
+//
+// Usage:
+//
+//	node.SetAttributeIf(button.New().Text("Save"), saving, "disabled", "")
+func SetAttributeIf[T Node](n T, cond bool, key, value string) T {
+	if cond && !isNil(n) {
+		n.SetAttribute(key, value)
+	}
+	return n
+}
@@ -0,0 +1,36 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/button"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestAttrIf(t *testing.T) {
+	link := func(active bool) node.Node {
+		return node.SetAttributes(a.New().Href("/docs").Text("Docs"),
+			node.AttrIf(active, "aria-current", "page"),
+			node.AttrIf(active, "data-active", ""),
+			node.AttrIf(true, "data-nav", "main"),
+		)
+	}
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"active", link(true), `<a href="/docs" aria-current="page" data-active="" data-nav="main">Docs</a>`},
+		{"inactive", link(false), `<a href="/docs" data-nav="main">Docs</a>`},
+		{"set if", node.SetAttributeIf(button.New().Text("Save"), true, "disabled", ""), `<button disabled="">Save</button>`},
+		{"unset if", node.SetAttributeIf(button.New().Text("Save"), false, "disabled", ""), `<button>Save</button>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"bytes"
+	"io"
+)
+
+// Bytes returns a node writing b verbatim, without escaping it or copying it
+// into a string first, for embedding pre-rendered fragments such as cached
+// output or files from an embed.FS. b is trusted markup and must not be
+// modified once passed in. The node is static and implements Prerendered.
+//
+// Usage:
+//
+//	//go:embed banner.html
+//	var banner []byte
+//
+//	div.New(node.Bytes(banner))
+func Bytes(b []byte) Node {
+	return rawBytes(b)
+}
+
+type rawBytes []byte
+
+// Render writes the bytes.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, a copy of the bytes is returned.
+func (b rawBytes) Render(w ...io.Writer) []byte {
+	if len(w) > 0 && w[0] != nil {
+		_, _ = w[0].Write(b)
+		return nil
+	}
+	return bytes.Clone([]byte(b))
+}
+
+// WriteTo implements io.WriterTo, writing the bytes to w.
+func (b rawBytes) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// RenderBuilder copies the bytes into buf.
+func (b rawBytes) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(b)
+}
+
+// Prerendered returns the bytes. The slice must not be modified.
+func (b rawBytes) Prerendered() []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+// SizeHint returns the number of bytes.
+func (b rawBytes) SizeHint() int {
+	return len(b)
+}
+
+func (b rawBytes) Nodes() []Node {
+	return []Node{}
+}
+
+// Dynamic returns false as the bytes never change.
+func (b rawBytes) Dynamic() bool {
+	return false
+}
+
+// Clone returns the node itself, as the bytes are never modified.
+func (b rawBytes) Clone() Node {
+	return b
+}
+
+func (b rawBytes) SetAttribute(_ string, _ string) {
+	// rawBytes does not support attributes
+}
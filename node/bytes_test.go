@@ -0,0 +1,33 @@
+package node_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestBytes(t *testing.T) {
+	src := []byte(`<b>cached & trusted</b>`)
+	n := node.Bytes(src)
+
+	if got, want := string(div.New(n).Render()), `<div><b>cached & trusted</b></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	out := n.Render()
+	out[0] = 'X'
+	if src[0] != '<' {
+		t.Error("Render returned the node's own bytes")
+	}
+	var buf bytes.Buffer
+	if written, err := node.WriteTo(&buf, n); err != nil || written != int64(len(src)) {
+		t.Errorf("got %d, %v, want %d bytes", written, err, len(src))
+	}
+	if p, ok := n.(node.Prerendered); !ok || !bytes.Equal(p.Prerendered(), src) {
+		t.Error("expected the bytes to be prerendered")
+	}
+	if node.SizeHint(n) != len(src) {
+		t.Errorf("got hint %d, want %d", node.SizeHint(n), len(src))
+	}
+}
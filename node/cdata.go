@@ -0,0 +1,85 @@
+package node
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// The delimiters of a CDATA section. cdataSplit replaces "]]>" within
+// content, ending the section after "]]" and starting a new one before ">".
+const (
+	cdataStart = "<![CDATA["
+	cdataEnd   = "]]>"
+	cdataSplit = "]]]]><![CDATA[>"
+)
+
+// CDATA returns a node writing content as an XML CDATA section, for feeds,
+// sitemaps and other XML output holding markup that should not be escaped.
+// Any "]]>" in content is split across two sections so it cannot end the
+// section early. CDATA sections are not parsed in HTML documents outside
+// SVG and MathML.
+//
+// Usage:
+//
+//	svg.New(style.New(node.CDATA(css))) // <style><![CDATA[...]]></style>
+func CDATA(content string) Node {
+	return cdata(content)
+}
+
+type cdata string
+
+func (c cdata) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	c.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (c cdata) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, c)
+}
+
+// RenderBuilder writes the CDATA section to buf.
+func (c cdata) RenderBuilder(buf *bytes.Buffer) {
+	s := string(c)
+	buf.WriteString(cdataStart)
+	for {
+		i := strings.Index(s, cdataEnd)
+		if i < 0 {
+			break
+		}
+		buf.WriteString(s[:i])
+		buf.WriteString(cdataSplit)
+		s = s[i+len(cdataEnd):]
+	}
+	buf.WriteString(s)
+	buf.WriteString(cdataEnd)
+}
+
+// SizeHint returns the length of the section when content needs no split.
+func (c cdata) SizeHint() int {
+	return len(cdataStart) + len(c) + len(cdataEnd)
+}
+
+func (c cdata) Nodes() []Node {
+	return []Node{}
+}
+
+// Dynamic returns false as the content is fixed.
+func (c cdata) Dynamic() bool {
+	return false
+}
+
+func (c cdata) SetAttribute(_ string, _ string) {
+	// cdata does not support attributes
+}
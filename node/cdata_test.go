@@ -0,0 +1,27 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestCDATA(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"", "<![CDATA[]]>"},
+		{"<p>a & b</p>", "<![CDATA[<p>a & b</p>]]>"},
+		{"a]]>b", "<![CDATA[a]]]]><![CDATA[>b]]>"},
+		{"]]>]]>", "<![CDATA[]]]]><![CDATA[>]]]]><![CDATA[>]]>"},
+		{"]]", "<![CDATA[]]]]>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			if got := string(node.CDATA(tt.content).Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
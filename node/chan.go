@@ -0,0 +1,109 @@
+package node
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// Chan returns a node that renders the nodes received from ch, in order, as
+// they arrive, until ch is closed. It lets a producer such as a database
+// scanner emit rows while the page renders; with fluent.StreamRender each
+// node is flushed as it is rendered.
+//
+// Receiving stops early when the render's context is done, following the
+// render's deadline policy. The producer should also watch the context, so
+// it does not block sending to a channel that is no longer read. A channel
+// can only be drained once, so the node renders its content once.
+//
+// Usage:
+//
+//	rows := make(chan node.Node)
+//	go func() {
+//	    defer close(rows)
+//	    for r := range db.Scan(ctx) {
+//	        select {
+//	        case rows <- tr.New(td.Text(r.Name)):
+//	        case <-ctx.Done():
+//	            return
+//	        }
+//	    }
+//	}()
+//	table.New(tbody.New(node.Chan(rows)))
+func Chan(ch <-chan Node) *ChanNode {
+	return &ChanNode{ch: ch}
+}
+
+// ChanNode renders nodes received from a channel. Create one with Chan.
+type ChanNode struct {
+	ch <-chan Node
+}
+
+// Each receives nodes from the channel and calls fn with each until the
+// channel is closed or the render writing into buf is interrupted. Nil
+// nodes are skipped. Renderers that write output incrementally use it to
+// flush between nodes.
+func (c *ChanNode) Each(buf *bytes.Buffer, fn func(Node)) {
+	if c.ch == nil {
+		return
+	}
+	done := Context(buf).Done()
+	for {
+		select {
+		case <-done:
+			Interrupted(buf)
+			return
+		case n, ok := <-c.ch:
+			if !ok {
+				return
+			}
+			if !isNil(n) {
+				fn(n)
+			}
+		}
+	}
+}
+
+// Render generates the HTML representation of the received nodes.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (c *ChanNode) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	c.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (c *ChanNode) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, c)
+}
+
+// RenderBuilder writes each received node directly to a buffer.
+func (c *ChanNode) RenderBuilder(buf *bytes.Buffer) {
+	c.Each(buf, func(n Node) {
+		n.RenderBuilder(buf)
+	})
+}
+
+// Nodes returns an empty slice as the nodes are only known while rendering.
+func (c *ChanNode) Nodes() []Node {
+	return []Node{}
+}
+
+// Dynamic returns true as the content arrives during the render.
+func (c *ChanNode) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for ChanNode as it does not have attributes.
+func (c *ChanNode) SetAttribute(_ string, _ string) {
+	// ChanNode does not support attributes
+}
@@ -0,0 +1,51 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestChan(t *testing.T) {
+	ch := make(chan node.Node)
+	go func() {
+		defer close(ch)
+		for _, s := range []string{"a", "b", "c"} {
+			ch <- li.Text(s)
+		}
+		ch <- nil
+	}()
+	list := ul.New(node.Chan(ch))
+	if got, want := string(list.Render()), "<ul><li>a</li><li>b</li><li>c</li></ul>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// The channel is drained.
+	if got, want := string(list.Render()), "<ul></ul>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := string(node.Chan(nil).Render()); got != "" {
+		t.Errorf("got %q, want nothing for a nil channel", got)
+	}
+}
+
+func TestChanInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan node.Node)
+	go func() {
+		ch <- li.Text("first")
+		cancel()
+	}()
+
+	var buf bytes.Buffer
+	release := node.Bind(&buf, &node.Session{Context: ctx, Partial: true, Placeholder: span.Static("…")})
+	node.Chan(ch).RenderBuilder(&buf)
+	release()
+	if got, want := buf.String(), "<li>first</li><span>…</span>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,195 @@
+package node
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// Cloneable is implemented by nodes that copy themselves. Clone returns a
+// node sharing no mutable state with the original, copying its children
+// with the package-level Clone.
+type Cloneable interface {
+	Node
+	Clone() Node
+}
+
+// Clone returns an independent deep copy of n, so a prototype tree can be
+// built once and each request can set attributes on its own copy without
+// racing other requests. Nodes implementing Cloneable copy themselves; any
+// other node, including the html5 elements, is copied field by field with
+// its children and attributes cloned in turn.
+//
+// Only nodes and the attributes they hold are copied. Every other pointer,
+// such as a *cache.Store or a *template.Template, is shared with the
+// original, along with functions, channels and strings: they are either
+// immutable or state the copy must keep using, not part of the tree. A node
+// reached twice is copied once. Clone returns nil for a nil node.
+//
+// Usage:
+//
+//	var card = div.New(h2.Text("Title"), p.Text("Body")).Class("card")
+//
+//	func Card(id string) node.Node {
+//	    c := node.Clone(card)
+//	    c.SetAttribute("id", id)
+//	    return c
+//	}
+func Clone(n Node) Node {
+	if n == nil {
+		return nil
+	}
+	if c, ok := n.(Cloneable); ok {
+		return c.Clone()
+	}
+	c := cloner{}
+	return c.copy(reflect.ValueOf(n)).Interface().(Node) //nolint:forcetypeassert // copy preserves the type
+}
+
+// cloneNodes returns a copy of nodes with each node cloned.
+func cloneNodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	out := make([]Node, len(nodes))
+	for i, n := range nodes {
+		out[i] = Clone(n)
+	}
+	return out
+}
+
+var (
+	nodeType = reflect.TypeFor[Node]()
+
+	// html5 is the package of the attribute sets the generated elements
+	// point to.
+	html5 = strings.TrimSuffix(nodeType.PkgPath(), "/node") + "/html5"
+)
+
+// attributes reports whether t holds a node's attributes, which Clone copies
+// when a node points to them.
+func attributes(t reflect.Type) bool {
+	return t == reflect.TypeFor[[]Attribute]() || (t.Kind() == reflect.Struct && t.PkgPath() == html5)
+}
+
+// cloner copies one tree, remembering the copy of each pointer it follows
+// so that shared and cyclic references are copied once.
+type cloner struct {
+	seen map[pointer]reflect.Value
+}
+
+// pointer identifies a followed pointer. The type is part of the key as a
+// struct and its first field share an address.
+type pointer struct {
+	t reflect.Type
+	p uintptr
+}
+
+// copy returns a copy of v. Nodes are cloned, slices, arrays and maps are
+// copied, and pointers are followed only to nodes and attributes.
+func (c *cloner) copy(v reflect.Value) reflect.Value {
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || !v.Elem().Type().Implements(nodeType) {
+			return v
+		}
+		out := reflect.New(t).Elem()
+		if n := c.node(v.Elem()); n.IsValid() {
+			out.Set(n)
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() || !(t.Implements(nodeType) || attributes(t.Elem())) {
+			return v
+		}
+		if t.Implements(nodeType) {
+			if cl, ok := v.Interface().(Cloneable); ok {
+				if out := reflect.ValueOf(cl.Clone()); out.IsValid() && out.Type() == t {
+					return out
+				}
+			}
+		}
+		key := pointer{t, v.Pointer()}
+		if out, ok := c.seen[key]; ok {
+			return out
+		}
+		if c.seen == nil {
+			c.seen = make(map[pointer]reflect.Value)
+		}
+		out := reflect.New(t.Elem())
+		c.seen[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(t).Elem()
+		out.Set(v)
+		for i := range t.NumField() {
+			f := writable(out.Field(i))
+			f.Set(c.copy(f))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		if shallow(t.Elem()) {
+			reflect.Copy(out, v)
+			return out
+		}
+		for i := range v.Len() {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(t).Elem()
+		out.Set(v)
+		if !shallow(t.Elem()) {
+			for i := range v.Len() {
+				out.Index(i).Set(c.copy(v.Index(i)))
+			}
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(t, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			out.SetMapIndex(it.Key(), c.copy(it.Value()))
+		}
+		return out
+	}
+	return v
+}
+
+// node returns a copy of the node held in an interface. Cloneable nodes copy
+// themselves; others are copied within this tree.
+func (c *cloner) node(v reflect.Value) reflect.Value {
+	if cl, ok := v.Interface().(Cloneable); ok {
+		if n := cl.Clone(); n != nil {
+			return reflect.ValueOf(n)
+		}
+		return reflect.Value{}
+	}
+	return c.copy(v)
+}
+
+// shallow reports whether values of t contain nothing deepCopy would copy.
+func shallow(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return false
+	}
+	return true
+}
+
+// writable returns v, a field of an addressable struct, with the read-only
+// flag of unexported fields removed so its value can be read and replaced.
+func writable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem() //nolint:gosec // v is addressable
+}
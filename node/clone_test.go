@@ -0,0 +1,119 @@
+package node_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestClone(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+	}{
+		{"element", div.New(span.Text("a & b"), p.Static("x")).Class("card").Title("t")},
+		{"component", node.Component("List", ul.New(li.Text("one"), li.Text("two")))},
+		{"conditional", node.Condition(false).True(span.Text("yes")).False(span.Text("no"))},
+		{"func", node.Func(func() node.Node { return text.Text("f") })},
+		{"prerendered", node.Prerender(div.Text("fixed"))},
+		{"text", text.Text("<t>")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := string(tt.n.Render())
+			c := node.Clone(tt.n)
+			if got := string(c.Render()); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+	if node.Clone(nil) != nil {
+		t.Error("expected nil for a nil node")
+	}
+}
+
+func TestCloneIndependent(t *testing.T) {
+	inner := span.Text("inner")
+	proto := node.Component("Card", div.New(inner).Class("card"))
+
+	c := node.Clone(proto)
+	c.SetAttribute("data-id", "1")
+	c.Nodes()[0].Nodes()[0].SetAttribute("title", "changed")
+
+	if got, want := string(proto.Render()), `<div class="card"><span>inner</span></div>`; got != want {
+		t.Errorf("prototype changed: got %q, want %q", got, want)
+	}
+	if got, want := string(c.Render()), `<div class="card" data-id="1"><span title="changed">inner</span></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCloneConcurrent(t *testing.T) {
+	proto := div.New(p.Text("body")).Class("card")
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := node.Clone(proto)
+			c.SetAttribute("data-id", strconv.Itoa(i))
+			want := `<div class="card" data-id="` + strconv.Itoa(i) + `"><p>body</p></div>`
+			if got := string(c.Render()); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// store stands in for state a node uses but does not own, such as a cache.
+type store struct {
+	mu     sync.Mutex
+	builds int
+}
+
+// stored counts its renders in a shared store and points back to itself.
+type stored struct {
+	store *store
+	self  *stored
+}
+
+func (s *stored) Render(w ...io.Writer) []byte    { return nil }
+func (s *stored) Nodes() []node.Node              { return nil }
+func (s *stored) SetAttribute(_ string, _ string) {}
+func (s *stored) RenderBuilder(buf *bytes.Buffer) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.builds++
+}
+
+func TestCloneSharesState(t *testing.T) {
+	st := &store{}
+	proto := &stored{store: st}
+	proto.self = proto
+
+	c := node.Clone(div.New(proto)).Nodes()[0].(*stored) //nolint:forcetypeassert // built above
+	if c == proto {
+		t.Fatal("node was not copied")
+	}
+	if c.store != st {
+		t.Error("store was copied, want it shared")
+	}
+	if c.self != c {
+		t.Error("cycle not preserved in the copy")
+	}
+	c.RenderBuilder(new(bytes.Buffer))
+	if st.builds != 1 {
+		t.Errorf("got %d builds in the shared store, want 1", st.builds)
+	}
+}
@@ -0,0 +1,112 @@
+package node
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// NamedComponent labels a subtree with a component name. It renders exactly
+// like the wrapped node; the name exists for tooling that attributes output,
+// timings or warnings to components rather than to individual elements.
+//
+// Usage:
+//
+//	func ProductCard(p Product) node.Node {
+//	    return node.Component("ProductCard", div.New(
+//	        h3.Text(p.Name),
+//	    ).Class("card"))
+//	}
+type NamedComponent struct {
+	name string
+	node Node
+	pc   uintptr
+}
+
+// Component wraps n with a component name. While TrackSources is enabled it
+// also records the calling function's location for Source.
+func Component(name string, n Node) *NamedComponent {
+	return component(name, n)
+}
+
+// component creates a NamedComponent attributed to the caller of the exported
+// function calling it.
+func component(name string, n Node) *NamedComponent {
+	c := &NamedComponent{
+		name: name,
+		node: n,
+	}
+	if tracking.Load() {
+		c.pc = callerAt(4)
+	}
+	return c
+}
+
+// Name returns the component name.
+func (c *NamedComponent) Name() string {
+	return c.name
+}
+
+// Unwrap returns the wrapped node.
+func (c *NamedComponent) Unwrap() Node {
+	return c.node
+}
+
+// Render generates the HTML representation of the wrapped node.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (c *NamedComponent) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	c.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (c *NamedComponent) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, c)
+}
+
+// RenderBuilder writes the wrapped node directly to a buffer, calling any
+// hooks the render has registered with WithComponentHook around it.
+func (c *NamedComponent) RenderBuilder(buf *bytes.Buffer) {
+	if hooks := componentHooks(buf); len(hooks) > 0 {
+		for _, hook := range hooks {
+			if done := hook(buf, c); done != nil {
+				defer done()
+			}
+		}
+	}
+	if c.node != nil {
+		c.node.RenderBuilder(buf)
+	}
+}
+
+// Clone returns a copy of the component with the wrapped node cloned.
+func (c *NamedComponent) Clone() Node {
+	cp := *c
+	cp.node = Clone(c.node)
+	return &cp
+}
+
+// Nodes returns the wrapped node as the only child.
+func (c *NamedComponent) Nodes() []Node {
+	if c.node == nil {
+		return []Node{}
+	}
+	return []Node{c.node}
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (c *NamedComponent) SetAttribute(key string, value string) {
+	if c.node != nil {
+		c.node.SetAttribute(key, value)
+	}
+}
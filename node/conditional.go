@@ -5,7 +5,7 @@ import (
 	"io"
 	"reflect"
 
-	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/pool"
 )
 
 // ConditionalBuilder provides a fluent API for conditional rendering.
@@ -23,6 +23,8 @@ type ConditionalBuilder struct {
 	condition bool
 	trueNode  Node
 	falseNode Node
+	trueFn    func() Node
+	falseFn   func() Node
 }
 
 // Condition creates a new conditional builder with the given boolean condition.
@@ -57,6 +59,7 @@ func Unless(condition bool, node Node) *ConditionalBuilder {
 func (c *ConditionalBuilder) True(node Node) *ConditionalBuilder {
 	if node != nil && !reflect.ValueOf(node).IsNil() {
 		c.trueNode = node
+		c.trueFn = nil
 	}
 	return c
 }
@@ -66,6 +69,35 @@ func (c *ConditionalBuilder) True(node Node) *ConditionalBuilder {
 func (c *ConditionalBuilder) False(node Node) *ConditionalBuilder {
 	if node != nil && !reflect.ValueOf(node).IsNil() {
 		c.falseNode = node
+		c.falseFn = nil
+	}
+	return c
+}
+
+// TrueFunc sets a function building the node to render when the condition
+// is true. It is called on each render that takes the branch and never
+// otherwise, so the branch may use data that is only valid when the
+// condition holds. It replaces any node set with True.
+//
+// Usage:
+//
+//	Condition(user != nil).
+//	    TrueFunc(func() Node { return p.Text("Hello " + user.Name) }).
+//	    False(p.Text("Please log in"))
+func (c *ConditionalBuilder) TrueFunc(fn func() Node) *ConditionalBuilder {
+	if fn != nil {
+		c.trueFn = fn
+		c.trueNode = nil
+	}
+	return c
+}
+
+// FalseFunc sets a function building the node to render when the condition
+// is false. See TrueFunc.
+func (c *ConditionalBuilder) FalseFunc(fn func() Node) *ConditionalBuilder {
+	if fn != nil {
+		c.falseFn = fn
+		c.falseNode = nil
 	}
 	return c
 }
@@ -74,29 +106,55 @@ func (c *ConditionalBuilder) False(node Node) *ConditionalBuilder {
 // If a writer is provided, the output is written to it and nil is returned.
 // If no writer is provided, the output is returned as a byte slice.
 func (c *ConditionalBuilder) Render(w ...io.Writer) []byte {
-	buf := fluent.NewBuffer()
+	buf := pool.Get(0)
 	c.RenderBuilder(buf)
 
 	if len(w) > 0 && w[0] != nil {
 		_, _ = buf.WriteTo(w[0])
-		fluent.PutBuffer(buf)
+		pool.Put(buf)
 		return nil
 	}
 	return buf.Bytes()
 }
 
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (c *ConditionalBuilder) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, c)
+}
+
 // RenderBuilder writes the HTML representation directly to a buffer.
 // Renders the appropriate node based on the condition.
 func (c *ConditionalBuilder) RenderBuilder(buf *bytes.Buffer) {
-	if c.condition && c.trueNode != nil {
-		c.trueNode.RenderBuilder(buf)
-	} else if !c.condition && c.falseNode != nil {
-		c.falseNode.RenderBuilder(buf)
+	n, fn := c.falseNode, c.falseFn
+	if c.condition {
+		n, fn = c.trueNode, c.trueFn
+	}
+	if fn != nil {
+		n = fn()
+	}
+	if !isNil(n) {
+		n.RenderBuilder(buf)
 	}
 	// If condition doesn't match or no node is set, render nothing
 }
 
+// Clone returns a copy of the builder with both branches cloned.
+func (c *ConditionalBuilder) Clone() Node {
+	cp := *c
+	cp.trueNode = Clone(c.trueNode)
+	cp.falseNode = Clone(c.falseNode)
+	return &cp
+}
+
+// Dynamic returns true when a branch is built by TrueFunc or FalseFunc, as
+// its content is evaluated on each render.
+func (c *ConditionalBuilder) Dynamic() bool {
+	return c.trueFn != nil || c.falseFn != nil
+}
+
 // Nodes returns the potential child nodes of the ConditionalBuilder.
+// Branches built by TrueFunc or FalseFunc are not included.
 func (c *ConditionalBuilder) Nodes() []Node {
 	children := []Node{}
 	if c.trueNode != nil {
@@ -0,0 +1,56 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+type user struct{ Name string }
+
+func TestConditionFunc(t *testing.T) {
+	greeting := func(u *user) *node.ConditionalBuilder {
+		return node.Condition(u != nil).
+			TrueFunc(func() node.Node { return p.Text("Hello " + u.Name) }).
+			False(p.Text("Please log in"))
+	}
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"true", greeting(&user{Name: "Ann"}), "<p>Hello Ann</p>"},
+		{"false branch never built", greeting(nil), "<p>Please log in</p>"},
+		{"false func", node.Condition(false).True(p.Text("yes")).FalseFunc(func() node.Node { return p.Text("no") }), "<p>no</p>"},
+		{"nil result", node.Condition(true).TrueFunc(func() node.Node { return nil }), ""},
+		{"typed nil result", node.Condition(true).TrueFunc(func() node.Node { var n *node.ConditionalBuilder; return n }), ""},
+		{"node replaces func", node.Condition(true).TrueFunc(func() node.Node { return p.Text("func") }).True(p.Text("node")), "<p>node</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionFuncCalls(t *testing.T) {
+	calls := 0
+	c := node.When(false, nil).FalseFunc(func() node.Node {
+		calls++
+		return p.Text("x")
+	})
+	c.Render()
+	c.Render()
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+	if !c.Dynamic() {
+		t.Error("expected a func branch to be dynamic")
+	}
+	if node.When(true, p.Text("x")).Dynamic() {
+		t.Error("expected node branches to be static")
+	}
+}
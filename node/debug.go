@@ -0,0 +1,76 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var tracking atomic.Bool
+
+// TrackSources sets whether Component records the file and line it is called
+// from. It is off by default as capturing the caller costs time on every
+// component built; enable it at start up in development, before building
+// any pages.
+func TrackSources(enabled bool) {
+	tracking.Store(enabled)
+}
+
+// Source returns the file and line where the component was constructed, or
+// an empty file when it was built while TrackSources was disabled.
+func (c *NamedComponent) Source() (file string, line int) {
+	if c.pc == 0 {
+		return "", 0
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{c.pc}).Next()
+	return frame.File, frame.Line
+}
+
+// WithDebug returns a context whose renders wrap the output of each named
+// component in HTML comments naming it and, when TrackSources is enabled, the
+// file and line that built it, so the Go function producing a DOM subtree
+// can be found from the browser's element inspector.
+//
+// Usage:
+//
+//	node.TrackSources(true)
+//	fluent.RenderContext(node.WithDebug(r.Context()), page, w)
+//
+// Renders:
+//
+//	<!-- ProductCard /src/app/components/card.go:24 --><div class="card">...</div><!-- /ProductCard -->
+func WithDebug(ctx context.Context) context.Context {
+	return WithComponentHook(ctx, debugHook)
+}
+
+func debugHook(buf *bytes.Buffer, c *NamedComponent) func() {
+	name := comment(c.name)
+	buf.WriteString("<!-- ")
+	buf.WriteString(name)
+	if file, line := c.Source(); file != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(comment(file))
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(line))
+	}
+	buf.WriteString(" -->")
+	return func() {
+		buf.WriteString("<!-- /")
+		buf.WriteString(name)
+		buf.WriteString(" -->")
+	}
+}
+
+// comment makes s safe to place inside an HTML comment, which cannot contain
+// "--" or a ">" that would end it early. Runs of dashes are split until no
+// two are adjacent, as one pass leaves "- --" from "---".
+func comment(s string) string {
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "- -")
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestDebug(t *testing.T) {
+	node.TrackSources(true)
+	defer node.TrackSources(false)
+
+	_, file, line, _ := runtime.Caller(0)
+	badge := node.Component("Badge", span.Static("new"))
+	card := node.Layout("Card-->x", func(s node.Slots) node.Node { return s.Slot("body") }, "body")
+	filled := card.Fill(node.WithSlot("body", badge))
+
+	if f, l := badge.Source(); f != file || l != line+1 {
+		t.Errorf("Component source: got %s:%d, want %s:%d", f, l, file, line+1)
+	}
+	if f, l := filled.Source(); f != file || l != line+3 {
+		t.Errorf("Fill source: got %s:%d, want %s:%d", f, l, file, line+3)
+	}
+
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(node.WithDebug(context.Background()), filled, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("<!-- Card- -&gt;x %s:%d --><!-- Badge %s:%d --><span>new</span><!-- /Badge --><!-- /Card- -&gt;x -->", file, line+3, file, line+1)
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if got := string(filled.Render()); got != "<span>new</span>" {
+		t.Errorf("render without WithDebug: got %q", got)
+	}
+}
+
+func TestDebugDashes(t *testing.T) {
+	for name, want := range map[string]string{
+		"a---b": "a- - -b",
+		"----":  "- - - -",
+		"a-b":   "a-b",
+	} {
+		var buf bytes.Buffer
+		c := node.Component(name, span.Static("new"))
+		if err := fluent.RenderContext(node.WithDebug(context.Background()), c, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if want := "<!-- " + want + " --><span>new</span><!-- /" + want + " -->"; buf.String() != want {
+			t.Errorf("%q: got %q, want %q", name, buf.String(), want)
+		}
+	}
+}
+
+func TestDebugUntracked(t *testing.T) {
+	c := node.Component("Badge", span.Static("new"))
+	if f, _ := c.Source(); f != "" {
+		t.Errorf("source recorded while tracking disabled: %s", f)
+	}
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(node.WithDebug(context.Background()), c, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<!-- Badge --><span>new</span><!-- /Badge -->"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,226 @@
+package node
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// Deprecation describes one render of a deprecated component.
+type Deprecation struct {
+	// Component is the function that called Deprecated, such as
+	// "example.com/ui.OldCard".
+	Component string
+	Message   string
+	// File and Line locate the code that called the deprecated component,
+	// which is what has to change to migrate.
+	File string
+	Line int
+}
+
+func (d Deprecation) String() string {
+	return fmt.Sprintf("%s:%d: %s is deprecated: %s", d.File, d.Line, d.Component, d.Message)
+}
+
+var deprecationHook atomic.Pointer[func(Deprecation)]
+
+// OnDeprecated sets the hook called each time a deprecated component renders.
+// A nil hook, the default, disables reporting so deprecated components cost
+// nothing beyond their wrapper. The hook may be called concurrently.
+//
+// Usage:
+//
+//	report := node.NewDeprecationReport()
+//	node.OnDeprecated(report.Record)
+//	...
+//	report.WriteTo(os.Stderr)
+func OnDeprecated(hook func(Deprecation)) {
+	if hook == nil {
+		deprecationHook.Store(nil)
+		return
+	}
+	deprecationHook.Store(&hook)
+}
+
+// DeprecatedNode renders a wrapped node and reports the render to the
+// deprecation hook. Create one with Deprecated.
+type DeprecatedNode struct {
+	node Node
+	msg  string
+	pcs  [2]uintptr
+}
+
+// Deprecated wraps n, the output of a legacy component, so each render is
+// reported to the OnDeprecated hook with msg and the location of the code
+// using the component. n renders unchanged.
+//
+// Usage:
+//
+//	func OldCard(title string) node.Node {
+//	    return node.Deprecated(div.Text(title).Class("card"), "use ui.Card")
+//	}
+func Deprecated(n Node, msg string) *DeprecatedNode {
+	d := &DeprecatedNode{node: n, msg: msg}
+	// Skip runtime.Callers and Deprecated: the first frame is the deprecated
+	// component and the second the code calling it.
+	runtime.Callers(2, d.pcs[:])
+	return d
+}
+
+// Unwrap returns the wrapped node.
+func (d *DeprecatedNode) Unwrap() Node {
+	return d.node
+}
+
+// Deprecation returns the usage reported when d renders.
+func (d *DeprecatedNode) Deprecation() Deprecation {
+	dep := Deprecation{Message: d.msg}
+	frames := runtime.CallersFrames(d.pcs[:])
+	if f, more := frames.Next(); f.Function != "" {
+		dep.Component = f.Function
+		if more {
+			if f, _ = frames.Next(); f.Function != "" {
+				dep.File, dep.Line = f.File, f.Line
+			}
+		}
+	}
+	return dep
+}
+
+// Render generates the HTML representation of the wrapped node.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (d *DeprecatedNode) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	d.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (d *DeprecatedNode) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, d)
+}
+
+// RenderBuilder reports the render and writes the wrapped node to buf.
+func (d *DeprecatedNode) RenderBuilder(buf *bytes.Buffer) {
+	if hook := deprecationHook.Load(); hook != nil {
+		(*hook)(d.Deprecation())
+	}
+	if d.node != nil {
+		d.node.RenderBuilder(buf)
+	}
+}
+
+// Clone returns a copy of the node with the wrapped node cloned.
+func (d *DeprecatedNode) Clone() Node {
+	cp := *d
+	cp.node = Clone(d.node)
+	return &cp
+}
+
+// Nodes returns the wrapped node as the only child.
+func (d *DeprecatedNode) Nodes() []Node {
+	if d.node == nil {
+		return []Node{}
+	}
+	return []Node{d.node}
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (d *DeprecatedNode) SetAttribute(key string, value string) {
+	if d.node != nil {
+		d.node.SetAttribute(key, value)
+	}
+}
+
+// DeprecationUsage counts the renders of a deprecated component from one
+// call site.
+type DeprecationUsage struct {
+	Deprecation
+	Count int64
+}
+
+// DeprecationReport aggregates deprecation reports by component and call
+// site, giving a migration backlog. Pass its Record method to OnDeprecated.
+// It is safe for concurrent use.
+type DeprecationReport struct {
+	mu     sync.Mutex
+	usages map[Deprecation]*DeprecationUsage
+}
+
+// NewDeprecationReport creates an empty report.
+func NewDeprecationReport() *DeprecationReport {
+	return &DeprecationReport{usages: map[Deprecation]*DeprecationUsage{}}
+}
+
+// Record counts one render.
+func (r *DeprecationReport) Record(d Deprecation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.usages[d]
+	if u == nil {
+		u = &DeprecationUsage{Deprecation: d}
+		r.usages[d] = u
+	}
+	u.Count++
+}
+
+// Usages returns the recorded usages ordered by component, then by call site.
+func (r *DeprecationReport) Usages() []DeprecationUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]DeprecationUsage, 0, len(r.usages))
+	for _, u := range r.usages {
+		out = append(out, *u)
+	}
+	slices.SortFunc(out, func(a, b DeprecationUsage) int {
+		return cmp.Or(
+			cmp.Compare(a.Component, b.Component),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return out
+}
+
+// Reset clears the report.
+func (r *DeprecationReport) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.usages)
+}
+
+// WriteTo writes the report as text, one block per component listing its
+// message and each call site with its render count.
+func (r *DeprecationReport) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	component := ""
+	for i, u := range r.Usages() {
+		if i == 0 || u.Component != component {
+			component = u.Component
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%s: %s\n", u.Component, u.Message)
+		}
+		fmt.Fprintf(&b, "\t%s:%d\t%d renders\n", u.File, u.Line, u.Count)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
@@ -0,0 +1,70 @@
+package node_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func oldCard(title string) node.Node {
+	return node.Deprecated(div.Text(title).Class("card"), "use Card")
+}
+
+func TestDeprecated(t *testing.T) {
+	report := node.NewDeprecationReport()
+	node.OnDeprecated(report.Record)
+	defer node.OnDeprecated(nil)
+
+	page := div.New(
+		oldCard("a"),
+		oldCard("b"),
+	)
+	for range 3 {
+		if got := string(page.Render()); got != `<div><div class="card">a</div><div class="card">b</div></div>` {
+			t.Fatalf("got %q", got)
+		}
+	}
+
+	usages := report.Usages()
+	if len(usages) != 2 {
+		t.Fatalf("got %d usages, want 2: %+v", len(usages), usages)
+	}
+	for _, u := range usages {
+		if !strings.HasSuffix(u.Component, ".oldCard") {
+			t.Errorf("Component = %q, want oldCard", u.Component)
+		}
+		if !strings.HasSuffix(u.File, "deprecated_test.go") {
+			t.Errorf("File = %q, want the calling test file", u.File)
+		}
+		if u.Message != "use Card" || u.Count != 3 {
+			t.Errorf("got %q x%d, want %q x3", u.Message, u.Count, "use Card")
+		}
+	}
+	if usages[0].Line == usages[1].Line {
+		t.Error("call sites were not distinguished")
+	}
+
+	var buf bytes.Buffer
+	if _, err := report.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); strings.Count(got, "use Card") != 1 || strings.Count(got, "3 renders") != 2 {
+		t.Errorf("unexpected report:\n%s", got)
+	}
+
+	report.Reset()
+	if len(report.Usages()) != 0 {
+		t.Error("Reset left usages")
+	}
+}
+
+func TestDeprecatedWithoutHook(t *testing.T) {
+	n := oldCard("x")
+	n.SetAttribute("id", "old")
+	if got := string(n.Render()); got != `<div class="card" id="old">x</div>` {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package node
+
+import (
+	"bytes"
+	"io"
+)
+
+// Empty returns a node that renders nothing. Use it where a Node is required
+// but there is no content, rather than nil, which not every container
+// accepts.
+//
+// Usage:
+//
+//	func Badge(count int) node.Node {
+//	    if count == 0 {
+//	        return node.Empty()
+//	    }
+//	    return span.Textf("%d", count).Class("badge")
+//	}
+func Empty() Node {
+	return empty{}
+}
+
+type empty struct{}
+
+func (empty) Render(w ...io.Writer) []byte {
+	if len(w) > 0 && w[0] != nil {
+		return nil
+	}
+	return []byte{}
+}
+
+// WriteTo implements io.WriterTo, writing nothing.
+func (empty) WriteTo(_ io.Writer) (int64, error) {
+	return 0, nil
+}
+
+func (empty) RenderBuilder(_ *bytes.Buffer) {}
+
+func (empty) Nodes() []Node {
+	return []Node{}
+}
+
+// Prerendered returns the empty output.
+func (empty) Prerendered() []byte {
+	return []byte{}
+}
+
+// Dynamic returns false as the output never changes.
+func (empty) Dynamic() bool {
+	return false
+}
+
+// Clone returns the node itself, as it has no state.
+func (e empty) Clone() Node {
+	return e
+}
+
+func (empty) SetAttribute(_ string, _ string) {
+	// empty does not support attributes
+}
@@ -0,0 +1,29 @@
+package node_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestEmpty(t *testing.T) {
+	e := node.Empty()
+	if got := string(div.New(e, node.Empty()).Render()); got != "<div></div>" {
+		t.Errorf("got %q, want %q", got, "<div></div>")
+	}
+	if out := e.Render(); out == nil || len(out) != 0 {
+		t.Errorf("got %q, want an empty slice", out)
+	}
+	var buf bytes.Buffer
+	if out := e.Render(&buf); out != nil || buf.Len() != 0 {
+		t.Errorf("got %q and %q, want nothing written", out, buf.String())
+	}
+	if d, ok := e.(node.Dynamic); !ok || d.Dynamic() {
+		t.Error("expected Empty to be static")
+	}
+	if len(e.Nodes()) != 0 {
+		t.Error("expected no children")
+	}
+}
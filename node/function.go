@@ -2,9 +2,10 @@ package node
 
 import (
 	"bytes"
+	"context"
 	"io"
 
-	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/pool"
 )
 
 // FunctionComponent enables dynamic content generation through function calls.
@@ -22,7 +23,8 @@ import (
 //	    return div.Text("Please log in")
 //	})
 type FunctionComponent struct {
-	fn func() Node
+	fn    func() Node
+	ctxFn func(context.Context) Node
 }
 
 // Func creates a new function component that will call the provided function
@@ -33,31 +35,70 @@ func Func(fn func() Node) *FunctionComponent {
 	}
 }
 
+// FuncContext is Func for functions that need the render context, such as
+// to read values provided with fluent.WithValue or fluent.Provide. The
+// context is context.Background() outside a context-aware render.
+//
+// Usage:
+//
+//	node.FuncContext(func(ctx context.Context) node.Node {
+//	    user, ok := fluent.Value[*User](ctx, userKey{})
+//	    if !ok {
+//	        return a.New().Href("/login").Text("Sign in")
+//	    }
+//	    return span.Text(user.Name)
+//	})
+func FuncContext(fn func(ctx context.Context) Node) *FunctionComponent {
+	return &FunctionComponent{
+		ctxFn: fn,
+	}
+}
+
 // Render generates the HTML representation by calling the function.
 // If a writer is provided, the output is written to it and nil is returned.
 // If no writer is provided, the output is returned as a byte slice.
 func (f *FunctionComponent) Render(w ...io.Writer) []byte {
-	buf := fluent.NewBuffer()
+	buf := pool.Get(0)
 	f.RenderBuilder(buf)
 
 	if len(w) > 0 && w[0] != nil {
 		_, _ = buf.WriteTo(w[0])
-		fluent.PutBuffer(buf)
+		pool.Put(buf)
 		return nil
 	}
 	return buf.Bytes()
 }
 
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (f *FunctionComponent) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, f)
+}
+
 // RenderBuilder writes the HTML representation directly to a buffer.
 // Calls the function to get the actual node and renders it.
-// Nil nodes are safely ignored.
+// Nil nodes are safely ignored. The function is not called once a
+// context-aware render has expired (see Interrupted).
 func (f *FunctionComponent) RenderBuilder(buf *bytes.Buffer) {
+	if f.fn == nil && f.ctxFn == nil || Interrupted(buf) {
+		return
+	}
+	var node Node
 	if f.fn != nil {
-		node := f.fn()
-		if node != nil {
-			node.RenderBuilder(buf)
-		}
+		node = f.fn()
+	} else {
+		node = f.ctxFn(Context(buf))
 	}
+	if node != nil {
+		node.RenderBuilder(buf)
+	}
+}
+
+// Clone returns a copy sharing the function, which builds new nodes on
+// each render.
+func (f *FunctionComponent) Clone() Node {
+	cp := *f
+	return &cp
 }
 
 // Nodes returns an empty slice as FunctionComponent nodes do not have static children.
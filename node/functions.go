@@ -2,9 +2,10 @@ package node
 
 import (
 	"bytes"
+	"context"
 	"io"
 
-	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/pool"
 )
 
 // FunctionsComponent enables dynamic content generation of multiple nodes.
@@ -23,7 +24,8 @@ import (
 //	    return nodes
 //	})
 type FunctionsComponent struct {
-	fn func() []Node
+	fn    func() []Node
+	ctxFn func(context.Context) []Node
 }
 
 // FuncNodes creates a new function component that will call the provided function
@@ -34,35 +36,63 @@ func FuncNodes(fn func() []Node) *FunctionsComponent {
 	}
 }
 
+// FuncNodesContext is FuncNodes for functions that need the render context.
+// See FuncContext.
+func FuncNodesContext(fn func(ctx context.Context) []Node) *FunctionsComponent {
+	return &FunctionsComponent{
+		ctxFn: fn,
+	}
+}
+
 // Render generates the HTML representation by calling the function.
 // If a writer is provided, the output is written to it and nil is returned.
 // If no writer is provided, the output is returned as a byte slice.
 func (f *FunctionsComponent) Render(w ...io.Writer) []byte {
-	buf := fluent.NewBuffer()
+	buf := pool.Get(0)
 	f.RenderBuilder(buf)
 
 	if len(w) > 0 && w[0] != nil {
 		_, _ = buf.WriteTo(w[0])
-		fluent.PutBuffer(buf)
+		pool.Put(buf)
 		return nil
 	}
 	return buf.Bytes()
 }
 
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (f *FunctionsComponent) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, f)
+}
+
 // RenderBuilder writes the HTML representation directly to a buffer.
 // Calls the function to get the actual nodes and renders them.
-// Nil nodes are safely ignored.
+// Nil nodes are safely ignored. The function is not called once a
+// context-aware render has expired (see Interrupted).
 func (f *FunctionsComponent) RenderBuilder(buf *bytes.Buffer) {
+	if f.fn == nil && f.ctxFn == nil || Interrupted(buf) {
+		return
+	}
+	var nodes []Node
 	if f.fn != nil {
-		nodes := f.fn()
-		for _, node := range nodes {
-			if node != nil {
-				node.RenderBuilder(buf)
-			}
+		nodes = f.fn()
+	} else {
+		nodes = f.ctxFn(Context(buf))
+	}
+	for _, node := range nodes {
+		if node != nil {
+			node.RenderBuilder(buf)
 		}
 	}
 }
 
+// Clone returns a copy sharing the function, which builds new nodes on
+// each render.
+func (f *FunctionsComponent) Clone() Node {
+	cp := *f
+	return &cp
+}
+
 // Nodes returns an empty slice as FunctionsComponent nodes do not have static children.
 func (f *FunctionsComponent) Nodes() []Node {
 	return []Node{}
@@ -0,0 +1,38 @@
+package node
+
+// SizeHinter is implemented by nodes that can estimate the size of their
+// output, such as text whose content is already known.
+type SizeHinter interface {
+	// SizeHint returns the expected size of the rendered output in bytes.
+	SizeHint() int
+}
+
+// bufferHinter is implemented by elements, whose BufferHint is set to the
+// expected size of their output.
+type bufferHinter interface {
+	BufferHint(hint ...int) int
+}
+
+// SizeHint estimates the size of n's output in bytes, so a top-level render
+// can grow its buffer once instead of reallocating as a large page is
+// written into it. Nodes implementing SizeHinter give their own estimate,
+// elements with a BufferHint use it and anything else counts as the sum of
+// its children. Output produced at render time, such as that of Func, is
+// not counted, so the result is a lower bound.
+func SizeHint(n Node) int {
+	switch h := n.(type) {
+	case nil:
+		return 0
+	case SizeHinter:
+		return h.SizeHint()
+	case bufferHinter:
+		if size := h.BufferHint(); size > 0 {
+			return size
+		}
+	}
+	size := 0
+	for _, child := range n.Nodes() {
+		size += SizeHint(child)
+	}
+	return size
+}
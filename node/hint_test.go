@@ -0,0 +1,39 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+type hinted struct{ node.Node }
+
+func (hinted) SizeHint() int { return 1000 }
+
+func TestSizeHint(t *testing.T) {
+	explicit := div.New(text.Text("x"))
+	explicit.BufferHint(64)
+
+	tests := []struct {
+		name string
+		n    node.Node
+		want int
+	}{
+		{"nil", nil, 0},
+		{"text", text.Text("a<b"), len("a&lt;b")},
+		{"children", div.New(text.Text("abc"), p.New(text.Static("de"))), 5},
+		{"own hint", div.New(hinted{text.Text("x")}, text.Text("yz")), 1002},
+		{"buffer hint", div.New(explicit, text.Text("yz")), 66},
+		{"func", node.Func(func() node.Node { return text.Text("later") }), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := node.SizeHint(tt.n); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"slices"
+)
+
+// ComponentHook observes the render of named components. It is called as each
+// NamedComponent starts writing into buf and returns a function to call once
+// the component's output is complete, or nil when it has nothing to finish.
+//
+// Tracing, profiling and debugging tools use hooks to attribute render work
+// and output to components. A hook may replace the session's Context for the
+// duration of the component, restoring it in the returned function.
+type ComponentHook func(buf *bytes.Buffer, c *NamedComponent) (done func())
+
+type hooksKey struct{}
+
+// WithComponentHook returns a context whose renders call hook around each
+// named component. Hooks run in the order they were added and their done
+// functions in reverse, so each hook sees the output of those added after it.
+//
+// Usage:
+//
+//	ctx = node.WithComponentHook(ctx, func(buf *bytes.Buffer, c *node.NamedComponent) func() {
+//	    start := time.Now()
+//	    return func() { log.Printf("%s rendered in %s", c.Name(), time.Since(start)) }
+//	})
+func WithComponentHook(ctx context.Context, hook ComponentHook) context.Context {
+	hooks, _ := ctx.Value(hooksKey{}).([]ComponentHook)
+	return context.WithValue(ctx, hooksKey{}, append(slices.Clip(hooks), hook))
+}
+
+// componentHooks returns the hooks of the render writing into buf.
+func componentHooks(buf *bytes.Buffer) []ComponentHook {
+	s := SessionOf(buf)
+	if s == nil {
+		return nil
+	}
+	hooks, _ := s.Context.Value(hooksKey{}).([]ComponentHook)
+	return hooks
+}
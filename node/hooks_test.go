@@ -0,0 +1,54 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestComponentHook(t *testing.T) {
+	var events []string
+	hook := func(tag string) node.ComponentHook {
+		return func(buf *bytes.Buffer, c *node.NamedComponent) func() {
+			start := buf.Len()
+			events = append(events, fmt.Sprintf("%s start %s", tag, c.Name()))
+			return func() {
+				events = append(events, fmt.Sprintf("%s end %s %q", tag, c.Name(), buf.Bytes()[start:]))
+			}
+		}
+	}
+	ctx := node.WithComponentHook(context.Background(), hook("a"))
+	ctx = node.WithComponentHook(ctx, hook("b"))
+
+	tree := node.Component("Card", div.New(node.Component("Badge", span.Static("new"))))
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, tree, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"a start Card",
+		"b start Card",
+		"a start Badge",
+		"b start Badge",
+		`b end Badge "<span>new</span>"`,
+		`a end Badge "<span>new</span>"`,
+		`b end Card "<div><span>new</span></div>"`,
+		`a end Card "<div><span>new</span></div>"`,
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", events, want)
+	}
+
+	events = nil
+	tree.Render()
+	if len(events) != 0 {
+		t.Errorf("hooks ran outside a context-aware render: %q", events)
+	}
+}
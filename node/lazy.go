@@ -0,0 +1,96 @@
+package node
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// Lazy returns a node that builds its content with fn the first time it is
+// needed and reuses the result for every render after, giving
+// construct-on-first-use for expensive but stable subtrees such as a
+// navigation tree built from configuration. Unlike Func, fn is called at
+// most once, even by concurrent renders; use Func for content that changes
+// between renders. A nil result renders nothing.
+//
+// Usage:
+//
+//	var nav = node.Lazy(func() node.Node {
+//	    return buildNav(config.Menu)
+//	})
+func Lazy(fn func() Node) *LazyNode {
+	return &LazyNode{build: sync.OnceValue(func() Node {
+		if fn == nil {
+			return nil
+		}
+		return fn()
+	})}
+}
+
+// LazyNode is a node built on first use. Create one with Lazy.
+type LazyNode struct {
+	build func() Node
+}
+
+// Node returns the built node, building it first if needed.
+func (l *LazyNode) Node() Node {
+	return l.build()
+}
+
+// Render generates the HTML representation of the built node.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (l *LazyNode) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	l.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (l *LazyNode) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, l)
+}
+
+// RenderBuilder writes the built node directly to a buffer.
+func (l *LazyNode) RenderBuilder(buf *bytes.Buffer) {
+	if n := l.build(); !isNil(n) {
+		n.RenderBuilder(buf)
+	}
+}
+
+// Nodes returns the built node as the only child, building it if needed.
+func (l *LazyNode) Nodes() []Node {
+	if n := l.build(); !isNil(n) {
+		return []Node{n}
+	}
+	return []Node{}
+}
+
+// Dynamic returns false as the content does not change once built.
+func (l *LazyNode) Dynamic() bool {
+	return false
+}
+
+// Clone returns a lazy node building a clone of this node's content, so the
+// copy shares nothing mutable with the original.
+func (l *LazyNode) Clone() Node {
+	return Lazy(func() Node {
+		return Clone(l.build())
+	})
+}
+
+// SetAttribute sets the attribute on the built node, building it if needed.
+func (l *LazyNode) SetAttribute(key string, value string) {
+	if n := l.build(); !isNil(n) {
+		n.SetAttribute(key, value)
+	}
+}
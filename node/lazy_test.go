@@ -0,0 +1,50 @@
+package node_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestLazy(t *testing.T) {
+	var calls atomic.Int32
+	nav := node.Lazy(func() node.Node {
+		calls.Add(1)
+		return ul.New(li.Text("Home"), li.Text("About"))
+	})
+	if calls.Load() != 0 {
+		t.Fatal("expected nothing to be built before first use")
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, want := string(nav.Render()), "<ul><li>Home</li><li>About</li></ul>"; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls, want 1", calls.Load())
+	}
+
+	c := node.Clone(nav)
+	c.SetAttribute("id", "nav")
+	if got, want := string(nav.Render()), "<ul><li>Home</li><li>About</li></ul>"; got != want {
+		t.Errorf("clone changed the original: got %q, want %q", got, want)
+	}
+	if got, want := string(c.Render()), `<ul id="nav"><li>Home</li><li>About</li></ul>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := string(node.Lazy(func() node.Node { return nil }).Render()); got != "" {
+		t.Errorf("got %q, want nothing", got)
+	}
+}
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// PI returns a node writing an XML processing instruction, such as the XML
+// declaration or a stylesheet reference, so XML documents built from nodes
+// can be complete. A processing instruction cannot escape its end, so any
+// "?>" in data is written as "? >". Characters that cannot appear in a
+// name are dropped from target.
+//
+// Usage:
+//
+//	node.PI("xml", `version="1.0" encoding="UTF-8"`)      // <?xml version="1.0" encoding="UTF-8"?>
+//	node.PI("xml-stylesheet", `type="text/xsl" href="/feed.xsl"`)
+func PI(target, data string) Node {
+	return &pi{target: target, data: data}
+}
+
+type pi struct {
+	target string
+	data   string
+}
+
+func (p *pi) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	p.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (p *pi) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, p)
+}
+
+// RenderBuilder writes the processing instruction to buf.
+func (p *pi) RenderBuilder(buf *bytes.Buffer) {
+	buf.WriteString("<?")
+	for _, r := range p.target {
+		if nameRune(r) {
+			buf.WriteRune(r)
+		}
+	}
+	if p.data != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(strings.ReplaceAll(p.data, "?>", "? >"))
+	}
+	buf.WriteString("?>")
+}
+
+// nameRune reports whether r may appear in an XML name. Non-ASCII letters
+// are allowed as XML allows most of them.
+func nameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '-' || r == '_' || r == '.' || r == ':' || r >= 0x80
+}
+
+func (p *pi) Nodes() []Node {
+	return []Node{}
+}
+
+// Dynamic returns false as the instruction is fixed.
+func (p *pi) Dynamic() bool {
+	return false
+}
+
+func (p *pi) SetAttribute(_ string, _ string) {
+	// pi does not support attributes
+}
@@ -0,0 +1,29 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		data   string
+		want   string
+	}{
+		{"declaration", "xml", `version="1.0" encoding="UTF-8"`, `<?xml version="1.0" encoding="UTF-8"?>`},
+		{"stylesheet", "xml-stylesheet", `type="text/xsl" href="/feed.xsl"`, `<?xml-stylesheet type="text/xsl" href="/feed.xsl"?>`},
+		{"no data", "page-break", "", `<?page-break?>`},
+		{"end in data", "app", "a ?> b", `<?app a ? > b?>`},
+		{"invalid target", "a b?>", "x", `<?ab x?>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(node.PI(tt.target, tt.data).Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// Clock supplies the current time to time-dependent nodes.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// FixedClock returns a Clock that always reports t. It is useful for tests and
+// for previewing a site as it will appear at a future date.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+type clockKey struct{}
+
+// WithClock returns a context whose renders use c as the current time.
+func WithClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// Now returns the current time for the render writing into buf: the clock
+// injected with WithClock when there is one, otherwise time.Now().
+func Now(buf *bytes.Buffer) time.Time {
+	if c, ok := Context(buf).Value(clockKey{}).(Clock); ok {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// Schedule records when scheduled content rendered under a context next
+// changes, so callers can expire caches or schedule a rebuild at the right time.
+type Schedule struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+type scheduleKey struct{}
+
+// TrackSchedule returns a context that records scheduled content boundaries
+// into the returned Schedule.
+//
+// Usage:
+//
+//	ctx, schedule := node.TrackSchedule(r.Context())
+//	fluent.RenderContext(ctx, page, &buf)
+//	if next := schedule.Next(); !next.IsZero() {
+//	    w.Header().Set("Expires", next.UTC().Format(http.TimeFormat))
+//	}
+func TrackSchedule(ctx context.Context) (context.Context, *Schedule) {
+	s := &Schedule{}
+	return context.WithValue(ctx, scheduleKey{}, s), s
+}
+
+// Next returns the earliest recorded time at which rendered content changes,
+// or the zero time when nothing scheduled was rendered.
+func (s *Schedule) Next() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// record notes a future boundary.
+func (s *Schedule) record(t time.Time) {
+	s.mu.Lock()
+	if s.next.IsZero() || t.Before(s.next) {
+		s.next = t
+	}
+	s.mu.Unlock()
+}
+
+// ScheduleAt records t as a future boundary for the render writing into buf,
+// if that render is tracking its schedule. Custom time-dependent nodes call
+// this so caches built around them expire at the right moment.
+func ScheduleAt(buf *bytes.Buffer, t time.Time) {
+	if s, ok := Context(buf).Value(scheduleKey{}).(*Schedule); ok {
+		s.record(t)
+	}
+}
+
+// Window renders content only within a time window. Create one with Between.
+type Window struct {
+	start    time.Time
+	end      time.Time
+	node     Node
+	fallback Node
+}
+
+// Between renders n while the current time is within [start, end) and
+// fallback otherwise. A zero start or end leaves that side of the window open.
+// The current time comes from the render's Clock (see WithClock).
+//
+// Usage:
+//
+//	// Embargoed until launch
+//	node.Between(launch, time.Time{}, announcement, nil)
+//
+//	// Banner that expires at the end of the sale
+//	node.Between(time.Time{}, saleEnds, banner, nil)
+func Between(start, end time.Time, n Node, fallback Node) *Window {
+	return &Window{
+		start:    start,
+		end:      end,
+		node:     n,
+		fallback: fallback,
+	}
+}
+
+// Active reports whether t falls inside the window.
+func (w *Window) Active(t time.Time) bool {
+	return (w.start.IsZero() || !t.Before(w.start)) && (w.end.IsZero() || t.Before(w.end))
+}
+
+// Render generates the HTML representation for the current time.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (w *Window) Render(wr ...io.Writer) []byte {
+	buf := pool.Get(0)
+	w.RenderBuilder(buf)
+
+	if len(wr) > 0 && wr[0] != nil {
+		_, _ = buf.WriteTo(wr[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// RenderBuilder writes the node or fallback directly to a buffer, recording
+// the next window boundary with the render's Schedule.
+func (w *Window) RenderBuilder(buf *bytes.Buffer) {
+	now := Now(buf)
+	if !w.start.IsZero() && now.Before(w.start) {
+		ScheduleAt(buf, w.start)
+	} else if !w.end.IsZero() && now.Before(w.end) {
+		ScheduleAt(buf, w.end)
+	}
+
+	n := w.fallback
+	if w.Active(now) {
+		n = w.node
+	}
+	if !isNil(n) {
+		n.RenderBuilder(buf)
+	}
+}
+
+// Clone returns a copy of the window with both branches cloned.
+func (w *Window) Clone() Node {
+	cp := *w
+	cp.node = Clone(w.node)
+	cp.fallback = Clone(w.fallback)
+	return &cp
+}
+
+// Nodes returns the potential child nodes of the Window.
+func (w *Window) Nodes() []Node {
+	children := []Node{}
+	if !isNil(w.node) {
+		children = append(children, w.node)
+	}
+	if !isNil(w.fallback) {
+		children = append(children, w.fallback)
+	}
+	return children
+}
+
+// Dynamic returns true as the rendered branch depends on the current time.
+func (w *Window) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op for Window as it does not have attributes.
+func (w *Window) SetAttribute(_ string, _ string) {
+	// Window does not support attributes
+}
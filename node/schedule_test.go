@@ -0,0 +1,67 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestBetween(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+	window := node.Between(start, end, span.Static("sale"), span.Static("soon"))
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+		next time.Time
+	}{
+		{"before", start.Add(-time.Hour), "<span>soon</span>", start},
+		{"at start", start, "<span>sale</span>", end},
+		{"during", start.Add(time.Hour), "<span>sale</span>", end},
+		{"at end", end, "<span>soon</span>", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := node.WithClock(context.Background(), node.FixedClock(tt.now))
+			ctx, schedule := node.TrackSchedule(ctx)
+			var buf bytes.Buffer
+			if err := fluent.RenderContext(ctx, window, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+			if !schedule.Next().Equal(tt.next) {
+				t.Errorf("Next() = %v, want %v", schedule.Next(), tt.next)
+			}
+		})
+	}
+}
+
+func TestBetweenOpenEnded(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	if got := string(node.Between(past, time.Time{}, span.Static("live"), nil).Render()); got != "<span>live</span>" {
+		t.Errorf("open end: got %q", got)
+	}
+	if got := string(node.Between(time.Time{}, past, span.Static("expired"), nil).Render()); got != "" {
+		t.Errorf("expired: got %q", got)
+	}
+}
+
+func TestBetweenTypedNil(t *testing.T) {
+	var fallback *node.Window
+	w := node.Between(time.Time{}, time.Now().Add(-time.Hour), span.Static("expired"), fallback)
+	if got := string(w.Render()); got != "" {
+		t.Errorf("got %q, want nothing", got)
+	}
+	if n := len(w.Nodes()); n != 1 {
+		t.Errorf("Nodes() has %d children, want 1", n)
+	}
+}
@@ -0,0 +1,199 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// Session carries the state of a context-aware render.
+//
+// A session is bound to the buffer a render writes into, so any node rendering
+// into that buffer can look it up with SessionOf without the Node interface
+// having to thread a context through every RenderBuilder call.
+type Session struct {
+	// Context is the render context. It is never nil for a bound session.
+	Context context.Context
+
+	// Partial selects the behaviour once Context is done. When false, nodes
+	// that evaluate content at render time (Func, FuncNodes) stop evaluating
+	// and the render is abandoned. When true, they emit Placeholder instead
+	// and the rest of the tree renders normally.
+	Partial bool
+
+	// Placeholder is rendered in place of each skipped dynamic node when Partial is set.
+	// A nil Placeholder renders nothing.
+	Placeholder Node
+
+	// Arena supplies the intermediate buffers returned by Scratch. A nil
+	// Arena uses the shared pools.
+	Arena *pool.Arena
+
+	mu       sync.Mutex
+	deferred []func(*bytes.Buffer)
+	marker   []byte
+}
+
+var (
+	sessions sync.Map     // *bytes.Buffer -> *Session
+	bound    atomic.Int64 // number of live sessions, lets SessionOf skip the map lookup
+)
+
+// Bind associates s with buf until the returned release function is called.
+// Renders that create their own intermediate buffers do not inherit the session.
+func Bind(buf *bytes.Buffer, s *Session) (release func()) {
+	if s.Context == nil {
+		s.Context = context.Background()
+	}
+	sessions.Store(buf, s)
+	bound.Add(1)
+	return func() {
+		sessions.Delete(buf)
+		bound.Add(-1)
+	}
+}
+
+// SessionOf returns the session bound to buf, or nil if the buffer is not
+// part of a context-aware render.
+func SessionOf(buf *bytes.Buffer) *Session {
+	if bound.Load() == 0 {
+		return nil
+	}
+	if s, ok := sessions.Load(buf); ok {
+		return s.(*Session) //nolint:forcetypeassert // Map only contains *Session
+	}
+	return nil
+}
+
+// Context returns the context of the render writing into buf.
+// It returns context.Background() when buf has no session.
+func Context(buf *bytes.Buffer) context.Context {
+	if s := SessionOf(buf); s != nil {
+		return s.Context
+	}
+	return context.Background()
+}
+
+// Scratch returns an empty buffer for intermediate output of the render
+// writing into buf, taken from the session's arena when it has one. Return
+// it with PutScratch once its contents have been copied out.
+func Scratch(buf *bytes.Buffer) *bytes.Buffer {
+	if s := SessionOf(buf); s != nil {
+		return s.Arena.Get(0)
+	}
+	return pool.Get(0)
+}
+
+// PutScratch returns a buffer obtained from Scratch(buf).
+func PutScratch(buf, scratch *bytes.Buffer) {
+	if s := SessionOf(buf); s != nil {
+		s.Arena.Put(scratch)
+		return
+	}
+	pool.Put(scratch)
+}
+
+// Interrupted reports whether the render writing into buf has passed its
+// deadline or been cancelled. When it has and the session is partial, the
+// placeholder is written to buf. Nodes that evaluate content at render time
+// call this first and skip their work when it returns true.
+func Interrupted(buf *bytes.Buffer) bool {
+	s := SessionOf(buf)
+	if s == nil || s.Context.Err() == nil {
+		return false
+	}
+	if s.Partial && s.Placeholder != nil {
+		s.Placeholder.RenderBuilder(buf)
+	}
+	return true
+}
+
+// deferPrefix starts the placeholder written by Defer. Escaping passes NUL
+// bytes through, so rendered text could contain the prefix; each session
+// follows it with a random token that content cannot predict.
+const deferPrefix = "\x00fluent:defer:"
+
+// placeholder returns the session's placeholder prefix, creating it on first
+// use. The caller must hold mu.
+func (s *Session) placeholder() []byte {
+	if s.marker == nil {
+		var token [16]byte
+		_, _ = rand.Read(token[:])
+		s.marker = hex.AppendEncode([]byte(deferPrefix), token[:])
+		s.marker = append(s.marker, ':')
+	}
+	return s.marker
+}
+
+// Defer writes fn's output at the current position of buf once the rest of
+// the render has completed. Outlets use it to emit content collected from
+// nodes that render after them, such as head tags or hoisted scripts.
+//
+// Within a context-aware render a placeholder is written and replaced by
+// Expand; without a session fn is called immediately.
+func Defer(buf *bytes.Buffer, fn func(*bytes.Buffer)) {
+	s := SessionOf(buf)
+	if s == nil {
+		fn(buf)
+		return
+	}
+	s.mu.Lock()
+	s.deferred = append(s.deferred, fn)
+	id := len(s.deferred) - 1
+	marker := s.placeholder()
+	s.mu.Unlock()
+
+	buf.Write(marker)
+	buf.WriteString(strconv.Itoa(id))
+	buf.WriteByte(0)
+}
+
+// Deferred reports whether any placeholders were written during the render.
+func (s *Session) Deferred() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.deferred) > 0
+}
+
+// Expand writes src to dst, replacing each placeholder written by Defer with
+// the output of its function.
+func (s *Session) Expand(dst *bytes.Buffer, src []byte) {
+	s.mu.Lock()
+	marker := s.marker
+	s.mu.Unlock()
+	for {
+		i := -1
+		if marker != nil {
+			i = bytes.Index(src, marker)
+		}
+		if i < 0 {
+			dst.Write(src)
+			return
+		}
+		dst.Write(src[:i])
+		src = src[i+len(marker):]
+		end := bytes.IndexByte(src, 0)
+		if end < 0 {
+			dst.Write(marker)
+			dst.Write(src)
+			return
+		}
+		id, err := strconv.Atoi(string(src[:end]))
+		src = src[end+1:]
+		s.mu.Lock()
+		var fn func(*bytes.Buffer)
+		if err == nil && id >= 0 && id < len(s.deferred) {
+			fn = s.deferred[id]
+		}
+		s.mu.Unlock()
+		if fn != nil {
+			fn(dst)
+		}
+	}
+}
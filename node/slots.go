@@ -0,0 +1,137 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// SlotFill is content for a named slot of a layout. Create one with WithSlot.
+type SlotFill struct {
+	name  string
+	nodes []Node
+}
+
+// WithSlot fills the named slot with nodes.
+func WithSlot(name string, nodes ...Node) SlotFill {
+	return SlotFill{name: name, nodes: nodes}
+}
+
+// Slots holds the content filled into a layout's slots. A layout's render
+// function places each slot with Slot.
+type Slots struct {
+	fills map[string][]Node
+}
+
+// Slot returns the content of the named slot, or fallback when the slot was
+// not filled.
+func (s Slots) Slot(name string, fallback ...Node) Node {
+	if nodes, ok := s.fills[name]; ok {
+		return fragment(nodes)
+	}
+	return fragment(fallback)
+}
+
+// Has reports whether the named slot was filled, so a layout can omit the
+// wrapper of an empty slot.
+func (s Slots) Has(name string) bool {
+	_, ok := s.fills[name]
+	return ok
+}
+
+// LayoutComponent is a reusable layout with named slots. Create one with Layout.
+type LayoutComponent struct {
+	name   string
+	slots  []string
+	render func(Slots) Node
+}
+
+// Layout declares a layout with the named slots. render builds the layout's
+// tree, placing content with Slots.Slot. Each use fills the slots by name
+// rather than position, so callers only pass what they need and slots can be
+// added without breaking them.
+//
+// Usage:
+//
+//	var Card = node.Layout("Card", func(s node.Slots) node.Node {
+//	    return div.New(
+//	        header.New(s.Slot("header")),
+//	        div.New(s.Slot("body")).Class("card-body"),
+//	        node.When(s.Has("footer"), footer.New(s.Slot("footer"))),
+//	    ).Class("card")
+//	}, "header", "body", "footer")
+//
+//	Card.Fill(
+//	    node.WithSlot("header", h2.Text(title)),
+//	    node.WithSlot("body", p.Text(summary)),
+//	)
+func Layout(name string, render func(Slots) Node, slots ...string) *LayoutComponent {
+	return &LayoutComponent{name: name, slots: slots, render: render}
+}
+
+// Slots returns the names of the layout's slots.
+func (l *LayoutComponent) Slots() []string {
+	return slices.Clone(l.slots)
+}
+
+// Fill returns the layout with its slots filled, labelled with the layout's
+// name as a NamedComponent. Filling a slot more than once appends to it. It
+// panics when a fill names an undeclared slot, as that is a programming error
+// that would otherwise silently drop content.
+func (l *LayoutComponent) Fill(fills ...SlotFill) *NamedComponent {
+	s := Slots{fills: make(map[string][]Node, len(fills))}
+	for _, f := range fills {
+		if !slices.Contains(l.slots, f.name) {
+			panic(fmt.Sprintf("node: layout %s has no slot %q (slots: %v)", l.name, f.name, l.slots))
+		}
+		s.fills[f.name] = append(s.fills[f.name], f.nodes...)
+	}
+	return component(l.name, l.render(s))
+}
+
+// fragment renders a sequence of nodes without a wrapper. Nil nodes are skipped.
+type fragment []Node
+
+func (f fragment) Render(w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	f.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (f fragment) WriteTo(w io.Writer) (int64, error) {
+	return WriteTo(w, f)
+}
+
+func (f fragment) RenderBuilder(buf *bytes.Buffer) {
+	for _, n := range f {
+		if n != nil {
+			n.RenderBuilder(buf)
+		}
+	}
+}
+
+func (f fragment) Clone() Node {
+	return fragment(cloneNodes(f))
+}
+
+func (f fragment) Nodes() []Node {
+	if f == nil {
+		return []Node{}
+	}
+	return f
+}
+
+func (f fragment) SetAttribute(_ string, _ string) {
+	// fragment does not support attributes
+}
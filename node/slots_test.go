@@ -0,0 +1,68 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/footer"
+	"github.com/jpl-au/fluent/html5/h2"
+	"github.com/jpl-au/fluent/html5/header"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+var card = node.Layout("Card", func(s node.Slots) node.Node {
+	return div.New(
+		header.New(s.Slot("header", h2.Static("Untitled"))),
+		div.New(s.Slot("body")).Class("body"),
+		node.When(s.Has("footer"), footer.New(s.Slot("footer"))),
+	).Class("card")
+}, "header", "body", "footer")
+
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		fills []node.SlotFill
+		want  string
+	}{
+		{
+			"all slots",
+			[]node.SlotFill{
+				node.WithSlot("footer", p.Static("foot")),
+				node.WithSlot("header", h2.Static("Title")),
+				node.WithSlot("body", p.Static("a"), nil, p.Static("b")),
+			},
+			`<div class="card"><header><h2>Title</h2></header><div class="body"><p>a</p><p>b</p></div><footer><p>foot</p></footer></div>`,
+		},
+		{
+			"fallback and omitted",
+			[]node.SlotFill{node.WithSlot("body", p.Static("a"))},
+			`<div class="card"><header><h2>Untitled</h2></header><div class="body"><p>a</p></div></div>`,
+		},
+		{
+			"repeated fill appends",
+			[]node.SlotFill{node.WithSlot("body", p.Static("a")), node.WithSlot("body", p.Static("b"))},
+			`<div class="card"><header><h2>Untitled</h2></header><div class="body"><p>a</p><p>b</p></div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := card.Fill(tt.fills...)
+			if c.Name() != "Card" {
+				t.Errorf("Name() = %q", c.Name())
+			}
+			if got := string(c.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutUnknownSlot(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Fill did not panic on an undeclared slot")
+		}
+	}()
+	card.Fill(node.WithSlot("sidebar", p.Static("x")))
+}
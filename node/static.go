@@ -0,0 +1,99 @@
+package node
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Prerendered is implemented by nodes whose output is fixed and already
+// rendered. Parents and the JIT compiler can copy the bytes straight into
+// their output, and merge runs of prerendered siblings into a single static
+// region, without calling RenderBuilder.
+type Prerendered interface {
+	Node
+	// Prerendered returns the node's output, or nil if it has none stored.
+	// The slice must not be modified.
+	Prerendered() []byte
+}
+
+// Static is a node whose output is rendered once and then reused. Create one
+// with Prerender.
+type Static struct {
+	node Node
+	out  func() []byte
+}
+
+// Prerender returns a node that renders n the first time it is needed and
+// writes the stored bytes on every render after, so a fixed subtree such as
+// a footer or an icon costs a single copy however large it is. n must not
+// contain content evaluated at render time, which would be frozen at its
+// first value; it is rendered outside any render context.
+//
+// Usage:
+//
+//	var footer = node.Prerender(footer.New(
+//	    nav.New(...),
+//	    p.Static("Copyright 2025"),
+//	))
+func Prerender(n Node) *Static {
+	return &Static{node: n, out: sync.OnceValue(func() []byte {
+		if n == nil {
+			return []byte{}
+		}
+		var buf bytes.Buffer
+		n.RenderBuilder(&buf)
+		return buf.Bytes()
+	})}
+}
+
+// Prerendered returns the stored output, rendering it first if needed.
+func (s *Static) Prerendered() []byte {
+	return s.out()
+}
+
+// Render writes the stored output.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, a copy of the output is returned as a byte slice.
+func (s *Static) Render(w ...io.Writer) []byte {
+	if len(w) > 0 && w[0] != nil {
+		_, _ = w[0].Write(s.out())
+		return nil
+	}
+	return bytes.Clone(s.out())
+}
+
+// WriteTo implements io.WriterTo, writing the stored output to w.
+func (s *Static) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.out())
+	return int64(n), err
+}
+
+// RenderBuilder copies the stored output into buf.
+func (s *Static) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(s.out())
+}
+
+// Clone returns s itself, as its output never changes.
+func (s *Static) Clone() Node {
+	return s
+}
+
+// Nodes returns the prerendered node as the only child, so tools inspecting
+// the tree still see its structure.
+func (s *Static) Nodes() []Node {
+	if s.node == nil {
+		return []Node{}
+	}
+	return []Node{s.node}
+}
+
+// Dynamic returns false as the output never changes.
+func (s *Static) Dynamic() bool {
+	return false
+}
+
+// SetAttribute is a no-op as the output is fixed.
+func (s *Static) SetAttribute(_ string, _ string) {
+	// Static does not support attributes
+}
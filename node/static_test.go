@@ -0,0 +1,58 @@
+package node_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestPrerender(t *testing.T) {
+	calls := 0
+	footer := node.Prerender(div.New(
+		span.Static("fixed"),
+		node.Func(func() node.Node {
+			calls++
+			return text.Text("once")
+		}),
+	))
+	want := "<div><span>fixed</span>once</div>"
+	for range 3 {
+		var buf bytes.Buffer
+		div.New(footer).RenderBuilder(&buf)
+		if buf.String() != "<div>"+want+"</div>" {
+			t.Errorf("got %q, want %q", buf.String(), "<div>"+want+"</div>")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("rendered %d times, want 1", calls)
+	}
+	if got := string(footer.Prerendered()); got != want {
+		t.Errorf("Prerendered() = %q, want %q", got, want)
+	}
+
+	out := footer.Render()
+	out[0] = 'X'
+	if got := string(footer.Render()); got != want {
+		t.Errorf("Render() returned the stored bytes: %q", got)
+	}
+	if footer.Dynamic() || len(footer.Nodes()) != 1 {
+		t.Error("expected a static node exposing its prerendered child")
+	}
+	if got := string(node.Prerender(nil).Render()); got != "" {
+		t.Errorf("got %q for nil, want empty", got)
+	}
+}
+
+func TestPrerenderedText(t *testing.T) {
+	var p node.Prerendered = text.Static("<b>fixed</b>")
+	if got := string(p.Prerendered()); got != "<b>fixed</b>" {
+		t.Errorf("got %q", got)
+	}
+	if text.Text("dynamic").Prerendered() != nil {
+		t.Error("expected no stored output for escaped text")
+	}
+}
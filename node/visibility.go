@@ -0,0 +1,29 @@
+package node
+
+// Show renders n when cond is true and omits it from the output otherwise.
+// Use it for content that should not exist on the page at all; use Hidden
+// for content that must stay in the document, such as a target for an htmx
+// swap or a panel toggled by script.
+//
+// Usage:
+//
+//	node.Show(cart.Len() > 0, checkoutButton)
+func Show(cond bool, n Node) *ConditionalBuilder {
+	return When(cond, n)
+}
+
+// Hidden always renders n and sets the hidden attribute on it when cond is
+// true. Browsers do not display hidden elements and assistive technology
+// ignores them, but they stay in the document for scripts and swaps to find
+// and reveal. n is returned; it should be an element, as other nodes ignore
+// attributes.
+//
+// Usage:
+//
+//	node.Hidden(!open, div.New(details...).ID("panel"))
+func Hidden(cond bool, n Node) Node {
+	if cond && !isNil(n) {
+		n.SetAttribute("hidden", "")
+	}
+	return n
+}
@@ -0,0 +1,30 @@
+package node_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestVisibility(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"show", node.Show(true, div.Text("x")), "<div>x</div>"},
+		{"omit", node.Show(false, div.Text("x")), ""},
+		{"visible", node.Hidden(false, div.Text("x").ID("panel")), `<div id="panel">x</div>`},
+		{"hidden", node.Hidden(true, div.Text("x").ID("panel")), `<div id="panel" hidden="">x</div>`},
+		{"hidden component", node.Hidden(true, node.Component("Panel", div.Text("x"))), `<div hidden="">x</div>`},
+		{"hidden nil", div.New(node.Hidden(true, nil)), "<div></div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package node
+
+import "reflect"
+
+// Walk calls fn for n and each node below it in depth-first order, parents
+// before children. depth is 0 for n and one more for each level down. When
+// fn returns false the children of that node are skipped. Nil nodes,
+// including typed nil pointers, are never passed to fn.
+//
+// Walk visits the tree as built, through Nodes: content produced at render
+// time, such as the output of Func, is not visited.
+//
+// Usage:
+//
+//	node.Walk(page, func(n node.Node, depth int) bool {
+//	    fmt.Printf("%*s%T\n", depth*2, "", n)
+//	    return true
+//	})
+func Walk(n Node, fn func(n Node, depth int) (descend bool)) {
+	walk(n, 0, fn)
+}
+
+func walk(n Node, depth int, fn func(Node, int) bool) {
+	if isNil(n) || !fn(n, depth) {
+		return
+	}
+	for _, child := range n.Nodes() {
+		walk(child, depth+1, fn)
+	}
+}
+
+// Visit calls fn for each node below and including n that is of type T,
+// which may be a concrete node type or an interface such as Element.
+// All nodes are descended into, whatever their type.
+//
+// Usage:
+//
+//	node.Visit(page, func(c *node.NamedComponent) {
+//	    fmt.Println(c.Name())
+//	})
+func Visit[T any](n Node, fn func(T)) {
+	Walk(n, func(n Node, _ int) bool {
+		if v, ok := n.(T); ok {
+			fn(v)
+		}
+		return true
+	})
+}
+
+// Collect returns the nodes below and including n that are of type T, in
+// the order Walk visits them.
+//
+// Usage:
+//
+//	components := node.Collect[*node.NamedComponent](page)
+func Collect[T any](n Node) []T {
+	var out []T
+	Visit(n, func(v T) {
+		out = append(out, v)
+	})
+	return out
+}
+
+// isNil reports whether n is nil or a nil pointer held in the interface.
+func isNil(n Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package node_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func tree() node.Node {
+	var missing *node.NamedComponent
+	return div.New(
+		node.Component("Menu", ul.New(li.Text("a"), li.Text("b"))),
+		missing,
+		span.Text("c"),
+	)
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		skip string // type whose children are skipped
+		want string
+	}{
+		{"all", "", "0 div.element,1 node.NamedComponent,2 ul.element,3 li.element,4 text.Node,3 li.element,4 text.Node,1 span.element,2 text.Node"},
+		{"skip component", "*node.NamedComponent", "0 div.element,1 node.NamedComponent,1 span.element,2 text.Node"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			node.Walk(tree(), func(n node.Node, depth int) bool {
+				got = append(got, fmt.Sprintf("%d %s", depth, strings.TrimPrefix(fmt.Sprintf("%T", n), "*")))
+				return fmt.Sprintf("%T", n) != tt.skip
+			})
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("got %q, want %q", strings.Join(got, ","), tt.want)
+			}
+		})
+	}
+	node.Walk(nil, func(node.Node, int) bool {
+		t.Error("fn called for a nil node")
+		return true
+	})
+}
+
+func TestVisit(t *testing.T) {
+	var names []string
+	node.Visit(tree(), func(c *node.NamedComponent) {
+		names = append(names, c.Name())
+	})
+	if len(names) != 1 || names[0] != "Menu" {
+		t.Errorf("got %q, want %q", names, []string{"Menu"})
+	}
+
+	texts := node.Collect[*text.Node](tree())
+	var got []string
+	for _, tn := range texts {
+		got = append(got, tn.String())
+	}
+	if want := "a,b,c"; strings.Join(got, ",") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, ","), want)
+	}
+	if n := len(node.Collect[node.Element](tree())); n != 5 {
+		t.Errorf("got %d elements, want 5", n)
+	}
+}
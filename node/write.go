@@ -0,0 +1,35 @@
+package node
+
+import (
+	"io"
+
+	"github.com/jpl-au/fluent/pool"
+)
+
+// WriteTo renders n to w through a pooled buffer and returns the number of
+// bytes written and any error from w, which Render discards. Node types use
+// it to implement io.WriterTo.
+func WriteTo(w io.Writer, n Node) (int64, error) {
+	if n == nil {
+		return 0, nil
+	}
+	buf := pool.Get(0)
+	defer pool.Put(buf)
+	n.RenderBuilder(buf)
+	return buf.WriteTo(w)
+}
+
+// Render renders n through a pooled buffer. When a writer is provided the
+// output is written to it and nil is returned; otherwise the output is
+// returned. Node types use it to implement Render.
+func Render(n Node, w ...io.Writer) []byte {
+	buf := pool.Get(0)
+	n.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		pool.Put(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,55 @@
+package node_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestWriteTo(t *testing.T) {
+	tests := []struct {
+		name string
+		n    io.WriterTo
+		want string
+	}{
+		{"component", node.Component("Badge", span.Text("new")), "<span>new</span>"},
+		{"func", node.Func(func() node.Node { return span.Text("f") }), "<span>f</span>"},
+		{"funcs", node.FuncNodes(func() []node.Node { return []node.Node{text.Text("a"), text.Text("b")} }), "ab"},
+		{"condition", node.When(true, text.Text("yes")), "yes"},
+		{"prerendered", node.Prerender(span.Static("fixed")), "<span>fixed</span>"},
+		{"text", text.Text("a<b"), "a&lt;b"},
+		{"static text", text.Static("<b>"), "<b>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.n.WriteTo(&buf)
+			if err != nil || buf.String() != tt.want || n != int64(len(tt.want)) {
+				t.Errorf("got %q, %d, %v; want %q", buf.String(), n, err, tt.want)
+			}
+			if _, err := tt.n.WriteTo(failWriter{}); err == nil {
+				t.Error("expected the writer's error")
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	n := span.Text("a<b")
+	if got := string(node.Render(n)); got != "<span>a&lt;b</span>" {
+		t.Errorf("got %q", got)
+	}
+	var buf bytes.Buffer
+	if out := node.Render(n, &buf); out != nil || buf.String() != "<span>a&lt;b</span>" {
+		t.Errorf("got %q and %q written", out, buf.String())
+	}
+}
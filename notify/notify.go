@@ -0,0 +1,71 @@
+// Package notify delivers rendered components to webhook targets, so alert
+// and notification content reuses the components of the application's pages.
+//
+// A node is rendered once into a Message holding both HTML, suitable for an
+// email body, and the visible text, and the message is posted to every
+// target. Slack and generic JSON webhooks are built in; other services are
+// supported by implementing Target or by giving a Webhook its own Encode.
+// Slack messages are mapped to Block Kit with the blockkit package.
+//
+// Usage:
+//
+//	err := notify.Send(ctx, "Deploy failed", DeployAlert(run),
+//	    notify.Slack(os.Getenv("SLACK_WEBHOOK")),
+//	    notify.JSON("https://ops.example.com/hooks/alerts"),
+//	)
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+	"github.com/jpl-au/fluent/textdiff"
+)
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string
+	// HTML is the rendered markup of the node.
+	HTML string
+	// Text is the visible text of the node, one paragraph per line.
+	Text string
+}
+
+// Target receives notifications.
+type Target interface {
+	Send(ctx context.Context, m *Message) error
+}
+
+// Render renders n through fluent.RenderContext and returns it as a message.
+func Render(ctx context.Context, subject string, n node.Node) (*Message, error) {
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		return nil, err
+	}
+	markup := buf.String()
+	return &Message{
+		Subject: subject,
+		HTML:    markup,
+		Text:    textdiff.Text(text.Static(markup)),
+	}, nil
+}
+
+// Send renders n once and sends it to every target. Delivery continues past
+// failing targets; their errors are joined.
+func Send(ctx context.Context, subject string, n node.Node, targets ...Target) error {
+	m, err := Render(ctx, subject, n)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range targets {
+		if err := t.Send(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
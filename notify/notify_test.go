@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/blockkit"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+func alert() node.Node {
+	return div.New(
+		h1.Text("Deploy failed"),
+		p.Text("Build <42> broke & rolled back."),
+	).Class("alert")
+}
+
+func TestRender(t *testing.T) {
+	m, err := Render(context.Background(), "Deploy", alert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div class="alert"><h1>Deploy failed</h1><p>Build &lt;42&gt; broke &amp; rolled back.</p></div>`; m.HTML != want {
+		t.Errorf("HTML: got %q, want %q", m.HTML, want)
+	}
+	if want := "Deploy failed\nBuild <42> broke & rolled back."; m.Text != want {
+		t.Errorf("Text: got %q, want %q", m.Text, want)
+	}
+}
+
+func TestSlackBlocks(t *testing.T) {
+	body, err := SlackBlocks(&Message{
+		Subject: "Deploy <prod>",
+		HTML:    `<p>a <b>b</b></p>` + strings.Repeat("<hr>", 60),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Text   string
+		Blocks []blockkit.Block
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "Deploy &lt;prod&gt;" {
+		t.Errorf("fallback: got %q", got.Text)
+	}
+	if len(got.Blocks) != blockkit.MaxBlocks {
+		t.Fatalf("got %d blocks, want %d", len(got.Blocks), blockkit.MaxBlocks)
+	}
+	if b := got.Blocks[0]; b.Type != "header" || b.Text.Text != "Deploy <prod>" {
+		t.Errorf("header: got %+v", b)
+	}
+	if b := got.Blocks[1]; b.Type != "section" || b.Text.Text != "a *b*" {
+		t.Errorf("section: got %+v", b)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if r.URL.Path == "/fail" {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	err := Send(context.Background(), "Deploy", alert(), JSON(srv.URL+"/fail"), Slack(srv.URL+"/slack"))
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusBadRequest || !strings.Contains(se.Body, "invalid_payload") {
+		t.Fatalf("err: got %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, want 2 despite the failure", len(bodies))
+	}
+	var generic map[string]string
+	if err := json.Unmarshal([]byte(bodies[0]), &generic); err != nil || generic["subject"] != "Deploy" || !strings.HasPrefix(generic["html"], "<div") {
+		t.Errorf("json body: got %s", bodies[0])
+	}
+	if !strings.Contains(bodies[1], `"blocks":[`) {
+		t.Errorf("slack body: got %s", bodies[1])
+	}
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jpl-au/fluent/blockkit"
+)
+
+// StatusError is returned when a webhook responds with a non-2xx status.
+type StatusError struct {
+	URL  string
+	Code int
+	// Body is the start of the response body, which services use to explain
+	// the rejection.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("notify: %s: status %d: %s", e.URL, e.Code, e.Body)
+}
+
+// Webhook posts messages as JSON to a URL.
+type Webhook struct {
+	URL string
+	// Client sends the request. Nil uses http.DefaultClient.
+	Client *http.Client
+	// Encode builds the request body from a message.
+	Encode func(m *Message) ([]byte, error)
+}
+
+// Send posts m to the webhook.
+func (h *Webhook) Send(ctx context.Context, m *Message) error {
+	body, err := h.Encode(m)
+	if err != nil {
+		return fmt.Errorf("notify: encode: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{URL: h.URL, Code: resp.StatusCode, Body: string(snippet)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// JSON returns a webhook that posts {"subject", "html", "text"}.
+func JSON(url string) *Webhook {
+	return &Webhook{URL: url, Encode: func(m *Message) ([]byte, error) {
+		return json.Marshal(struct {
+			Subject string `json:"subject"`
+			HTML    string `json:"html"`
+			Text    string `json:"text"`
+		}{m.Subject, m.HTML, m.Text})
+	}}
+}
+
+// Slack returns a webhook for a Slack incoming webhook URL. The message is
+// sent as Block Kit blocks: see SlackBlocks.
+func Slack(url string) *Webhook {
+	return &Webhook{URL: url, Encode: SlackBlocks}
+}
+
+// SlackBlocks encodes m as a Slack message payload. The subject becomes a
+// header block followed by the HTML mapped with blockkit.Parse; blocks beyond
+// Slack's limit are dropped. The subject, or the start of the text, is also
+// sent as the notification fallback.
+func SlackBlocks(m *Message) ([]byte, error) {
+	var blocks []blockkit.Block
+	if m.Subject != "" {
+		blocks = append(blocks, blockkit.Block{Type: "header", Text: &blockkit.Text{Type: "plain_text", Text: clip(m.Subject, 150)}})
+	}
+	body, _ := blockkit.Parse(m.HTML)
+	blocks = append(blocks, body...)
+	blocks = blocks[:min(len(blocks), blockkit.MaxBlocks)]
+
+	fallback := m.Subject
+	if fallback == "" {
+		fallback = clip(m.Text, 3000)
+	}
+	return json.Marshal(struct {
+		Text   string           `json:"text"`
+		Blocks []blockkit.Block `json:"blocks,omitempty"`
+	}{slackEscape(fallback), blocks})
+}
+
+// slackEscape escapes the characters Slack treats as control sequences.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// clip returns the longest prefix of s of at most n runes.
+func clip(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
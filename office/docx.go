@@ -0,0 +1,305 @@
+package office
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOCX writes Office Open XML word-processing documents (.docx).
+var DOCX Writer = docx{}
+
+type docx struct{}
+
+func (docx) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+}
+
+func (docx) Extension() string {
+	return ".docx"
+}
+
+// emuPerPixel converts CSS pixels (96 per inch) to English Metric Units.
+const emuPerPixel = 9525
+
+// maxImageEMU is the widest image that fits a default page: 6 inches.
+const maxImageEMU = 6 * 914400
+
+func (docx) Write(w io.Writer, doc *Document) error {
+	d := &docxWriter{}
+	d.rels.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	d.rels.WriteString(`<Relationship Id="rIdNumbering" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+	for _, b := range doc.Blocks {
+		d.block(b)
+	}
+
+	z := zip.NewWriter(w)
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", docxCore(doc.Title)},
+		{"word/document.xml", docxDocumentOpen + d.body.String() + docxDocumentClose},
+		{"word/styles.xml", docxStyles},
+		{"word/numbering.xml", d.numbering()},
+		{"word/_rels/document.xml.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + d.rels.String() + `</Relationships>`},
+	}
+	for _, f := range files {
+		if err := writeZip(z, f.name, []byte(f.body), zip.Deflate); err != nil {
+			return err
+		}
+	}
+	for _, m := range d.media {
+		if err := writeZip(z, "word/"+m.name, m.data, zip.Store); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+type media struct {
+	name string
+	data []byte
+}
+
+type docxWriter struct {
+	body  strings.Builder
+	rels  strings.Builder
+	media []media
+	lists []bool // ordered flag per numbering instance
+	ids   int
+}
+
+func (d *docxWriter) rel(typ, target string, external bool) string {
+	d.ids++
+	id := "rId" + strconv.Itoa(d.ids)
+	d.rels.WriteString(`<Relationship Id="` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/` + typ + `" Target="` + xmlText(target) + `"`)
+	if external {
+		d.rels.WriteString(` TargetMode="External"`)
+	}
+	d.rels.WriteString(`/>`)
+	return id
+}
+
+func (d *docxWriter) block(b Block) {
+	switch b := b.(type) {
+	case Heading:
+		d.paragraph("Heading"+strconv.Itoa(b.Level), "", b.Runs)
+	case Paragraph:
+		d.paragraph("", "", b.Runs)
+	case List:
+		d.lists = append(d.lists, b.Ordered)
+		num := strconv.Itoa(len(d.lists))
+		for _, it := range b.Items {
+			numPr := `<w:numPr><w:ilvl w:val="` + strconv.Itoa(min(it.Level, 8)) + `"/><w:numId w:val="` + num + `"/></w:numPr>`
+			d.paragraph("ListParagraph", numPr, it.Runs)
+		}
+	case Table:
+		d.table(b)
+	case Image:
+		d.image(b)
+	}
+}
+
+func (d *docxWriter) paragraph(style, props string, runs []Run) {
+	d.body.WriteString("<w:p>")
+	if style != "" || props != "" {
+		d.body.WriteString("<w:pPr>")
+		if style != "" {
+			d.body.WriteString(`<w:pStyle w:val="` + style + `"/>`)
+		}
+		d.body.WriteString(props)
+		d.body.WriteString("</w:pPr>")
+	}
+	d.runs(runs, false)
+	d.body.WriteString("</w:p>")
+}
+
+func (d *docxWriter) runs(runs []Run, bold bool) {
+	for _, r := range runs {
+		if r.Href != "" {
+			id := d.rel("hyperlink", r.Href, true)
+			d.body.WriteString(`<w:hyperlink r:id="` + id + `">`)
+			d.run(r, bold, true)
+			d.body.WriteString(`</w:hyperlink>`)
+			continue
+		}
+		d.run(r, bold, false)
+	}
+}
+
+func (d *docxWriter) run(r Run, bold, link bool) {
+	if r.Text == "\n" {
+		d.body.WriteString("<w:r><w:br/></w:r>")
+		return
+	}
+	d.body.WriteString("<w:r>")
+	if r.Bold || r.Italic || bold || link {
+		d.body.WriteString("<w:rPr>")
+		if link {
+			d.body.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+		}
+		if r.Bold || bold {
+			d.body.WriteString("<w:b/>")
+		}
+		if r.Italic {
+			d.body.WriteString("<w:i/>")
+		}
+		d.body.WriteString("</w:rPr>")
+	}
+	d.body.WriteString(`<w:t xml:space="preserve">` + xmlText(r.Text) + `</w:t></w:r>`)
+}
+
+func (d *docxWriter) table(t Table) {
+	cols := t.Columns()
+	d.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid>`)
+	for range cols {
+		d.body.WriteString(`<w:gridCol/>`)
+	}
+	d.body.WriteString(`</w:tblGrid>`)
+	for _, r := range t.Rows {
+		d.body.WriteString("<w:tr>")
+		if r.Header {
+			d.body.WriteString("<w:trPr><w:tblHeader/></w:trPr>")
+		}
+		for i := range cols {
+			d.body.WriteString("<w:tc><w:p>")
+			if i < len(r.Cells) {
+				d.runs(r.Cells[i], r.Header)
+			}
+			d.body.WriteString("</w:p></w:tc>")
+		}
+		d.body.WriteString("</w:tr>")
+	}
+	d.body.WriteString("</w:tbl>")
+}
+
+func (d *docxWriter) image(img Image) {
+	if img.Data == nil {
+		if img.Alt != "" {
+			d.paragraph("", "", []Run{{Text: img.Alt, Italic: true}})
+		}
+		return
+	}
+	n := len(d.media) + 1
+	name := fmt.Sprintf("media/image%d.%s", n, img.Format)
+	d.media = append(d.media, media{name: name, data: img.Data})
+	id := d.rel("image", name, false)
+
+	cx, cy := img.Width*emuPerPixel, img.Height*emuPerPixel
+	if cx > maxImageEMU {
+		cy = cy * maxImageEMU / cx
+		cx = maxImageEMU
+	}
+	ext := `cx="` + strconv.Itoa(cx) + `" cy="` + strconv.Itoa(cy) + `"`
+	num := strconv.Itoa(n)
+	d.body.WriteString(`<w:p><w:r><w:drawing><wp:inline><wp:extent ` + ext + `/>` +
+		`<wp:docPr id="` + num + `" name="Picture ` + num + `" descr="` + xmlText(img.Alt) + `"/>` +
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">` +
+		`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">` +
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">` +
+		`<pic:nvPicPr><pic:cNvPr id="` + num + `" name="image` + num + `"/><pic:cNvPicPr/></pic:nvPicPr>` +
+		`<pic:blipFill><a:blip r:embed="` + id + `"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>` +
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext ` + ext + `/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>` +
+		`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>`)
+}
+
+// numbering defines one bullet and one decimal scheme, and an instance per
+// list so each numbered list restarts at 1.
+func (d *docxWriter) numbering() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	for abs, ordered := range []bool{false, true} {
+		b.WriteString(`<w:abstractNum w:abstractNumId="` + strconv.Itoa(abs) + `"><w:multiLevelType w:val="hybridMultilevel"/>`)
+		for lvl := range 9 {
+			l := strconv.Itoa(lvl)
+			indent := strconv.Itoa(720 * (lvl + 1))
+			b.WriteString(`<w:lvl w:ilvl="` + l + `"><w:start w:val="1"/>`)
+			if ordered {
+				b.WriteString(`<w:numFmt w:val="decimal"/><w:lvlText w:val="%` + strconv.Itoa(lvl+1) + `."/>`)
+			} else {
+				b.WriteString(`<w:numFmt w:val="bullet"/><w:lvlText w:val="•"/>`)
+			}
+			b.WriteString(`<w:lvlJc w:val="left"/><w:pPr><w:ind w:left="` + indent + `" w:hanging="360"/></w:pPr></w:lvl>`)
+		}
+		b.WriteString(`</w:abstractNum>`)
+	}
+	for i, ordered := range d.lists {
+		abs := "0"
+		if ordered {
+			abs = "1"
+		}
+		b.WriteString(`<w:num w:numId="` + strconv.Itoa(i+1) + `"><w:abstractNumId w:val="` + abs + `"/>`)
+		if ordered {
+			b.WriteString(`<w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride>`)
+		}
+		b.WriteString(`</w:num>`)
+	}
+	b.WriteString(`</w:numbering>`)
+	return b.String()
+}
+
+func docxCore(title string) string {
+	return xmlHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title>` + xmlText(title) + `</dc:title></cp:coreProperties>`
+}
+
+func writeZip(z *zip.Writer, name string, data []byte, method uint16) error {
+	f, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`
+
+const docxContentTypes = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Default Extension="png" ContentType="image/png"/>` +
+	`<Default Extension="jpeg" ContentType="image/jpeg"/>` +
+	`<Default Extension="gif" ContentType="image/gif"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`</Types>`
+
+const docxRootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+const docxDocumentOpen = xmlHeader + `<w:document` +
+	` xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"` +
+	` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"` +
+	` xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"><w:body>`
+
+const docxDocumentClose = `</w:body></w:document>`
+
+// docxStyles defines the built-in style IDs Word recognises, so headings
+// appear in the navigation pane and lists and tables pick up native styling.
+var docxStyles = func() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	b.WriteString(`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:sz w:val="22"/></w:rPr></w:style>`)
+	sizes := []int{40, 32, 28, 26, 24, 22}
+	for i, sz := range sizes {
+		n := strconv.Itoa(i + 1)
+		b.WriteString(`<w:style w:type="paragraph" w:styleId="Heading` + n + `"><w:name w:val="heading ` + n + `"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+			`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="` + strconv.Itoa(i) + `"/></w:pPr>` +
+			`<w:rPr><w:b/><w:sz w:val="` + strconv.Itoa(sz) + `"/></w:rPr></w:style>`)
+	}
+	b.WriteString(`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0"/></w:pPr></w:style>`)
+	b.WriteString(`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr></w:style>`)
+	b.WriteString(`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		b.WriteString(`<w:` + side + ` w:val="single" w:sz="4" w:space="0" w:color="auto"/>`)
+	}
+	b.WriteString(`</w:tblBorders><w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>`)
+	b.WriteString(`</w:styles>`)
+	return b.String()
+}()
@@ -0,0 +1,303 @@
+package office
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ODT writes OpenDocument text documents (.odt).
+var ODT Writer = odt{}
+
+type odt struct{}
+
+func (odt) ContentType() string {
+	return "application/vnd.oasis.opendocument.text"
+}
+
+func (odt) Extension() string {
+	return ".odt"
+}
+
+// maxImageInches is the widest image that fits a default page.
+const maxImageInches = 6.0
+
+func (o odt) Write(w io.Writer, doc *Document) error {
+	d := &odtWriter{}
+	for _, b := range doc.Blocks {
+		d.block(b)
+	}
+
+	var manifest strings.Builder
+	manifest.WriteString(xmlHeader + `<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.3">`)
+	manifest.WriteString(`<manifest:file-entry manifest:full-path="/" manifest:media-type="` + o.ContentType() + `"/>`)
+	for _, f := range []string{"content.xml", "styles.xml", "meta.xml"} {
+		manifest.WriteString(`<manifest:file-entry manifest:full-path="` + f + `" manifest:media-type="text/xml"/>`)
+	}
+	for _, m := range d.media {
+		manifest.WriteString(`<manifest:file-entry manifest:full-path="` + m.name + `" manifest:media-type="image/` + m.name[strings.LastIndexByte(m.name, '.')+1:] + `"/>`)
+	}
+	manifest.WriteString(`</manifest:manifest>`)
+
+	z := zip.NewWriter(w)
+	// The mimetype entry must come first and be stored uncompressed so
+	// the format can be identified from the first bytes of the file.
+	if err := writeZip(z, "mimetype", []byte(o.ContentType()), zip.Store); err != nil {
+		return err
+	}
+	files := []struct{ name, body string }{
+		{"META-INF/manifest.xml", manifest.String()},
+		{"meta.xml", odtMeta(doc.Title)},
+		{"styles.xml", odtStyles},
+		{"content.xml", odtContentOpen + d.body.String() + odtContentClose},
+	}
+	for _, f := range files {
+		if err := writeZip(z, f.name, []byte(f.body), zip.Deflate); err != nil {
+			return err
+		}
+	}
+	for _, m := range d.media {
+		if err := writeZip(z, m.name, m.data, zip.Store); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+type odtWriter struct {
+	body   strings.Builder
+	media  []media
+	tables int
+}
+
+func (d *odtWriter) block(b Block) {
+	switch b := b.(type) {
+	case Heading:
+		d.body.WriteString(`<text:h text:style-name="Heading_20_` + strconv.Itoa(b.Level) + `" text:outline-level="` + strconv.Itoa(b.Level) + `">`)
+		d.runs(b.Runs, false)
+		d.body.WriteString(`</text:h>`)
+	case Paragraph:
+		d.paragraph(b.Runs, false)
+	case List:
+		d.list(b, b.Items, 0)
+	case Table:
+		d.table(b)
+	case Image:
+		d.image(b)
+	}
+}
+
+func (d *odtWriter) paragraph(runs []Run, bold bool) {
+	d.body.WriteString(`<text:p text:style-name="Standard">`)
+	d.runs(runs, bold)
+	d.body.WriteString(`</text:p>`)
+}
+
+func (d *odtWriter) runs(runs []Run, bold bool) {
+	for _, r := range runs {
+		if r.Text == "\n" {
+			d.body.WriteString(`<text:line-break/>`)
+			continue
+		}
+		if r.Href != "" {
+			d.body.WriteString(`<text:a xlink:type="simple" xlink:href="` + xmlText(r.Href) + `">`)
+		}
+		style := ""
+		switch {
+		case (r.Bold || bold) && r.Italic:
+			style = "BoldItalic"
+		case r.Bold || bold:
+			style = "Bold"
+		case r.Italic:
+			style = "Italic"
+		}
+		if style != "" {
+			d.body.WriteString(`<text:span text:style-name="` + style + `">` + odtText(r.Text) + `</text:span>`)
+		} else {
+			d.body.WriteString(odtText(r.Text))
+		}
+		if r.Href != "" {
+			d.body.WriteString(`</text:a>`)
+		}
+	}
+}
+
+// list writes items at level as a <text:list>, nesting deeper items inside
+// the preceding list item as OpenDocument requires. It returns the number of
+// items consumed.
+func (d *odtWriter) list(l List, items []ListItem, level int) int {
+	style := "Bullets"
+	if l.Ordered {
+		style = "Numbers"
+	}
+	d.body.WriteString(`<text:list text:style-name="` + style + `">`)
+	i := 0
+	for i < len(items) && items[i].Level >= level {
+		d.body.WriteString(`<text:list-item>`)
+		if items[i].Level == level {
+			d.paragraph(items[i].Runs, false)
+			i++
+		}
+		if i < len(items) && items[i].Level > level {
+			i += d.list(l, items[i:], level+1)
+		}
+		d.body.WriteString(`</text:list-item>`)
+	}
+	d.body.WriteString(`</text:list>`)
+	return i
+}
+
+func (d *odtWriter) table(t Table) {
+	d.tables++
+	cols := t.Columns()
+	d.body.WriteString(`<table:table table:name="Table` + strconv.Itoa(d.tables) + `" table:style-name="Grid">`)
+	d.body.WriteString(`<table:table-column table:number-columns-repeated="` + strconv.Itoa(cols) + `"/>`)
+	inHeader := false
+	for _, r := range t.Rows {
+		if r.Header != inHeader {
+			if r.Header {
+				d.body.WriteString(`<table:table-header-rows>`)
+			} else {
+				d.body.WriteString(`</table:table-header-rows>`)
+			}
+			inHeader = r.Header
+		}
+		d.body.WriteString(`<table:table-row>`)
+		for i := range cols {
+			d.body.WriteString(`<table:table-cell table:style-name="Cell" office:value-type="string">`)
+			var runs []Run
+			if i < len(r.Cells) {
+				runs = r.Cells[i]
+			}
+			d.paragraph(runs, r.Header)
+			d.body.WriteString(`</table:table-cell>`)
+		}
+		d.body.WriteString(`</table:table-row>`)
+	}
+	if inHeader {
+		d.body.WriteString(`</table:table-header-rows>`)
+	}
+	d.body.WriteString(`</table:table>`)
+}
+
+func (d *odtWriter) image(img Image) {
+	if img.Data == nil {
+		if img.Alt != "" {
+			d.paragraph([]Run{{Text: img.Alt, Italic: true}}, false)
+		}
+		return
+	}
+	n := len(d.media) + 1
+	name := fmt.Sprintf("Pictures/image%d.%s", n, img.Format)
+	d.media = append(d.media, media{name: name, data: img.Data})
+
+	w, h := float64(img.Width)/96, float64(img.Height)/96
+	if w > maxImageInches {
+		h = h * maxImageInches / w
+		w = maxImageInches
+	}
+	d.body.WriteString(`<text:p text:style-name="Standard"><draw:frame draw:name="image` + strconv.Itoa(n) + `" text:anchor-type="as-char"` +
+		` svg:width="` + strconv.FormatFloat(w, 'f', 3, 64) + `in" svg:height="` + strconv.FormatFloat(h, 'f', 3, 64) + `in">` +
+		`<draw:image xlink:href="` + name + `" xlink:type="simple" xlink:show="embed" xlink:actuate="onLoad"/>`)
+	if img.Alt != "" {
+		d.body.WriteString(`<svg:desc>` + xmlText(img.Alt) + `</svg:desc>`)
+	}
+	d.body.WriteString(`</draw:frame></text:p>`)
+}
+
+// odtText escapes s and preserves runs of spaces, which OpenDocument
+// otherwise collapses.
+func odtText(s string) string {
+	s = xmlText(s)
+	if !strings.Contains(s, "  ") {
+		return s
+	}
+	var b strings.Builder
+	spaces := 0
+	flush := func() {
+		if spaces == 1 {
+			b.WriteByte(' ')
+		} else if spaces > 1 {
+			b.WriteString(` <text:s text:c="` + strconv.Itoa(spaces-1) + `"/>`)
+		}
+		spaces = 0
+	}
+	for _, r := range s {
+		if r == ' ' {
+			spaces++
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
+
+func odtMeta(title string) string {
+	return xmlHeader + `<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/" office:version="1.3">` +
+		`<office:meta><dc:title>` + xmlText(title) + `</dc:title></office:meta></office:document-meta>`
+}
+
+const odtNamespaces = ` xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+	` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"` +
+	` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"` +
+	` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"` +
+	` xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"` +
+	` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"` +
+	` xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"` +
+	` xmlns:xlink="http://www.w3.org/1999/xlink"` +
+	` office:version="1.3"`
+
+var odtContentOpen = xmlHeader + `<office:document-content` + odtNamespaces + `>` +
+	`<office:automatic-styles>` +
+	`<style:style style:name="Bold" style:family="text"><style:text-properties fo:font-weight="bold"/></style:style>` +
+	`<style:style style:name="Italic" style:family="text"><style:text-properties fo:font-style="italic"/></style:style>` +
+	`<style:style style:name="BoldItalic" style:family="text"><style:text-properties fo:font-weight="bold" fo:font-style="italic"/></style:style>` +
+	`<style:style style:name="Grid" style:family="table"><style:table-properties table:border-model="collapsing"/></style:style>` +
+	`<style:style style:name="Cell" style:family="table-cell"><style:table-cell-properties fo:border="0.5pt solid #000000" fo:padding="0.05in"/></style:style>` +
+	`<text:list-style style:name="Bullets">` + odtListLevels(false) + `</text:list-style>` +
+	`<text:list-style style:name="Numbers">` + odtListLevels(true) + `</text:list-style>` +
+	`</office:automatic-styles><office:body><office:text>`
+
+const odtContentClose = `</office:text></office:body></office:document-content>`
+
+func odtListLevels(ordered bool) string {
+	var b strings.Builder
+	for lvl := 1; lvl <= 10; lvl++ {
+		indent := strconv.FormatFloat(0.25*float64(lvl), 'f', 2, 64) + "in"
+		if ordered {
+			b.WriteString(`<text:list-level-style-number text:level="` + strconv.Itoa(lvl) + `" style:num-format="1" style:num-suffix=".">`)
+		} else {
+			b.WriteString(`<text:list-level-style-bullet text:level="` + strconv.Itoa(lvl) + `" text:bullet-char="•">`)
+		}
+		b.WriteString(`<style:list-level-properties text:list-level-position-and-space-mode="label-alignment">` +
+			`<style:list-level-label-alignment text:label-followed-by="listtab" fo:text-indent="-0.25in" fo:margin-left="` + indent + `"/>` +
+			`</style:list-level-properties>`)
+		if ordered {
+			b.WriteString(`</text:list-level-style-number>`)
+		} else {
+			b.WriteString(`</text:list-level-style-bullet>`)
+		}
+	}
+	return b.String()
+}
+
+// odtStyles defines the paragraph styles referenced by content.xml. Heading
+// styles use the names LibreOffice and Word map to their built-in headings.
+var odtStyles = func() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<office:document-styles` + odtNamespaces + `><office:styles>`)
+	b.WriteString(`<style:style style:name="Standard" style:family="paragraph"><style:paragraph-properties fo:margin-bottom="0.08in"/><style:text-properties fo:font-size="11pt"/></style:style>`)
+	sizes := []string{"20pt", "16pt", "14pt", "13pt", "12pt", "11pt"}
+	for i, sz := range sizes {
+		n := strconv.Itoa(i + 1)
+		b.WriteString(`<style:style style:name="Heading_20_` + n + `" style:display-name="Heading ` + n + `" style:family="paragraph" style:parent-style-name="Standard" style:next-style-name="Standard" style:default-outline-level="` + n + `">` +
+			`<style:paragraph-properties fo:margin-top="0.17in" fo:keep-with-next="always"/>` +
+			`<style:text-properties fo:font-size="` + sz + `" fo:font-weight="bold"/></style:style>`)
+	}
+	b.WriteString(`</office:styles></office:document-styles>`)
+	return b.String()
+}()
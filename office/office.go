@@ -0,0 +1,472 @@
+// Package office exports rendered fluent content to editable office documents.
+//
+// Outline converts a node tree into a Document holding a restricted set of
+// blocks: headings, paragraphs, lists, tables and images. Containers such as
+// <div> and <section> are flattened, and anything outside the subset (forms,
+// scripts, media) is dropped. A Writer then encodes the Document; DOCX and
+// ODT are provided, and other formats can be added by implementing Writer.
+//
+// Usage:
+//
+//	func download(w http.ResponseWriter, r *http.Request) {
+//	    office.Serve(w, "quarterly-report", report(r), office.DOCX, nil)
+//	}
+package office
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	// Register decoders so image sizes can be read for embedding.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Document is the outline of a node tree.
+type Document struct {
+	Title  string
+	Blocks []Block
+}
+
+// Block is one of Heading, Paragraph, List, Table or Image.
+type Block interface {
+	block()
+}
+
+// Run is a span of text with uniform formatting. A Text of "\n" is a line break.
+type Run struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	// Href is set for hyperlinks.
+	Href string
+}
+
+// Heading is a section heading of Level 1 to 6.
+type Heading struct {
+	Level int
+	Runs  []Run
+}
+
+// Paragraph is a block of text.
+type Paragraph struct {
+	Runs []Run
+}
+
+// ListItem is an entry in a List. Level is 0 for top-level items and
+// increases for each nested list.
+type ListItem struct {
+	Level int
+	Runs  []Run
+}
+
+// List is a bulleted or numbered list.
+type List struct {
+	Ordered bool
+	Items   []ListItem
+}
+
+// Row is a table row.
+type Row struct {
+	Header bool
+	Cells  [][]Run
+}
+
+// Table is a grid of text cells.
+type Table struct {
+	Rows []Row
+}
+
+// Columns returns the number of cells in the widest row.
+func (t Table) Columns() int {
+	n := 0
+	for _, r := range t.Rows {
+		n = max(n, len(r.Cells))
+	}
+	return n
+}
+
+// Image is an embedded picture. Data is nil when the image could not be
+// loaded; writers then render Alt as text instead.
+type Image struct {
+	Src    string
+	Alt    string
+	Data   []byte
+	Format string // "png", "jpeg" or "gif"
+	Width  int    // pixels
+	Height int    // pixels
+}
+
+func (Heading) block()   {}
+func (Paragraph) block() {}
+func (List) block()      {}
+func (Table) block()     {}
+func (Image) block()     {}
+
+// ImageLoader returns the data of the image at src. It may be nil, in which
+// case images are exported as their alternative text.
+type ImageLoader func(src string) ([]byte, error)
+
+// Writer encodes a Document in an office format.
+type Writer interface {
+	// ContentType is the media type of the output.
+	ContentType() string
+	// Extension is the file extension, including the leading dot.
+	Extension() string
+	Write(w io.Writer, doc *Document) error
+}
+
+// Export outlines n and writes it to w in the given format.
+func Export(w io.Writer, n node.Node, format Writer, images ImageLoader) error {
+	return format.Write(w, Outline(n, images))
+}
+
+// Serve exports n as a download named filename plus the format's extension.
+func Serve(w http.ResponseWriter, filename string, n node.Node, format Writer, images ImageLoader) {
+	var buf bytes.Buffer
+	if err := Export(&buf, n, format, images); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(filename, `"`, "")+format.Extension()+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	_, _ = buf.WriteTo(w)
+}
+
+// Outline renders n and converts it into a Document.
+func Outline(n node.Node, images ImageLoader) *Document {
+	root := markup.Parse(string(n.Render()))
+	o := &outliner{images: images}
+	root.Walk(func(n *markup.Node) bool {
+		if n.Type == markup.ElementNode && n.Tag == "title" && o.doc.Title == "" {
+			o.doc.Title = strings.TrimSpace(n.Text())
+		}
+		return o.doc.Title == ""
+	})
+	o.container(root)
+	o.flush()
+	if o.doc.Title == "" {
+		for _, b := range o.doc.Blocks {
+			if h, ok := b.(Heading); ok && h.Level == 1 {
+				o.doc.Title = plain(h.Runs)
+				break
+			}
+		}
+	}
+	return &o.doc
+}
+
+// inline elements contribute runs to the surrounding paragraph.
+var inline = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "br": true,
+	"cite": true, "code": true, "data": true, "dfn": true, "em": true, "i": true,
+	"kbd": true, "label": true, "mark": true, "q": true, "s": true, "samp": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true,
+	"time": true, "u": true, "var": true,
+}
+
+// skipped elements and their content are not exported.
+var skipped = map[string]bool{
+	"script": true, "style": true, "template": true, "noscript": true,
+	"head": true, "form": true, "button": true, "select": true, "textarea": true,
+	"input": true, "iframe": true, "video": true, "audio": true, "canvas": true,
+	"svg": true, "object": true, "embed": true, "dialog": true,
+}
+
+type outliner struct {
+	doc     Document
+	images  ImageLoader
+	pending []Run
+}
+
+// flush ends the paragraph formed by loose inline content.
+func (o *outliner) flush() {
+	if runs := trim(o.pending); len(runs) > 0 {
+		o.doc.Blocks = append(o.doc.Blocks, Paragraph{Runs: runs})
+	}
+	o.pending = nil
+}
+
+func (o *outliner) container(n *markup.Node) {
+	for _, c := range n.Children {
+		switch {
+		case c.Type == markup.TextNode:
+			o.pending = append(o.pending, Run{Text: collapse(c.Data)})
+		case c.Type != markup.ElementNode:
+		case inline[c.Tag]:
+			o.pending = append(o.pending, runs(c, Run{}, false)...)
+		default:
+			o.block(c)
+		}
+	}
+}
+
+func (o *outliner) block(n *markup.Node) {
+	if skipped[n.Tag] || n.Tag == "title" {
+		return
+	}
+	o.flush()
+	switch n.Tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		o.add(Heading{Level: int(n.Tag[1] - '0'), Runs: trim(runs(n, Run{}, false))})
+	case "p", "blockquote", "figcaption", "dt", "dd", "address", "caption":
+		o.add(Paragraph{Runs: trim(runs(n, Run{}, false))})
+	case "pre":
+		o.add(Paragraph{Runs: runs(n, Run{}, true)})
+	case "ul", "ol":
+		l := List{Ordered: n.Tag == "ol"}
+		items(n, 0, &l)
+		if len(l.Items) > 0 {
+			o.doc.Blocks = append(o.doc.Blocks, l)
+		}
+	case "table":
+		var t Table
+		rows(n, &t)
+		if len(t.Rows) > 0 {
+			o.doc.Blocks = append(o.doc.Blocks, t)
+		}
+	case "img":
+		o.doc.Blocks = append(o.doc.Blocks, o.image(n))
+	default:
+		o.container(n)
+		o.flush()
+	}
+}
+
+// add appends a heading or paragraph unless it has no text.
+func (o *outliner) add(b Block) {
+	switch b := b.(type) {
+	case Heading:
+		if len(b.Runs) == 0 {
+			return
+		}
+	case Paragraph:
+		if len(b.Runs) == 0 {
+			return
+		}
+	}
+	o.doc.Blocks = append(o.doc.Blocks, b)
+}
+
+func (o *outliner) image(n *markup.Node) Image {
+	img := Image{}
+	img.Src, _ = n.Attr("src")
+	img.Alt, _ = n.Attr("alt")
+	if o.images == nil || img.Src == "" {
+		return img
+	}
+	data, err := o.images(img.Src)
+	if err != nil {
+		return img
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return img
+	}
+	img.Data, img.Format, img.Width, img.Height = data, format, cfg.Width, cfg.Height
+	return img
+}
+
+// items appends the entries of a list, descending into nested lists.
+func items(n *markup.Node, level int, l *List) {
+	for _, li := range n.Children {
+		if li.Type != markup.ElementNode || li.Tag != "li" {
+			continue
+		}
+		var text []Run
+		var nested []*markup.Node
+		for _, c := range li.Children {
+			if c.Type == markup.ElementNode && (c.Tag == "ul" || c.Tag == "ol") {
+				nested = append(nested, c)
+				continue
+			}
+			text = append(text, runs(c, Run{}, false)...)
+		}
+		l.Items = append(l.Items, ListItem{Level: level, Runs: trim(text)})
+		for _, c := range nested {
+			items(c, level+1, l)
+		}
+	}
+}
+
+// rows appends the rows of a table, descending into row groups.
+func rows(n *markup.Node, t *Table) {
+	for _, c := range n.Children {
+		if c.Type != markup.ElementNode {
+			continue
+		}
+		switch c.Tag {
+		case "thead", "tbody", "tfoot":
+			rows(c, t)
+		case "tr":
+			r := Row{Header: n.Tag == "thead"}
+			header := true
+			for _, cell := range c.Children {
+				if cell.Type != markup.ElementNode || (cell.Tag != "td" && cell.Tag != "th") {
+					continue
+				}
+				header = header && cell.Tag == "th"
+				r.Cells = append(r.Cells, trim(runs(cell, Run{}, false)))
+			}
+			r.Header = r.Header || (header && len(r.Cells) > 0)
+			t.Rows = append(t.Rows, r)
+		}
+	}
+}
+
+// runs flattens the text under n, applying inline formatting on top of style.
+func runs(n *markup.Node, style Run, pre bool) []Run {
+	if n.Type == markup.TextNode {
+		text := n.Data
+		if !pre {
+			text = collapse(text)
+		}
+		style.Text = text
+		if pre {
+			return splitLines(style)
+		}
+		return []Run{style}
+	}
+	if n.Type != markup.ElementNode || skipped[n.Tag] {
+		return nil
+	}
+	switch n.Tag {
+	case "br":
+		return []Run{{Text: "\n"}}
+	case "b", "strong", "th":
+		style.Bold = true
+	case "i", "em", "cite", "dfn", "var":
+		style.Italic = true
+	case "a":
+		style.Href, _ = n.Attr("href")
+	case "img":
+		if alt, _ := n.Attr("alt"); alt != "" {
+			style.Text = alt
+			return []Run{style}
+		}
+		return nil
+	}
+	var out []Run
+	for _, c := range n.Children {
+		out = append(out, runs(c, style, pre || n.Tag == "pre")...)
+	}
+	return out
+}
+
+// splitLines turns newlines in preformatted text into line-break runs.
+func splitLines(r Run) []Run {
+	var out []Run
+	for i, line := range strings.Split(r.Text, "\n") {
+		if i > 0 {
+			out = append(out, Run{Text: "\n"})
+		}
+		if line != "" {
+			r.Text = line
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// collapse replaces each run of whitespace with a single space.
+func collapse(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// trim merges adjacent runs with the same formatting, removes leading and
+// trailing whitespace, and drops empty runs.
+func trim(in []Run) []Run {
+	var out []Run
+	for _, r := range in {
+		if r.Text == "" {
+			continue
+		}
+		if len(out) > 0 && r.Text != "\n" {
+			last := &out[len(out)-1]
+			if last.Text != "\n" && last.Bold == r.Bold && last.Italic == r.Italic && last.Href == r.Href {
+				last.Text += r.Text
+				if strings.HasSuffix(last.Text, "  ") {
+					last.Text = strings.TrimRight(last.Text, " ") + " "
+				}
+				continue
+			}
+			if strings.HasSuffix(last.Text, " ") && strings.HasPrefix(r.Text, " ") {
+				r.Text = r.Text[1:]
+			}
+		}
+		out = append(out, r)
+	}
+	if len(out) > 0 {
+		out[0].Text = strings.TrimLeft(out[0].Text, " ")
+		out[len(out)-1].Text = strings.TrimRight(out[len(out)-1].Text, " ")
+	}
+	filtered := out[:0]
+	for _, r := range out {
+		if r.Text != "" {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// plain returns the text of runs without formatting.
+func plain(runs []Run) string {
+	var b strings.Builder
+	for _, r := range runs {
+		if r.Text == "\n" {
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// xmlText escapes s for use in XML text and attribute values.
+func xmlText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			// Drop characters XML 1.0 does not allow.
+			if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,217 @@
+package office
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/em"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/h2"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/ol"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/html5/strong"
+	"github.com/jpl-au/fluent/html5/table"
+	"github.com/jpl-au/fluent/html5/td"
+	"github.com/jpl-au/fluent/html5/th"
+	"github.com/jpl-au/fluent/html5/thead"
+	"github.com/jpl-au/fluent/html5/tr"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func report() node.Node {
+	return div.New(
+		h1.Text("Q3 Report"),
+		p.New(text.Text("Revenue was "), strong.Text("up"), text.Text(" on "), em.Text("all"), text.Text(" lines. "), a.Link("https://acme.example", "Details")),
+		script.RawText("ignored()"),
+		h2.Text("Highlights"),
+		ul.New(
+			li.Text("Sales"),
+			li.New(text.Text("Support"), ol.New(li.Text("Tickets"), li.Text("Chat"))),
+		),
+		table.New(
+			thead.New(tr.New(th.Text("Region"), th.Text("Total"))),
+			tr.New(td.Text("APAC"), td.Text("1 & 2")),
+			tr.New(td.Text("EMEA")),
+		),
+		img.New().Src("/chart.png").Alt("Chart"),
+		text.Text("loose text"),
+	)
+}
+
+func TestOutline(t *testing.T) {
+	doc := Outline(report(), nil)
+	want := &Document{
+		Title: "Q3 Report",
+		Blocks: []Block{
+			Heading{Level: 1, Runs: []Run{{Text: "Q3 Report"}}},
+			Paragraph{Runs: []Run{
+				{Text: "Revenue was "},
+				{Text: "up", Bold: true},
+				{Text: " on "},
+				{Text: "all", Italic: true},
+				{Text: " lines. "},
+				{Text: "Details", Href: "https://acme.example"},
+			}},
+			Heading{Level: 2, Runs: []Run{{Text: "Highlights"}}},
+			List{Items: []ListItem{
+				{Level: 0, Runs: []Run{{Text: "Sales"}}},
+				{Level: 0, Runs: []Run{{Text: "Support"}}},
+				{Level: 1, Runs: []Run{{Text: "Tickets"}}},
+				{Level: 1, Runs: []Run{{Text: "Chat"}}},
+			}},
+			Table{Rows: []Row{
+				{Header: true, Cells: [][]Run{{{Text: "Region", Bold: true}}, {{Text: "Total", Bold: true}}}},
+				{Cells: [][]Run{{{Text: "APAC"}}, {{Text: "1 & 2"}}}},
+				{Cells: [][]Run{{{Text: "EMEA"}}}},
+			}},
+			Image{Src: "/chart.png", Alt: "Chart"},
+			Paragraph{Runs: []Run{{Text: "loose text"}}},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("got\n%#v\nwant\n%#v", doc, want)
+	}
+}
+
+func loader(t *testing.T) ImageLoader {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1200, 300))); err != nil {
+		t.Fatal(err)
+	}
+	return func(src string) ([]byte, error) {
+		if src == "/chart.png" {
+			return buf.Bytes(), nil
+		}
+		return nil, errors.New("not found")
+	}
+}
+
+// unzip returns the entries of an archive in order.
+func unzip(t *testing.T, data []byte) ([]string, map[string]string) {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		names = append(names, f.Name)
+		files[f.Name] = string(b)
+		if strings.HasSuffix(f.Name, ".xml") || strings.HasSuffix(f.Name, ".rels") {
+			if err := wellFormed(b); err != nil {
+				t.Errorf("%s: %v", f.Name, err)
+			}
+		}
+	}
+	return names, files
+}
+
+func wellFormed(b []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func TestDOCX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, report(), DOCX, loader(t)); err != nil {
+		t.Fatal(err)
+	}
+	_, files := unzip(t, buf.Bytes())
+
+	doc := files["word/document.xml"]
+	for _, want := range []string{
+		`<w:pStyle w:val="Heading1"/>`,
+		`<w:t xml:space="preserve">1 &amp; 2</w:t>`,
+		`<w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr>`,
+		`<w:hyperlink r:id="rId1">`,
+		`<wp:extent cx="5486400" cy="1371600"/>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+	if _, ok := files["word/media/image1.png"]; !ok {
+		t.Error("image not embedded")
+	}
+	if !strings.Contains(files["docProps/core.xml"], "<dc:title>Q3 Report</dc:title>") {
+		t.Error("title not set")
+	}
+}
+
+func TestODT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, report(), ODT, loader(t)); err != nil {
+		t.Fatal(err)
+	}
+	names, files := unzip(t, buf.Bytes())
+	if names[0] != "mimetype" || files["mimetype"] != ODT.ContentType() {
+		t.Errorf("mimetype must be the first entry, got %v", names)
+	}
+
+	content := files["content.xml"]
+	for _, want := range []string{
+		`<text:h text:style-name="Heading_20_2" text:outline-level="2">Highlights</text:h>`,
+		`<text:list-item><text:p text:style-name="Standard">Support</text:p><text:list text:style-name="Bullets"><text:list-item>`,
+		`<table:table-header-rows><table:table-row>`,
+		`<text:a xlink:type="simple" xlink:href="https://acme.example">Details</text:a>`,
+		`svg:width="6.000in" svg:height="1.500in"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content.xml missing %s", want)
+		}
+	}
+	if !strings.Contains(files["META-INF/manifest.xml"], "Pictures/image1.png") {
+		t.Error("image missing from manifest")
+	}
+}
+
+func TestImageFallback(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DOCX.Write(&buf, &Document{Blocks: []Block{Image{Src: "/missing.png", Alt: "Missing"}}}); err != nil {
+		t.Fatal(err)
+	}
+	_, files := unzip(t, buf.Bytes())
+	if !strings.Contains(files["word/document.xml"], "Missing") {
+		t.Error("alt text not written")
+	}
+}
+
+func TestServe(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Serve(rec, `q3"report`, report(), ODT, nil)
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="q3report.odt"` {
+		t.Errorf("Content-Disposition: got %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ODT.ContentType() {
+		t.Errorf("Content-Type: got %q", got)
+	}
+}
@@ -0,0 +1,247 @@
+// Package offline stores rendered fragments by content hash so offline-capable
+// clients, such as a service worker, fetch only what changed after a deploy.
+//
+// Each page is published as a list of named fragments. Fragment bodies are
+// stored under the hash of their content and a manifest records which hashes
+// make up each page. Clients poll the manifest, compare hashes with what they
+// hold, and download only the fragments they are missing. Fragment URLs never
+// change content, so they are served as immutable.
+//
+// Usage:
+//
+//	store, err := offline.Open(offline.Dir("var/fragments"))
+//	...
+//	_, err = store.Publish("/docs/install",
+//	    offline.Fragment{Name: "nav", Node: layout.Nav()},
+//	    offline.Fragment{Name: "main", Node: docs.Install()},
+//	)
+//	...
+//	mux.Handle("/offline/", http.StripPrefix("/offline", store.Handler()))
+package offline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Fragment is a named part of a page.
+type Fragment struct {
+	Name string
+	Node node.Node
+}
+
+// Entry is a fragment in a manifest.
+type Entry struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+// Page lists the fragments of a page in order.
+type Page struct {
+	Fragments []Entry `json:"fragments"`
+}
+
+// Manifest describes every published page. Version is a hash of the
+// manifest's contents, so it changes whenever any fragment does.
+type Manifest struct {
+	Version string           `json:"version"`
+	Pages   map[string]*Page `json:"pages"`
+}
+
+// indexKey is the storage key of the persisted manifest.
+const indexKey = "manifest.json"
+
+// Store publishes pages and serves their fragments. It is safe for
+// concurrent use.
+type Store struct {
+	storage Storage
+
+	mu       sync.RWMutex
+	manifest *Manifest
+	encoded  []byte
+}
+
+// Open returns a Store backed by storage, loading any manifest it holds.
+func Open(storage Storage) (*Store, error) {
+	s := &Store{storage: storage, manifest: &Manifest{Pages: map[string]*Page{}}}
+	b, err := storage.Get(indexKey)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(b, s.manifest); err != nil {
+			return nil, err
+		}
+		if s.manifest.Pages == nil {
+			s.manifest.Pages = map[string]*Page{}
+		}
+	}
+	s.encoded, err = s.encode()
+	return s, err
+}
+
+// Hash returns the content hash used as a fragment's key.
+func Hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// Publish renders the fragments of page, stores any bodies not already held
+// and records the page in the manifest, replacing its previous entry.
+func (s *Store) Publish(page string, fragments ...Fragment) (*Page, error) {
+	p := &Page{Fragments: make([]Entry, 0, len(fragments))}
+	for _, f := range fragments {
+		var body []byte
+		if f.Node != nil {
+			body = f.Node.Render()
+		}
+		hash := Hash(body)
+		if _, err := s.storage.Get(fragmentKey(hash)); errors.Is(err, ErrNotFound) {
+			if err := s.storage.Put(fragmentKey(hash), body); err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		p.Fragments = append(p.Fragments, Entry{Name: f.Name, Hash: hash})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, had := s.manifest.Pages[page]
+	s.manifest.Pages[page] = p
+	encoded, err := s.encode()
+	if err == nil {
+		err = s.storage.Put(indexKey, encoded)
+	}
+	if err != nil {
+		if had {
+			s.manifest.Pages[page] = prev
+		} else {
+			delete(s.manifest.Pages, page)
+		}
+		return nil, err
+	}
+	s.encoded = encoded
+	return p, nil
+}
+
+// Remove drops page from the manifest. Its fragment bodies are kept, as other
+// pages or clients may still reference them.
+func (s *Store) Remove(page string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.manifest.Pages[page]
+	if !ok {
+		return nil
+	}
+	delete(s.manifest.Pages, page)
+	encoded, err := s.encode()
+	if err == nil {
+		err = s.storage.Put(indexKey, encoded)
+	}
+	if err != nil {
+		s.manifest.Pages[page] = prev
+		return err
+	}
+	s.encoded = encoded
+	return nil
+}
+
+// Manifest returns a copy of the current manifest.
+func (s *Store) Manifest() *Manifest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m := &Manifest{Version: s.manifest.Version, Pages: make(map[string]*Page, len(s.manifest.Pages))}
+	for k, p := range s.manifest.Pages {
+		m.Pages[k] = &Page{Fragments: slices.Clone(p.Fragments)}
+	}
+	return m
+}
+
+// Fragment returns the body stored under hash.
+func (s *Store) Fragment(hash string) ([]byte, error) {
+	if !validHash(hash) {
+		return nil, ErrNotFound
+	}
+	return s.storage.Get(fragmentKey(hash))
+}
+
+// encode updates the manifest version and returns the manifest as JSON. The
+// caller must hold s.mu for writing.
+func (s *Store) encode() ([]byte, error) {
+	s.manifest.Version = ""
+	b, err := json.Marshal(s.manifest)
+	if err != nil {
+		return nil, err
+	}
+	s.manifest.Version = Hash(b)
+	return json.Marshal(s.manifest)
+}
+
+// Handler serves the manifest at /manifest.json and fragment bodies at
+// /fragments/{hash}. Mount it with http.StripPrefix.
+//
+// The manifest is served with its version as ETag, so polling clients get
+// 304 Not Modified until something is published. Fragments are served as
+// immutable.
+func (s *Store) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		body, version := s.encoded, s.manifest.Version
+		s.mu.RUnlock()
+		etag := `"` + version + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("GET /fragments/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		body, err := s.Fragment(r.PathValue("hash"))
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", `"`+r.PathValue("hash")+`"`)
+		_, _ = w.Write(body)
+	})
+	return mux
+}
+
+func fragmentKey(hash string) string {
+	return "fragments/" + hash
+}
+
+// validHash reports whether s could be a hash returned by Hash, so request
+// paths never reach the storage unchecked.
+func validHash(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !('0' <= s[i] && s[i] <= '9' || 'a' <= s[i] && s[i] <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package offline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/nav"
+	"github.com/jpl-au/fluent/html5/p"
+)
+
+func TestPublish(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := s.Publish("/docs", Fragment{Name: "nav", Node: nav.Text("Home")}, Fragment{Name: "main", Node: p.Text("v1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	navHash := page.Fragments[0].Hash
+	if want := Hash([]byte("<nav>Home</nav>")); navHash != want {
+		t.Errorf("nav hash: got %s, want %s", navHash, want)
+	}
+	v1 := s.Manifest().Version
+
+	// Republishing with a changed fragment changes only that hash.
+	page, err = s.Publish("/docs", Fragment{Name: "nav", Node: nav.Text("Home")}, Fragment{Name: "main", Node: p.Text("v2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Fragments[0].Hash != navHash || page.Fragments[1].Hash != Hash([]byte("<p>v2</p>")) {
+		t.Errorf("got %+v", page.Fragments)
+	}
+	if s.Manifest().Version == v1 {
+		t.Error("version did not change")
+	}
+
+	// A new store over the same directory sees the persisted manifest.
+	reopened, err := Open(Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := reopened.Manifest()
+	if m.Version != s.Manifest().Version || len(m.Pages["/docs"].Fragments) != 2 {
+		t.Errorf("reopened manifest: got %+v", m)
+	}
+	if b, err := reopened.Fragment(navHash); err != nil || string(b) != "<nav>Home</nav>" {
+		t.Errorf("fragment: got %q, %v", b, err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s, _ := Open(Memory())
+	page, _ := s.Publish("/", Fragment{Name: "main", Node: p.Text("hi")})
+	h := s.Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/manifest.json", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag != `"`+s.Manifest().Version+`"` {
+		t.Fatalf("manifest: %d %q", rec.Code, etag)
+	}
+
+	req := httptest.NewRequest("GET", "/manifest.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional manifest: got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/fragments/"+page.Fragments[0].Hash, nil))
+	if rec.Body.String() != "<p>hi</p>" || rec.Header().Get("Cache-Control") != "public, max-age=31536000, immutable" {
+		t.Errorf("fragment: %q %q", rec.Body.String(), rec.Header().Get("Cache-Control"))
+	}
+
+	for _, path := range []string{"/fragments/0123456789abcdef0123456789abcdef", "/fragments/..%2fmanifest.json"} {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package offline
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// ErrNotFound is returned by a Storage for a key it does not hold.
+var ErrNotFound = errors.New("offline: not found")
+
+// Storage persists blobs by key. Keys are slash-separated relative paths.
+type Storage interface {
+	Get(key string) ([]byte, error)
+	Put(key string, b []byte) error
+}
+
+// Memory returns a Storage held in memory, for tests and single-process
+// deployments that republish on start.
+func Memory() Storage {
+	return &memory{blobs: map[string][]byte{}}
+}
+
+type memory struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func (m *memory) Get(key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return b, nil
+}
+
+func (m *memory) Put(key string, b []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = slices.Clone(b)
+	return nil
+}
+
+// Dir returns a Storage that keeps each blob in a file under dir.
+func Dir(dir string) Storage {
+	return dirStorage(dir)
+}
+
+type dirStorage string
+
+func (d dirStorage) Get(key string) ([]byte, error) {
+	b, err := os.ReadFile(filepath.Join(string(d), filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return b, err
+}
+
+// Put writes through a temporary file and renames it into place, so readers
+// never see a partial blob.
+func (d dirStorage) Put(key string, b []byte) error {
+	path := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,131 @@
+package fluent
+
+import (
+	"context"
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// RenderOption adjusts a single call to Render.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	ctx    context.Context
+	policy DeadlinePolicy
+	chunk  int
+	config []func(*Config)
+}
+
+// Render renders n to w through RenderContext, with opts applied to this
+// call only. Options that change the output adjust the Config of the
+// render's context, so one binary can write pretty output for development
+// and minified output in production side by side.
+//
+// Usage:
+//
+//	err := fluent.Render(page, w, fluent.WithContext(r.Context()), fluent.WithMinify())
+func Render(n node.Node, w io.Writer, opts ...RenderOption) error {
+	o := renderOptions{ctx: context.Background(), policy: Abort}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx := o.ctx
+	if len(o.config) > 0 {
+		c := ConfigFrom(ctx)
+		for _, fn := range o.config {
+			fn(&c)
+		}
+		ctx = WithConfig(ctx, c)
+	}
+	if o.chunk > 0 {
+		w = &chunkWriter{w: w, size: o.chunk}
+	}
+	return RenderContext(ctx, n, w, o.policy)
+}
+
+// WithContext renders with ctx as the render context. The default is
+// context.Background().
+func WithContext(ctx context.Context) RenderOption {
+	return func(o *renderOptions) {
+		if ctx != nil {
+			o.ctx = ctx
+		}
+	}
+}
+
+// WithDeadlinePolicy selects what happens when the context is done before
+// the render completes. The default is Abort.
+func WithDeadlinePolicy(p DeadlinePolicy) RenderOption {
+	return func(o *renderOptions) {
+		o.policy = p
+	}
+}
+
+// WithWriterChunkSize splits the output into writes of at most n bytes, for
+// writers that limit the size of a single write such as message-based
+// connections. A value of 0 or less writes the output at once.
+func WithWriterChunkSize(n int) RenderOption {
+	return func(o *renderOptions) {
+		o.chunk = n
+	}
+}
+
+// WithMinify minifies the output (see Config.Minify).
+func WithMinify() RenderOption {
+	return withConfig(func(c *Config) { c.Minify = true })
+}
+
+// WithPretty indents the output (see Config.Pretty).
+func WithPretty() RenderOption {
+	return withConfig(func(c *Config) { c.Pretty = true })
+}
+
+// WithXHTML writes the output as XML (see Config.XHTML).
+func WithXHTML() RenderOption {
+	return withConfig(func(c *Config) { c.XHTML = true })
+}
+
+// WithEscape selects the escape policy (see Config.Escape).
+func WithEscape(p EscapePolicy) RenderOption {
+	return withConfig(func(c *Config) { c.Escape = p })
+}
+
+// WithDebugComments marks the output of named components (see
+// Config.DebugComments).
+func WithDebugComments() RenderOption {
+	return withConfig(func(c *Config) { c.DebugComments = true })
+}
+
+// WithDeterministicAttributes sorts attributes by name (see
+// Config.DeterministicAttributes).
+func WithDeterministicAttributes() RenderOption {
+	return withConfig(func(c *Config) { c.DeterministicAttributes = true })
+}
+
+// withConfig returns an option applying fn to the render's Config.
+func withConfig(fn func(*Config)) RenderOption {
+	return func(o *renderOptions) {
+		o.config = append(o.config, fn)
+	}
+}
+
+// chunkWriter splits each write into writes of at most size bytes.
+type chunkWriter struct {
+	w    io.Writer
+	size int
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), c.size)]
+		n, err := c.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
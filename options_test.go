@@ -0,0 +1,80 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+)
+
+// writes records the size of each write.
+type writes struct {
+	bytes.Buffer
+	sizes []int
+}
+
+func (w *writes) Write(b []byte) (int, error) {
+	w.sizes = append(w.sizes, len(b))
+	return w.Buffer.Write(b)
+}
+
+func TestRender(t *testing.T) {
+	page := func() node.Node {
+		return div.New(p.Text("a"), node.Component("Card", p.Text("b")))
+	}
+	tests := []struct {
+		name string
+		opts []fluent.RenderOption
+		want string
+	}{
+		{"none", nil, "<div><p>a</p><p>b</p></div>"},
+		{"pretty", []fluent.RenderOption{fluent.WithPretty()}, "<div>\n  <p>a</p>\n  <p>b</p>\n</div>"},
+		{"debug comments", []fluent.RenderOption{fluent.WithDebugComments()},
+			"<div><p>a</p><!-- Card --><p>b</p><!-- /Card --></div>"},
+		{"options add to the context config", []fluent.RenderOption{
+			fluent.WithContext(fluent.WithConfig(context.Background(), fluent.Config{DebugComments: true})),
+			fluent.WithMinify(),
+		}, "<div><p>a</p><p>b</p></div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := fluent.Render(page(), &buf, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChunkSize(t *testing.T) {
+	var w writes
+	if err := fluent.Render(div.New(p.Text("hello")), &w, fluent.WithWriterChunkSize(8)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<div><p>hello</p></div>"; w.String() != want {
+		t.Errorf("got %q, want %q", w.String(), want)
+	}
+	for _, n := range w.sizes {
+		if n > 8 {
+			t.Errorf("got a write of %d bytes, want at most 8", n)
+		}
+	}
+}
+
+func TestRenderContextOption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	err := fluent.Render(div.New(), &buf, fluent.WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,256 @@
+// Package partial defines reusable page fragments - a site footer, the
+// navigation - once by name and includes them wherever they are needed, with
+// optional memoisation of their rendered output.
+//
+// A partial's constructor runs when an include renders, so partials may be
+// defined after the pages that include them, such as from a plugin's init.
+// A cached partial is rendered once per distinct set of arguments and served
+// from the cache until its TTL passes or it is invalidated, independently of
+// the pages that include it.
+//
+// Usage:
+//
+//	partial.Define("footer", func(args ...any) node.Node {
+//	    return footer.New(p.Textf("© %d Example Ltd", args[0]))
+//	}, partial.Cache(time.Hour))
+//
+//	body.New(
+//	    main.New(content),
+//	    partial.Include("footer", time.Now().Year()),
+//	)
+//
+//	partial.Invalidate("footer")
+package partial
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent/cache"
+	"github.com/jpl-au/fluent/component"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Constructor builds a partial from the arguments passed to Include.
+type Constructor func(args ...any) node.Node
+
+// Option configures a partial.
+type Option func(*partial)
+
+// Cache memoises the partial's output for ttl, keyed by its arguments. A ttl
+// of zero or less keeps it until invalidated.
+//
+// Arguments must be booleans, numbers or strings, of any named type, or
+// implement component.Hashable. An include with any other argument, such as
+// a pointer whose address says nothing about the data behind it, is
+// rendered without the cache and reported as an ArgumentError to the
+// render's assertion handler (see node.WithAssertions).
+//
+// Cached partials are rendered outside the including page's render, so
+// their output must not depend on the request: they cannot read render
+// context values or declare assets.
+func Cache(ttl time.Duration) Option {
+	return func(p *partial) {
+		p.cached = true
+		p.ttl = ttl
+	}
+}
+
+type partial struct {
+	ctor   Constructor
+	cached bool
+	ttl    time.Duration
+}
+
+// UndefinedError is reported when an include names a partial that was never
+// defined.
+type UndefinedError struct {
+	Name string
+}
+
+func (e *UndefinedError) Error() string {
+	return fmt.Sprintf("partial: %q is not defined", e.Name)
+}
+
+// ArgumentError is reported when a cached partial is included with an
+// argument that cannot be part of its cache key.
+type ArgumentError struct {
+	Name  string
+	Index int
+	Type  reflect.Type
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("partial: %q argument %d: %v is not a scalar or component.Hashable, so cannot be cached", e.Name, e.Index, e.Type)
+}
+
+// Registry holds named partials. It is safe for concurrent use.
+type Registry struct {
+	// Store holds cached output. NewRegistry creates a dedicated store.
+	Store *cache.Store
+
+	mu       sync.RWMutex
+	partials map[string]*partial
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{Store: cache.New(), partials: map[string]*partial{}}
+}
+
+// Default is the registry used by the package-level functions.
+var Default = NewRegistry()
+
+// Define registers the partial name, replacing any previous definition and
+// its cached output.
+func (r *Registry) Define(name string, ctor Constructor, opts ...Option) {
+	p := &partial{ctor: ctor}
+	for _, opt := range opts {
+		opt(p)
+	}
+	r.mu.Lock()
+	r.partials[name] = p
+	r.mu.Unlock()
+	r.Invalidate(name)
+}
+
+// Include returns a node rendering the named partial with args. The node is
+// labelled "partial:name" as a node.Component. Including an undefined
+// partial renders nothing and is reported as an *UndefinedError to renders
+// with assertions enabled (see node.WithAssertions).
+func (r *Registry) Include(name string, args ...any) node.Node {
+	return node.Component("partial:"+name, &include{registry: r, name: name, args: args})
+}
+
+// Invalidate removes the cached output of the named partial for every set of
+// arguments and returns the number of entries removed.
+func (r *Registry) Invalidate(name string) int {
+	return r.Store.DeletePrefix(prefix(name))
+}
+
+func (r *Registry) lookup(name string) *partial {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.partials[name]
+}
+
+// Define registers a partial with the Default registry.
+func Define(name string, ctor Constructor, opts ...Option) {
+	Default.Define(name, ctor, opts...)
+}
+
+// Include includes a partial from the Default registry.
+func Include(name string, args ...any) node.Node {
+	return Default.Include(name, args...)
+}
+
+// Invalidate removes a partial's cached output from the Default registry.
+func Invalidate(name string) int {
+	return Default.Invalidate(name)
+}
+
+// prefix returns the start of every cache key of the named partial. The NUL
+// separator keeps "nav" from matching the keys of "navbar".
+func prefix(name string) string {
+	return "partial\x00" + name + "\x00"
+}
+
+// key returns the cache key for an include of name with args, or an error
+// naming the first argument that cannot be keyed.
+func key(name string, args []any) (string, error) {
+	var b strings.Builder
+	b.WriteString(prefix(name))
+	for i, a := range args {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		if h, ok := a.(component.Hashable); ok {
+			fmt.Fprintf(&b, "%T:%q", a, h.Hash())
+			continue
+		}
+		if !scalar(a) {
+			return "", &ArgumentError{Name: name, Index: i, Type: reflect.TypeOf(a)}
+		}
+		fmt.Fprintf(&b, "%T:%#v", a, a)
+	}
+	return b.String(), nil
+}
+
+// scalar reports whether a is a boolean, number or string, whose value
+// alone identifies it.
+func scalar(a any) bool {
+	if a == nil {
+		return true
+	}
+	switch reflect.TypeOf(a).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+type include struct {
+	registry *Registry
+	name     string
+	args     []any
+}
+
+func (in *include) Render(w ...io.Writer) []byte {
+	return node.Render(in, w...)
+}
+
+// RenderBuilder renders the partial, or writes its cached output.
+func (in *include) RenderBuilder(buf *bytes.Buffer) {
+	p := in.registry.lookup(in.name)
+	if p == nil {
+		node.Fail(buf, &UndefinedError{Name: in.name})
+		return
+	}
+	if !p.cached {
+		if n := p.ctor(in.args...); n != nil {
+			n.RenderBuilder(buf)
+		}
+		return
+	}
+
+	k, err := key(in.name, in.args)
+	if err != nil {
+		node.Fail(buf, err)
+		if n := p.ctor(in.args...); n != nil {
+			n.RenderBuilder(buf)
+		}
+		return
+	}
+	if b, ok := in.registry.Store.Get(k); ok {
+		buf.Write(b)
+		return
+	}
+	var out []byte
+	if n := p.ctor(in.args...); n != nil {
+		out = n.Render()
+	}
+	in.registry.Store.Set(k, out, p.ttl)
+	buf.Write(out)
+}
+
+// Nodes returns an empty slice as the partial is only built at render time.
+func (in *include) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the partial is looked up when it renders.
+func (in *include) Dynamic() bool {
+	return true
+}
+
+func (in *include) SetAttribute(_ string, _ string) {
+	// include does not support attributes
+}
@@ -0,0 +1,140 @@
+package partial
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/footer"
+	"github.com/jpl-au/fluent/html5/nav"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestInclude(t *testing.T) {
+	r := NewRegistry()
+	calls := 0
+	r.Define("footer", func(args ...any) node.Node {
+		calls++
+		return footer.New(text.Textf("© %v", args[0]))
+	})
+	page := div.New(r.Include("footer", 2025), r.Include("footer", 2026))
+	for range 2 {
+		if got := string(page.Render()); got != "<div><footer>© 2025</footer><footer>© 2026</footer></div>" {
+			t.Errorf("got %q", got)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("uncached partial built %d times, want 4", calls)
+	}
+}
+
+func TestCache(t *testing.T) {
+	r := NewRegistry()
+	calls := 0
+	r.Define("nav", func(args ...any) node.Node {
+		calls++
+		return nav.New(text.Textf("%v #%d", args[0], calls))
+	}, Cache(0))
+	r.Define("navbar", func(args ...any) node.Node { return text.Static("bar") }, Cache(0))
+
+	render := func(section string) string { return string(r.Include("nav", section).Render()) }
+	if got := render("docs"); got != "<nav>docs #1</nav>" {
+		t.Errorf("first render: got %q", got)
+	}
+	if got := render("docs"); got != "<nav>docs #1</nav>" {
+		t.Errorf("cached render: got %q", got)
+	}
+	if got := render("blog"); got != "<nav>blog #2</nav>" {
+		t.Errorf("other arguments: got %q", got)
+	}
+	r.Include("navbar").Render()
+
+	if n := r.Invalidate("nav"); n != 2 {
+		t.Errorf("Invalidate removed %d entries, want 2", n)
+	}
+	if r.Store.Len() != 1 {
+		t.Errorf("Invalidate removed another partial's entries")
+	}
+	if got := render("docs"); got != "<nav>docs #3</nav>" {
+		t.Errorf("after Invalidate: got %q", got)
+	}
+
+	r.Define("nav", func(args ...any) node.Node { return text.Static("new") }, Cache(0))
+	if got := render("docs"); got != "new" {
+		t.Errorf("after redefinition: got %q", got)
+	}
+}
+
+type section struct{ name string }
+
+type hashed struct{ name string }
+
+func (h *hashed) Hash() string { return h.name }
+
+func TestCacheArguments(t *testing.T) {
+	r := NewRegistry()
+	calls := 0
+	r.Define("nav", func(args ...any) node.Node {
+		calls++
+		return text.Static("nav")
+	}, Cache(0))
+
+	// Equal data behind different pointers shares one entry.
+	r.Include("nav", &hashed{"docs"}).Render()
+	r.Include("nav", &hashed{"docs"}).Render()
+	if calls != 1 || r.Store.Len() != 1 {
+		t.Errorf("Hashable: %d builds and %d entries, want 1 and 1", calls, r.Store.Len())
+	}
+
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	for range 2 {
+		var buf bytes.Buffer
+		if err := fluent.RenderContext(ctx, r.Include("nav", &section{"docs"}), &buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != "nav" {
+			t.Errorf("got %q, want the partial rendered uncached", buf.String())
+		}
+	}
+	var ae *ArgumentError
+	if len(errs) != 2 || !errors.As(errs[0], &ae) || ae.Index != 0 {
+		t.Errorf("got %v, want an ArgumentError per include", errs)
+	}
+	if calls != 3 || r.Store.Len() != 1 {
+		t.Errorf("pointer argument: %d builds and %d entries, want 3 and 1", calls, r.Store.Len())
+	}
+}
+
+func TestUndefined(t *testing.T) {
+	r := NewRegistry()
+	n := div.New(r.Include("missing"))
+	if got := string(n.Render()); got != "<div></div>" {
+		t.Errorf("got %q", got)
+	}
+
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var ue *UndefinedError
+	if len(errs) != 1 || !errors.As(errs[0], &ue) || ue.Name != "missing" {
+		t.Errorf("got %v, want UndefinedError for missing", errs)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	Define("greeting", func(args ...any) node.Node { return text.Textf("hi %v", args...) })
+	if got := string(Include("greeting", "<b>").Render()); got != "hi &lt;b&gt;" {
+		t.Errorf("got %q", got)
+	}
+	if Invalidate("greeting") != 0 {
+		t.Error("uncached partial had cache entries")
+	}
+}
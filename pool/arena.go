@@ -0,0 +1,119 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"sync"
+)
+
+// Arena hands out the buffers of one render session, such as an HTTP
+// response, and returns them to the pools together when the session ends.
+// Buffers put back to the arena during the session are reused by its later
+// Gets rather than going through the shared pools, so a response that
+// renders many nodes into intermediate buffers touches the pools once per
+// buffer it needs at the same time instead of once per node.
+//
+// Buffers obtained from an arena must not be used after Release. A nil
+// *Arena is valid and passes Get and Put straight to the pools.
+//
+// Usage:
+//
+//	func withArena(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        a := pool.NewArena()
+//	        defer a.Release()
+//	        next.ServeHTTP(w, r.WithContext(pool.WithArena(r.Context(), a)))
+//	    })
+//	}
+type Arena struct {
+	mu    sync.Mutex
+	owned []*bytes.Buffer // every buffer taken from the pools
+	free  []*bytes.Buffer // buffers put back and ready for reuse
+}
+
+// NewArena returns an empty arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// Get returns a buffer put back earlier in the session, or one from the
+// pools when none is free, grown to at least hint bytes.
+func (a *Arena) Get(hint int) *bytes.Buffer {
+	if a == nil {
+		return Get(hint)
+	}
+	a.mu.Lock()
+	if n := len(a.free); n > 0 {
+		buf := a.free[n-1]
+		a.free = a.free[:n-1]
+		a.mu.Unlock()
+		buf.Reset()
+		if hint > 0 {
+			buf.Grow(hint)
+		}
+		return buf
+	}
+	a.mu.Unlock()
+
+	buf := Get(hint)
+	a.mu.Lock()
+	a.owned = append(a.owned, buf)
+	a.mu.Unlock()
+	return buf
+}
+
+// Put makes buf available to the arena's later Gets. It is kept, not
+// returned to the pools, until Release.
+func (a *Arena) Put(buf *bytes.Buffer) {
+	if a == nil {
+		Put(buf)
+		return
+	}
+	if buf == nil {
+		return
+	}
+	a.mu.Lock()
+	a.free = append(a.free, buf)
+	a.mu.Unlock()
+}
+
+// Buffers returns the number of buffers the arena has taken from the pools.
+func (a *Arena) Buffers() int {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.owned)
+}
+
+// Release returns every buffer the arena has handed out to the pools,
+// including those never put back. The arena is empty afterwards and may be
+// used for another session.
+func (a *Arena) Release() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	owned := a.owned
+	a.owned, a.free = nil, nil
+	a.mu.Unlock()
+	for _, buf := range owned {
+		Put(buf)
+	}
+}
+
+type arenaKey struct{}
+
+// WithArena returns a copy of ctx carrying a. fluent.RenderContext takes
+// its buffers from the arena in its context and makes it available to the
+// nodes it renders.
+func WithArena(ctx context.Context, a *Arena) context.Context {
+	return context.WithValue(ctx, arenaKey{}, a)
+}
+
+// ArenaFrom returns the arena carried by ctx, or nil.
+func ArenaFrom(ctx context.Context) *Arena {
+	a, _ := ctx.Value(arenaKey{}).(*Arena)
+	return a
+}
@@ -0,0 +1,61 @@
+package pool
+
+import (
+	"context"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	Enable()
+	a := NewArena()
+
+	first := a.Get(100)
+	first.WriteString("scratch")
+	a.Put(first)
+	second := a.Get(10)
+	if second != first {
+		t.Error("expected the arena to reuse the buffer put back")
+	}
+	if second.Len() != 0 {
+		t.Errorf("got %q, want an empty buffer", second.String())
+	}
+	other := a.Get(10)
+	if other == second {
+		t.Error("expected a distinct buffer while the first is in use")
+	}
+	if got := a.Buffers(); got != 2 {
+		t.Errorf("got %d buffers, want 2", got)
+	}
+
+	ResetStats()
+	a.Release()
+	if got := a.Buffers(); got != 0 {
+		t.Errorf("got %d buffers after release, want 0", got)
+	}
+	if s := Stats(); s.Puts != 2 {
+		t.Errorf("got %d puts on release, want 2", s.Puts)
+	}
+}
+
+func TestNilArena(t *testing.T) {
+	var a *Arena
+	buf := a.Get(10)
+	if buf == nil || buf.Cap() < 10 {
+		t.Fatal("expected a pooled buffer from a nil arena")
+	}
+	a.Put(buf)
+	a.Release()
+	if a.Buffers() != 0 {
+		t.Error("expected a nil arena to hold no buffers")
+	}
+}
+
+func TestArenaContext(t *testing.T) {
+	if ArenaFrom(context.Background()) != nil {
+		t.Error("expected no arena in a plain context")
+	}
+	a := NewArena()
+	if ArenaFrom(WithArena(context.Background(), a)) != a {
+		t.Error("expected the arena carried by the context")
+	}
+}
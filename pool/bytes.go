@@ -0,0 +1,51 @@
+package pool
+
+import "sync"
+
+// headers holds the empty *[]byte boxes left behind by GetBytes, so that
+// PutBytes can reuse one instead of allocating a pointer on every call.
+var headers sync.Pool
+
+// GetBytes returns an empty slice with a capacity of at least hint, for code
+// that needs raw scratch space, such as escaping or hashing, rather than a
+// bytes.Buffer. It follows the buffer pool configuration: slices come from
+// the size class of the hint, and a new slice is allocated when pooling is
+// disabled.
+func GetBytes(hint int) []byte {
+	if !Enabled() {
+		return make([]byte, 0, hint)
+	}
+	// Slices are pooled as pointers so that putting one does not allocate
+	// its interface value on every call. The emptied pointer is kept for the
+	// next PutBytes.
+	p := &tierFor(current(), hint).bytes
+	if v, ok := p.Get().(*[]byte); ok {
+		if cap(*v) >= hint {
+			b := (*v)[:0]
+			*v = nil
+			headers.Put(v)
+			return b
+		}
+		// Too small for this hint; keep it for a smaller request.
+		p.Put(v)
+	}
+	return make([]byte, 0, hint)
+}
+
+// PutBytes returns b to the pool. Slices larger than MaxPoolSize are
+// discarded when DiscardOversized is set. b must not be used afterwards.
+func PutBytes(b []byte) {
+	if !Enabled() || cap(b) == 0 {
+		return
+	}
+	c := cap(b)
+	if c > MaxPoolSize() && DiscardOversized() {
+		return
+	}
+	v, ok := headers.Get().(*[]byte)
+	if !ok {
+		v = new([]byte)
+	}
+	*v = b[:0]
+	tierFor(current(), c).bytes.Put(v)
+}
@@ -0,0 +1,50 @@
+package pool
+
+import "testing"
+
+func TestGetBytes(t *testing.T) {
+	Enable()
+	for _, hint := range []int{0, 10, Threshold() - 1, Threshold(), Threshold() * 2} {
+		b := GetBytes(hint)
+		if len(b) != 0 || cap(b) < hint {
+			t.Errorf("GetBytes(%d) returned len %d cap %d", hint, len(b), cap(b))
+		}
+		PutBytes(append(b, "scratch"...))
+	}
+}
+
+func TestGetBytesReuse(t *testing.T) {
+	Enable()
+	b := append(GetBytes(64), "data"...)
+	PutBytes(b)
+	// sync.Pool may drop items at any time, so only a reused slice's contents
+	// can be checked.
+	if got := GetBytes(64); len(got) != 0 || cap(got) < 64 {
+		t.Errorf("got len %d cap %d, want an empty slice of at least 64", len(got), cap(got))
+	}
+}
+
+func TestBytesNoAlloc(t *testing.T) {
+	Enable()
+	// Fresh size classes, so slices too small for the hint left by other
+	// tests are not in the way.
+	SetSizeClasses([]int{Threshold()})
+	PutBytes(GetBytes(64))
+	allocs := testing.AllocsPerRun(100, func() {
+		PutBytes(GetBytes(64))
+	})
+	if allocs != 0 {
+		t.Errorf("GetBytes/PutBytes allocated %v times per run, want 0", allocs)
+	}
+}
+
+func TestGetBytesDisabled(t *testing.T) {
+	Disable()
+	defer Enable()
+	b := GetBytes(32)
+	if cap(b) < 32 {
+		t.Errorf("got cap %d, want at least 32", cap(b))
+	}
+	PutBytes(b)
+	PutBytes(make([]byte, 0, MaxPoolSize()+1))
+}
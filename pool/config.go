@@ -0,0 +1,176 @@
+package pool
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Option adjusts the pool configuration applied by Configure.
+type Option func(*config)
+
+type config struct {
+	enabled bool
+	classes []int
+	maxSize int
+	discard bool
+}
+
+// configuring serialises Configure so concurrent calls do not interleave
+// their options.
+var configuring sync.Mutex
+
+// Configure applies opts on top of the current configuration. Each setting
+// is stored atomically, so pools may be reconfigured while renders run;
+// a render in flight sees each setting either before or after the change.
+//
+// Usage:
+//
+//	pool.Configure(
+//	    pool.WithSizeClasses(1024, 8192, 65536),
+//	    pool.WithMaxSize(512<<10, true),
+//	)
+func Configure(opts ...Option) {
+	configuring.Lock()
+	defer configuring.Unlock()
+
+	c := config{
+		enabled: Enabled(),
+		classes: SizeClasses(),
+		maxSize: MaxPoolSize(),
+		discard: DiscardOversized(),
+	}
+	before := c.classes
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if !slices.Equal(before, c.classes) {
+		SetSizeClasses(c.classes)
+	}
+	SetMaxPoolSize(c.maxSize, c.discard)
+	enabled.Store(c.enabled)
+}
+
+// WithEnabled turns pooling on or off.
+func WithEnabled(on bool) Option {
+	return func(c *config) {
+		c.enabled = on
+	}
+}
+
+// WithThreshold sets the size threshold between small and large pools in
+// bytes, replacing any size classes.
+func WithThreshold(size int) Option {
+	return func(c *config) {
+		c.classes = []int{size}
+	}
+}
+
+// WithSizeClasses sets the size class boundaries in bytes (see
+// SetSizeClasses).
+func WithSizeClasses(bounds ...int) Option {
+	return func(c *config) {
+		c.classes = bounds
+	}
+}
+
+// WithMaxSize sets the largest buffer kept in the pools in bytes and whether
+// larger buffers are discarded.
+func WithMaxSize(size int, discard bool) Option {
+	return func(c *config) {
+		c.maxSize = size
+		c.discard = discard
+	}
+}
+
+// Environment variables read by FromEnv.
+const (
+	EnvEnabled          = "FLUENT_POOL_ENABLED"           // true or false
+	EnvThreshold        = "FLUENT_POOL_THRESHOLD"         // size, e.g. 4096 or 4KB
+	EnvSizeClasses      = "FLUENT_POOL_SIZE_CLASSES"      // comma separated sizes, e.g. 1KB,8KB,64KB
+	EnvMaxSize          = "FLUENT_POOL_MAX_SIZE"          // size, e.g. 256KB
+	EnvDiscardOversized = "FLUENT_POOL_DISCARD_OVERSIZED" // true or false
+)
+
+// FromEnv returns the options set by the FLUENT_POOL_* environment
+// variables, so deployments can tune pooling without code changes. Unset
+// variables leave their setting alone. Sizes are in bytes, optionally with
+// a KB or MB suffix (multiples of 1024). FLUENT_POOL_SIZE_CLASSES takes
+// precedence over FLUENT_POOL_THRESHOLD.
+//
+// Usage:
+//
+//	opts, err := pool.FromEnv()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	pool.Configure(opts...)
+func FromEnv() ([]Option, error) {
+	var opts []Option
+	if v, ok := os.LookupEnv(EnvEnabled); ok {
+		on, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("pool: %s: %w", EnvEnabled, err)
+		}
+		opts = append(opts, WithEnabled(on))
+	}
+	if v, ok := os.LookupEnv(EnvThreshold); ok {
+		size, err := parseSize(v)
+		if err != nil {
+			return nil, fmt.Errorf("pool: %s: %w", EnvThreshold, err)
+		}
+		opts = append(opts, WithThreshold(size))
+	}
+	if v, ok := os.LookupEnv(EnvSizeClasses); ok {
+		var bounds []int
+		for _, f := range strings.Split(v, ",") {
+			if strings.TrimSpace(f) == "" {
+				continue
+			}
+			size, err := parseSize(f)
+			if err != nil {
+				return nil, fmt.Errorf("pool: %s: %w", EnvSizeClasses, err)
+			}
+			bounds = append(bounds, size)
+		}
+		opts = append(opts, WithSizeClasses(bounds...))
+	}
+	if v, ok := os.LookupEnv(EnvMaxSize); ok {
+		size, err := parseSize(v)
+		if err != nil {
+			return nil, fmt.Errorf("pool: %s: %w", EnvMaxSize, err)
+		}
+		opts = append(opts, func(c *config) { c.maxSize = size })
+	}
+	if v, ok := os.LookupEnv(EnvDiscardOversized); ok {
+		discard, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("pool: %s: %w", EnvDiscardOversized, err)
+		}
+		opts = append(opts, func(c *config) { c.discard = discard })
+	}
+	return opts, nil
+}
+
+// parseSize parses a byte count such as "4096", "4K", "4KB" or "1MB".
+func parseSize(v string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	mult := 1
+	for _, u := range []struct {
+		suffix string
+		mult   int
+	}{{"KB", 1 << 10}, {"K", 1 << 10}, {"MB", 1 << 20}, {"M", 1 << 20}, {"B", 1}} {
+		if n, ok := strings.CutSuffix(s, u.suffix); ok {
+			s, mult = strings.TrimSpace(n), u.mult
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", v)
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,103 @@
+package pool
+
+import (
+	"slices"
+	"testing"
+)
+
+// restore resets the configuration to the defaults after a test.
+func restore(t *testing.T) {
+	t.Cleanup(func() {
+		Configure(WithEnabled(true), WithThreshold(4*1024), WithMaxSize(256*1024, true))
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	restore(t)
+	Configure(WithSizeClasses(1024, 8192), WithMaxSize(1<<20, false), WithEnabled(false))
+	if got := SizeClasses(); !slices.Equal(got, []int{1024, 8192}) {
+		t.Errorf("got classes %v, want [1024 8192]", got)
+	}
+	if MaxPoolSize() != 1<<20 || DiscardOversized() || Enabled() {
+		t.Errorf("got max %d discard %v enabled %v", MaxPoolSize(), DiscardOversized(), Enabled())
+	}
+
+	// Options not given keep their current values.
+	Configure(WithEnabled(true))
+	if got := SizeClasses(); !slices.Equal(got, []int{1024, 8192}) || MaxPoolSize() != 1<<20 || !Enabled() {
+		t.Errorf("got classes %v max %d enabled %v", got, MaxPoolSize(), Enabled())
+	}
+}
+
+func TestConfigureConcurrent(t *testing.T) {
+	restore(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 100 {
+			Configure(WithThreshold(1024*(i%4+1)), WithMaxSize(1<<20, i%2 == 0))
+		}
+	}()
+	for range 100 {
+		Put(Get(2048))
+		PutBytes(GetBytes(2048))
+	}
+	<-done
+}
+
+func TestFromEnv(t *testing.T) {
+	restore(t)
+	t.Setenv(EnvEnabled, "false")
+	t.Setenv(EnvSizeClasses, "1KB, 8k,64KB")
+	t.Setenv(EnvMaxSize, "1MB")
+	t.Setenv(EnvDiscardOversized, "false")
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Configure(opts...)
+	if got := SizeClasses(); !slices.Equal(got, []int{1024, 8192, 65536}) {
+		t.Errorf("got classes %v", got)
+	}
+	if MaxPoolSize() != 1<<20 || DiscardOversized() || Enabled() {
+		t.Errorf("got max %d discard %v enabled %v", MaxPoolSize(), DiscardOversized(), Enabled())
+	}
+}
+
+func TestFromEnvUnset(t *testing.T) {
+	opts, err := FromEnv()
+	if err != nil || len(opts) != 0 {
+		t.Errorf("got %d options, %v; want none", len(opts), err)
+	}
+}
+
+func TestFromEnvInvalid(t *testing.T) {
+	for _, tt := range []struct{ key, val string }{
+		{EnvEnabled, "maybe"},
+		{EnvThreshold, "4 parsecs"},
+		{EnvSizeClasses, "1KB,x"},
+		{EnvMaxSize, "-1"},
+		{EnvDiscardOversized, "2"},
+	} {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Setenv(tt.key, tt.val)
+			if _, err := FromEnv(); err == nil {
+				t.Errorf("%s=%q: expected an error", tt.key, tt.val)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"4096", 4096}, {"4K", 4096}, {"4kb", 4096}, {" 2 MB ", 2 << 20}, {"10B", 10},
+	}
+	for _, tt := range tests {
+		if got, err := parseSize(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -2,38 +2,70 @@ package pool
 
 import (
 	"bytes"
+	"slices"
 	"sync"
 	"sync/atomic"
 )
 
-// Global pool configuration - internal variables, access via getter/setter methods
+// Global pool configuration - internal variables, access via getter/setter methods.
+// They are atomic so that configuration may change while renders are running.
 var (
-	poolThreshold    = 4 * 1024   // Threshold defines the pool (small, large) the builder is returned to
-	maxPoolSize      = 256 * 1024 // Maximum size to keep in pools - discard larger buffers
-	discardOversized = true       // Whether to discard oversized buffers (true by default)
+	maxPoolSize      atomic.Int64 // Maximum size to keep in pools - discard larger buffers
+	discardOversized atomic.Bool  // Whether to discard oversized buffers (true by default)
 )
 
 // enabled controls whether sync.Pool optimizations are enabled globally.
 // Can be safely toggled at runtime using atomic operations.
 var enabled atomic.Bool
 
+// tier pools the buffers and byte slices of one size class.
+type tier struct {
+	bound   int // capacities below bound belong here; 0 for the last, unbounded tier
+	buffers sync.Pool
+	bytes   sync.Pool // holds *[]byte
+	stats   counters
+}
+
+// tiers holds the size classes in ascending order. SetSizeClasses replaces
+// the slice as a whole so that renders in flight keep a consistent view.
+var tiers atomic.Pointer[[]*tier]
+
 func init() {
 	enabled.Store(true) // Enable pool by default
+	maxPoolSize.Store(256 * 1024)
+	discardOversized.Store(true)
+	SetSizeClasses([]int{4 * 1024})
 }
 
-// Pool instances for any poolable objects
-var (
-	smallPool = sync.Pool{
-		New: func() any {
-			return &bytes.Buffer{}
-		},
+func newTiers(bounds []int) []*tier {
+	ts := make([]*tier, 0, len(bounds)+1)
+	for _, b := range bounds {
+		ts = append(ts, &tier{bound: b})
 	}
-	largePool = sync.Pool{
-		New: func() any {
+	ts = append(ts, &tier{})
+	for _, t := range ts {
+		t.buffers.New = func() any {
+			t.stats.news.Add(1)
 			return &bytes.Buffer{}
-		},
+		}
 	}
-)
+	return ts
+}
+
+// current returns the configured tiers.
+func current() []*tier {
+	return *tiers.Load()
+}
+
+// tierFor returns the tier holding buffers of the given size.
+func tierFor(ts []*tier, size int) *tier {
+	for _, t := range ts[:len(ts)-1] {
+		if size < t.bound {
+			return t
+		}
+	}
+	return ts[len(ts)-1]
+}
 
 // Enable turns on sync.Pool optimizations
 func Enable() {
@@ -57,18 +89,15 @@ func Get(hint int) *bytes.Buffer {
 		return bytes.NewBuffer(make([]byte, 0, hint))
 	}
 
+	t := tierFor(current(), hint)
+	t.stats.gets.Add(1)
 	var pooled *bytes.Buffer
-	if hint < poolThreshold {
-		if p := smallPool.Get(); p != nil {
-			pooled = p.(*bytes.Buffer) //nolint:forcetypeassert // Pool only contains *bytes.Buffer
-		}
-	} else {
-		if p := largePool.Get(); p != nil {
-			pooled = p.(*bytes.Buffer) //nolint:forcetypeassert // Pool only contains *bytes.Buffer
-		}
+	if p := t.buffers.Get(); p != nil {
+		pooled = p.(*bytes.Buffer) //nolint:forcetypeassert // Pool only contains *bytes.Buffer
 	}
 
 	if pooled != nil {
+		t.stats.retained.Add(-int64(pooled.Cap()))
 		pooled.Reset()
 		if hint > 0 {
 			pooled.Grow(hint)
@@ -76,6 +105,7 @@ func Get(hint int) *bytes.Buffer {
 		return pooled
 	}
 
+	t.stats.news.Add(1)
 	return bytes.NewBuffer(make([]byte, 0, hint))
 }
 
@@ -87,52 +117,107 @@ func Put(buf *bytes.Buffer) {
 	}
 
 	cap := buf.Cap()
+	// Route to the size class of the buffer's capacity
+	t := tierFor(current(), cap)
 
 	// Check if buffer is oversized
-	if cap > maxPoolSize {
-		if discardOversized {
+	if cap > MaxPoolSize() {
+		if DiscardOversized() {
 			// Discard oversized buffers to prevent memory bloat
+			t.stats.discards.Add(1)
 			return
 		}
 	}
 
 	buf.Reset()
-	// Route to appropriate pool based on capacity
-	if cap < poolThreshold {
-		smallPool.Put(buf)
-	} else {
-		largePool.Put(buf)
+	t.stats.puts.Add(1)
+	t.stats.retained.Add(int64(cap))
+	t.buffers.Put(buf)
+}
+
+// Warm pre-populates the pool with count buffers of size bytes capacity, so
+// the first requests after start up reuse buffers instead of allocating
+// them. Call it once per size class expected to be busy. It does nothing
+// when pooling is disabled, and buffers above MaxPoolSize are discarded as
+// by Put. The buffers count as Puts in Stats.
+//
+// sync.Pool frees idle buffers over two garbage collections, so warm the
+// pools shortly before traffic arrives rather than long in advance.
+//
+// Usage:
+//
+//	pool.Warm(64, 2*1024)   // fragments
+//	pool.Warm(16, 32*1024)  // full pages
+func Warm(count, size int) {
+	if !Enabled() || size < 0 {
+		return
+	}
+	for range count {
+		Put(bytes.NewBuffer(make([]byte, 0, size)))
 	}
 }
 
 // Configuration setters
 
-// SetThreshold sets the size threshold between small and large pools in bytes
+// SetThreshold sets the size threshold between small and large pools in
+// bytes. It is SetSizeClasses with a single boundary.
 func SetThreshold(size int) {
-	poolThreshold = size
+	SetSizeClasses([]int{size})
+}
+
+// SetSizeClasses splits the pool into size classes at the given capacities,
+// in bytes. Each boundary closes a class holding the buffers below it, and a
+// final class holds everything from the largest boundary up to MaxPoolSize:
+// SetSizeClasses([]int{1024, 8192, 65536}) creates classes for buffers under
+// 1KB, under 8KB, under 64KB and the rest. Get draws from the class of its
+// hint and Put returns a buffer to the class of its capacity, so buffers
+// that grow migrate upwards and large renders cannot crowd small buffers
+// out of their class.
+//
+// Boundaries are sorted and non-positive or repeated values ignored. An
+// empty list pools every buffer together. Buffers pooled under the previous
+// classes are dropped and the statistics restart.
+func SetSizeClasses(bounds []int) {
+	bs := slices.Clone(bounds)
+	slices.Sort(bs)
+	bs = slices.Compact(bs)
+	bs = slices.DeleteFunc(bs, func(b int) bool { return b <= 0 })
+	ts := newTiers(bs)
+	tiers.Store(&ts)
 }
 
 // SetMaxPoolSize configures the maximum buffer size to keep in pools.
 // Buffers larger than this will be discarded if discard is true, otherwise
 // they will be resized back to maxSize before being pooled.
 func SetMaxPoolSize(size int, discard bool) {
-	maxPoolSize = size
-	discardOversized = discard
+	maxPoolSize.Store(int64(size))
+	discardOversized.Store(discard)
 }
 
 // Configuration getters
 
-// Threshold returns the size threshold between small and large pools in bytes
+// Threshold returns the size threshold between small and large pools in
+// bytes: the smallest size class boundary, or 0 when there is only one class.
 func Threshold() int {
-	return poolThreshold
+	return current()[0].bound
+}
+
+// SizeClasses returns the size class boundaries in bytes.
+func SizeClasses() []int {
+	ts := current()
+	bounds := make([]int, 0, len(ts)-1)
+	for _, t := range ts[:len(ts)-1] {
+		bounds = append(bounds, t.bound)
+	}
+	return bounds
 }
 
 // MaxPoolSize returns the maximum buffer size to keep in pools in bytes
 func MaxPoolSize() int {
-	return maxPoolSize
+	return int(maxPoolSize.Load())
 }
 
 // DiscardOversized returns whether oversized buffers should be discarded
 func DiscardOversized() bool {
-	return discardOversized
+	return discardOversized.Load()
 }
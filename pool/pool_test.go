@@ -2,6 +2,7 @@ package pool
 
 import (
 	"bytes"
+	"slices"
 	"testing"
 )
 
@@ -24,8 +25,9 @@ func TestPoolGetPut(t *testing.T) {
 	// We can inspect internals since we are in the same package
 
 	// Clear pools for deterministic testing
-	smallPool.New = func() any { return &bytes.Buffer{} }
-	largePool.New = func() any { return &bytes.Buffer{} }
+	for _, t := range current() {
+		t.buffers.New = func() any { return &bytes.Buffer{} }
+	}
 
 	// Put a buffer into small pool
 	b1 := bytes.NewBuffer(make([]byte, 0, 100))
@@ -79,3 +81,72 @@ func TestDiscardOversized(t *testing.T) {
 	// We can't easily verify it was discarded without internal counters, but we can ensure it doesn't panic
 	t.Log("oversized buffer handled without panic")
 }
+
+func TestSizeClasses(t *testing.T) {
+	Enable()
+	defer SetThreshold(4 * 1024)
+
+	SetSizeClasses([]int{65536, 1024, 8192, 1024, -1})
+	if got, want := SizeClasses(), []int{1024, 8192, 65536}; !slices.Equal(got, want) {
+		t.Fatalf("got classes %v, want %v", got, want)
+	}
+	if got := Threshold(); got != 1024 {
+		t.Errorf("got threshold %d, want 1024", got)
+	}
+
+	for _, hint := range []int{10, 2000, 10000, 100000} {
+		Put(Get(hint))
+	}
+	Put(bytes.NewBuffer(make([]byte, 0, 5000)))
+	s := Stats()
+	if len(s.Classes) != 4 {
+		t.Fatalf("got %d classes in stats, want 4", len(s.Classes))
+	}
+	for i, want := range []uint64{1, 1, 1, 1} {
+		if got := s.Classes[i].Gets; got != want {
+			t.Errorf("class %d: got %d gets, want %d", i, got, want)
+		}
+	}
+	if got := s.Classes[1].Puts; got != 2 {
+		t.Errorf("got %d puts under 8KB, want 2", got)
+	}
+	if s.Small.Gets != 1 || s.Large.Gets != 3 {
+		t.Errorf("got small %d large %d gets, want 1 and 3", s.Small.Gets, s.Large.Gets)
+	}
+
+	SetSizeClasses(nil)
+	if got := Threshold(); got != 0 {
+		t.Errorf("got threshold %d with one class, want 0", got)
+	}
+	if buf := Get(100); buf.Cap() < 100 {
+		t.Errorf("got capacity %d, want at least 100", buf.Cap())
+	}
+}
+
+func TestWarm(t *testing.T) {
+	Enable()
+	ResetStats()
+	before := Stats().Small.RetainedBytes
+
+	Warm(8, 1024)
+	s := Stats()
+	if s.Small.Puts != 8 {
+		t.Errorf("got %d small puts, want 8", s.Small.Puts)
+	}
+	if got := s.Small.RetainedBytes - before; got != 8*1024 {
+		t.Errorf("got %d more retained bytes, want %d", got, 8*1024)
+	}
+
+	Warm(2, MaxPoolSize()+1)
+	if got := Stats().Discards; got != 2 {
+		t.Errorf("got %d discards for oversized warm up, want 2", got)
+	}
+
+	Disable()
+	defer Enable()
+	ResetStats()
+	Warm(4, 1024)
+	if got := Stats().Puts; got != 0 {
+		t.Errorf("got %d puts while disabled, want 0", got)
+	}
+}
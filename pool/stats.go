@@ -0,0 +1,95 @@
+package pool
+
+import "sync/atomic"
+
+// Counters describe the traffic through one pool, or through both.
+type Counters struct {
+	// Gets counts buffers requested with Get.
+	Gets uint64
+	// News counts Gets the pool could not satisfy, which allocated a buffer.
+	News uint64
+	// Puts counts buffers returned to the pool.
+	Puts uint64
+	// Discards counts buffers passed to Put and dropped as oversized.
+	Discards uint64
+	// RetainedBytes estimates the capacity of the buffers held by the pool:
+	// the capacity put, less the capacity taken back out by Gets. sync.Pool
+	// frees idle buffers during garbage collection without notice, so the
+	// true figure may be lower.
+	RetainedBytes int64
+}
+
+// Statistics are the pool counters since the last ResetStats. The embedded
+// Counters are the totals across every size class.
+type Statistics struct {
+	Counters
+	// Small counts the smallest size class, below Threshold, and Large the
+	// classes above it combined.
+	Small Counters
+	Large Counters
+	// Classes counts each size class: Classes[i] covers buffers below
+	// SizeClasses()[i], and the final entry those above the largest boundary.
+	Classes []Counters
+}
+
+// HitRate returns the fraction of Gets served by a pooled buffer, or 0
+// before the first Get.
+func (c Counters) HitRate() float64 {
+	if c.Gets == 0 {
+		return 0
+	}
+	return float64(c.Gets-c.News) / float64(c.Gets)
+}
+
+// counters are the live counters of one pool.
+type counters struct {
+	gets, news, puts, discards atomic.Uint64
+	retained                   atomic.Int64
+}
+
+func (c *counters) load() Counters {
+	return Counters{
+		Gets:          c.gets.Load(),
+		News:          c.news.Load(),
+		Puts:          c.puts.Load(),
+		Discards:      c.discards.Load(),
+		RetainedBytes: max(c.retained.Load(), 0),
+	}
+}
+
+// Stats returns the pool counters. Only Gets and Puts made while pooling is
+// enabled are counted.
+func Stats() Statistics {
+	var s Statistics
+	for i, t := range current() {
+		c := t.stats.load()
+		s.Classes = append(s.Classes, c)
+		if i == 0 {
+			s.Small = c
+		} else {
+			s.Large.add(c)
+		}
+		s.add(c)
+	}
+	return s
+}
+
+func (c *Counters) add(o Counters) {
+	c.Gets += o.Gets
+	c.News += o.News
+	c.Puts += o.Puts
+	c.Discards += o.Discards
+	c.RetainedBytes += o.RetainedBytes
+}
+
+// ResetStats zeroes the counters, for example after warm up or at the start
+// of each reporting interval. RetainedBytes is left alone as it describes
+// the buffers the pools currently hold rather than past traffic.
+func ResetStats() {
+	for _, t := range current() {
+		t.stats.gets.Store(0)
+		t.stats.news.Store(0)
+		t.stats.puts.Store(0)
+		t.stats.discards.Store(0)
+	}
+}
@@ -0,0 +1,61 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	Enable()
+	ResetStats()
+	before := Stats().RetainedBytes
+
+	small := Get(10)
+	large := Get(Threshold())
+	Put(bytes.NewBuffer(make([]byte, 0, 100)))
+	Put(bytes.NewBuffer(make([]byte, 0, MaxPoolSize()+1)))
+
+	s := Stats()
+	if s.Small.Gets != 1 || s.Large.Gets != 1 || s.Gets != 2 {
+		t.Errorf("got gets small=%d large=%d total=%d, want 1, 1 and 2", s.Small.Gets, s.Large.Gets, s.Gets)
+	}
+	if s.News > s.Gets {
+		t.Errorf("got %d news for %d gets", s.News, s.Gets)
+	}
+	if s.Small.Puts != 1 || s.Large.Discards != 1 || s.Puts != 1 || s.Discards != 1 {
+		t.Errorf("got %+v, want one small put and one large discard", s)
+	}
+	if s.RetainedBytes > before+100 {
+		t.Errorf("got %d retained bytes, want at most %d", s.RetainedBytes, before+100)
+	}
+	if s.Small.RetainedBytes+s.Large.RetainedBytes != s.RetainedBytes {
+		t.Errorf("retained bytes total %d does not match its parts", s.RetainedBytes)
+	}
+
+	Put(small)
+	Put(large)
+	ResetStats()
+	if s := Stats(); s.Gets != 0 || s.News != 0 || s.Puts != 0 || s.Discards != 0 {
+		t.Errorf("got %+v after reset, want zero counters", s.Counters)
+	}
+}
+
+func TestStatsDisabled(t *testing.T) {
+	Disable()
+	defer Enable()
+	ResetStats()
+
+	Put(Get(10))
+	if s := Stats(); s.Gets != 0 || s.Puts != 0 {
+		t.Errorf("got %+v while disabled, want nothing counted", s.Counters)
+	}
+}
+
+func TestHitRate(t *testing.T) {
+	if got := (Counters{}).HitRate(); got != 0 {
+		t.Errorf("got %v, want 0", got)
+	}
+	if got := (Counters{Gets: 4, News: 1}).HitRate(); got != 0.75 {
+		t.Errorf("got %v, want 0.75", got)
+	}
+}
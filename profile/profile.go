@@ -0,0 +1,210 @@
+// Package profile measures where render time goes, attributing timings and
+// allocations to named components (see node.Component) and to node types, so
+// the hotspot in a large page can be found rather than guessed.
+//
+// Usage:
+//
+//	report, err := profile.Render(ctx, page, io.Discard)
+//	if err != nil {
+//	    return err
+//	}
+//	report.WriteTo(os.Stderr)
+//	report.WriteJSON(file)
+package profile
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Entry is the profile of one component or node type.
+type Entry struct {
+	Name string `json:"name"`
+	// Count is the number of times it rendered.
+	Count int `json:"count"`
+	// Time is the total render time. For components it includes nested
+	// components; for node types it excludes children.
+	Time time.Duration `json:"time_ns"`
+	// Allocs is the number of heap allocations made while rendering,
+	// with the same scope as Time.
+	Allocs uint64 `json:"allocs"`
+	// Bytes is the size of the output, including children.
+	Bytes int `json:"bytes"`
+}
+
+// Report is the profile of one render.
+type Report struct {
+	Time   time.Duration `json:"time_ns"`
+	Allocs uint64        `json:"allocs"`
+	Bytes  int           `json:"bytes"`
+	Nodes  int           `json:"nodes"`
+	// Components and Types are ordered by time, slowest first.
+	Components []Entry `json:"components"`
+	Types      []Entry `json:"types"`
+}
+
+// Render renders n to w like fluent.RenderContext and returns its profile.
+//
+// Components are timed during the render itself. Node types are timed in a
+// second pass that renders each node's subtree again, discarding the output,
+// and subtracts the time of its children; content evaluated at render time,
+// such as node.Func, therefore runs more than once and is attributed to the
+// Func node rather than to the nodes it returns. Allocation counts cover the
+// whole process, so other goroutines allocating during the render inflate
+// them.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) (*Report, error) {
+	p := &profiler{components: map[string]*Entry{}, types: map[string]*Entry{}}
+	cw := &counter{w: w}
+
+	start, allocs := time.Now(), heapAllocs()
+	err := fluent.RenderContext(node.WithComponentHook(ctx, p.hook), n, cw, policy...)
+	r := &Report{
+		Time:   time.Since(start),
+		Allocs: heapAllocs() - allocs,
+		Bytes:  cw.n,
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if n != nil {
+		scratch := fluent.NewBuffer()
+		defer fluent.PutBuffer(scratch)
+		release := node.Bind(scratch, &node.Session{Context: ctx})
+		defer release()
+		p.measure(scratch, n)
+	}
+	r.Nodes = p.nodes
+	r.Components = sorted(p.components)
+	r.Types = sorted(p.types)
+	return r, nil
+}
+
+type profiler struct {
+	mu         sync.Mutex
+	components map[string]*Entry
+	types      map[string]*Entry
+	nodes      int
+}
+
+// hook times each named component.
+func (p *profiler) hook(buf *bytes.Buffer, c *node.NamedComponent) func() {
+	start, allocs, size := time.Now(), heapAllocs(), buf.Len()
+	return func() {
+		elapsed, allocated := time.Since(start), heapAllocs()-allocs
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.components[c.Name()] = add(p.components[c.Name()], c.Name(), elapsed, allocated, buf.Len()-size)
+	}
+}
+
+// measure renders n into scratch, records its self time against its type and
+// returns its inclusive time and allocations.
+func (p *profiler) measure(scratch *bytes.Buffer, n node.Node) (time.Duration, uint64) {
+	var childTime time.Duration
+	var childAllocs uint64
+	for _, child := range n.Nodes() {
+		if child != nil {
+			t, a := p.measure(scratch, child)
+			childTime += t
+			childAllocs += a
+		}
+	}
+
+	scratch.Reset()
+	start, allocs := time.Now(), heapAllocs()
+	n.RenderBuilder(scratch)
+	elapsed, allocated := time.Since(start), heapAllocs()-allocs
+
+	name := strings.TrimPrefix(fmt.Sprintf("%T", n), "*")
+	p.types[name] = add(p.types[name], name, max(elapsed-childTime, 0), allocated-min(childAllocs, allocated), scratch.Len())
+	p.nodes++
+	return elapsed, allocated
+}
+
+// add records one render in e, creating it if needed.
+func add(e *Entry, name string, elapsed time.Duration, allocs uint64, size int) *Entry {
+	if e == nil {
+		e = &Entry{Name: name}
+	}
+	e.Count++
+	e.Time += elapsed
+	e.Allocs += allocs
+	e.Bytes += size
+	return e
+}
+
+func sorted(entries map[string]*Entry) []Entry {
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, *e)
+	}
+	slices.SortFunc(out, func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(a.Name, b.Name))
+	})
+	return out
+}
+
+// heapAllocs returns the cumulative number of heap allocations.
+func heapAllocs() uint64 {
+	s := []metrics.Sample{{Name: "/gc/heap/allocs:objects"}}
+	metrics.Read(s)
+	if s[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return s[0].Value.Uint64()
+}
+
+// WriteTo writes the report as text: the totals followed by a table of
+// components and a table of node types.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "render: %s, %d allocs, %d bytes, %d nodes\n", r.Time, r.Allocs, r.Bytes, r.Nodes)
+	table(&b, "component", r.Components)
+	table(&b, "type", r.Types)
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func table(b *strings.Builder, heading string, entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	b.WriteByte('\n')
+	tw := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tcount\ttime\tallocs\tbytes\n", heading)
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\n", e.Name, e.Count, e.Time, e.Allocs, e.Bytes)
+	}
+	_ = tw.Flush()
+}
+
+// WriteJSON writes the report as JSON, with times in nanoseconds.
+func (r *Report) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// counter counts the bytes written through it.
+type counter struct {
+	w io.Writer
+	n int
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestRender(t *testing.T) {
+	card := func(i int) node.Node {
+		return node.Component("Card", div.New(node.Func(func() node.Node {
+			time.Sleep(time.Millisecond)
+			return span.Static("slow")
+		})))
+	}
+	page := node.Component("Page", div.New(card(1), card(2), span.Static("fast")))
+
+	var buf bytes.Buffer
+	r, err := Render(context.Background(), page, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Bytes != buf.Len() || !strings.Contains(buf.String(), "<span>slow</span>") {
+		t.Errorf("report bytes %d for output %q", r.Bytes, buf.String())
+	}
+	if r.Nodes != 10 {
+		t.Errorf("got %d nodes, want 10", r.Nodes)
+	}
+
+	if len(r.Components) != 2 || r.Components[0].Name != "Page" || r.Components[1].Name != "Card" {
+		t.Fatalf("got components %+v, want Page then Card", r.Components)
+	}
+	if c := r.Components[1]; c.Count != 2 || c.Time < 2*time.Millisecond || c.Bytes != 2*len("<div><span>slow</span></div>") {
+		t.Errorf("Card entry %+v", c)
+	}
+	if r.Types[0].Name != "node.FunctionComponent" {
+		t.Errorf("slowest type is %q, want the Func node", r.Types[0].Name)
+	}
+	if r.Types[0].Count != 2 {
+		t.Errorf("slowest type rendered %d times, want 2", r.Types[0].Count)
+	}
+
+	var text bytes.Buffer
+	if _, err := r.WriteTo(&text); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"render: ", "component  count", "Card  ", "type  ", "div.element"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("%q not found in text report:\n%s", want, text.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Nodes != r.Nodes || len(decoded.Components) != 2 || decoded.Components[1].Time != r.Components[1].Time {
+		t.Errorf("JSON round trip: got %+v", decoded)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Render(ctx, span.Static("x"), &bytes.Buffer{}); err == nil {
+		t.Error("expected the context error")
+	}
+}
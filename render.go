@@ -0,0 +1,134 @@
+package fluent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/pool"
+)
+
+// ErrPartial is returned by RenderContext when the context expired during a
+// partial render. The output, with placeholders for the skipped nodes, has
+// still been written.
+var ErrPartial = errors.New("fluent: partial render")
+
+// DeadlinePolicy controls what RenderContext does when its context is done
+// before the render completes.
+type DeadlinePolicy struct {
+	partial     bool
+	placeholder node.Node
+}
+
+// Abort discards the render and returns the context error. Nothing is written.
+// This is the default policy.
+var Abort = DeadlinePolicy{}
+
+// Partial stops evaluating Func and FuncNodes once the context is done and
+// renders placeholder in their place, so static content still reaches the
+// client. A nil placeholder renders nothing.
+//
+// Usage:
+//
+//	fluent.RenderContext(ctx, page, w, fluent.Partial(span.Static("Unavailable").Class("placeholder")))
+func Partial(placeholder node.Node) DeadlinePolicy {
+	return DeadlinePolicy{partial: true, placeholder: placeholder}
+}
+
+// RenderContext renders n to w, making ctx visible to every node in the tree
+// (see node.SessionOf). Nodes that evaluate content at render time stop doing
+// so once ctx is done; policy selects whether the render is then abandoned
+// (Abort, the default) or completed with placeholders (Partial).
+//
+// With Abort, an expired render writes nothing and returns the context error.
+// With Partial, the output is written and an error wrapping both ErrPartial
+// and the context error is returned.
+//
+// Placeholders written with node.Defer are expanded before anything is written.
+// The render runs inside any middleware registered with Use, and its output
+// is written as selected by the Config of ctx (see WithConfig).
+//
+// The buffer is grown up front to node.SizeHint(n), or to the size of
+// earlier renders from the same place when that is larger (see
+// EnableAdaptiveHints). When ctx carries a pool.Arena (see pool.WithArena)
+// the render's buffers, and those nodes obtain with node.Scratch, come from
+// the arena.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 200*time.Millisecond)
+//	defer cancel()
+//	if err := fluent.RenderContext(ctx, page, w); err != nil {
+//	    http.Error(w, "render timed out", http.StatusServiceUnavailable)
+//	}
+func RenderContext(ctx context.Context, n node.Node, w io.Writer, policy ...DeadlinePolicy) error {
+	p := Abort
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+	site, adaptive := siteOf(n)
+	return chain(RenderFunc(func(ctx context.Context, n node.Node, w io.Writer) error {
+		if !adaptive {
+			return render(ctx, n, w, p, 0, nil)
+		}
+		return render(ctx, n, w, p, hintFor(site), func(size int) { record(site, size) })
+	})).Render(ctx, n, w)
+}
+
+// render is the render at the end of the middleware chain. The buffer is
+// grown to at least hint bytes, and sized, when not nil, receives the size
+// of a completed render.
+func render(ctx context.Context, n node.Node, w io.Writer, p DeadlinePolicy, hint int, sized func(int)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cfg := ConfigFrom(ctx)
+	rctx := ctx
+	if cfg.DebugComments {
+		rctx = node.WithDebug(ctx)
+	}
+
+	arena := pool.ArenaFrom(ctx)
+	buf := arena.Get(max(node.SizeHint(n), hint))
+	defer arena.Put(buf)
+
+	session := &node.Session{
+		Context:     rctx,
+		Partial:     p.partial,
+		Placeholder: p.placeholder,
+		Arena:       arena,
+	}
+	release := node.Bind(buf, session)
+	defer release()
+	if n != nil {
+		n.RenderBuilder(buf)
+	}
+
+	err := ctx.Err()
+	if err != nil && !p.partial {
+		return err
+	}
+	if err == nil && sized != nil {
+		sized(buf.Len())
+	}
+	if session.Deferred() {
+		expanded := arena.Get(0)
+		defer arena.Put(expanded)
+		session.Expand(expanded, buf.Bytes())
+		buf = expanded
+	}
+	if cfg.transforms() {
+		if _, werr := w.Write(cfg.apply(buf.Bytes())); werr != nil {
+			return werr
+		}
+	} else if _, werr := buf.WriteTo(w); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPartial, err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/pool"
+)
+
+// page builds a tree whose first dynamic node cancels the render, so the
+// second dynamic node is evaluated after the deadline.
+func page(cancel context.CancelFunc, evaluated *int) node.Node {
+	return div.New(
+		span.Static("header"),
+		node.Func(func() node.Node {
+			*evaluated++
+			cancel()
+			return span.Static("first")
+		}),
+		node.Func(func() node.Node {
+			*evaluated++
+			return span.Static("second")
+		}),
+		span.Static("footer"),
+	)
+}
+
+func TestRenderContext(t *testing.T) {
+	var buf bytes.Buffer
+	err := fluent.RenderContext(context.Background(), div.Text("ok"), &buf)
+	if err != nil {
+		t.Fatalf("RenderContext() error = %v", err)
+	}
+	if got, want := buf.String(), `<div>ok</div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderContextAbort(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	evaluated := 0
+	var buf bytes.Buffer
+
+	err := fluent.RenderContext(ctx, page(cancel, &evaluated), &buf)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("aborted render wrote %q", buf.String())
+	}
+	if evaluated != 1 {
+		t.Errorf("evaluated %d dynamic nodes, want 1", evaluated)
+	}
+}
+
+func TestRenderContextPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	evaluated := 0
+	var buf bytes.Buffer
+
+	err := fluent.RenderContext(ctx, page(cancel, &evaluated), &buf, fluent.Partial(span.Static("…")))
+	if !errors.Is(err, fluent.ErrPartial) || !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want ErrPartial wrapping context.Canceled", err)
+	}
+	want := `<div><span>header</span><span>first</span><span>…</span><span>footer</span></div>`
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderContextExpiredBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, div.Text("x"), &buf, fluent.Partial(nil)); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestFuncOutsideSession(t *testing.T) {
+	got := string(node.Func(func() node.Node { return span.Static("x") }).Render())
+	if got != `<span>x</span>` {
+		t.Errorf("got %q, want %q", got, `<span>x</span>`)
+	}
+}
+
+// tally counts how many times it renders; its outlet reports the final count.
+type tally struct{ n *int }
+
+func (t tally) Render(w ...io.Writer) []byte    { return nil }
+func (t tally) RenderBuilder(buf *bytes.Buffer) { *t.n++ }
+func (t tally) Nodes() []node.Node              { return nil }
+func (t tally) SetAttribute(_ string, _ string) {}
+
+type tallyOutlet struct{ n *int }
+
+func (o tallyOutlet) Render(w ...io.Writer) []byte    { return nil }
+func (o tallyOutlet) Nodes() []node.Node              { return nil }
+func (o tallyOutlet) SetAttribute(_ string, _ string) {}
+func (o tallyOutlet) RenderBuilder(buf *bytes.Buffer) {
+	node.Defer(buf, func(b *bytes.Buffer) { b.WriteString(strconv.Itoa(*o.n)) })
+}
+
+func TestRenderContextDefer(t *testing.T) {
+	n := 0
+	tree := div.New(span.New(tallyOutlet{&n}), tally{&n}, tally{&n}, span.New(tallyOutlet{&n}))
+
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), tree, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `<div><span>2</span><span>2</span></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Without a session the outlet renders in place.
+	n = 0
+	if got, want := string(tree.Render()), `<div><span>0</span><span>2</span></div>`; got != want {
+		t.Errorf("without session: got %q, want %q", got, want)
+	}
+}
+
+// panicker records the buffer it renders into, then panics.
+type panicker struct{ buf **bytes.Buffer }
+
+func (p panicker) Render(w ...io.Writer) []byte    { return nil }
+func (p panicker) Nodes() []node.Node              { return nil }
+func (p panicker) SetAttribute(_ string, _ string) {}
+func (p panicker) RenderBuilder(buf *bytes.Buffer) {
+	*p.buf = buf
+	panic("render failed")
+}
+
+func TestRenderContextPanicReleasesSession(t *testing.T) {
+	var used *bytes.Buffer
+	func() {
+		defer func() { _ = recover() }()
+		_ = fluent.RenderContext(context.Background(), div.New(panicker{&used}), io.Discard)
+	}()
+	if used == nil {
+		t.Fatal("node did not render")
+	}
+	// The buffer has gone back to the pool; a later render must not see the
+	// panicked render's session.
+	if s := node.SessionOf(used); s != nil {
+		t.Error("session still bound to the buffer after a panic")
+	}
+}
+
+func TestRenderContextDeferIgnoresText(t *testing.T) {
+	n := 0
+	forged := "\x00fluent:defer:0\x00"
+	tree := div.New(span.New(tallyOutlet{&n}), span.Text(forged))
+
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(context.Background(), tree, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "<div><span>0</span><span>"+forged+"</span></div>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// scratchNode renders its child through a buffer from node.Scratch.
+type scratchNode struct{ node.Node }
+
+func (s scratchNode) RenderBuilder(buf *bytes.Buffer) {
+	tmp := node.Scratch(buf)
+	defer node.PutScratch(buf, tmp)
+	s.Node.RenderBuilder(tmp)
+	buf.Write(tmp.Bytes())
+}
+
+func TestRenderContextArena(t *testing.T) {
+	a := pool.NewArena()
+	defer a.Release()
+	ctx := pool.WithArena(context.Background(), a)
+
+	page := div.New(scratchNode{span.Text("a")}, scratchNode{span.Text("b")})
+	for range 3 {
+		var buf bytes.Buffer
+		if err := fluent.RenderContext(ctx, page, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if want := "<div><span>a</span><span>b</span></div>"; buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	}
+	// One buffer for the render and one reused by both scratch nodes,
+	// however many times the page renders.
+	if got := a.Buffers(); got != 2 {
+		t.Errorf("got %d arena buffers, want 2", got)
+	}
+}
@@ -0,0 +1,297 @@
+// Package report analyses rendered output to guide payload-size optimisation.
+//
+// Analyze attributes every byte of a page to the component or element that
+// produced it and flags repeated content that could be shared instead:
+// duplicated inline <style> and <script> blocks, repeated inline style
+// attributes and repeated class strings.
+//
+// Usage:
+//
+//	r := report.Analyze(page)
+//	fmt.Println(r)
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Entry aggregates the output of one component name or element tag.
+type Entry struct {
+	Name  string
+	Count int
+	Bytes int
+}
+
+// Subtree is the output size of a single node in the tree.
+type Subtree struct {
+	Path  string
+	Bytes int
+}
+
+// Duplicate is a piece of content emitted more than once.
+type Duplicate struct {
+	// Kind is one of "style", "script", "style-attr" or "class".
+	Kind  string
+	Value string
+	Count int
+	// Wasted is the number of bytes spent on the repeats after the first.
+	Wasted int
+}
+
+// Report is the result of analysing a node tree.
+type Report struct {
+	// Total is the rendered size of the tree in bytes.
+	Total int
+	// Components lists named components (see node.Component) by inclusive bytes.
+	Components []Entry
+	// Elements lists element tags by the bytes they produce directly: their
+	// own tags and attributes plus any text they contain.
+	Elements []Entry
+	// Largest lists every element and component subtree by inclusive bytes.
+	Largest []Subtree
+	// Duplicates lists repeated content by wasted bytes.
+	Duplicates []Duplicate
+}
+
+// Analyze renders n and builds a size report.
+// Func and FuncNodes components are rendered as a single unit, so their
+// output is attributed to the nearest enclosing element or component.
+func Analyze(n node.Node) *Report {
+	a := &analyzer{
+		components: map[string]*Entry{},
+		elements:   map[string]*Entry{},
+		out:        &bytes.Buffer{},
+	}
+	if n != nil {
+		a.measure(n, "")
+	}
+	r := &Report{
+		Total:      a.out.Len(),
+		Components: sorted(a.components),
+		Elements:   sorted(a.elements),
+		Largest:    a.subtrees,
+		Duplicates: duplicates(a.out.Bytes()),
+	}
+	sort.SliceStable(r.Largest, func(i, j int) bool { return r.Largest[i].Bytes > r.Largest[j].Bytes })
+	return r
+}
+
+// analyzer walks the tree, rendering each piece exactly once.
+type analyzer struct {
+	components map[string]*Entry
+	elements   map[string]*Entry
+	subtrees   []Subtree
+	out        *bytes.Buffer
+}
+
+// measure renders n into the analyzer output and returns the bytes written.
+func (a *analyzer) measure(n node.Node, parent string) int {
+	start := a.out.Len()
+	switch v := n.(type) {
+	case *node.NamedComponent:
+		path := join(parent, v.Name())
+		if child := v.Unwrap(); child != nil {
+			a.measure(child, path)
+		}
+		size := a.out.Len() - start
+		add(a.components, v.Name(), size)
+		a.subtrees = append(a.subtrees, Subtree{Path: path, Bytes: size})
+		return size
+	case node.Element:
+		v.RenderOpen(a.out)
+		tag, label := describe(a.out.Bytes()[start:])
+		path := join(parent, label)
+		own := a.out.Len() - start
+		for _, child := range v.Nodes() {
+			if child == nil {
+				continue
+			}
+			size := a.measure(child, path)
+			if !structural(child) {
+				own += size
+			}
+		}
+		mark := a.out.Len()
+		v.RenderClose(a.out)
+		own += a.out.Len() - mark
+		add(a.elements, tag, own)
+		size := a.out.Len() - start
+		a.subtrees = append(a.subtrees, Subtree{Path: path, Bytes: size})
+		return size
+	default:
+		n.RenderBuilder(a.out)
+		return a.out.Len() - start
+	}
+}
+
+// structural reports whether n is measured as its own entry rather than as
+// content of its parent.
+func structural(n node.Node) bool {
+	switch n.(type) {
+	case *node.NamedComponent, node.Element:
+		return true
+	}
+	return false
+}
+
+var (
+	openTag = regexp.MustCompile(`^<([a-zA-Z0-9-]+)`)
+	idAttr  = regexp.MustCompile(` id="([^"]*)"`)
+	clsAttr = regexp.MustCompile(` class="([^"]*)"`)
+)
+
+// describe extracts the tag name and a short CSS-like label from an opening tag.
+func describe(open []byte) (tag string, label string) {
+	m := openTag.FindSubmatch(open)
+	if m == nil {
+		return "?", "?"
+	}
+	tag = string(m[1])
+	label = tag
+	if id := idAttr.FindSubmatch(open); id != nil {
+		label += "#" + string(id[1])
+	} else if cls := clsAttr.FindSubmatch(open); cls != nil {
+		if f := strings.Fields(string(cls[1])); len(f) > 0 {
+			label += "." + f[0]
+		}
+	}
+	return tag, label
+}
+
+func join(parent, label string) string {
+	if parent == "" {
+		return label
+	}
+	return parent + " > " + label
+}
+
+func add(m map[string]*Entry, name string, size int) {
+	e, ok := m[name]
+	if !ok {
+		e = &Entry{Name: name}
+		m[name] = e
+	}
+	e.Count++
+	e.Bytes += size
+}
+
+func sorted(m map[string]*Entry) []Entry {
+	out := make([]Entry, 0, len(m))
+	for _, e := range m {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+var (
+	styleBlock  = regexp.MustCompile(`(?s)<style[^>]*>(.*?)</style>`)
+	scriptBlock = regexp.MustCompile(`(?s)<script[^>]*>(.+?)</script>`)
+	styleAttr   = regexp.MustCompile(` style="([^"]+)"`)
+)
+
+// duplicates finds content that appears more than once in out.
+func duplicates(out []byte) []Duplicate {
+	var dups []Duplicate
+	scan := func(kind string, re *regexp.Regexp) {
+		counts := map[string]int{}
+		var order []string
+		for _, m := range re.FindAllSubmatch(out, -1) {
+			v := string(m[1])
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+			if counts[v] == 0 {
+				order = append(order, v)
+			}
+			counts[v]++
+		}
+		for _, v := range order {
+			if c := counts[v]; c > 1 {
+				dups = append(dups, Duplicate{Kind: kind, Value: v, Count: c, Wasted: (c - 1) * len(v)})
+			}
+		}
+	}
+	scan("style", styleBlock)
+	scan("script", scriptBlock)
+	scan("style-attr", styleAttr)
+	scan("class", clsAttr)
+	sort.SliceStable(dups, func(i, j int) bool { return dups[i].Wasted > dups[j].Wasted })
+	return dups
+}
+
+// limit is the number of rows shown per section by String.
+const limit = 10
+
+// String formats the report as plain text tables.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Total: %d bytes\n", r.Total)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	section := func(title string) {
+		tw.Flush()
+		fmt.Fprintf(&b, "\n%s\n", title)
+	}
+	if len(r.Components) > 0 {
+		section("Components")
+		for _, e := range head(r.Components) {
+			fmt.Fprintf(tw, "  %s\t%d\t%d bytes\t%s\n", e.Name, e.Count, e.Bytes, percent(e.Bytes, r.Total))
+		}
+	}
+	if len(r.Elements) > 0 {
+		section("Elements")
+		for _, e := range head(r.Elements) {
+			fmt.Fprintf(tw, "  %s\t%d\t%d bytes\t%s\n", e.Name, e.Count, e.Bytes, percent(e.Bytes, r.Total))
+		}
+	}
+	if len(r.Largest) > 0 {
+		section("Largest subtrees")
+		for _, s := range head(r.Largest) {
+			fmt.Fprintf(tw, "  %s\t%d bytes\t%s\n", s.Path, s.Bytes, percent(s.Bytes, r.Total))
+		}
+	}
+	if len(r.Duplicates) > 0 {
+		section("Duplicates")
+		for _, d := range head(r.Duplicates) {
+			fmt.Fprintf(tw, "  %s\t%q\t%dx\t%d bytes wasted\n", d.Kind, preview(d.Value), d.Count, d.Wasted)
+		}
+	}
+	tw.Flush()
+	return b.String()
+}
+
+func head[T any](s []T) []T {
+	if len(s) > limit {
+		return s[:limit]
+	}
+	return s
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+func preview(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) > 40 {
+		return string([]rune(s)[:37]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/h3"
+	"github.com/jpl-au/fluent/html5/style"
+	"github.com/jpl-au/fluent/node"
+)
+
+func card(name string) node.Node {
+	return node.Component("Card", div.New(
+		style.RawText(".card{padding:1rem}"),
+		h3.Text(name).Class("card-title text-lg"),
+	).Class("card"))
+}
+
+func TestAnalyze(t *testing.T) {
+	page := body.New(
+		div.New(card("One"), card("Two"), card("Three")).ID("grid"),
+		node.Func(func() node.Node { return div.Static("footer") }),
+	)
+	r := Analyze(page)
+
+	if want := len(page.Render()); r.Total != want {
+		t.Errorf("Total = %d, want %d", r.Total, want)
+	}
+
+	if len(r.Components) != 1 || r.Components[0].Name != "Card" || r.Components[0].Count != 3 {
+		t.Fatalf("Components = %+v, want three Card entries", r.Components)
+	}
+
+	sum := 0
+	for _, e := range r.Elements {
+		sum += e.Bytes
+	}
+	if sum != r.Total {
+		t.Errorf("element bytes sum to %d, want Total %d", sum, r.Total)
+	}
+
+	if r.Largest[0].Path != "body" {
+		t.Errorf("Largest[0] = %+v, want body", r.Largest[0])
+	}
+	found := false
+	for _, s := range r.Largest {
+		if s.Path == "body > div#grid > Card > div.card" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Largest missing card path: %+v", r.Largest)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	r := Analyze(div.New(card("One"), card("Two")))
+
+	kinds := map[string]Duplicate{}
+	for _, d := range r.Duplicates {
+		kinds[d.Kind+":"+d.Value] = d
+	}
+	if d := kinds["style:.card{padding:1rem}"]; d.Count != 2 || d.Wasted != len(".card{padding:1rem}") {
+		t.Errorf("style duplicate = %+v", d)
+	}
+	if d := kinds["class:card-title text-lg"]; d.Count != 2 {
+		t.Errorf("class duplicate = %+v", d)
+	}
+}
+
+func TestString(t *testing.T) {
+	out := Analyze(div.New(card("One"), card("Two"))).String()
+	for _, want := range []string{"Total:", "Components", "Card", "Duplicates", "style"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("String() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	s := strings.Repeat("é", 50)
+	got := preview(s)
+	if !utf8.ValidString(got) || got != strings.Repeat("é", 37)+"..." {
+		t.Errorf("preview = %q", got)
+	}
+	if got := preview("short  text"); got != "short text" {
+		t.Errorf("preview = %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package rewrite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// ImagePolicy is a ready-made set of rules for <img> elements. Defaults are
+// added to images that do not set the attribute themselves, and problems
+// are reported as *ImageError to the render's assertion handler (see
+// node.WithAssertions), so development and test renders flag them while
+// production renders only pay for the defaults.
+type ImagePolicy struct {
+	// Loading is added as the loading attribute, such as "lazy". Images
+	// with fetchpriority="high" are left alone, as they are needed for the
+	// first paint. Empty adds nothing.
+	Loading string
+	// Decoding is added as the decoding attribute, such as "async". Empty
+	// adds nothing.
+	Decoding string
+	// RequireAlt reports images without an alt attribute. Decorative images
+	// should have an empty one.
+	RequireAlt bool
+	// MaxWidth and MaxHeight report images whose width or height attribute
+	// is larger, in pixels, which usually means an original has been served
+	// in place of a resized copy. Zero does not check.
+	MaxWidth, MaxHeight int
+}
+
+// DefaultImagePolicy lazy loads and asynchronously decodes images, requires
+// alternative text and reports images over 4096 pixels in either dimension.
+var DefaultImagePolicy = ImagePolicy{
+	Loading:    "lazy",
+	Decoding:   "async",
+	RequireAlt: true,
+	MaxWidth:   4096,
+	MaxHeight:  4096,
+}
+
+// ImageError reports an image that breaks an ImagePolicy.
+type ImageError struct {
+	// Src is the image's src attribute.
+	Src     string
+	Problem string
+}
+
+func (e *ImageError) Error() string {
+	return fmt.Sprintf("rewrite: image %q %s", e.Src, e.Problem)
+}
+
+// Rule returns the policy as a Rule.
+//
+// Usage:
+//
+//	fluent.Use(rewrite.Middleware(rewrite.DefaultImagePolicy.Rule()))
+func (p ImagePolicy) Rule() Rule {
+	return func(ctx context.Context, t *Tag) {
+		if t.Name != "img" {
+			return
+		}
+		if p.Loading != "" {
+			if pr, _ := t.Get("fetchpriority"); pr != "high" {
+				if _, ok := t.Get("loading"); !ok {
+					t.Set("loading", p.Loading)
+				}
+			}
+		}
+		if p.Decoding != "" {
+			if _, ok := t.Get("decoding"); !ok {
+				t.Set("decoding", p.Decoding)
+			}
+		}
+
+		src, _ := t.Get("src")
+		if p.RequireAlt {
+			if _, ok := t.Get("alt"); !ok {
+				node.FailContext(ctx, &ImageError{Src: src, Problem: "has no alt attribute"})
+			}
+		}
+		for _, dim := range []struct {
+			key string
+			max int
+		}{{"width", p.MaxWidth}, {"height", p.MaxHeight}} {
+			if dim.max <= 0 {
+				continue
+			}
+			v, _ := t.Get(dim.key)
+			if px, err := strconv.Atoi(v); err == nil && px > dim.max {
+				node.FailContext(ctx, &ImageError{Src: src, Problem: fmt.Sprintf("is %s %d, over the limit of %d", dim.key, px, dim.max)})
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package rewrite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestImagePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		want   string
+		errors []string
+	}{
+		{"defaults", `<img src="/a.png" alt="A">`, `<img src="/a.png" alt="A" loading="lazy" decoding="async">`, nil},
+		{"kept", `<img src="/a.png" alt="" loading="eager" decoding="sync">`, `<img src="/a.png" alt="" loading="eager" decoding="sync">`, nil},
+		{"priority", `<img src="/hero.png" alt="" fetchpriority="high" />`, `<img src="/hero.png" alt fetchpriority="high" decoding="async" />`, nil},
+		{"no alt", `<img src="/a.png" loading="lazy" decoding="async">`, `<img src="/a.png" loading="lazy" decoding="async">`,
+			[]string{`rewrite: image "/a.png" has no alt attribute`}},
+		{"oversized", `<img src="/big.jpg" alt="" width="6000" height="4000" loading="lazy" decoding="async">`,
+			`<img src="/big.jpg" alt="" width="6000" height="4000" loading="lazy" decoding="async">`,
+			[]string{`rewrite: image "/big.jpg" is width 6000, over the limit of 4096`}},
+		{"other elements", `<p>text</p>`, `<p>text</p>`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			ctx := node.WithAssertions(context.Background(), func(err error) {
+				var ie *ImageError
+				if !errors.As(err, &ie) {
+					t.Errorf("error %v is not an *ImageError", err)
+				}
+				got = append(got, err.Error())
+			})
+			out := string(Apply(ctx, []byte(tt.src), DefaultImagePolicy.Rule()))
+			if out != tt.want {
+				t.Errorf("got %q, want %q", out, tt.want)
+			}
+			if len(got) != len(tt.errors) {
+				t.Fatalf("errors = %q, want %q", got, tt.errors)
+			}
+			for i := range got {
+				if got[i] != tt.errors[i] {
+					t.Errorf("error %d = %q, want %q", i, got[i], tt.errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImagePolicyWithoutAssertions(t *testing.T) {
+	// Problems are only reported to renders checking assertions.
+	out := Apply(context.Background(), []byte(`<img src="/a.png">`), ImagePolicy{RequireAlt: true}.Rule())
+	if got, want := string(out), `<img src="/a.png">`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
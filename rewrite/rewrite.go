@@ -0,0 +1,254 @@
+// Package rewrite applies attribute rules to every element of a render, so a
+// policy such as a CSP nonce on every script, loading="lazy" on every image
+// or a prefix on every id is stated once rather than on each element.
+//
+// Rules run on the rendered output: each start tag is parsed, passed to the
+// rules, and written back only if a rule changed it. They therefore see every
+// element whatever node produced it, including the output of node.Func and
+// raw HTML, and leave the tree itself untouched.
+//
+// Usage:
+//
+//	// Every render.
+//	fluent.Use(rewrite.Middleware(
+//	    rewrite.Default("img", "loading", "lazy"),
+//	    rewrite.Nonce(nonceFrom),
+//	))
+//
+//	// One subtree.
+//	rewrite.Wrap(widget, rewrite.PrefixIDs("w1-"))
+package rewrite
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Tag is the start tag of an element as seen by a Rule. Attribute values
+// are unescaped; they are escaped again when the tag is written.
+type Tag struct {
+	// Name is the lowercase tag name.
+	Name  string
+	Attrs []node.Attribute
+
+	changed bool
+}
+
+// Get returns the value of the attribute key, matched case-insensitively.
+func (t *Tag) Get(key string) (string, bool) {
+	for _, a := range t.Attrs {
+		if strings.EqualFold(a.Key, key) {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set sets the attribute key, replacing any existing value.
+func (t *Tag) Set(key, value string) {
+	t.changed = true
+	for i := range t.Attrs {
+		if strings.EqualFold(t.Attrs[i].Key, key) {
+			t.Attrs[i].Value = value
+			return
+		}
+	}
+	t.Attrs = append(t.Attrs, node.Attribute{Key: key, Value: value})
+}
+
+// Remove deletes the attribute key if it is present.
+func (t *Tag) Remove(key string) {
+	for i := range t.Attrs {
+		if strings.EqualFold(t.Attrs[i].Key, key) {
+			t.Attrs = append(t.Attrs[:i], t.Attrs[i+1:]...)
+			t.changed = true
+			return
+		}
+	}
+}
+
+// Rule inspects and changes the start tag of an element. ctx is the context
+// of the render.
+type Rule func(ctx context.Context, t *Tag)
+
+// Apply returns src with rules applied to each start tag. src is returned
+// as it is when no rule changes anything.
+func Apply(ctx context.Context, src []byte, rules ...Rule) []byte {
+	if len(rules) == 0 {
+		return src
+	}
+	var out []byte
+	last := 0
+	for _, tok := range markup.Tokenize(string(src)) {
+		if tok.Type != markup.StartTagToken && tok.Type != markup.SelfClosingTagToken {
+			continue
+		}
+		t := &Tag{Name: tok.Data, Attrs: make([]node.Attribute, len(tok.Attrs))}
+		for i, a := range tok.Attrs {
+			t.Attrs[i] = node.Attribute{Key: a.Name, Value: a.Val}
+		}
+		for _, rule := range rules {
+			rule(ctx, t)
+		}
+		if !t.changed {
+			continue
+		}
+		if out == nil {
+			out = make([]byte, 0, len(src)+len(src)/8)
+		}
+		out = append(out, src[last:tok.Offset]...)
+		out = write(out, tok.Raw[1:1+len(t.Name)], t.Attrs, strings.HasSuffix(tok.Raw, "/>"))
+		last = tok.Offset + len(tok.Raw)
+	}
+	if out == nil {
+		return src
+	}
+	return append(out, src[last:]...)
+}
+
+// write appends a start tag. Attributes with an empty value are written as
+// bare names, as fluent writes boolean attributes.
+func write(out []byte, name string, attrs []node.Attribute, selfClosing bool) []byte {
+	out = append(out, '<')
+	out = append(out, name...)
+	for _, a := range attrs {
+		out = append(out, ' ')
+		out = append(out, a.Key...)
+		if a.Value != "" {
+			out = append(out, `="`...)
+			out = append(out, escape.String(a.Value)...)
+			out = append(out, '"')
+		}
+	}
+	if selfClosing {
+		return append(out, " />"...)
+	}
+	return append(out, '>')
+}
+
+// Middleware returns fluent middleware applying rules to the output of
+// every render (see fluent.Use).
+func Middleware(rules ...Rule) fluent.Middleware {
+	return fluent.Transform(func(ctx context.Context, out []byte) []byte {
+		return Apply(ctx, out, rules...)
+	})
+}
+
+// Wrap returns a node rendering n with rules applied to its output.
+func Wrap(n node.Node, rules ...Rule) node.Node {
+	return &wrapped{node: n, rules: rules}
+}
+
+type wrapped struct {
+	node  node.Node
+	rules []Rule
+}
+
+func (w *wrapped) Render(wr ...io.Writer) []byte {
+	return node.Render(w, wr...)
+}
+
+// RenderBuilder renders the wrapped node into a scratch buffer, in the same
+// render session as buf, and writes the rewritten output to buf.
+func (w *wrapped) RenderBuilder(buf *bytes.Buffer) {
+	if w.node == nil {
+		return
+	}
+	scratch := node.Scratch(buf)
+	defer node.PutScratch(buf, scratch)
+	if s := node.SessionOf(buf); s != nil {
+		release := node.Bind(scratch, s)
+		defer release()
+	}
+	w.node.RenderBuilder(scratch)
+	buf.Write(Apply(node.Context(buf), scratch.Bytes(), w.rules...))
+}
+
+// Nodes returns the wrapped node as the only child.
+func (w *wrapped) Nodes() []node.Node {
+	if w.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{w.node}
+}
+
+// SetAttribute forwards to the wrapped node.
+func (w *wrapped) SetAttribute(key string, value string) {
+	if w.node != nil {
+		w.node.SetAttribute(key, value)
+	}
+}
+
+// Default returns a rule setting key to value on elements named tag that do
+// not already have it. A tag of "*" matches every element.
+//
+// Usage:
+//
+//	rewrite.Default("img", "loading", "lazy")
+func Default(tag, key, value string) Rule {
+	return func(_ context.Context, t *Tag) {
+		if tag != "*" && t.Name != tag {
+			return
+		}
+		if _, ok := t.Get(key); !ok {
+			t.Set(key, value)
+		}
+	}
+}
+
+// Nonce returns a rule adding the nonce returned by nonce for the render's
+// context to every script and style element, for a nonce-based Content
+// Security Policy. Nothing is added when nonce returns "". Existing nonces
+// are kept.
+//
+// Usage:
+//
+//	rewrite.Nonce(func(ctx context.Context) string {
+//	    nonce, _ := fluent.Value[string](ctx, nonceKey{})
+//	    return nonce
+//	})
+func Nonce(nonce func(ctx context.Context) string) Rule {
+	return func(ctx context.Context, t *Tag) {
+		if t.Name != "script" && t.Name != "style" {
+			return
+		}
+		if _, ok := t.Get("nonce"); ok {
+			return
+		}
+		if n := nonce(ctx); n != "" {
+			t.Set("nonce", n)
+		}
+	}
+}
+
+// idRefs lists the attributes holding space-separated references to ids.
+var idRefs = []string{"for", "aria-labelledby", "aria-describedby", "aria-controls", "aria-owns", "headers", "list", "form"}
+
+// PrefixIDs returns a rule adding prefix to every id and to the attributes
+// that refer to ids, such as a label's for and aria-labelledby, so the
+// references still resolve. Fragment links (href="#id") are not changed.
+func PrefixIDs(prefix string) Rule {
+	return func(_ context.Context, t *Tag) {
+		if id, ok := t.Get("id"); ok && id != "" {
+			t.Set("id", prefix+id)
+		}
+		for _, key := range idRefs {
+			refs, ok := t.Get(key)
+			if !ok || refs == "" {
+				continue
+			}
+			fields := strings.Fields(refs)
+			for i, f := range fields {
+				fields[i] = prefix + f
+			}
+			t.Set(key, strings.Join(fields, " "))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rewrite
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/label"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+type nonceKey struct{}
+
+func nonceFrom(ctx context.Context) string {
+	nonce, _ := fluent.Value[string](ctx, nonceKey{})
+	return nonce
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		rules []Rule
+		want  string
+	}{
+		{"default", `<div><img src="a.png" alt=""><img src="b.png" loading="eager"></div>`,
+			[]Rule{Default("img", "loading", "lazy")},
+			`<div><img src="a.png" alt loading="lazy"><img src="b.png" loading="eager"></div>`},
+		{"any element", `<p>x</p><br />`, []Rule{Default("*", "data-x", "1")},
+			`<p data-x="1">x</p><br data-x="1" />`},
+		{"prefix ids", `<label for="name">Name</label><input id="name" aria-describedby="hint err"><a href="#name">`,
+			[]Rule{PrefixIDs("f-")},
+			`<label for="f-name">Name</label><input id="f-name" aria-describedby="f-hint f-err"><a href="#name">`},
+		{"escaping", `<p title="a &amp; b">`, []Rule{Default("p", "data-v", `"<>"`)},
+			`<p title="a &amp; b" data-v="&#34;&lt;&gt;&#34;">`},
+		{"remove", `<div onclick="x()" class="c">`, []Rule{func(_ context.Context, t *Tag) { t.Remove("onclick") }},
+			`<div class="c">`},
+		{"script content", `<script>if (a <b) {}</script>`, []Rule{Default("b", "x", "1")},
+			`<script>if (a <b) {}</script>`},
+		{"svg case", `<svg viewBox="0 0 1 1"></svg>`, []Rule{Default("svg", "role", "img")},
+			`<svg viewBox="0 0 1 1" role="img"></svg>`},
+		{"no rules", `<p>`, nil, `<p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Apply(context.Background(), []byte(tt.src), tt.rules...))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	defer fluent.ResetMiddleware()
+	fluent.Use(Middleware(Nonce(nonceFrom), Default("img", "loading", "lazy")))
+
+	page := div.New(
+		script.New().Text("run()"),
+		node.Func(func() node.Node { return img.New().Src("a.png").Alt("A") }),
+		text.RawText(`<script src="x.js" nonce="fixed"></script>`),
+	)
+	var buf bytes.Buffer
+	ctx := fluent.WithValue(context.Background(), nonceKey{}, "r4nd")
+	if err := fluent.RenderContext(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<div><script nonce="r4nd">run()</script><img src="a.png" alt="A" loading="lazy" /><script src="x.js" nonce="fixed"></script></div>`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	field := div.New(label.New().For("q").Text("Search"), input.New().ID("q"))
+	got := string(div.New(Wrap(field, PrefixIDs("s1-")), input.New().ID("q")).Render())
+	want := `<div><div><label for="s1-q">Search</label><input id="s1-q" /></div><input id="q" /></div>`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWrapKeepsContext(t *testing.T) {
+	field := node.FuncContext(func(ctx context.Context) node.Node {
+		return input.New().Value(nonceFrom(ctx))
+	})
+	ctx := fluent.WithValue(context.Background(), nonceKey{}, "n1")
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, Wrap(field, PrefixIDs("s1-")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `<input value="n1" />`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package rollout
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+// Policy chooses the version of a component to render for a subject.
+type Policy interface {
+	// Choose returns the version for s, or false to defer to the stable
+	// version. Unregistered versions are ignored.
+	Choose(component string, s *Subject) (string, bool)
+}
+
+// PolicyFunc adapts a function to Policy.
+type PolicyFunc func(component string, s *Subject) (string, bool)
+
+// Choose calls f.
+func (f PolicyFunc) Choose(component string, s *Subject) (string, bool) {
+	return f(component, s)
+}
+
+// First returns a policy that applies policies in order and uses the first
+// choice made.
+func First(policies ...Policy) Policy {
+	return PolicyFunc(func(component string, s *Subject) (string, bool) {
+		for _, p := range policies {
+			if v, ok := p.Choose(component, s); ok {
+				return v, true
+			}
+		}
+		return "", false
+	})
+}
+
+// Percentage selects version for percent of subjects, from 0 to 100. Subjects
+// are bucketed by a hash of their ID and the component name, so a subject
+// sees the same version on every request and raising the percentage only adds
+// subjects. Subjects without an ID are never selected.
+func Percentage(version string, percent float64) Policy {
+	return PolicyFunc(func(component string, s *Subject) (string, bool) {
+		if s.ID == "" {
+			return "", false
+		}
+		return version, float64(bucket(component, s.ID)) < percent*100
+	})
+}
+
+// bucket maps a subject to one of 10000 buckets, for 0.01% granularity.
+func bucket(component, id string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(component))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	return h.Sum32() % 10000
+}
+
+// Header selects the version named by the request header, such as
+// "X-Canary: v2", for testing a version on demand.
+func Header(name string) Policy {
+	return PolicyFunc(func(_ string, s *Subject) (string, bool) {
+		v := s.Header.Get(name)
+		return v, v != ""
+	})
+}
+
+// Cohort selects version for subjects in any of the cohorts.
+func Cohort(version string, cohorts ...string) Policy {
+	return PolicyFunc(func(_ string, s *Subject) (string, bool) {
+		for _, c := range s.Cohorts {
+			if slices.Contains(cohorts, c) {
+				return version, true
+			}
+		}
+		return "", false
+	})
+}
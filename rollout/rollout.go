@@ -0,0 +1,289 @@
+// Package rollout renders one of several versions of a component per request,
+// so a new implementation can be rolled out incrementally and compared with
+// the one it replaces.
+//
+// Versions of a named component are registered side by side. A Policy picks
+// the version for each request from the Subject in the render context, and
+// every render is reported to a Metrics sink labelled with the version that
+// ran. Without a subject, or when no policy applies, the stable version
+// renders.
+//
+// Usage:
+//
+//	stats := rollout.NewStats()
+//	reg := rollout.NewRegistry()
+//	reg.Metrics = stats
+//	reg.Register("Checkout", "v1", checkout.V1)
+//	reg.Register("Checkout", "v2", checkout.V2)
+//	reg.Policy("Checkout", rollout.First(
+//	    rollout.Header("X-Canary"),
+//	    rollout.Cohort("v2", "staff"),
+//	    rollout.Percentage("v2", 5),
+//	))
+//
+//	page := body.New(reg.Component("Checkout"))
+//
+//	mux.Handle("/", rollout.Middleware(func(r *http.Request) *rollout.Subject {
+//	    u := auth.User(r)
+//	    return &rollout.Subject{ID: u.ID, Cohorts: u.Groups}
+//	})(handler))
+//	fluent.RenderContext(r.Context(), page, w)
+package rollout
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Subject identifies who a render is for.
+type Subject struct {
+	// ID keeps a subject on the same version across requests, such as a user
+	// or session ID. Percentage rollouts bucket by it.
+	ID string
+	// Cohorts lists the groups the subject belongs to, such as "staff" or "beta".
+	Cohorts []string
+	// Header holds the request headers, for header-based selection.
+	Header http.Header
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a context whose renders select versions for s.
+func WithSubject(ctx context.Context, s *Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+// SubjectFrom returns the subject carried by ctx, or nil.
+func SubjectFrom(ctx context.Context) *Subject {
+	s, _ := ctx.Value(subjectKey{}).(*Subject)
+	return s
+}
+
+// Middleware adds a Subject built by identify to each request's context. The
+// subject's Header is set to the request headers when identify leaves it nil.
+func Middleware(identify func(r *http.Request) *Subject) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := identify(r)
+			if s == nil {
+				s = &Subject{}
+			}
+			if s.Header == nil {
+				s.Header = r.Header
+			}
+			next.ServeHTTP(w, r.WithContext(WithSubject(r.Context(), s)))
+		})
+	}
+}
+
+// Metrics receives a report of every versioned render.
+type Metrics interface {
+	Rendered(component, version string, d time.Duration)
+}
+
+// MetricsFunc adapts a function to Metrics.
+type MetricsFunc func(component, version string, d time.Duration)
+
+// Rendered calls f.
+func (f MetricsFunc) Rendered(component, version string, d time.Duration) {
+	f(component, version, d)
+}
+
+// Stat aggregates the renders of one component version.
+type Stat struct {
+	Component string
+	Version   string
+	Count     int64
+	Total     time.Duration
+}
+
+// Mean returns the mean render duration.
+func (s Stat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// Stats is a Metrics that aggregates renders by component and version, for
+// comparing a canary with the stable version. It is safe for concurrent use.
+type Stats struct {
+	mu    sync.Mutex
+	stats map[[2]string]*Stat
+}
+
+// NewStats creates an empty Stats.
+func NewStats() *Stats {
+	return &Stats{stats: map[[2]string]*Stat{}}
+}
+
+// Rendered records a render.
+func (s *Stats) Rendered(component, version string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := [2]string{component, version}
+	st := s.stats[k]
+	if st == nil {
+		st = &Stat{Component: component, Version: version}
+		s.stats[k] = st
+	}
+	st.Count++
+	st.Total += d
+}
+
+// Snapshot returns the current stats ordered by component and version.
+func (s *Stats) Snapshot() []Stat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Stat, 0, len(s.stats))
+	for _, st := range s.stats {
+		out = append(out, *st)
+	}
+	slices.SortFunc(out, func(a, b Stat) int {
+		return cmp.Or(cmp.Compare(a.Component, b.Component), cmp.Compare(a.Version, b.Version))
+	})
+	return out
+}
+
+// Registry holds the versions of named components. It is safe for concurrent
+// use; versions and policies may change while pages render.
+type Registry struct {
+	// Metrics, when set, receives every render.
+	Metrics Metrics
+
+	mu         sync.RWMutex
+	components map[string]*component
+}
+
+type component struct {
+	stable   string
+	versions map[string]func() node.Node
+	order    []string
+	policy   Policy
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{components: map[string]*component{}}
+}
+
+// Register adds a version of the named component. The first version
+// registered is the stable version until Stable is called.
+func (r *Registry) Register(name, version string, fn func() node.Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.components[name]
+	if c == nil {
+		c = &component{stable: version, versions: map[string]func() node.Node{}}
+		r.components[name] = c
+	}
+	if _, ok := c.versions[version]; !ok {
+		c.order = append(c.order, version)
+	}
+	c.versions[version] = fn
+}
+
+// Stable sets the version rendered when no policy selects another, such as
+// when promoting a canary or rolling back.
+func (r *Registry) Stable(name, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c := r.components[name]; c != nil {
+		if _, ok := c.versions[version]; ok {
+			c.stable = version
+		}
+	}
+}
+
+// Policy sets the rollout policy of the named component. A nil policy always
+// renders the stable version.
+func (r *Registry) Policy(name string, p Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c := r.components[name]; c != nil {
+		c.policy = p
+	}
+}
+
+// Versions returns the registered versions of the named component in
+// registration order.
+func (r *Registry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if c := r.components[name]; c != nil {
+		return slices.Clone(c.order)
+	}
+	return nil
+}
+
+// Select returns the version of the named component to render for s and its
+// constructor. It returns false when the component is not registered.
+func (r *Registry) Select(name string, s *Subject) (string, func() node.Node, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := r.components[name]
+	if c == nil {
+		return "", nil, false
+	}
+	version := c.stable
+	if c.policy != nil && s != nil {
+		if v, ok := c.policy.Choose(name, s); ok {
+			if _, known := c.versions[v]; known {
+				version = v
+			}
+		}
+	}
+	return version, c.versions[version], true
+}
+
+// Component returns a node rendering the version of the named component
+// selected for the subject in the render context. An unregistered name
+// renders nothing.
+func (r *Registry) Component(name string) node.Node {
+	return &versioned{registry: r, name: name}
+}
+
+type versioned struct {
+	registry *Registry
+	name     string
+}
+
+func (v *versioned) Render(w ...io.Writer) []byte {
+	return node.Render(v, w...)
+}
+
+// RenderBuilder selects a version and renders it as a node.Component named
+// "name@version", so tooling attributes output to the version that ran.
+func (v *versioned) RenderBuilder(buf *bytes.Buffer) {
+	version, fn, ok := v.registry.Select(v.name, SubjectFrom(node.Context(buf)))
+	if !ok || fn == nil {
+		return
+	}
+	start := time.Now()
+	node.Component(v.name+"@"+version, fn()).RenderBuilder(buf)
+	if m := v.registry.Metrics; m != nil {
+		m.Rendered(v.name, version, time.Since(start))
+	}
+}
+
+// Nodes returns an empty slice as the version is only chosen at render time.
+func (v *versioned) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the rendered version depends on the render context.
+func (v *versioned) Dynamic() bool {
+	return true
+}
+
+func (v *versioned) SetAttribute(_ string, _ string) {
+	// versioned does not support attributes
+}
@@ -0,0 +1,102 @@
+package rollout
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/node"
+)
+
+func registry() (*Registry, *Stats) {
+	stats := NewStats()
+	r := NewRegistry()
+	r.Metrics = stats
+	r.Register("Card", "v1", func() node.Node { return div.Static("one") })
+	r.Register("Card", "v2", func() node.Node { return div.Static("two") })
+	r.Policy("Card", First(Header("X-Canary"), Cohort("v2", "staff"), Percentage("v2", 50)))
+	return r, stats
+}
+
+func render(t *testing.T, r *Registry, s *Subject) string {
+	t.Helper()
+	var buf bytes.Buffer
+	ctx := context.Background()
+	if s != nil {
+		ctx = WithSubject(ctx, s)
+	}
+	if err := fluent.RenderContext(ctx, r.Component("Card"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestSelect(t *testing.T) {
+	r, stats := registry()
+	tests := []struct {
+		name    string
+		subject *Subject
+		want    string
+	}{
+		{"no subject", nil, "<div>one</div>"},
+		{"header", &Subject{Header: http.Header{"X-Canary": {"v2"}}}, "<div>two</div>"},
+		{"unknown header version", &Subject{Header: http.Header{"X-Canary": {"v9"}}}, "<div>one</div>"},
+		{"cohort", &Subject{Cohorts: []string{"staff"}}, "<div>two</div>"},
+		{"anonymous", &Subject{}, "<div>one</div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, r, tt.subject); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	snap := stats.Snapshot()
+	if len(snap) != 2 || snap[0].Version != "v1" || snap[0].Count != 3 || snap[1].Count != 2 {
+		t.Errorf("stats: got %+v", snap)
+	}
+
+	r.Stable("Card", "v2")
+	if got := render(t, r, nil); got != "<div>two</div>" {
+		t.Errorf("after promotion: got %q", got)
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	r, _ := registry()
+	canary := 0
+	for i := range 1000 {
+		s := &Subject{ID: "user-" + strconv.Itoa(i)}
+		got := render(t, r, s)
+		if got != render(t, r, s) {
+			t.Fatalf("subject %d switched versions", i)
+		}
+		if got == "<div>two</div>" {
+			canary++
+		}
+	}
+	if canary < 430 || canary > 570 {
+		t.Errorf("got %d of 1000 on the canary, want about 500", canary)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	r, _ := registry()
+	h := Middleware(func(req *http.Request) *Subject {
+		return &Subject{ID: req.URL.Query().Get("u")}
+	})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = fluent.RenderContext(req.Context(), r.Component("Card"), w)
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Canary", "v2")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Body.String() != "<div>two</div>" {
+		t.Errorf("got %q", rec.Body.String())
+	}
+}
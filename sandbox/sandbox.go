@@ -0,0 +1,272 @@
+// Package sandbox renders components contributed by third parties, such as
+// plugins or marketplace extensions, without letting them endanger the host
+// page.
+//
+// A sandboxed component is built and rendered in isolation: a panic is
+// recovered, and the render is abandoned once it exceeds its time limit or
+// its output grows past the size limit. Output that completes is then restricted to
+// a safe subset of HTML. Node types cannot tell escaped text from raw markup, so
+// the check is made on the rendered output: elements and attributes outside the
+// allowlist are removed, scripts, styles and embedded documents are dropped
+// with their content, URLs are limited to safe schemes and unbalanced tags are
+// repaired so a plugin cannot close the host's elements. Raw text, inline
+// scripts and event handlers therefore never reach the page.
+//
+// A component that fails renders the sandbox's Fallback and is reported to
+// OnError, so one broken plugin degrades to an empty slot rather than a broken
+// page.
+//
+// Usage:
+//
+//	box := sandbox.New()
+//	box.OnError = func(name string, err error) { log.Printf("plugin %s: %v", name, err) }
+//	box.Fallback = p.Static("This widget is unavailable.")
+//
+//	page := div.New(
+//	    box.Component("weather", plugins.Weather),
+//	)
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Defaults for the limits of a new Sandbox.
+const (
+	DefaultTimeout  = 100 * time.Millisecond
+	DefaultMaxBytes = 256 << 10
+)
+
+var (
+	// ErrTimeout is returned when a component does not finish rendering
+	// within the sandbox's Timeout.
+	ErrTimeout = errors.New("sandbox: render timed out")
+	// ErrTooLarge is returned when a component's output exceeds MaxBytes.
+	ErrTooLarge = errors.New("sandbox: output exceeds limit")
+)
+
+// PanicError reports a panic raised while building or rendering a component.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("sandbox: component panicked: %v", e.Value)
+}
+
+// Sandbox holds the limits and allowlists applied to untrusted components.
+// Configure it before use; it is safe for concurrent use afterwards.
+type Sandbox struct {
+	// Timeout limits how long a component may take to build and render.
+	Timeout time.Duration
+	// MaxBytes limits the size of a component's output before sanitisation.
+	// The render stops at the first node that takes it over the limit.
+	MaxBytes int
+	// Elements lists the permitted elements. Others are removed and their
+	// content kept, except for those in Drop.
+	Elements map[string]bool
+	// Drop lists elements removed together with their content.
+	Drop map[string]bool
+	// Attributes lists the permitted attributes. An entry ending in "*"
+	// permits every attribute with that prefix, such as "data-chart-*".
+	// aria-* attributes are always permitted.
+	Attributes map[string]bool
+	// Fallback renders in place of a component that fails. Nil renders nothing.
+	Fallback node.Node
+	// OnError, when set, is called for each component that fails.
+	OnError func(component string, err error)
+}
+
+// New returns a sandbox with the default limits and allowlists.
+func New() *Sandbox {
+	return &Sandbox{
+		Timeout:    DefaultTimeout,
+		MaxBytes:   DefaultMaxBytes,
+		Elements:   DefaultElements(),
+		Drop:       DefaultDrop(),
+		Attributes: DefaultAttributes(),
+	}
+}
+
+// Render builds and renders the component returned by fn under the sandbox's
+// limits and returns its sanitised output. ctx bounds the render in addition
+// to Timeout, but none of its values are visible to the component.
+//
+// A component that runs past its deadline in its own code, rather than in
+// fluent's render-time nodes, cannot be stopped: Render returns ErrTimeout
+// and the goroutine building it is left to finish in the background.
+func (s *Sandbox) Render(ctx context.Context, fn func() node.Node) ([]byte, error) {
+	if ctx.Err() != nil {
+		return nil, expired(ctx)
+	}
+	ctx, stop := isolate(ctx)
+	defer stop()
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		out []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var buf bytes.Buffer
+		defer func() {
+			if v := recover(); v != nil {
+				done <- result{err: &PanicError{Value: v, Stack: debug.Stack()}}
+			}
+		}()
+		l := &limited{fn: fn, max: s.MaxBytes}
+		err := fluent.RenderContext(ctx, l, &buf)
+		if l.over {
+			err = fmt.Errorf("%w: limit %d bytes", ErrTooLarge, s.MaxBytes)
+		}
+		done <- result{out: buf.Bytes(), err: err}
+	}()
+
+	var r result
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		return nil, expired(ctx)
+	}
+	switch {
+	case r.err != nil && ctx.Err() != nil:
+		return nil, expired(ctx)
+	case r.err != nil:
+		return nil, r.err
+	case s.MaxBytes > 0 && len(r.out) > s.MaxBytes:
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrTooLarge, len(r.out), s.MaxBytes)
+	}
+	return s.Sanitise(r.out), nil
+}
+
+// limited builds a component at render time and renders it node by node,
+// stopping once its output passes max bytes.
+type limited struct {
+	fn   func() node.Node
+	max  int
+	over bool
+}
+
+func (l *limited) Render(w ...io.Writer) []byte {
+	return node.Render(l, w...)
+}
+
+func (l *limited) RenderBuilder(buf *bytes.Buffer) {
+	if node.Interrupted(buf) {
+		return
+	}
+	l.render(buf, buf.Len(), l.fn())
+}
+
+// render writes n to buf, descending into elements so that the limit is
+// checked between their children. start is the length of buf before the
+// component.
+func (l *limited) render(buf *bytes.Buffer, start int, n node.Node) {
+	if n == nil || l.over {
+		return
+	}
+	if e, ok := n.(node.Element); ok {
+		e.RenderOpen(buf)
+		for _, child := range e.Nodes() {
+			l.render(buf, start, child)
+		}
+		if !l.over {
+			e.RenderClose(buf)
+		}
+	} else {
+		n.RenderBuilder(buf)
+	}
+	if l.max > 0 && buf.Len()-start > l.max {
+		l.over = true
+	}
+}
+
+func (l *limited) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+func (l *limited) SetAttribute(_ string, _ string) {
+	// limited does not support attributes
+}
+
+// expired returns ErrTimeout for a render that ran out of time, or the
+// cause of a host cancellation.
+func expired(ctx context.Context) error {
+	if cause := context.Cause(ctx); !errors.Is(cause, context.DeadlineExceeded) {
+		return cause
+	}
+	return ErrTimeout
+}
+
+// isolate returns a context cancelled with ctx but carrying none of its
+// values, so a component cannot reach host state such as the page's asset
+// registries.
+func isolate(ctx context.Context) (context.Context, context.CancelFunc) {
+	iso, cancel := context.WithCancelCause(context.Background())
+	unregister := context.AfterFunc(ctx, func() { cancel(context.Cause(ctx)) })
+	return iso, func() {
+		unregister()
+		cancel(nil)
+	}
+}
+
+// Component returns a node rendering the component built by fn in the
+// sandbox. name identifies the component to OnError.
+func (s *Sandbox) Component(name string, fn func() node.Node) node.Node {
+	return &component{sandbox: s, name: name, fn: fn}
+}
+
+type component struct {
+	sandbox *Sandbox
+	name    string
+	fn      func() node.Node
+}
+
+func (c *component) Render(w ...io.Writer) []byte {
+	return node.Render(c, w...)
+}
+
+// RenderBuilder renders the component in the sandbox, writing the fallback
+// if it fails. The sandbox's limits start from the host render's context.
+func (c *component) RenderBuilder(buf *bytes.Buffer) {
+	out, err := c.sandbox.Render(node.Context(buf), c.fn)
+	if err != nil {
+		if c.sandbox.OnError != nil {
+			c.sandbox.OnError(c.name, err)
+		}
+		if c.sandbox.Fallback != nil {
+			c.sandbox.Fallback.RenderBuilder(buf)
+		}
+		return
+	}
+	buf.Write(out)
+}
+
+// Nodes returns an empty slice as the component is only built at render time.
+func (c *component) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the component is built on every render.
+func (c *component) Dynamic() bool {
+	return true
+}
+
+func (c *component) SetAttribute(_ string, _ string) {
+	// component does not support attributes
+}
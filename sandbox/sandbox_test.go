@@ -0,0 +1,175 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestSanitise(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"allowed", `<div class="card"><p>Hi <b>there</b></p></div>`, `<div class="card"><p>Hi <b>there</b></p></div>`},
+		{"script", `<p>a</p><script>alert(1)</script><p>b</p>`, `<p>a</p><p>b</p>`},
+		{"nested drop", `<svg><svg></svg><a href="x">x</a></svg>ok`, `ok`},
+		{"handler", `<img src="a.png" onerror="alert(1)" alt="a" />`, `<img src="a.png" alt="a" />`},
+		{"style attribute", `<p style="position:fixed">x</p>`, `<p>x</p>`},
+		{"javascript url", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"obfuscated url", `<a href="java&#09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"safe urls", `<a href="/a?b=c:d">x</a><a href="https://e.com">y</a>`, `<a href="/a?b=c:d">x</a><a href="https://e.com">y</a>`},
+		{"srcset", `<img srcset="a.png 1x, javascript:x 2x" />`, `<img />`},
+		{"unwrap", `<form action="/steal"><span>x</span></form>`, `<span>x</span>`},
+		{"close host", `<p>x</p></div></body><p>y</p>`, `<p>x</p><p>y</p>`},
+		{"unclosed", `<div><p>x`, `<div><p>x</p></div>`},
+		{"misnested", `<div><b>x</div>y`, `<div><b>x</b></div>y`},
+		{"comment", `<!-- <script>x</script> -->a`, `a`},
+		{"text", `a &lt;b&gt; &amp; "c"`, `a &lt;b&gt; &amp; &#34;c&#34;`},
+		{"aria and data", `<div aria-label="l" data-id="1" hx-get="/x">x</div>`, `<div aria-label="l">x</div>`},
+		{"host hooks", `<button id="save" data-hx-post="/x" data-controller="c" data-turbo-frame="f">x</button>`, `<button>x</button>`},
+		{"blank target", `<a href="/x" target="_blank">x</a>`, `<a href="/x" target="_blank" rel="noopener noreferrer">x</a>`},
+	}
+	s := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(s.Sanitise([]byte(tt.in))); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponent(t *testing.T) {
+	var reported []error
+	s := New()
+	s.Timeout = 50 * time.Millisecond
+	s.MaxBytes = 100
+	s.Fallback = p.Static("unavailable")
+	s.OnError = func(name string, err error) {
+		if name != "plugin" {
+			t.Errorf("name = %q", name)
+		}
+		reported = append(reported, err)
+	}
+
+	tests := []struct {
+		name string
+		fn   func() node.Node
+		want string
+		err  error
+	}{
+		{"ok", func() node.Node { return div.New(text.RawText(`<p onclick="x()">hi</p>`)) }, `<div><p>hi</p></div>`, nil},
+		{"panic", func() node.Node { panic("boom") }, `<p>unavailable</p>`, &PanicError{}},
+		{"render panic", func() node.Node {
+			return node.Func(func() node.Node { panic("late") })
+		}, `<p>unavailable</p>`, &PanicError{}},
+		{"too large", func() node.Node { return text.Text(strings.Repeat("x", 200)) }, `<p>unavailable</p>`, ErrTooLarge},
+		{"slow", func() node.Node {
+			time.Sleep(200 * time.Millisecond)
+			return text.Static("late")
+		}, `<p>unavailable</p>`, ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+			got := string(div.New(s.Component("plugin", tt.fn)).Render())
+			if want := "<div>" + tt.want + "</div>"; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+			switch want := tt.err.(type) {
+			case nil:
+				if len(reported) != 0 {
+					t.Errorf("unexpected error %v", reported)
+				}
+			case *PanicError:
+				var pe *PanicError
+				if len(reported) != 1 || !errors.As(reported[0], &pe) || len(pe.Stack) == 0 {
+					t.Errorf("got %v, want a PanicError", reported)
+				}
+			default:
+				if len(reported) != 1 || !errors.Is(reported[0], want) {
+					t.Errorf("got %v, want %v", reported, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptInAttributes(t *testing.T) {
+	s := New()
+	s.Attributes["id"] = true
+	s.Attributes["data-chart-*"] = true
+	in := `<div id="c" data-chart-type="bar" data-action="x">x</div>`
+	if got, want := string(s.Sanitise([]byte(in))), `<div id="c" data-chart-type="bar">x</div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMaxBytesStopsRender(t *testing.T) {
+	s := New()
+	s.MaxBytes = 100
+	evaluated := 0
+	_, err := s.Render(context.Background(), func() node.Node {
+		return div.New(
+			text.Text(strings.Repeat("x", 200)),
+			node.Func(func() node.Node {
+				evaluated++
+				return text.Static("more")
+			}),
+		)
+	})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("got %v, want ErrTooLarge", err)
+	}
+	if evaluated != 0 {
+		t.Error("render continued past the limit")
+	}
+}
+
+type secretKey struct{}
+
+func TestIsolatedContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), secretKey{}, "host")
+	out, err := New().Render(ctx, func() node.Node {
+		return node.Func(func() node.Node {
+			return text.Text("no secret")
+		})
+	})
+	if err != nil || string(out) != "no secret" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+
+	var seen any
+	_, _ = New().Render(ctx, func() node.Node {
+		return &probe{seen: &seen}
+	})
+	if seen != nil {
+		t.Errorf("component saw host value %v", seen)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New().Render(cancelled, func() node.Node { return text.Static("x") }); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+type probe struct {
+	seen *any
+}
+
+func (p *probe) Render(...io.Writer) []byte { return nil }
+func (p *probe) RenderBuilder(buf *bytes.Buffer) {
+	*p.seen = node.Context(buf).Value(secretKey{})
+}
+func (p *probe) Nodes() []node.Node          { return nil }
+func (p *probe) SetAttribute(string, string) {}
@@ -0,0 +1,188 @@
+package sandbox
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/internal/markup"
+)
+
+// DefaultElements returns the elements a new Sandbox permits: text-level,
+// grouping, list, table and media elements. Forms, frames, scripts and
+// document-level elements are excluded.
+func DefaultElements() map[string]bool {
+	return set("a", "abbr", "article", "aside", "b", "bdi", "bdo", "blockquote",
+		"br", "button", "caption", "cite", "code", "col", "colgroup", "data",
+		"dd", "del", "details", "dfn", "div", "dl", "dt", "em", "figcaption",
+		"figure", "footer", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr",
+		"i", "img", "ins", "kbd", "li", "mark", "nav", "ol", "p", "picture",
+		"pre", "q", "s", "samp", "section", "small", "source", "span", "strong",
+		"sub", "summary", "sup", "table", "tbody", "td", "tfoot", "th", "thead",
+		"time", "tr", "u", "ul", "var", "wbr")
+}
+
+// DefaultDrop returns the elements a new Sandbox removes with their content.
+func DefaultDrop() map[string]bool {
+	return set("script", "style", "iframe", "object", "embed", "template",
+		"noscript", "svg", "math", "textarea", "title", "select", "head")
+}
+
+// DefaultAttributes returns the attributes a new Sandbox permits. Event
+// handlers, style and form attributes are excluded, as are id, which could
+// clobber the host's elements, and data-* attributes, which drive
+// libraries such as htmx, Stimulus and Turbo on the host page.
+func DefaultAttributes() map[string]bool {
+	return set("alt", "cite", "class", "colspan", "datetime", "dir", "disabled",
+		"headers", "height", "href", "hreflang", "lang", "loading", "open",
+		"reversed", "role", "rowspan", "scope", "sizes", "span", "src", "srcset",
+		"start", "title", "type", "value", "width")
+}
+
+func set(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, item := range items {
+		m[item] = true
+	}
+	return m
+}
+
+// urlAttributes lists attributes holding URLs, whose schemes are checked.
+var urlAttributes = set("href", "src", "cite", "srcset")
+
+// Sanitise restricts rendered HTML to the sandbox's allowlists. Comments and
+// doctypes are removed, text is re-escaped, end tags without a matching open
+// element are dropped and elements left open are closed.
+func (s *Sandbox) Sanitise(src []byte) []byte {
+	var out bytes.Buffer
+	var open []string
+	dropping, depth := "", 0
+	for _, tok := range markup.Tokenize(string(src)) {
+		if dropping != "" {
+			switch {
+			case tok.Type == markup.StartTagToken && tok.Data == dropping:
+				depth++
+			case tok.Type == markup.EndTagToken && tok.Data == dropping:
+				if depth--; depth == 0 {
+					dropping = ""
+				}
+			}
+			continue
+		}
+		switch tok.Type {
+		case markup.TextToken:
+			escape.WriteString(&out, tok.Data)
+		case markup.StartTagToken, markup.SelfClosingTagToken:
+			if s.Drop[tok.Data] {
+				if tok.Type == markup.StartTagToken {
+					dropping, depth = tok.Data, 1
+				}
+				continue
+			}
+			if !s.Elements[tok.Data] {
+				continue
+			}
+			s.writeTag(&out, tok)
+			if tok.Type == markup.StartTagToken {
+				open = append(open, tok.Data)
+			}
+		case markup.EndTagToken:
+			i := len(open) - 1
+			for i >= 0 && open[i] != tok.Data {
+				i--
+			}
+			if i < 0 {
+				continue
+			}
+			for j := len(open) - 1; j >= i; j-- {
+				out.WriteString("</" + open[j] + ">")
+			}
+			open = open[:i]
+		}
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		out.WriteString("</" + open[j] + ">")
+	}
+	return out.Bytes()
+}
+
+// writeTag writes a start tag with its permitted attributes.
+func (s *Sandbox) writeTag(out *bytes.Buffer, tok markup.Token) {
+	out.WriteByte('<')
+	out.WriteString(tok.Data)
+	for _, a := range tok.Attrs {
+		if !s.attribute(a.Key) {
+			continue
+		}
+		if urlAttributes[a.Key] && !safeURL(a.Key, a.Val) {
+			continue
+		}
+		out.WriteByte(' ')
+		out.WriteString(a.Key)
+		out.WriteString(`="`)
+		escape.WriteString(out, a.Val)
+		out.WriteByte('"')
+	}
+	if a, ok := attr(tok, "target"); ok && tok.Data == "a" && a == "_blank" {
+		// Links opening a new window must not hand it a reference to the page.
+		out.WriteString(` target="_blank" rel="noopener noreferrer"`)
+	}
+	if tok.Type == markup.SelfClosingTagToken {
+		out.WriteString(" /")
+	}
+	out.WriteByte('>')
+}
+
+// attribute reports whether key is permitted: aria-* attributes always are,
+// others when Attributes lists them by name or by a prefix ending in "*".
+func (s *Sandbox) attribute(key string) bool {
+	if s.Attributes[key] || strings.HasPrefix(key, "aria-") {
+		return true
+	}
+	for pattern, ok := range s.Attributes {
+		if prefix, wild := strings.CutSuffix(pattern, "*"); ok && wild && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(tok markup.Token, key string) (string, bool) {
+	for _, a := range tok.Attrs {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// safeURL reports whether a URL attribute value is relative or uses a safe
+// scheme. A srcset is checked candidate by candidate.
+func safeURL(key, v string) bool {
+	if key == "srcset" {
+		for _, candidate := range strings.Split(v, ",") {
+			fields := strings.Fields(candidate)
+			if len(fields) > 0 && !safeURL("src", fields[0]) {
+				return false
+			}
+		}
+		return true
+	}
+	// Browsers ignore control characters and whitespace inside a scheme,
+	// so "java\tscript:" must be caught.
+	v = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, v)
+	colon := strings.IndexByte(v, ':')
+	if colon < 0 || strings.ContainsAny(v[:colon], "/?#") {
+		return true
+	}
+	switch strings.ToLower(v[:colon]) {
+	case "http", "https", "mailto", "tel":
+		return true
+	}
+	return false
+}
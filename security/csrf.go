@@ -0,0 +1,203 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/meta"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Default names used for the CSRF token.
+const (
+	DefaultCSRFField  = "csrf_token"
+	DefaultCSRFHeader = "X-CSRF-Token"
+)
+
+// CSRF carries a request's CSRF token into the render context, where
+// CSRFField, CSRFMeta and CSRFHeaders emit it. Token generation and checking
+// stay with the CSRF middleware in use; CSRF only needs a function returning
+// the current request's token.
+//
+// Usage with gorilla/csrf:
+//
+//	protect := csrf.Protect(key, csrf.FieldName("csrf_token"))
+//	tokens := security.CSRF{Token: csrf.Token}
+//	http.ListenAndServe(":8080", protect(tokens.Middleware(mux)))
+//
+//	form.New(
+//	    security.CSRFField(),
+//	    ...
+//	)
+//	fluent.RenderContext(r.Context(), page, w)
+type CSRF struct {
+	// Token returns the token for a request.
+	Token func(r *http.Request) string
+	// Field is the form field name. Defaults to DefaultCSRFField.
+	Field string
+	// Header is the request header name. Defaults to DefaultCSRFHeader.
+	Header string
+}
+
+type csrfKey struct{}
+
+// csrfValue is the token of one request with the names it is sent under.
+type csrfValue struct {
+	token, field, header string
+}
+
+// Middleware adds the request's token to its context.
+func (c CSRF) Middleware(next http.Handler) http.Handler {
+	field, header := c.Field, c.Header
+	if field == "" {
+		field = DefaultCSRFField
+	}
+	if header == "" {
+		header = DefaultCSRFHeader
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := csrfValue{token: c.Token(r), field: field, header: header}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, v)))
+	})
+}
+
+// WithCSRFToken returns a context carrying token under the default field and
+// header names, for applications that manage tokens themselves.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfKey{}, csrfValue{token: token, field: DefaultCSRFField, header: DefaultCSRFHeader})
+}
+
+// CSRFToken returns the token carried by ctx and the field and header names
+// it is sent under. ok is false when ctx has no token.
+func CSRFToken(ctx context.Context) (token, field, header string, ok bool) {
+	v, _ := ctx.Value(csrfKey{}).(csrfValue)
+	return v.token, v.field, v.header, v.token != ""
+}
+
+// CSRFField returns a node rendering the token as a hidden input. It renders
+// nothing outside a context-aware render carrying a token.
+func CSRFField() node.Node {
+	return &csrfNode{emit: func(token, field, _ string) node.Node {
+		return input.Hidden(field, token)
+	}}
+}
+
+// CSRFMeta returns a node rendering the token and header name as
+// <meta name="csrf-token"> and <meta name="csrf-header"> tags, for scripts
+// that add the header to their own requests.
+func CSRFMeta() node.Node {
+	return &csrfNode{emit: func(token, _, header string) node.Node {
+		return hidden{
+			meta.New().Name("csrf-token").Content(token),
+			meta.New().Name("csrf-header").Content(header),
+		}
+	}}
+}
+
+// CSRFHeaders adds hx-headers to n at render time so every htmx request
+// made from n and its descendants carries the token. Place it on <body> or a
+// container to cover a whole page. n itself is not modified, so it may be
+// shared between concurrent renders; it should not set hx-headers itself.
+func CSRFHeaders(n node.Node) node.Node {
+	return &csrfNode{target: n, emit: func(token, _, header string) node.Node {
+		b, err := json.Marshal(map[string]string{header: token})
+		if err != nil {
+			return n
+		}
+		return &withAttribute{node: n, key: "hx-headers", value: string(b)}
+	}}
+}
+
+// withAttribute renders node with an extra attribute, without setting it on
+// node. Elements have it written into their opening tag; other nodes are
+// rendered from a copy.
+type withAttribute struct {
+	node       node.Node
+	key, value string
+}
+
+func (a *withAttribute) Render(w ...io.Writer) []byte {
+	return node.Render(a, w...)
+}
+
+func (a *withAttribute) RenderBuilder(buf *bytes.Buffer) {
+	e, ok := a.node.(node.Element)
+	if !ok {
+		c := node.Clone(a.node)
+		c.SetAttribute(a.key, a.value)
+		c.RenderBuilder(buf)
+		return
+	}
+	tmp := node.Scratch(buf)
+	defer node.PutScratch(buf, tmp)
+	e.RenderOpen(tmp)
+	open := tmp.Bytes()
+	end := len(open) - 1
+	if bytes.HasSuffix(open, []byte(" />")) {
+		end = len(open) - 3
+	}
+	buf.Write(open[:end])
+	buf.WriteString(" " + a.key + `="`)
+	escape.WriteString(buf, a.value)
+	buf.WriteByte('"')
+	buf.Write(open[end:])
+	for _, child := range e.Nodes() {
+		if child != nil {
+			child.RenderBuilder(buf)
+		}
+	}
+	e.RenderClose(buf)
+}
+
+func (a *withAttribute) Nodes() []node.Node {
+	return []node.Node{a.node}
+}
+
+func (a *withAttribute) SetAttribute(_ string, _ string) {
+	// withAttribute does not support attributes
+}
+
+// csrfNode renders emit's node for the token in the render context, or
+// target when there is none.
+type csrfNode struct {
+	target node.Node
+	emit   func(token, field, header string) node.Node
+}
+
+func (c *csrfNode) Render(w ...io.Writer) []byte {
+	return node.Render(c, w...)
+}
+
+func (c *csrfNode) RenderBuilder(buf *bytes.Buffer) {
+	if token, field, header, ok := CSRFToken(node.Context(buf)); ok {
+		c.emit(token, field, header).RenderBuilder(buf)
+		return
+	}
+	if c.target != nil {
+		c.target.RenderBuilder(buf)
+	}
+}
+
+func (c *csrfNode) Nodes() []node.Node {
+	if c.target == nil {
+		return []node.Node{}
+	}
+	return []node.Node{c.target}
+}
+
+// Dynamic returns true as the token differs per request.
+func (c *csrfNode) Dynamic() bool {
+	return true
+}
+
+// SetAttribute forwards the attribute to the wrapped node of CSRFHeaders.
+func (c *csrfNode) SetAttribute(key string, value string) {
+	if c.target != nil {
+		c.target.SetAttribute(key, value)
+	}
+}
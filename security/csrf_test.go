@@ -0,0 +1,98 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/form"
+	"github.com/jpl-au/fluent/node"
+)
+
+func renderCtx(t *testing.T, ctx context.Context, n node.Node) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, n, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCSRF(t *testing.T) {
+	ctx := WithCSRFToken(context.Background(), `a"b&c`)
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"field", form.New(CSRFField()), `<form><input name="csrf_token" value="a&#34;b&amp;c" type="hidden" /></form>`},
+		{"meta", CSRFMeta(), `<meta name="csrf-token" content="a&#34;b&amp;c" /><meta name="csrf-header" content="X-CSRF-Token" />`},
+		{"headers", CSRFHeaders(body.New()), `<body hx-headers="{&#34;X-CSRF-Token&#34;:&#34;a\&#34;b\u0026c&#34;}"></body>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderCtx(t, ctx, tt.n); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSRFWithoutToken(t *testing.T) {
+	tests := []struct {
+		name string
+		n    node.Node
+		want string
+	}{
+		{"field", form.New(CSRFField()), `<form></form>`},
+		{"meta", CSRFMeta(), ``},
+		{"headers", CSRFHeaders(body.New()), `<body></body>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.n.Render()); got != tt.want {
+				t.Errorf("Render: got %q, want %q", got, tt.want)
+			}
+			if got := renderCtx(t, context.Background(), tt.n); got != tt.want {
+				t.Errorf("RenderContext: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSRFHeadersShared(t *testing.T) {
+	page := body.New(form.New()).Class("app")
+	n := CSRFHeaders(page)
+
+	with := renderCtx(t, WithCSRFToken(context.Background(), "t1"), n)
+	if want := `<body class="app" hx-headers="{&#34;X-CSRF-Token&#34;:&#34;t1&#34;}"><form></form></body>`; with != want {
+		t.Errorf("got %q, want %q", with, want)
+	}
+	// The token is not left on the shared node for the next render.
+	if got, want := renderCtx(t, context.Background(), n), `<body class="app"><form></form></body>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	csrf := CSRF{
+		Token:  func(r *http.Request) string { return "tok-" + r.URL.Path },
+		Field:  "gorilla.csrf.Token",
+		Header: "X-Token",
+	}
+	var got string
+	h := csrf.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = renderCtx(t, r.Context(), CSRFField())
+		if token, field, header, ok := CSRFToken(r.Context()); !ok || token != "tok-/a" || field != "gorilla.csrf.Token" || header != "X-Token" {
+			t.Errorf("CSRFToken = %q, %q, %q, %v", token, field, header, ok)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+	if want := `<input name="gorilla.csrf.Token" value="tok-/a" type="hidden" />`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
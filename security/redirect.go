@@ -0,0 +1,179 @@
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ErrRedirect is returned for a redirect target that is off-origin and not
+// allowlisted, malformed, or carries an invalid signature.
+var ErrRedirect = errors.New("unsafe redirect target")
+
+// Redirects validates and signs redirect targets carried in query parameters
+// and forms, such as /login?next=/account, so they cannot be used to send
+// users to another site.
+//
+// Same-origin targets are root-relative paths. Absolute URLs are accepted
+// only for allowlisted hosts. When Key is set, targets are also signed, so
+// only links and forms the application generated are honoured.
+//
+// Usage:
+//
+//	redirects := security.NewRedirects(key, "accounts.example.com")
+//
+//	href, err := redirects.URL("/login", r.URL.RequestURI())
+//	a.New(text.Text("Sign in")).Href(href)
+//
+//	form.New(&login).Action("/login").Redirect(redirects, next)
+//
+//	http.Redirect(w, r, redirects.Target(r, "/"), http.StatusSeeOther)
+type Redirects struct {
+	// Key signs targets. Without a key targets are validated but not signed.
+	Key []byte
+	// Allow lists hosts that absolute targets may point to. An entry starting
+	// with a dot, such as ".example.com", also allows every subdomain.
+	Allow []string
+	// Param is the name of the target parameter. The signature is carried in
+	// Param + "_sig". Defaults to "next".
+	Param string
+}
+
+// NewRedirects returns a Redirects signing with key and allowing absolute
+// targets on the given hosts.
+func NewRedirects(key []byte, allow ...string) *Redirects {
+	return &Redirects{Key: key, Allow: allow}
+}
+
+func (r *Redirects) param() string {
+	if r.Param == "" {
+		return "next"
+	}
+	return r.Param
+}
+
+// Check returns an error wrapping ErrRedirect unless target is a root-relative
+// path or an http(s) URL on an allowlisted host.
+func (r *Redirects) Check(target string) error {
+	if target == "" || strings.ContainsAny(target, "\\\x00\t\r\n") {
+		return ErrRedirect
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return ErrRedirect
+	}
+	if u.Scheme == "" && u.Host == "" {
+		// A path must be rooted, and "//host" is a scheme-relative URL.
+		if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+			return ErrRedirect
+		}
+		return nil
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.User != nil || !r.allowed(u.Hostname()) {
+		return ErrRedirect
+	}
+	return nil
+}
+
+func (r *Redirects) allowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, a := range r.Allow {
+		a = strings.ToLower(a)
+		if host == a || strings.HasPrefix(a, ".") && (host == a[1:] || strings.HasSuffix(host, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature of target, or "" without a Key.
+func (r *Redirects) Sign(target string) string {
+	if len(r.Key) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, r.Key)
+	mac.Write([]byte(target))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks target and, when a Key is set, its signature.
+func (r *Redirects) Verify(target, sig string) error {
+	if err := r.Check(target); err != nil {
+		return err
+	}
+	if len(r.Key) > 0 && !hmac.Equal([]byte(sig), []byte(r.Sign(target))) {
+		return ErrRedirect
+	}
+	return nil
+}
+
+// URL returns base with target and its signature added to the query.
+func (r *Redirects) URL(base, target string) (string, error) {
+	if err := r.Check(target); err != nil {
+		return "", err
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(r.param(), target)
+	if sig := r.Sign(target); sig != "" {
+		q.Set(r.param()+"_sig", sig)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Hidden returns hidden inputs carrying target and its signature through a
+// form. An unsafe target renders nothing.
+func (r *Redirects) Hidden(target string) node.Node {
+	if r.Check(target) != nil {
+		return hidden(nil)
+	}
+	fields := hidden{input.Hidden(r.param(), target)}
+	if sig := r.Sign(target); sig != "" {
+		fields = append(fields, input.Hidden(r.param()+"_sig", sig))
+	}
+	return fields
+}
+
+// Target returns the verified target of req, from its query or form, or
+// fallback when it is missing or unsafe.
+func (r *Redirects) Target(req *http.Request, fallback string) string {
+	target := req.FormValue(r.param())
+	if r.Verify(target, req.FormValue(r.param()+"_sig")) != nil {
+		return fallback
+	}
+	return target
+}
+
+// hidden renders a sequence of inputs.
+type hidden []node.Node
+
+func (h hidden) Render(w ...io.Writer) []byte {
+	return node.Render(h, w...)
+}
+
+func (h hidden) RenderBuilder(buf *bytes.Buffer) {
+	for _, n := range h {
+		n.RenderBuilder(buf)
+	}
+}
+
+func (h hidden) Nodes() []node.Node {
+	return h
+}
+
+func (h hidden) SetAttribute(_ string, _ string) {
+	// hidden does not support attributes
+}
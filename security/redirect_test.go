@@ -0,0 +1,74 @@
+package security
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRedirectsCheck(t *testing.T) {
+	r := NewRedirects(nil, "accounts.example.com", ".example.org")
+	tests := []struct {
+		target string
+		ok     bool
+	}{
+		{"/account?tab=1#top", true},
+		{"https://accounts.example.com/x", true},
+		{"https://a.example.org/", true},
+		{"http://example.org", true},
+		{"", false},
+		{"account", false},
+		{"//evil.com", false},
+		{"/\\evil.com", false},
+		{"https://evil.com", false},
+		{"https://example.org.evil.com", false},
+		{"https://accounts.example.com@evil.com", false},
+		{"javascript:alert(1)", false},
+		{"/ok\r\nLocation: x", false},
+	}
+	for _, tt := range tests {
+		err := r.Check(tt.target)
+		if (err == nil) != tt.ok {
+			t.Errorf("Check(%q) = %v, want ok %v", tt.target, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrRedirect) {
+			t.Errorf("Check(%q) = %v, want ErrRedirect", tt.target, err)
+		}
+	}
+}
+
+func TestRedirectsSigned(t *testing.T) {
+	r := NewRedirects([]byte("secret"))
+	href, err := r.URL("/login?lang=en", "/account")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(href)
+	if u.Path != "/login" || u.Query().Get("lang") != "en" || u.Query().Get("next") != "/account" {
+		t.Fatalf("got %q", href)
+	}
+
+	req := httptest.NewRequest("GET", href, nil)
+	if got := r.Target(req, "/"); got != "/account" {
+		t.Errorf("signed target: got %q", got)
+	}
+	req = httptest.NewRequest("GET", "/login?next=/admin&next_sig="+u.Query().Get("next_sig"), nil)
+	if got := r.Target(req, "/"); got != "/" {
+		t.Errorf("tampered target: got %q, want fallback", got)
+	}
+	if _, err := r.URL("/login", "https://evil.com"); !errors.Is(err, ErrRedirect) {
+		t.Errorf("off-origin URL: got %v", err)
+	}
+}
+
+func TestRedirectsHidden(t *testing.T) {
+	r := &Redirects{Key: []byte("secret"), Param: "return"}
+	want := `<input name="return" value="/a?x=1&amp;y=2" type="hidden" /><input name="return_sig" value="` + r.Sign("/a?x=1&y=2") + `" type="hidden" />`
+	if got := string(r.Hidden("/a?x=1&y=2").Render()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := string(r.Hidden("https://evil.com").Render()); got != "" {
+		t.Errorf("unsafe target: got %q", got)
+	}
+}
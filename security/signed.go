@@ -0,0 +1,238 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/node"
+)
+
+var (
+	// ErrSignature is returned for a URL or token that is unsigned, altered or
+	// signed with an unknown key.
+	ErrSignature = errors.New("invalid signature")
+	// ErrExpired is returned for a correctly signed URL or token past its expiry.
+	ErrExpired = errors.New("signature expired")
+)
+
+// Keyring supplies HMAC keys by ID so keys can be rotated: new signatures use
+// the current key while those made with older keys still verify until the
+// key is retired.
+type Keyring interface {
+	// Current returns the ID and secret used for new signatures.
+	Current() (id string, secret []byte)
+	// Lookup returns the secret with the given ID.
+	Lookup(id string) ([]byte, bool)
+}
+
+// Key is a signing key.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keys is a Keyring whose first key is current.
+type Keys []Key
+
+// Current returns the first key.
+func (k Keys) Current() (string, []byte) {
+	if len(k) == 0 {
+		return "", nil
+	}
+	return k[0].ID, k[0].Secret
+}
+
+// Lookup returns the key with id.
+func (k Keys) Lookup(id string) ([]byte, bool) {
+	for _, key := range k {
+		if key.ID == id {
+			return key.Secret, true
+		}
+	}
+	return nil, false
+}
+
+// Signer creates and verifies signed, optionally expiring URLs and tokens,
+// for links such as downloads, previews and unsubscribes that must work
+// without a session but cannot be forged.
+//
+// Usage:
+//
+//	signer := security.NewSigner(security.Keys{{ID: "2025-06", Secret: key}})
+//
+//	href, _ := signer.SignURL("/files/report.pdf", 15*time.Minute)
+//	a.New(text.Text("Download")).Href(href)
+//
+//	func download(w http.ResponseWriter, r *http.Request) {
+//	    if err := signer.Verify(r); err != nil {
+//	        http.Error(w, "link expired", http.StatusForbidden)
+//	        return
+//	    }
+//	    ...
+//	}
+type Signer struct {
+	Keys Keyring
+	// Now returns the current time. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// NewSigner returns a Signer using keys.
+func NewSigner(keys Keyring) *Signer {
+	return &Signer{Keys: keys}
+}
+
+// Query parameters added by SignURL.
+const (
+	paramExpires = "exp"
+	paramKey     = "kid"
+	paramSig     = "sig"
+)
+
+func (s *Signer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Signer) expiry(ttl time.Duration) string {
+	if ttl <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+}
+
+func (s *Signer) expired(exp string) error {
+	if exp == "" {
+		return nil
+	}
+	t, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if s.now().Unix() >= t {
+		return ErrExpired
+	}
+	return nil
+}
+
+func mac(secret []byte, parts ...string) []byte {
+	m := hmac.New(sha256.New, secret)
+	for _, p := range parts {
+		m.Write([]byte(p))
+		m.Write([]byte{0})
+	}
+	return m.Sum(nil)
+}
+
+// SignURL returns rawURL with expiry, key ID and signature parameters added.
+// The path and every query parameter are covered by the signature. A ttl of
+// zero or less never expires.
+func (s *Signer) SignURL(rawURL string, ttl time.Duration) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	id, secret := s.Keys.Current()
+	q := u.Query()
+	q.Del(paramSig)
+	q.Set(paramKey, id)
+	if exp := s.expiry(ttl); exp != "" {
+		q.Set(paramExpires, exp)
+	} else {
+		q.Del(paramExpires)
+	}
+	q.Set(paramSig, base64.RawURLEncoding.EncodeToString(mac(secret, "url", u.EscapedPath(), q.Encode())))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// VerifyURL checks the signature and expiry of a URL made by SignURL.
+func (s *Signer) VerifyURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrSignature
+	}
+	return s.verifyURL(u)
+}
+
+// Verify checks the signature and expiry of the request URL.
+func (s *Signer) Verify(r *http.Request) error {
+	return s.verifyURL(r.URL)
+}
+
+func (s *Signer) verifyURL(u *url.URL) error {
+	q := u.Query()
+	sig, err := base64.RawURLEncoding.DecodeString(q.Get(paramSig))
+	if err != nil || len(sig) == 0 {
+		return ErrSignature
+	}
+	secret, ok := s.Keys.Lookup(q.Get(paramKey))
+	if !ok {
+		return ErrSignature
+	}
+	q.Del(paramSig)
+	// Re-encoding sorts the parameters the same way SignURL did.
+	if !hmac.Equal(sig, mac(secret, "url", u.EscapedPath(), q.Encode())) {
+		return ErrSignature
+	}
+	return s.expired(q.Get(paramExpires))
+}
+
+// Token returns a signed token carrying value. The purpose is bound into the
+// signature so a token issued for one use, such as "unsubscribe", is not
+// accepted for another. A ttl of zero or less never expires.
+func (s *Signer) Token(purpose, value string, ttl time.Duration) string {
+	id, secret := s.Keys.Current()
+	payload := id + ":" + s.expiry(ttl) + ":" + value
+	sig := mac(secret, "token", purpose, payload)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// ParseToken verifies a token made by Token for purpose and returns its value.
+func (s *Signer) ParseToken(purpose, token string) (string, error) {
+	enc, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrSignature
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return "", ErrSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil {
+		return "", ErrSignature
+	}
+	id, rest, ok1 := strings.Cut(string(payload), ":")
+	exp, value, ok2 := strings.Cut(rest, ":")
+	if !ok1 || !ok2 {
+		return "", ErrSignature
+	}
+	secret, ok := s.Keys.Lookup(id)
+	if !ok || !hmac.Equal(sig, mac(secret, "token", purpose, string(payload))) {
+		return "", ErrSignature
+	}
+	if err := s.expired(exp); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Hidden returns a hidden input named name carrying a token for value.
+func (s *Signer) Hidden(name, purpose, value string, ttl time.Duration) node.Node {
+	return input.Hidden(name, s.Token(purpose, value, ttl))
+}
+
+// FormValue verifies the token submitted in the named field and returns its
+// value.
+func (s *Signer) FormValue(r *http.Request, name, purpose string) (string, error) {
+	return s.ParseToken(purpose, r.FormValue(name))
+}
@@ -0,0 +1,104 @@
+package security
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSigner(now *time.Time) *Signer {
+	s := NewSigner(Keys{{ID: "new", Secret: []byte("k2")}, {ID: "old", Secret: []byte("k1")}})
+	s.Now = func() time.Time { return *now }
+	return s
+}
+
+func TestSignURL(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := testSigner(&now)
+
+	signed, err := s.SignURL("/files/report.pdf?b=2&a=1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyURL(signed); err != nil {
+		t.Fatalf("fresh URL: %v", err)
+	}
+	if err := s.Verify(httptest.NewRequest("GET", signed, nil)); err != nil {
+		t.Errorf("request: %v", err)
+	}
+
+	tampered := strings.Replace(signed, "a=1", "a=2", 1)
+	if err := s.VerifyURL(tampered); !errors.Is(err, ErrSignature) {
+		t.Errorf("tampered: got %v", err)
+	}
+	u, _ := url.Parse(signed)
+	u.Path = "/files/other.pdf"
+	if err := s.VerifyURL(u.String()); !errors.Is(err, ErrSignature) {
+		t.Errorf("other path: got %v", err)
+	}
+	if err := s.VerifyURL("/files/report.pdf"); !errors.Is(err, ErrSignature) {
+		t.Errorf("unsigned: got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := s.VerifyURL(signed); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired: got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	old := NewSigner(Keys{{ID: "old", Secret: []byte("k1")}})
+	signed, _ := old.SignURL("/preview/42", 0)
+	token := old.Token("unsubscribe", "ana@example.com", 0)
+
+	rotated := testSigner(&now)
+	if err := rotated.VerifyURL(signed); err != nil {
+		t.Errorf("URL signed with retired-but-known key: %v", err)
+	}
+	if _, err := rotated.ParseToken("unsubscribe", token); err != nil {
+		t.Errorf("token signed with retired-but-known key: %v", err)
+	}
+
+	retired := NewSigner(Keys{{ID: "new", Secret: []byte("k2")}})
+	if err := retired.VerifyURL(signed); !errors.Is(err, ErrSignature) {
+		t.Errorf("removed key: got %v", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := testSigner(&now)
+
+	token := s.Token("unsubscribe", "list:7:ana@example.com", time.Hour)
+	if got, err := s.ParseToken("unsubscribe", token); err != nil || got != "list:7:ana@example.com" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := s.ParseToken("preview", token); !errors.Is(err, ErrSignature) {
+		t.Errorf("wrong purpose: got %v", err)
+	}
+	if _, err := s.ParseToken("unsubscribe", token+"x"); !errors.Is(err, ErrSignature) {
+		t.Errorf("altered: got %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := s.ParseToken("unsubscribe", token); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired: got %v", err)
+	}
+}
+
+func TestSignerHidden(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := testSigner(&now)
+	html := string(s.Hidden("token", "preview", "draft-9", 0).Render())
+	value := html[strings.Index(html, `value="`)+7:]
+	value = value[:strings.IndexByte(value, '"')]
+
+	req := httptest.NewRequest("POST", "/preview", strings.NewReader(url.Values{"token": {value}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if got, err := s.FormValue(req, "token", "preview"); err != nil || got != "draft-9" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package seo provides builders for search-engine and social metadata:
+// sitemaps and the meta tags consumed by crawlers and link previews.
+package seo
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// MaxURLs is the maximum number of URLs the sitemap protocol allows in a single file.
+const MaxURLs = 50000
+
+// ChangeFreq is how frequently a page is likely to change.
+type ChangeFreq string
+
+const (
+	Always  ChangeFreq = "always"
+	Hourly  ChangeFreq = "hourly"
+	Daily   ChangeFreq = "daily"
+	Weekly  ChangeFreq = "weekly"
+	Monthly ChangeFreq = "monthly"
+	Yearly  ChangeFreq = "yearly"
+	Never   ChangeFreq = "never"
+)
+
+var (
+	xmlDecl      = []byte(`<?xml version="1.0" encoding="UTF-8"?>`)
+	urlsetOpen   = []byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	urlsetClose  = []byte(`</urlset>`)
+	indexOpen    = []byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	indexClose   = []byte(`</sitemapindex>`)
+	urlOpen      = []byte(`<url><loc>`)
+	urlClose     = []byte(`</url>`)
+	sitemapOpen  = []byte(`<sitemap><loc>`)
+	sitemapClose = []byte(`</sitemap>`)
+	locClose     = []byte(`</loc>`)
+	lastModOpen  = []byte(`<lastmod>`)
+	lastModClose = []byte(`</lastmod>`)
+	freqOpen     = []byte(`<changefreq>`)
+	freqClose    = []byte(`</changefreq>`)
+	prioOpen     = []byte(`<priority>`)
+	prioClose    = []byte(`</priority>`)
+)
+
+// Entry is a single URL in a sitemap. Create one with URL.
+type Entry struct {
+	loc        string
+	lastMod    time.Time
+	changeFreq ChangeFreq
+	priority   float64
+	hasPrio    bool
+}
+
+// URL creates a sitemap entry for the absolute URL loc.
+//
+// Usage:
+//
+//	seo.URL("https://example.com/blog").LastMod(post.Updated).ChangeFreq(seo.Weekly).Priority(0.8)
+func URL(loc string) *Entry {
+	return &Entry{loc: loc}
+}
+
+// LastMod sets when the page was last modified.
+func (e *Entry) LastMod(t time.Time) *Entry {
+	e.lastMod = t
+	return e
+}
+
+// ChangeFreq sets how frequently the page is likely to change.
+func (e *Entry) ChangeFreq(f ChangeFreq) *Entry {
+	e.changeFreq = f
+	return e
+}
+
+// Priority sets the priority of the page relative to other pages on the site,
+// clamped to the range 0.0 to 1.0.
+func (e *Entry) Priority(p float64) *Entry {
+	e.priority = min(max(p, 0), 1)
+	e.hasPrio = true
+	return e
+}
+
+// write renders the entry as a <url> element.
+func (e *Entry) write(buf *bytes.Buffer) {
+	buf.Write(urlOpen)
+	escape.WriteString(buf, e.loc)
+	buf.Write(locClose)
+	writeLastMod(buf, e.lastMod)
+	if e.changeFreq != "" {
+		buf.Write(freqOpen)
+		escape.WriteString(buf, string(e.changeFreq))
+		buf.Write(freqClose)
+	}
+	if e.hasPrio {
+		buf.Write(prioOpen)
+		buf.Write(strconv.AppendFloat(nil, e.priority, 'f', 1, 64))
+		buf.Write(prioClose)
+	}
+	buf.Write(urlClose)
+}
+
+func writeLastMod(buf *bytes.Buffer, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	buf.Write(lastModOpen)
+	buf.WriteString(t.Format(time.RFC3339))
+	buf.Write(lastModClose)
+}
+
+// Sitemap is a <urlset> document. It implements node.Node, so it renders
+// through the same pipeline as pages and can be registered as an ssg route.
+//
+// Usage:
+//
+//	sm := seo.NewSitemap(
+//	    seo.URL("https://example.com/").Priority(1),
+//	    seo.URL("https://example.com/about").ChangeFreq(seo.Monthly),
+//	)
+//	sm.Render(w)
+type Sitemap struct {
+	entries []*Entry
+}
+
+// NewSitemap creates a sitemap with the given entries.
+func NewSitemap(entries ...*Entry) *Sitemap {
+	return &Sitemap{entries: entries}
+}
+
+// Add appends entries to the sitemap.
+func (s *Sitemap) Add(entries ...*Entry) *Sitemap {
+	s.entries = append(s.entries, entries...)
+	return s
+}
+
+// Len returns the number of entries.
+func (s *Sitemap) Len() int {
+	return len(s.entries)
+}
+
+// Split divides the sitemap into files of at most MaxURLs entries and returns
+// an index referencing them. loc returns the absolute URL of the i-th file
+// (starting at 0). Each index entry's lastmod is the latest lastmod in its file.
+//
+// Usage:
+//
+//	index, parts := sm.Split(func(i int) string {
+//	    return fmt.Sprintf("https://example.com/sitemap-%d.xml", i)
+//	})
+func (s *Sitemap) Split(loc func(i int) string) (*Index, []*Sitemap) {
+	return s.split(MaxURLs, loc)
+}
+
+func (s *Sitemap) split(size int, loc func(i int) string) (*Index, []*Sitemap) {
+	index := &Index{}
+	var parts []*Sitemap
+	for start := 0; start < len(s.entries); start += size {
+		end := min(start+size, len(s.entries))
+		part := &Sitemap{entries: s.entries[start:end]}
+		var latest time.Time
+		for _, e := range part.entries {
+			if e.lastMod.After(latest) {
+				latest = e.lastMod
+			}
+		}
+		index.Add(loc(len(parts)), latest)
+		parts = append(parts, part)
+	}
+	return index, parts
+}
+
+// Render generates the XML document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (s *Sitemap) Render(w ...io.Writer) []byte {
+	return node.Render(s, w...)
+}
+
+// RenderBuilder writes the XML document directly to a buffer.
+func (s *Sitemap) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(xmlDecl)
+	buf.Write(urlsetOpen)
+	for _, e := range s.entries {
+		e.write(buf)
+	}
+	buf.Write(urlsetClose)
+}
+
+// Nodes returns an empty slice as sitemap entries are not nodes.
+func (s *Sitemap) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op for Sitemap as it does not have attributes.
+func (s *Sitemap) SetAttribute(_ string, _ string) {
+	// Sitemap does not support attributes
+}
+
+// Index is a <sitemapindex> document referencing other sitemap files.
+type Index struct {
+	locs     []string
+	lastMods []time.Time
+}
+
+// Add appends a sitemap file to the index. A zero lastMod is omitted.
+func (x *Index) Add(loc string, lastMod time.Time) *Index {
+	x.locs = append(x.locs, loc)
+	x.lastMods = append(x.lastMods, lastMod)
+	return x
+}
+
+// Render generates the XML document.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (x *Index) Render(w ...io.Writer) []byte {
+	return node.Render(x, w...)
+}
+
+// RenderBuilder writes the XML document directly to a buffer.
+func (x *Index) RenderBuilder(buf *bytes.Buffer) {
+	buf.Write(xmlDecl)
+	buf.Write(indexOpen)
+	for i, loc := range x.locs {
+		buf.Write(sitemapOpen)
+		escape.WriteString(buf, loc)
+		buf.Write(locClose)
+		writeLastMod(buf, x.lastMods[i])
+		buf.Write(sitemapClose)
+	}
+	buf.Write(indexClose)
+}
+
+// Nodes returns an empty slice as index entries are not nodes.
+func (x *Index) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op for Index as it does not have attributes.
+func (x *Index) SetAttribute(_ string, _ string) {
+	// Index does not support attributes
+}
+
+// render implements Render for the XML documents in this package.
+func render(n node.Node, w []io.Writer) []byte {
+	return node.Render(n, w...)
+}
@@ -0,0 +1,73 @@
+package seo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSitemap(t *testing.T) {
+	mod := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sm := NewSitemap(
+		URL("https://example.com/").Priority(1),
+		URL("https://example.com/search?q=a&b=c").LastMod(mod).ChangeFreq(Weekly).Priority(1.7),
+	)
+
+	got := string(sm.Render())
+	want := `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>https://example.com/</loc><priority>1.0</priority></url>` +
+		`<url><loc>https://example.com/search?q=a&amp;b=c</loc><lastmod>2026-01-02T03:04:05Z</lastmod>` +
+		`<changefreq>weekly</changefreq><priority>1.0</priority></url>` +
+		`</urlset>`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var doc struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if doc.URLs[1].Loc != "https://example.com/search?q=a&b=c" {
+		t.Errorf("round-tripped loc = %q", doc.URLs[1].Loc)
+	}
+}
+
+func TestSitemapSplit(t *testing.T) {
+	sm := NewSitemap()
+	for i := range 5 {
+		sm.Add(URL(fmt.Sprintf("https://example.com/%d", i)).LastMod(time.Date(2026, 1, i+1, 0, 0, 0, 0, time.UTC)))
+	}
+
+	index, parts := sm.split(2, func(i int) string {
+		return fmt.Sprintf("https://example.com/sitemap-%d.xml", i)
+	})
+	if len(parts) != 3 || parts[0].Len() != 2 || parts[2].Len() != 1 {
+		t.Fatalf("parts = %d", len(parts))
+	}
+
+	var doc struct {
+		Sitemaps []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(index.Render(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if len(doc.Sitemaps) != 3 {
+		t.Fatalf("index entries = %d, want 3", len(doc.Sitemaps))
+	}
+	if doc.Sitemaps[1].Loc != "https://example.com/sitemap-1.xml" || doc.Sitemaps[1].LastMod != "2026-01-04T00:00:00Z" {
+		t.Errorf("index entry = %+v", doc.Sitemaps[1])
+	}
+
+	if _, parts := sm.Split(func(int) string { return "" }); len(parts) != 1 {
+		t.Errorf("Split() under MaxURLs produced %d parts", len(parts))
+	}
+}
@@ -0,0 +1,212 @@
+package seo
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpl-au/fluent/html5/meta"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Length limits applied to social titles and descriptions. Longer values are
+// cut at a word boundary and suffixed with an ellipsis, as platforms would
+// otherwise truncate them mid-word.
+const (
+	MaxTitle       = 70
+	MaxDescription = 200
+)
+
+// OGType is an Open Graph object type.
+type OGType string
+
+const (
+	Website OGType = "website"
+	Article OGType = "article"
+	Profile OGType = "profile"
+	Book    OGType = "book"
+	Video   OGType = "video.other"
+	Music   OGType = "music.song"
+)
+
+// Card is a Twitter card type.
+type Card string
+
+const (
+	Summary           Card = "summary"
+	SummaryLargeImage Card = "summary_large_image"
+	App               Card = "app"
+	Player            Card = "player"
+)
+
+// Tag is a single meta tag. Key is its property or name, such as "og:title",
+// and identifies the tag for deduplication.
+type Tag struct {
+	Key  string
+	Node node.Node
+}
+
+// Meta is an ordered set of meta tags. Setting a key that is already present
+// replaces the earlier tag. Create one with OpenGraph or TwitterCard.
+type Meta struct {
+	tags []Tag
+}
+
+// OpenGraph creates the Open Graph tags for a page. Empty arguments are
+// omitted. title is limited to MaxTitle and description to MaxDescription.
+//
+// Usage:
+//
+//	seo.OpenGraph(post.Title, post.Summary, post.Cover, post.URL, seo.Article).
+//	    SiteName("Acme Blog").
+//	    ImageAlt(post.CoverAlt)
+func OpenGraph(title, description, image, url string, typ OGType) *Meta {
+	m := &Meta{}
+	m.property("og:title", truncate(title, MaxTitle))
+	m.property("og:description", truncate(description, MaxDescription))
+	m.property("og:image", image)
+	m.property("og:url", url)
+	m.property("og:type", string(typ))
+	return m
+}
+
+// TwitterCard creates the Twitter (X) card tags for a page. Empty arguments
+// are omitted. title is limited to MaxTitle and description to MaxDescription.
+//
+// Usage:
+//
+//	seo.TwitterCard(seo.SummaryLargeImage, post.Title, post.Summary, post.Cover).Site("@acme")
+func TwitterCard(card Card, title, description, image string) *Meta {
+	m := &Meta{}
+	m.name("twitter:card", string(card))
+	m.name("twitter:title", truncate(title, MaxTitle))
+	m.name("twitter:description", truncate(description, MaxDescription))
+	m.name("twitter:image", image)
+	return m
+}
+
+// SiteName sets og:site_name.
+func (m *Meta) SiteName(name string) *Meta {
+	m.property("og:site_name", name)
+	return m
+}
+
+// Locale sets og:locale, e.g. "en_AU".
+func (m *Meta) Locale(locale string) *Meta {
+	m.property("og:locale", locale)
+	return m
+}
+
+// ImageAlt sets the alternative text for the preview image, using the
+// og: or twitter: prefix to match the tags already present.
+func (m *Meta) ImageAlt(alt string) *Meta {
+	if m.twitter() {
+		m.name("twitter:image:alt", alt)
+	} else {
+		m.property("og:image:alt", alt)
+	}
+	return m
+}
+
+// ImageSize sets og:image:width and og:image:height, which lets crawlers
+// lay out the preview before fetching the image.
+func (m *Meta) ImageSize(width, height int) *Meta {
+	m.property("og:image:width", strconv.Itoa(width))
+	m.property("og:image:height", strconv.Itoa(height))
+	return m
+}
+
+// Site sets twitter:site, the @handle of the website.
+func (m *Meta) Site(handle string) *Meta {
+	m.name("twitter:site", handle)
+	return m
+}
+
+// Creator sets twitter:creator, the @handle of the content author.
+func (m *Meta) Creator(handle string) *Meta {
+	m.name("twitter:creator", handle)
+	return m
+}
+
+// Property sets an arbitrary property tag, such as "article:published_time".
+func (m *Meta) Property(key, content string) *Meta {
+	m.property(key, content)
+	return m
+}
+
+// Tags returns the tags in order, for contributing to a head manager.
+func (m *Meta) Tags() []Tag {
+	return m.tags
+}
+
+// Render generates the HTML representation of the tags.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (m *Meta) Render(w ...io.Writer) []byte {
+	return node.Render(m, w...)
+}
+
+// RenderBuilder writes the tags directly to a buffer.
+func (m *Meta) RenderBuilder(buf *bytes.Buffer) {
+	for _, t := range m.tags {
+		t.Node.RenderBuilder(buf)
+	}
+}
+
+// Nodes returns the meta elements.
+func (m *Meta) Nodes() []node.Node {
+	nodes := make([]node.Node, len(m.tags))
+	for i, t := range m.tags {
+		nodes[i] = t.Node
+	}
+	return nodes
+}
+
+// SetAttribute is a no-op for Meta as it is a group of elements.
+func (m *Meta) SetAttribute(_ string, _ string) {
+	// Meta does not support attributes
+}
+
+func (m *Meta) twitter() bool {
+	return len(m.tags) > 0 && strings.HasPrefix(m.tags[0].Key, "twitter:")
+}
+
+func (m *Meta) property(key, content string) {
+	if content == "" {
+		return
+	}
+	m.set(key, meta.OG(key, content))
+}
+
+func (m *Meta) name(key, content string) {
+	if content == "" {
+		return
+	}
+	m.set(key, meta.New().Name(key).Content(content))
+}
+
+func (m *Meta) set(key string, n node.Node) {
+	for i := range m.tags {
+		if m.tags[i].Key == key {
+			m.tags[i].Node = n
+			return
+		}
+	}
+	m.tags = append(m.tags, Tag{Key: key, Node: n})
+}
+
+// truncate limits s to max runes, cutting at the last word boundary and
+// appending an ellipsis.
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)[:max-1]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
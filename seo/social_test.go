@@ -0,0 +1,67 @@
+package seo
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestOpenGraph(t *testing.T) {
+	got := string(OpenGraph(`Fish & "Chips"`, "", "https://acme.example/a.png", "https://acme.example/", Article).
+		SiteName("Acme").
+		ImageAlt("A <plate>").
+		Render())
+	want := `<meta content="Fish &amp; &#34;Chips&#34;" property="og:title" />` +
+		`<meta content="https://acme.example/a.png" property="og:image" />` +
+		`<meta content="https://acme.example/" property="og:url" />` +
+		`<meta content="article" property="og:type" />` +
+		`<meta content="Acme" property="og:site_name" />` +
+		`<meta content="A &lt;plate&gt;" property="og:image:alt" />`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTwitterCard(t *testing.T) {
+	m := TwitterCard(SummaryLargeImage, "Title", "Desc", "").Site("@acme").Site("@acme_au").ImageAlt("alt")
+	got := string(m.Render())
+	want := `<meta name="twitter:card" content="summary_large_image" />` +
+		`<meta name="twitter:title" content="Title" />` +
+		`<meta name="twitter:description" content="Desc" />` +
+		`<meta name="twitter:site" content="@acme_au" />` +
+		`<meta name="twitter:image:alt" content="alt" />`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var keys []string
+	for _, tag := range m.Tags() {
+		keys = append(keys, tag.Key)
+	}
+	if got := strings.Join(keys, ","); got != "twitter:card,twitter:title,twitter:description,twitter:site,twitter:image:alt" {
+		t.Errorf("keys: got %s", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"the quick brown fox", 12, "the quick…"},
+		{"the quick, brown fox", 12, "the quick…"},
+		{"unbrokenword", 6, "unbro…"},
+		{"ééééééé", 4, "ééé…"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.in, tt.max)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d): got %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+		if utf8.RuneCountInString(got) > tt.max {
+			t.Errorf("truncate(%q, %d): %q exceeds limit", tt.in, tt.max, got)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package signature
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Format is the image format of a decoded signature.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+var (
+	// ErrEmpty is returned when no signature was drawn.
+	ErrEmpty = errors.New("signature: empty")
+	// ErrFormat is returned for malformed data URLs and unsupported types.
+	ErrFormat = errors.New("signature: unsupported format")
+	// ErrTooLarge is returned when the image exceeds the byte or pixel limits.
+	ErrTooLarge = errors.New("signature: too large")
+	// ErrUnsafe is returned for SVG containing scripts, event handlers,
+	// style sheets or external references, including processing
+	// instructions such as <?xml-stylesheet?> and CSS url() or @import.
+	ErrUnsafe = errors.New("signature: unsafe SVG content")
+)
+
+// Limits bounds what Decode accepts. Zero fields use the defaults.
+type Limits struct {
+	// MaxBytes is the maximum decoded image size. Default 256 KiB.
+	MaxBytes int
+	// MaxWidth and MaxHeight are the maximum image dimensions. Default 2000×1000.
+	MaxWidth  int
+	MaxHeight int
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = 256 << 10
+	}
+	if l.MaxWidth <= 0 {
+		l.MaxWidth = 2000
+	}
+	if l.MaxHeight <= 0 {
+		l.MaxHeight = 1000
+	}
+	return l
+}
+
+// Signature is a decoded and validated signature image.
+type Signature struct {
+	Format Format
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Decode parses a data URL submitted by a Pad (PNG) or supplied by another
+// client (PNG or SVG), and validates its size and content. An empty value, or
+// an image with no visible strokes, returns ErrEmpty.
+func Decode(dataURL string, limits Limits) (*Signature, error) {
+	l := limits.withDefaults()
+	if dataURL == "" {
+		return nil, ErrEmpty
+	}
+
+	rest, ok := strings.CutPrefix(dataURL, "data:")
+	if !ok {
+		return nil, ErrFormat
+	}
+	meta, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, ErrFormat
+	}
+	mediaType, params, _ := strings.Cut(meta, ";")
+	b64 := strings.HasSuffix(params, "base64")
+
+	// Reject oversized input before allocating the decoded image.
+	if (b64 && base64.StdEncoding.DecodedLen(len(payload)) > l.MaxBytes+3) || (!b64 && len(payload) > 3*l.MaxBytes) {
+		return nil, ErrTooLarge
+	}
+
+	var data []byte
+	var err error
+	if b64 {
+		data, err = base64.StdEncoding.DecodeString(payload)
+	} else {
+		var s string
+		s, err = url.PathUnescape(payload)
+		data = []byte(s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+	if len(data) > l.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	switch mediaType {
+	case "image/png":
+		return decodePNG(data, l)
+	case "image/svg+xml":
+		return decodeSVG(data, l)
+	}
+	return nil, ErrFormat
+}
+
+func decodePNG(data []byte, l Limits) (*Signature, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+	if cfg.Width > l.MaxWidth || cfg.Height > l.MaxHeight {
+		return nil, ErrTooLarge
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+	if blank(img) {
+		return nil, ErrEmpty
+	}
+	return &Signature{Format: PNG, Data: data, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// blank reports whether every pixel is fully transparent, which is what an
+// untouched canvas exports.
+func blank(img image.Image) bool {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// unsafeElements can execute script or load external content.
+var unsafeElements = map[string]bool{
+	"script": true, "foreignObject": true, "iframe": true, "image": true,
+	"use": true, "animate": true, "set": true, "style": true,
+}
+
+// strokeElements draw visible marks.
+var strokeElements = map[string]bool{
+	"path": true, "line": true, "polyline": true, "polygon": true,
+	"circle": true, "ellipse": true, "rect": true,
+}
+
+func decodeSVG(data []byte, l Limits) (*Signature, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = true
+
+	sig := &Signature{Format: SVG, Data: data}
+	root, strokes := false, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !root {
+				if t.Name.Local != "svg" {
+					return nil, ErrFormat
+				}
+				root = true
+				sig.Width, sig.Height = svgSize(t.Attr)
+			}
+			if unsafeElements[t.Name.Local] {
+				return nil, ErrUnsafe
+			}
+			for _, a := range t.Attr {
+				name := strings.ToLower(a.Name.Local)
+				if strings.HasPrefix(name, "on") {
+					return nil, ErrUnsafe
+				}
+				if name == "href" && !strings.HasPrefix(a.Value, "#") || external(a.Value) {
+					return nil, ErrUnsafe
+				}
+			}
+			if strokeElements[t.Name.Local] {
+				strokes++
+			}
+		case xml.ProcInst:
+			// Only the XML declaration is allowed; others, such as
+			// xml-stylesheet, can load external content.
+			if t.Target != "xml" {
+				return nil, ErrUnsafe
+			}
+		case xml.Directive:
+			// DOCTYPE declarations can define entities that expand to external content.
+			return nil, ErrUnsafe
+		}
+	}
+	if !root {
+		return nil, ErrFormat
+	}
+	if sig.Width > l.MaxWidth || sig.Height > l.MaxHeight {
+		return nil, ErrTooLarge
+	}
+	if strokes == 0 {
+		return nil, ErrEmpty
+	}
+	return sig, nil
+}
+
+// external reports whether an attribute value, such as a style or a fill,
+// uses CSS to load content from outside the document. Backslashes are
+// rejected as CSS escapes could otherwise hide either form.
+func external(v string) bool {
+	v = strings.ToLower(v)
+	if strings.Contains(v, "@import") || strings.Contains(v, `\`) {
+		return true
+	}
+	for {
+		i := strings.Index(v, "url(")
+		if i < 0 {
+			return false
+		}
+		v = strings.TrimLeft(v[i+4:], " \t\n\r\f'\"")
+		if !strings.HasPrefix(v, "#") {
+			return true
+		}
+	}
+}
+
+// svgSize reads the width and height attributes, falling back to the viewBox.
+func svgSize(attrs []xml.Attr) (int, int) {
+	var w, h int
+	var viewBox string
+	for _, a := range attrs {
+		switch a.Name.Local {
+		case "width":
+			w = dimension(a.Value)
+		case "height":
+			h = dimension(a.Value)
+		case "viewBox":
+			viewBox = a.Value
+		}
+	}
+	if (w == 0 || h == 0) && viewBox != "" {
+		if f := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(f) == 4 {
+			if w == 0 {
+				w = dimension(f[2])
+			}
+			if h == 0 {
+				h = dimension(f[3])
+			}
+		}
+	}
+	return w, h
+}
+
+func dimension(s string) int {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "px"), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return int(f + 0.5)
+}
@@ -0,0 +1,117 @@
+// Package signature provides a signature and initials capture component and
+// the server-side decoding and validation of what it submits.
+//
+// The pad is a <canvas> the user draws on with a mouse, pen or finger. Each
+// stroke updates a hidden form field with the drawing as a PNG data URL, so
+// the signature is submitted with the surrounding form like any other field.
+//
+// Usage:
+//
+//	form.New(
+//	    signature.Pad("signature").Label("Sign here"),
+//	    signature.Script(),
+//	    button.Submit("Accept"),
+//	)
+//
+//	sig, err := signature.Decode(r.FormValue("signature"), signature.Limits{})
+//	if errors.Is(err, signature.ErrEmpty) {
+//	    // ask the user to sign
+//	}
+package signature
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jpl-au/fluent/html5/button"
+	"github.com/jpl-au/fluent/html5/canvas"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/node"
+)
+
+// PadNode is a signature capture field. Create one with Pad or Initials.
+type PadNode struct {
+	name   string
+	label  string
+	width  int
+	height int
+}
+
+// Pad creates a signature field that submits under name.
+func Pad(name string) *PadNode {
+	return &PadNode{name: name, label: "Signature", width: 480, height: 160}
+}
+
+// Initials creates a smaller field for initialling individual clauses.
+func Initials(name string) *PadNode {
+	return &PadNode{name: name, label: "Initials", width: 160, height: 80}
+}
+
+// Label sets the accessible label of the drawing surface.
+func (p *PadNode) Label(label string) *PadNode {
+	p.label = label
+	return p
+}
+
+// Size sets the canvas size in pixels. This is also the size of the
+// submitted image, so keep it within the Limits used by Decode.
+func (p *PadNode) Size(width, height int) *PadNode {
+	p.width, p.height = width, height
+	return p
+}
+
+// Render generates the HTML representation of the pad.
+// If a writer is provided, the output is written to it and nil is returned.
+// If no writer is provided, the output is returned as a byte slice.
+func (p *PadNode) Render(w ...io.Writer) []byte {
+	return node.Render(p, w...)
+}
+
+// RenderBuilder writes the pad directly to a buffer.
+func (p *PadNode) RenderBuilder(buf *bytes.Buffer) {
+	p.tree().RenderBuilder(buf)
+}
+
+// Nodes returns the element tree of the pad.
+func (p *PadNode) Nodes() []node.Node {
+	return []node.Node{p.tree()}
+}
+
+// SetAttribute is a no-op for PadNode as its attributes are fixed.
+func (p *PadNode) SetAttribute(_ string, _ string) {
+	// PadNode does not support attributes
+}
+
+func (p *PadNode) tree() node.Node {
+	return div.New(
+		canvas.New().
+			Width(p.width).
+			Height(p.height).
+			Role("img").
+			SetAria("label", p.label).
+			Style("touch-action:none"),
+		input.Hidden(p.name, ""),
+		button.Button("Clear").SetData("signature-clear", ""),
+	).Class("signature-pad").SetData("signature-pad", "")
+}
+
+// Script returns the client script that drives every pad on the page.
+// Include it once, after the pads.
+func Script() node.Node {
+	return script.RawText(padJS)
+}
+
+const padJS = `document.querySelectorAll("[data-signature-pad]").forEach(function(el){` +
+	`if(el.dataset.ready)return;el.dataset.ready="1";` +
+	`var c=el.querySelector("canvas"),f=el.querySelector("input"),x=c.getContext("2d"),d=false;` +
+	`x.lineWidth=2;x.lineCap="round";x.lineJoin="round";` +
+	`function at(e){var r=c.getBoundingClientRect();return[(e.clientX-r.left)*c.width/r.width,(e.clientY-r.top)*c.height/r.height]}` +
+	`c.addEventListener("pointerdown",function(e){d=true;c.setPointerCapture(e.pointerId);var q=at(e);x.beginPath();x.moveTo(q[0],q[1])});` +
+	`c.addEventListener("pointermove",function(e){if(!d)return;var q=at(e);x.lineTo(q[0],q[1]);x.stroke()});` +
+	`function end(){if(!d)return;d=false;f.value=c.toDataURL("image/png")}` +
+	`c.addEventListener("pointerup",end);c.addEventListener("pointercancel",end);` +
+	`var b=el.querySelector("[data-signature-clear]");` +
+	`if(b)b.addEventListener("click",function(){x.clearRect(0,0,c.width,c.height);f.value=""})` +
+	`});`
@@ -0,0 +1,102 @@
+package signature
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func pngURL(t *testing.T, w, h int, mark bool) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if mark {
+		img.Set(w/2, h/2, color.Black)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func svgURL(svg string) string {
+	return "data:image/svg+xml," + url.PathEscape(svg)
+}
+
+func TestPad(t *testing.T) {
+	got := string(Pad(`sig"x`).Label("Sign <here>").Render())
+	want := `<div class="signature-pad" data-signature-pad="">` +
+		`<canvas height="160" width="480" aria-label="Sign &lt;here&gt;" style="touch-action:none" role="img"></canvas>` +
+		`<input name="sig&#34;x" type="hidden" />` +
+		`<button type="button" data-signature-clear="">Clear</button></div>`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if !strings.Contains(string(Initials("i").Render()), `height="80" width="160"`) {
+		t.Error("initials: unexpected size")
+	}
+}
+
+func TestDecodePNG(t *testing.T) {
+	sig, err := Decode(pngURL(t, 300, 100, true), Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.Format != PNG || sig.Width != 300 || sig.Height != 100 {
+		t.Errorf("got %+v", sig)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		limits Limits
+		want   error
+	}{
+		{"empty value", "", Limits{}, ErrEmpty},
+		{"blank canvas", pngURL(t, 50, 50, false), Limits{}, ErrEmpty},
+		{"not a data url", "https://example.com/x.png", Limits{}, ErrFormat},
+		{"unsupported type", "data:image/gif;base64,R0lG", Limits{}, ErrFormat},
+		{"bad base64", "data:image/png;base64,!!!", Limits{}, ErrFormat},
+		{"too wide", pngURL(t, 300, 100, true), Limits{MaxWidth: 200}, ErrTooLarge},
+		{"too many bytes", pngURL(t, 300, 100, true), Limits{MaxBytes: 10}, ErrTooLarge},
+		{"svg script", svgURL(`<svg width="10" height="10"><script>alert(1)</script><path d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg handler", svgURL(`<svg width="10" height="10"><path onclick="x()" d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg external href", svgURL(`<svg xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="https://x"><path d="M0 0"/></a></svg>`), Limits{}, ErrUnsafe},
+		{"svg stylesheet", svgURL(`<?xml version="1.0"?><?xml-stylesheet href="https://x/s.css"?><svg><path d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg style url", svgURL(`<svg><path style="fill:URL( 'https://x/p' )" d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg style import", svgURL(`<svg><path style="@import 'https://x'" d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg style escape", svgURL(`<svg><path style="fill:u\72l(https://x)" d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg fill url", svgURL(`<svg><path fill="url(https://x/p)" d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg style element", svgURL(`<svg><style>@import url(https://x);</style><path d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg doctype", svgURL(`<!DOCTYPE svg [<!ENTITY x "y">]><svg><path d="M0 0"/></svg>`), Limits{}, ErrUnsafe},
+		{"svg no strokes", svgURL(`<svg width="10" height="10"></svg>`), Limits{}, ErrEmpty},
+		{"svg too tall", svgURL(`<svg viewBox="0 0 100 5000"><path d="M0 0"/></svg>`), Limits{}, ErrTooLarge},
+		{"not svg", svgURL(`<html></html>`), Limits{}, ErrFormat},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt.in, tt.limits); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeSVG(t *testing.T) {
+	sig, err := Decode(svgURL(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 400 120"><path d="M10 10 L50 50"/></svg>`), Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.Format != SVG || sig.Width != 400 || sig.Height != 120 {
+		t.Errorf("got %+v", sig)
+	}
+	if _, err := Decode(svgURL(`<svg width="10" height="10"><path style="stroke:url(#ink)" fill="url( '#ink' )" d="M0 0"/></svg>`), Limits{}); err != nil {
+		t.Errorf("local url(): %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package skeleton generates loading placeholders from element trees: the
+// same elements and classes as the real content, with text replaced by grey
+// bars sized from its length and images replaced by blocks. Shown while a
+// page streams in or in place of content skipped by a partial render, a
+// skeleton keeps the layout steady instead of jumping as content arrives.
+//
+// Usage:
+//
+//	// A placeholder shaped like a card built from sample data.
+//	placeholder := skeleton.Of(Card(Product{Name: "Product name", Price: 100}))
+//	fluent.RenderContext(ctx, page, w, fluent.Partial(placeholder))
+//
+//	// With the default styles.
+//	head.New(style.RawText(skeleton.CSS))
+package skeleton
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Classes of the generated placeholders.
+const (
+	// BarClass marks the bars written in place of text.
+	BarClass = "skeleton-bar"
+	// BlockClass marks the blocks written in place of images and other
+	// embedded content.
+	BlockClass = "skeleton-block"
+)
+
+// CSS styles BarClass and BlockClass as pulsing grey shapes. Pages may use
+// it as it is or style the classes themselves.
+const CSS = `.skeleton-bar,.skeleton-block{display:inline-block;background:#e2e5e9;border-radius:4px;animation:skeleton-pulse 1.5s ease-in-out infinite}` +
+	`.skeleton-bar{height:1em;max-width:100%;margin-right:.5ch;vertical-align:middle}` +
+	`.skeleton-block{min-width:4em;min-height:4em}` +
+	`@keyframes skeleton-pulse{50%{opacity:.5}}` +
+	`@media (prefers-reduced-motion:reduce){.skeleton-bar,.skeleton-block{animation:none}}`
+
+// LineWidth is the widest bar, in characters. Longer text is written as
+// several bars.
+var LineWidth = 60
+
+// DefaultWidth is the width, in characters, of the bar written for content
+// whose length is not known before it renders, such as that of node.Func,
+// and for form controls.
+var DefaultWidth = 24
+
+// omitted lists elements left out of a skeleton.
+var omitted = map[string]bool{
+	"script": true, "style": true, "template": true, "noscript": true,
+	"link": true, "meta": true, "base": true, "title": true,
+}
+
+// media lists elements replaced by a block.
+var media = map[string]bool{
+	"img": true, "picture": true, "video": true, "audio": true, "canvas": true,
+	"svg": true, "iframe": true, "embed": true, "object": true,
+}
+
+// controls lists form controls replaced by a bar.
+var controls = map[string]bool{
+	"input": true, "select": true, "textarea": true, "progress": true, "meter": true,
+}
+
+// kept lists the attributes copied to a skeleton element. Only those that
+// affect layout are kept, so the placeholder has no behaviour, ids or links.
+var kept = map[string]bool{
+	"class": true, "style": true, "hidden": true,
+	"colspan": true, "rowspan": true, "span": true,
+}
+
+// Of returns a skeleton of n. The tree is walked once, when Of is called,
+// and the skeleton is stored as static output, so it costs nothing to render
+// repeatedly. Functions in the tree are not called; each is shown as a bar
+// of DefaultWidth.
+func Of(n node.Node) node.Node {
+	var buf bytes.Buffer
+	write(&buf, n)
+	return node.Bytes(buf.Bytes())
+}
+
+// write appends the skeleton of n.
+func write(buf *bytes.Buffer, n node.Node) {
+	if n == nil {
+		return
+	}
+	if el, ok := n.(node.Element); ok {
+		element(buf, el)
+		return
+	}
+	if children := n.Nodes(); len(children) > 0 {
+		for _, child := range children {
+			write(buf, child)
+		}
+		return
+	}
+	if size := node.SizeHint(n); size > 0 {
+		bars(buf, size)
+		return
+	}
+	if d, ok := n.(interface{ Dynamic() bool }); !ok || d.Dynamic() {
+		bars(buf, DefaultWidth)
+	}
+}
+
+// element appends the skeleton of el: its tag with the attributes in kept,
+// around the skeletons of its children.
+func element(buf *bytes.Buffer, el node.Element) {
+	var open bytes.Buffer
+	el.RenderOpen(&open)
+	tokens := markup.Tokenize(open.String())
+	if len(tokens) == 0 || tokens[0].Type != markup.StartTagToken && tokens[0].Type != markup.SelfClosingTagToken {
+		for _, child := range el.Nodes() {
+			write(buf, child)
+		}
+		return
+	}
+	tag := tokens[0]
+	switch {
+	case omitted[tag.Data]:
+		return
+	case media[tag.Data]:
+		block(buf, tag.Attrs)
+		return
+	case controls[tag.Data]:
+		if !hiddenInput(tag) {
+			bars(buf, DefaultWidth)
+		}
+		return
+	}
+
+	buf.WriteString("<" + tag.Data)
+	for _, a := range tag.Attrs {
+		if !kept[a.Key] {
+			continue
+		}
+		buf.WriteString(" " + a.Key)
+		if a.Val != "" {
+			buf.WriteString(`="` + escape.String(a.Val) + `"`)
+		}
+	}
+	buf.WriteByte('>')
+	if markup.IsVoid(tag.Data) {
+		return
+	}
+	for _, child := range el.Nodes() {
+		write(buf, child)
+	}
+	buf.WriteString("</" + tag.Data + ">")
+}
+
+// hiddenInput reports whether tag is an input of type hidden.
+func hiddenInput(tag markup.Token) bool {
+	if tag.Data != "input" {
+		return false
+	}
+	for _, a := range tag.Attrs {
+		if a.Key == "type" && a.Val == "hidden" {
+			return true
+		}
+	}
+	return false
+}
+
+// bars appends bars covering size characters, none wider than LineWidth.
+func bars(buf *bytes.Buffer, size int) {
+	line := max(LineWidth, 1)
+	for size > 0 {
+		w := min(size, line)
+		buf.WriteString(`<span class="` + BarClass + `" style="width:` + strconv.Itoa(w) + `ch" aria-hidden="true"></span>`)
+		size -= w
+	}
+}
+
+// block appends a block sized from the width and height attributes in
+// attrs, when they are given in pixels.
+func block(buf *bytes.Buffer, attrs []markup.Attr) {
+	var style string
+	for _, a := range attrs {
+		if a.Key != "width" && a.Key != "height" {
+			continue
+		}
+		if px, err := strconv.Atoi(a.Val); err == nil && px > 0 {
+			style += a.Key + ":" + a.Val + "px;"
+		}
+	}
+	buf.WriteString(`<span class="` + BlockClass + `"`)
+	if style != "" {
+		buf.WriteString(` style="` + style + `"`)
+	}
+	buf.WriteString(` aria-hidden="true"></span>`)
+}
@@ -0,0 +1,50 @@
+package skeleton_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/a"
+	"github.com/jpl-au/fluent/html5/article"
+	"github.com/jpl-au/fluent/html5/attr/inputtype"
+	"github.com/jpl-au/fluent/html5/h2"
+	"github.com/jpl-au/fluent/html5/img"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/skeleton"
+)
+
+func bar(width int) string {
+	return `<span class="skeleton-bar" style="width:` + strconv.Itoa(width) + `ch" aria-hidden="true"></span>`
+}
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"text", h2.Text("Hello").Class("title").ID("t"), `<h2 class="title">` + bar(5) + `</h2>`},
+		{"long text", p.Text(strings.Repeat("x", 70)), `<p>` + bar(60) + bar(10) + `</p>`},
+		{"link", a.Text("Go").Href("/x"), `<a>` + bar(2) + `</a>`},
+		{"image", img.New().Src("/a.png").Width(320).Height(200), `<span class="skeleton-block" style="width:320px;height:200px;" aria-hidden="true"></span>`},
+		{"input", input.New().Name("q"), bar(skeleton.DefaultWidth)},
+		{"hidden input", input.New().Type(inputtype.Hidden).Name("csrf"), ``},
+		{"script", script.RawText("alert(1)"), ``},
+		{"func", p.New(node.Func(func() node.Node { return nil })), `<p>` + bar(skeleton.DefaultWidth) + `</p>`},
+		{"empty", p.New(node.Empty()), `<p></p>`},
+		{"nil", nil, ``},
+		{"nested", article.New(h2.Text("Title"), p.Text("Body")).Class("card"),
+			`<article class="card"><h2>` + bar(5) + `</h2><p>` + bar(4) + `</p></article>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(skeleton.Of(tt.node).Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
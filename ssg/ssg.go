@@ -0,0 +1,214 @@
+// Package ssg renders fluent pages to static files.
+//
+// Register a constructor for each route, add any asset directories, and call
+// Build to write the complete site to disk - no server required.
+//
+// Usage:
+//
+//	site := ssg.New()
+//	site.Route("/", pages.Home)
+//	site.Route("/about", pages.About)
+//	site.Route("/feed.xml", pages.Feed)
+//	site.Assets("static", "assets")
+//	site.Minify = true
+//	if err := site.Build("public"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Routes without a file extension are written as directory indexes, so
+// "/about" becomes about/index.html and is served at /about/ by any static host.
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/minify"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Site is a set of routes and assets to render. Create one with New.
+type Site struct {
+	// Minify runs HTML output through minify.HTML. Files with a non-HTML
+	// extension (feeds, sitemaps) are never minified.
+	Minify bool
+
+	// Clock sets the time pages are rendered at. Scheduled content (see
+	// node.Between) is included or omitted as it would be at that time,
+	// which lets you preview the site at a future date. Defaults to time.Now.
+	Clock node.Clock
+
+	mu     sync.Mutex
+	routes map[string]func() node.Node
+	assets []asset
+	next   time.Time
+}
+
+// asset is a source tree copied into the output directory.
+type asset struct {
+	fsys fs.FS
+	dst  string
+}
+
+// New creates an empty Site.
+func New() *Site {
+	return &Site{routes: map[string]func() node.Node{}}
+}
+
+// Route registers ctor to render the page at path. Registering the same
+// path twice replaces the earlier constructor.
+func (s *Site) Route(path string, ctor func() node.Node) {
+	s.mu.Lock()
+	s.routes[clean(path)] = ctor
+	s.mu.Unlock()
+}
+
+// Routes returns the registered paths in sorted order.
+func (s *Site) Routes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := make([]string, 0, len(s.routes))
+	for p := range s.routes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Assets copies the directory src into dst within the output directory.
+// An empty dst copies into the output root.
+func (s *Site) Assets(src, dst string) {
+	s.AssetsFS(os.DirFS(src), dst)
+}
+
+// AssetsFS copies fsys, such as an embed.FS, into dst within the output directory.
+func (s *Site) AssetsFS(fsys fs.FS, dst string) {
+	s.mu.Lock()
+	s.assets = append(s.assets, asset{fsys: fsys, dst: dst})
+	s.mu.Unlock()
+}
+
+// Build renders every route and copies every asset into dir, creating
+// directories as needed. Existing files are overwritten; other files in dir
+// are left alone.
+func (s *Site) Build(dir string) error {
+	s.mu.Lock()
+	assets := append([]asset(nil), s.assets...)
+	s.next = time.Time{}
+	s.mu.Unlock()
+
+	ctx := context.Background()
+	if s.Clock != nil {
+		ctx = node.WithClock(ctx, s.Clock)
+	}
+	ctx, schedule := node.TrackSchedule(ctx)
+	defer func() {
+		s.mu.Lock()
+		s.next = schedule.Next()
+		s.mu.Unlock()
+	}()
+
+	for _, a := range assets {
+		if err := copyFS(a.fsys, filepath.Join(dir, filepath.FromSlash(a.dst))); err != nil {
+			return fmt.Errorf("ssg: copying assets to %q: %w", a.dst, err)
+		}
+	}
+	for _, route := range s.Routes() {
+		if err := s.render(ctx, dir, route); err != nil {
+			return fmt.Errorf("ssg: rendering %q: %w", route, err)
+		}
+	}
+	return nil
+}
+
+// NextChange returns the earliest time after the last Build at which
+// scheduled content on any page starts or stops showing, or the zero time
+// when the site has no pending scheduled content. Rebuild the site at that
+// time to publish embargoed content and remove expired content.
+func (s *Site) NextChange() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// render writes a single route to disk.
+func (s *Site) render(ctx context.Context, dir, route string) error {
+	s.mu.Lock()
+	ctor := s.routes[route]
+	s.mu.Unlock()
+
+	file := Output(route)
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, ctor(), &buf); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	if s.Minify && strings.HasSuffix(file, ".html") {
+		out = minify.HTML(out)
+	}
+	target := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, out, 0o644)
+}
+
+// Output returns the file path, relative to the output directory, that route
+// is written to.
+//
+//	"/"          -> "index.html"
+//	"/about"     -> "about/index.html"
+//	"/feed.xml"  -> "feed.xml"
+func Output(route string) string {
+	route = strings.TrimPrefix(clean(route), "/")
+	if route == "" {
+		return "index.html"
+	}
+	if path.Ext(route) != "" {
+		return route
+	}
+	return route + "/index.html"
+}
+
+// clean normalises a route to a rooted path without a trailing slash.
+func clean(route string) string {
+	return path.Clean("/" + route)
+}
+
+// copyFS copies every file in fsys into dst.
+func copyFS(fsys fs.FS, dst string) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		src, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, src); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
@@ -0,0 +1,101 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestOutput(t *testing.T) {
+	tests := map[string]string{
+		"/":              "index.html",
+		"":               "index.html",
+		"/about":         "about/index.html",
+		"/about/":        "about/index.html",
+		"/docs/intro":    "docs/intro/index.html",
+		"/feed.xml":      "feed.xml",
+		"/404.html":      "404.html",
+		"/../etc/passwd": "etc/passwd/index.html",
+	}
+	for route, want := range tests {
+		if got := Output(route); got != want {
+			t.Errorf("Output(%q) = %q, want %q", route, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	site := New()
+	site.Minify = true
+	site.Route("/", func() node.Node {
+		return html.New(body.New(text.RawText("\n  "), p.Text("home")))
+	})
+	site.Route("/about", func() node.Node { return p.Text("about") })
+	site.Route("/feed.xml", func() node.Node { return text.RawText("<rss>\n  <channel/>\n</rss>") })
+	site.AssetsFS(fstest.MapFS{
+		"css/site.css": {Data: []byte("body{}")},
+	}, "static")
+
+	if err := site.Build(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"index.html":          "<!DOCTYPE html><html><body><p>home</p></body></html>",
+		"about/index.html":    "<p>about</p>",
+		"feed.xml":            "<rss>\n  <channel/>\n</rss>",
+		"static/css/site.css": "body{}",
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("reading %s: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBuildSchedule(t *testing.T) {
+	launch := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	site := New()
+	site.Route("/", func() node.Node {
+		return node.Between(launch, time.Time{}, p.Text("launched"), p.Text("coming soon"))
+	})
+
+	dir := t.TempDir()
+	site.Clock = node.FixedClock(launch.Add(-24 * time.Hour))
+	if err := site.Build(dir); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "index.html"))
+	if string(got) != "<p>coming soon</p>" {
+		t.Errorf("before launch = %q", got)
+	}
+	if !site.NextChange().Equal(launch) {
+		t.Errorf("NextChange() = %v, want %v", site.NextChange(), launch)
+	}
+
+	site.Clock = node.FixedClock(launch)
+	if err := site.Build(dir); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(filepath.Join(dir, "index.html"))
+	if string(got) != "<p>launched</p>" {
+		t.Errorf("after launch = %q", got)
+	}
+	if !site.NextChange().IsZero() {
+		t.Errorf("NextChange() = %v, want zero", site.NextChange())
+	}
+}
@@ -0,0 +1,190 @@
+// Package stimulus builds the data attributes that wire server-rendered markup
+// to Stimulus controllers, converting names to the casing Stimulus expects so
+// markup and controllers cannot drift apart through a typo.
+//
+// A Controller is created once from the controller's name and its methods
+// return attributes for the element they belong on. Apply sets them, joining
+// the space-separated attributes (data-controller, data-action and targets)
+// when several controllers share an element.
+//
+// Usage:
+//
+//	var clipboard = stimulus.New("clipboard")
+//
+//	func Copy(text string) node.Node {
+//	    return stimulus.Apply(div.New(
+//	        stimulus.Apply(input.Text("source").Value(text).Readonly(),
+//	            clipboard.Target("source")),
+//	        stimulus.Apply(button.Text("Copy"),
+//	            clipboard.Action("click", "copy")),
+//	    ), clipboard.Attach(), clipboard.Value("successDuration", 2000))
+//	}
+//
+// renders
+//
+//	<div data-controller="clipboard" data-clipboard-success-duration-value="2000">
+//	  <input ... data-clipboard-target="source" />
+//	  <button data-action="click-&gt;clipboard#copy">Copy</button>
+//	</div>
+package stimulus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// Controller builds attributes for one Stimulus controller.
+type Controller struct {
+	id string
+}
+
+// New returns the controller for name, which may be given as a Stimulus
+// identifier ("list-item"), as a controller file path ("users/list_item")
+// or in camel case ("ListItem"); all are converted to the identifier.
+func New(name string) Controller {
+	return Controller{id: Identifier(name)}
+}
+
+// Identifier converts a controller name or path to a Stimulus identifier:
+// path separators become "--", underscores "-" and camel case is split, so
+// "admin/UserList_controller" becomes "admin--user-list".
+func Identifier(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".js"), ".ts")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "_controller"), "-controller")
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = kebab(strings.ReplaceAll(p, "_", "-"))
+	}
+	return strings.Join(parts, "--")
+}
+
+// Identifier returns the controller's identifier.
+func (c Controller) Identifier() string {
+	return c.id
+}
+
+// Attach returns data-controller, connecting the controller to an element.
+func (c Controller) Attach() node.Attribute {
+	return node.Attribute{Key: "data-controller", Value: c.id}
+}
+
+// Target returns the attribute marking an element as the named target,
+// available to the controller as this.<name>Target.
+func (c Controller) Target(name string) node.Attribute {
+	return node.Attribute{Key: "data-" + c.id + "-target", Value: name}
+}
+
+// Action returns data-action calling method on event. An empty event uses
+// the element's default event, such as click for a button. Options such as
+// "prevent" or "once" are appended as ":option".
+func (c Controller) Action(event, method string, options ...string) node.Attribute {
+	var b strings.Builder
+	if event != "" {
+		b.WriteString(event)
+		for _, o := range options {
+			b.WriteByte(':')
+			b.WriteString(o)
+		}
+		b.WriteString("->")
+	}
+	b.WriteString(c.id)
+	b.WriteByte('#')
+	b.WriteString(method)
+	if event == "" {
+		for _, o := range options {
+			b.WriteByte(':')
+			b.WriteString(o)
+		}
+	}
+	return node.Attribute{Key: "data-action", Value: b.String()}
+}
+
+// Value returns the attribute for the named value, available to the
+// controller as this.<name>Value. Strings are written as given, numbers and
+// booleans in their literal form, and anything else as JSON.
+func (c Controller) Value(name string, v any) node.Attribute {
+	return node.Attribute{Key: "data-" + c.id + "-" + kebab(name) + "-value", Value: encode(v)}
+}
+
+// Param returns the attribute for the named action parameter, passed to
+// actions on the same element as event.params.<name>.
+func (c Controller) Param(name string, v any) node.Attribute {
+	return node.Attribute{Key: "data-" + c.id + "-" + kebab(name) + "-param", Value: encode(v)}
+}
+
+// Class returns the attribute for the named CSS class, available to the
+// controller as this.<name>Class.
+func (c Controller) Class(name, classes string) node.Attribute {
+	return node.Attribute{Key: "data-" + c.id + "-" + kebab(name) + "-class", Value: classes}
+}
+
+// Outlet returns the attribute connecting the outlet controller to the
+// elements matching selector.
+func (c Controller) Outlet(outlet Controller, selector string) node.Attribute {
+	return node.Attribute{Key: "data-" + c.id + "-" + outlet.id + "-outlet", Value: selector}
+}
+
+// joined lists attributes whose values are space-separated lists.
+func joined(key string) bool {
+	return key == "data-controller" || key == "data-action" || strings.HasSuffix(key, "-target")
+}
+
+// Apply sets attrs on n and returns it. Repeated data-controller, data-action
+// and target attributes are joined with spaces rather than replaced.
+func Apply[T node.Node](n T, attrs ...node.Attribute) T {
+	var order []string
+	values := map[string]string{}
+	for _, a := range attrs {
+		prev, seen := values[a.Key]
+		switch {
+		case !seen:
+			order = append(order, a.Key)
+			values[a.Key] = a.Value
+		case joined(a.Key):
+			values[a.Key] = prev + " " + a.Value
+		default:
+			values[a.Key] = a.Value
+		}
+	}
+	for _, k := range order {
+		n.SetAttribute(k, values[k])
+	}
+	return n
+}
+
+// encode formats a value attribute.
+func encode(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// kebab converts camelCase to kebab-case.
+func kebab(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower-to-upper change, or at the last
+			// capital of an acronym ("HTMLParser" -> "html-parser").
+			if i > 0 && runes[i-1] != '-' && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
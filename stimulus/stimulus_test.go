@@ -0,0 +1,68 @@
+package stimulus
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/button"
+	"github.com/jpl-au/fluent/html5/div"
+)
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"clipboard", "clipboard"},
+		{"list-item", "list-item"},
+		{"list_item", "list-item"},
+		{"ListItem", "list-item"},
+		{"users/list_item_controller.js", "users--list-item"},
+		{"admin/HTMLEditor", "admin--html-editor"},
+	}
+	for _, tt := range tests {
+		if got := Identifier(tt.in); got != tt.want {
+			t.Errorf("Identifier(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	c := New("users/ListItem")
+	tests := []struct {
+		name     string
+		key, val string
+		got      func() (string, string)
+	}{
+		{"target", "data-users--list-item-target", "nameField", func() (string, string) { a := c.Target("nameField"); return a.Key, a.Value }},
+		{"action", "data-action", "click:prevent->users--list-item#save", func() (string, string) { a := c.Action("click", "save", "prevent"); return a.Key, a.Value }},
+		{"default action", "data-action", "users--list-item#save:once", func() (string, string) { a := c.Action("", "save", "once"); return a.Key, a.Value }},
+		{"number value", "data-users--list-item-success-duration-value", "2000", func() (string, string) { a := c.Value("successDuration", 2000); return a.Key, a.Value }},
+		{"object value", "data-users--list-item-user-value", `{"id":7}`, func() (string, string) { a := c.Value("user", map[string]int{"id": 7}); return a.Key, a.Value }},
+		{"param", "data-users--list-item-item-id-param", "42", func() (string, string) { a := c.Param("itemId", 42); return a.Key, a.Value }},
+		{"class", "data-users--list-item-loading-class", "opacity-50 busy", func() (string, string) { a := c.Class("loading", "opacity-50 busy"); return a.Key, a.Value }},
+		{"outlet", "data-users--list-item-result-outlet", ".result", func() (string, string) { a := c.Outlet(New("result"), ".result"); return a.Key, a.Value }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, v := tt.got()
+			if k != tt.key || v != tt.val {
+				t.Errorf("got %s=%q, want %s=%q", k, v, tt.key, tt.val)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	clip, tip := New("clipboard"), New("tooltip")
+	el := Apply(button.Text("Copy"),
+		clip.Attach(), tip.Attach(),
+		clip.Action("click", "copy"), tip.Action("mouseenter", "show"),
+		clip.Value("text", `a"b`), clip.Value("text", "final"),
+	)
+	want := `<button data-controller="clipboard tooltip" data-action="click-&gt;clipboard#copy mouseenter-&gt;tooltip#show" data-clipboard-text-value="final">Copy</button>`
+	if got := string(el.Render()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	d := Apply(div.New(), clip.Target("source"), clip.Target("output"))
+	if got, want := string(d.Render()), `<div data-clipboard-target="source output"></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
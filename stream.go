@@ -0,0 +1,146 @@
+package fluent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// streamChunk is the amount of output StreamRender collects before writing
+// it to the pipe.
+const streamChunk = 4 << 10
+
+// StreamRender renders n in the background and returns its output as a
+// reader, for APIs that consume an io.Reader such as object storage uploads
+// and multipart writers. The render runs only as fast as the reader drains
+// it: output is written through an io.Pipe in chunks between nodes, and the
+// render waits while a chunk is unread, so a large page is never held in
+// memory whole.
+//
+// Elements are streamed child by child, and node.Chan node by node as they
+// arrive; other nodes are written whole. Once a node defers output with
+// node.Defer, the rest of the page is held back until the placeholders can
+// be expanded at the end.
+//
+// The render stops if ctx is done, following policy as RenderContext does;
+// with Abort the reader returns the context error after whatever output was
+// already sent. Closing the reader early cancels the render.
+//
+// Usage:
+//
+//	body := fluent.StreamRender(ctx, report)
+//	defer body.Close()
+//	_, err := uploader.Upload(ctx, &s3.PutObjectInput{Bucket: b, Key: k, Body: body})
+func StreamRender(ctx context.Context, n node.Node, policy ...DeadlinePolicy) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		defer cancel()
+		_ = pw.CloseWithError(stream(ctx, n, pw, policy))
+	}()
+	return &streamReader{PipeReader: pr, cancel: cancel}
+}
+
+type streamReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+// Close stops the render and closes the pipe.
+func (s *streamReader) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
+func stream(ctx context.Context, n node.Node, w io.Writer, policy []DeadlinePolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p := Abort
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+
+	buf := NewBuffer()
+	defer PutBuffer(buf)
+
+	session := &node.Session{
+		Context:     ctx,
+		Partial:     p.partial,
+		Placeholder: p.placeholder,
+	}
+	s := &streamer{w: w, buf: buf, session: session}
+	release := node.Bind(buf, session)
+	defer release()
+	s.render(n)
+	if s.err != nil {
+		return s.err
+	}
+
+	err := ctx.Err()
+	if err != nil && !p.partial {
+		return err
+	}
+	if session.Deferred() {
+		expanded := NewBuffer()
+		defer PutBuffer(expanded)
+		session.Expand(expanded, buf.Bytes())
+		buf = expanded
+	}
+	if _, werr := buf.WriteTo(w); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPartial, err)
+	}
+	return nil
+}
+
+// streamer writes a render to w as it progresses.
+type streamer struct {
+	w       io.Writer
+	buf     *bytes.Buffer
+	session *node.Session
+	err     error
+}
+
+func (s *streamer) render(n node.Node) {
+	if n == nil || s.err != nil {
+		return
+	}
+	if c, ok := n.(*node.ChanNode); ok {
+		c.Each(s.buf, func(child node.Node) {
+			s.render(child)
+			s.flush()
+		})
+		return
+	}
+	e, ok := n.(node.Element)
+	if !ok {
+		n.RenderBuilder(s.buf)
+		return
+	}
+	e.RenderOpen(s.buf)
+	for _, child := range e.Nodes() {
+		s.render(child)
+		s.flush()
+	}
+	e.RenderClose(s.buf)
+}
+
+// flush writes the buffered output once a chunk has built up. Nothing more
+// is written once a placeholder has been deferred, as it can only be
+// expanded when the render is complete.
+func (s *streamer) flush() {
+	if s.err != nil || s.buf.Len() < streamChunk || s.session.Deferred() {
+		return
+	}
+	if s.session.Context.Err() != nil {
+		// Leave the output for stream to discard or finish by policy.
+		return
+	}
+	_, s.err = s.buf.WriteTo(s.w)
+}
@@ -0,0 +1,144 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/html5/ul"
+	"github.com/jpl-au/fluent/node"
+)
+
+// rows builds a list of n items, counting how many have been evaluated.
+func rows(n int, evaluated *atomic.Int64) node.Node {
+	items := make([]node.Node, n)
+	for i := range items {
+		items[i] = node.Func(func() node.Node {
+			evaluated.Add(1)
+			return li.Text(strings.Repeat("x", 100))
+		})
+	}
+	return div.New(ul.New(items...))
+}
+
+func TestStreamRender(t *testing.T) {
+	var evaluated atomic.Int64
+	page := rows(500, &evaluated)
+	want := string(page.Render())
+	evaluated.Store(0)
+
+	r := fluent.StreamRender(context.Background(), page)
+	defer r.Close()
+
+	head := make([]byte, 16)
+	if _, err := io.ReadFull(r, head); err != nil {
+		t.Fatal(err)
+	}
+	// The render waits for the reader, so it cannot have run far ahead.
+	if got := evaluated.Load(); got >= 500 {
+		t.Errorf("evaluated %d rows before they were read, want fewer than 500", got)
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(head) + string(rest); got != want {
+		t.Errorf("streamed %d bytes, want the %d rendered", len(got), len(want))
+	}
+}
+
+// deferred writes the number of items rendered after it.
+type deferred struct {
+	node.Node
+	count *int
+}
+
+func (d deferred) RenderBuilder(buf *bytes.Buffer) {
+	node.Defer(buf, func(out *bytes.Buffer) {
+		out.WriteString(strings.Repeat("!", *d.count))
+	})
+}
+
+type counted struct {
+	node.Node
+	count *int
+}
+
+func (c counted) RenderBuilder(buf *bytes.Buffer) {
+	*c.count++
+	c.Node.RenderBuilder(buf)
+}
+
+func TestStreamRenderDefer(t *testing.T) {
+	count := 0
+	items := []node.Node{deferred{div.New(), &count}}
+	for range 100 {
+		items = append(items, counted{div.Text(strings.Repeat("y", 100)), &count})
+	}
+	out, err := io.ReadAll(fluent.StreamRender(context.Background(), div.New(items...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "<div>"+strings.Repeat("!", 100)+"<div>") {
+		t.Errorf("got %q..., want the placeholder expanded", out[:min(len(out), 120)])
+	}
+}
+
+func TestStreamRenderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := io.ReadAll(fluent.StreamRender(ctx, div.Text("x")))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamRenderClose(t *testing.T) {
+	var evaluated atomic.Int64
+	r := fluent.StreamRender(context.Background(), rows(10000, &evaluated))
+	if _, err := r.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("got %v after close, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestStreamRenderChan(t *testing.T) {
+	ch := make(chan node.Node)
+	sent := make(chan struct{})
+	go func() {
+		defer close(ch)
+		ch <- li.Text(strings.Repeat("x", 5000))
+		// Wait until the first row has reached the reader.
+		<-sent
+		ch <- li.Text("last")
+	}()
+
+	r := fluent.StreamRender(context.Background(), ul.New(node.Chan(ch)))
+	defer r.Close()
+	head := make([]byte, 16)
+	if _, err := io.ReadFull(r, head); err != nil {
+		t.Fatal(err)
+	}
+	close(sent)
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<ul><li>" + strings.Repeat("x", 5000) + "</li><li>last</li></ul>"
+	if got := string(head) + string(rest); got != want {
+		t.Errorf("streamed %d bytes, want %d", len(got), len(want))
+	}
+}
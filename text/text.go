@@ -3,10 +3,9 @@ package text
 import (
 	"bytes"
 	"fmt"
-	"html"
 	"io"
 
-	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/escape"
 	"github.com/jpl-au/fluent/node"
 )
 
@@ -14,7 +13,9 @@ import (
 // It implements the node.Node interface and is used internally by Text() and RawText()
 // constructor functions to handle different security models.
 type Node struct {
-	content string // The text content, possibly HTML-escaped
+	content string // The text content
+	static  []byte // The content of Static nodes, ready to copy
+	escape  bool   // Whether content is HTML-escaped as it is written
 	dynamic bool   // Whether the content is dynamically generated
 }
 
@@ -22,12 +23,16 @@ type Node struct {
 // This should be used for content that never changes, allowing JIT optimisation.
 // Warning: Static content is not html encoded/escaped.
 //
+// The content is converted to bytes once, at construction, and copied into
+// the output on each render (see node.Prerendered).
+//
 // Example:
 //
 //	text.Static("Copyright 2024") // Renders as: Copyright 2024
 func Static(str string) *Node {
 	return &Node{
 		content: str,
+		static:  []byte(str),
 		dynamic: false,
 	}
 }
@@ -35,14 +40,16 @@ func Static(str string) *Node {
 // Text creates a safe text component with automatic HTML escaping for security.
 // This is now marked as dynamic by default to handle variables and user content.
 //
-// Special HTML characters like <, >, &, and quotes are automatically escaped.
+// Special HTML characters like <, >, &, and quotes are automatically escaped
+// as the node renders, in a single pass into the output.
 //
 // Example:
 //
 //	text.Text(userName) // Renders with HTML escaping, marked as dynamic
 func Text(str string) *Node {
 	return &Node{
-		content: html.EscapeString(str),
+		content: str,
+		escape:  true,
 		dynamic: true,
 	}
 }
@@ -70,7 +77,8 @@ func RawText(str string) *Node {
 //	text.Textf("Hello, %s!", "<world>") // Renders as: Hello, &lt;world&gt;!
 func Textf(format string, a ...any) *Node {
 	return &Node{
-		content: html.EscapeString(fmt.Sprintf(format, a...)),
+		content: fmt.Sprintf(format, a...),
+		escape:  true,
 		dynamic: true,
 	}
 }
@@ -91,20 +99,63 @@ func RawTextf(format string, a ...any) *Node {
 // RenderBuilder writes the text content directly to the provided buffer.
 // This method provides efficient rendering for large node trees.
 func (tn *Node) RenderBuilder(buf *bytes.Buffer) {
+	if tn.static != nil {
+		buf.Write(tn.static)
+		return
+	}
+	if tn.escape {
+		escape.WriteString(buf, tn.content)
+		return
+	}
 	buf.WriteString(tn.content)
 }
 
 // Render returns the text content as a byte slice or writes to the provided writer.
+// The content is already a string, so it is written straight to the writer
+// without an intermediate buffer; writers implementing io.StringWriter, such
+// as bytes.Buffer and http.ResponseWriter, receive it without allocating.
 func (tn *Node) Render(w ...io.Writer) []byte {
-	buf := fluent.NewBuffer()
-	tn.RenderBuilder(buf)
-
 	if len(w) > 0 && w[0] != nil {
-		_, _ = buf.WriteTo(w[0])
-		fluent.PutBuffer(buf)
+		_, _ = tn.WriteTo(w[0])
 		return nil
 	}
-	return buf.Bytes()
+	return []byte(tn.String())
+}
+
+// WriteTo implements io.WriterTo, writing the content straight to w and
+// returning the number of bytes written and any write error.
+func (tn *Node) WriteTo(w io.Writer) (int64, error) {
+	var n int
+	var err error
+	switch {
+	case tn.static != nil:
+		n, err = w.Write(tn.static)
+	case tn.escape:
+		n, err = escape.Write(w, tn.content)
+	default:
+		n, err = io.WriteString(w, tn.content)
+	}
+	return int64(n), err
+}
+
+// SizeHint returns the rendered size of the text content.
+func (tn *Node) SizeHint() int {
+	if tn.escape {
+		return escape.Len(tn.content)
+	}
+	return len(tn.content)
+}
+
+// Prerendered returns the content of Static nodes, and nil for others.
+func (tn *Node) Prerendered() []byte {
+	return tn.static
+}
+
+// Clone returns a copy of the text node. The stored static bytes are never
+// modified, so they are shared.
+func (tn *Node) Clone() node.Node {
+	cp := *tn
+	return &cp
 }
 
 // Nodes returns an empty slice as text nodes do not have children.
@@ -123,8 +174,11 @@ func (tn *Node) SetAttribute(_ string, _ string) {
 	// Node does not support attributes
 }
 
-// String returns the text content as a string.
+// String returns the text content as a string, escaped as it renders.
 // This allows RawText to be used in contexts that require string values.
 func (tn *Node) String() string {
+	if tn.escape {
+		return escape.String(tn.content)
+	}
 	return tn.content
 }
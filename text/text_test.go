@@ -81,3 +81,22 @@ func BenchmarkRenderWriterLarge(b *testing.B) {
 		node.Render(io.Discard)
 	}
 }
+
+func TestNode_Render_WriterAllocs(t *testing.T) {
+	node := Text("Hello <World>")
+	var buf bytes.Buffer
+	buf.Grow(64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		node.Render(&buf)
+	})
+	if allocs != 0 {
+		t.Errorf("Render(writer) allocated %v times, want 0", allocs)
+	}
+	if got, want := buf.String(), "Hello &lt;World&gt;"; got != want {
+		t.Errorf("Render(writer) = %q, want %q", got, want)
+	}
+	if got := string(node.Render()); got != "Hello &lt;World&gt;" {
+		t.Errorf("Render() = %q", got)
+	}
+}
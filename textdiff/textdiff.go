@@ -0,0 +1,166 @@
+// Package textdiff compares the visible text of two renders.
+//
+// It is aimed at editorial approval screens: render the published and draft
+// versions of a page, and show the reviewer a word-level diff of what a
+// reader would actually see, with markup changes that don't affect the text
+// ignored.
+//
+// Usage:
+//
+//	div.New(
+//	    h2.Static("Changes"),
+//	    textdiff.Diff(published, draft),
+//	)
+package textdiff
+
+import (
+	"strings"
+
+	"github.com/jpl-au/fluent/html5/del"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/ins"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/internal/markup"
+	"github.com/jpl-au/fluent/internal/myers"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+)
+
+// blocks lists elements that start a new paragraph of text.
+var blocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true, "ol": true,
+	"p": true, "pre": true, "section": true, "summary": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// skipped lists elements whose content is never visible text.
+var skipped = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true, "noscript": true,
+}
+
+// breakToken marks a paragraph boundary in the token stream.
+const breakToken = "\n"
+
+// Text renders n and returns its visible text with whitespace collapsed,
+// one paragraph per line.
+func Text(n node.Node) string {
+	words := tokens(n)
+	var b strings.Builder
+	for i, w := range words {
+		if w == breakToken {
+			b.WriteString(breakToken)
+			continue
+		}
+		if i > 0 && words[i-1] != breakToken {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// tokens renders n and splits its visible text into words and paragraph breaks.
+func tokens(n node.Node) []string {
+	if n == nil {
+		return nil
+	}
+	var out []string
+	brk := func() {
+		if len(out) > 0 && out[len(out)-1] != breakToken {
+			out = append(out, breakToken)
+		}
+	}
+	var walk func(*markup.Node)
+	walk = func(m *markup.Node) {
+		switch m.Type {
+		case markup.TextNode:
+			out = append(out, strings.Fields(m.Data)...)
+			return
+		case markup.ElementNode:
+			if skipped[m.Tag] {
+				return
+			}
+			if blocks[m.Tag] {
+				brk()
+			}
+		}
+		for _, c := range m.Children {
+			walk(c)
+		}
+		if m.Type == markup.ElementNode && blocks[m.Tag] {
+			brk()
+		}
+	}
+	walk(markup.Parse(string(n.Render())))
+	if len(out) > 0 && out[len(out)-1] == breakToken {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// segment is a run of words sharing the same edit operation.
+type segment struct {
+	op    myers.Op
+	words []string
+}
+
+// Diff renders before and after and returns a node showing the word-level
+// changes to their visible text. Removed words are wrapped in <del> and added
+// words in <ins>; each paragraph is rendered as a <p> inside a
+// <div class="text-diff">.
+func Diff(before, after node.Node) node.Node {
+	a, b := tokens(before), tokens(after)
+
+	var paragraphs []node.Node
+	var segs []segment
+	flush := func() {
+		if len(segs) > 0 {
+			paragraphs = append(paragraphs, paragraph(segs))
+			segs = nil
+		}
+	}
+	for _, e := range myers.Diff(a, b) {
+		word := ""
+		switch e.Op {
+		case myers.Insert:
+			word = b[e.B]
+		default:
+			word = a[e.A]
+		}
+		if word == breakToken {
+			flush()
+			continue
+		}
+		if len(segs) > 0 && segs[len(segs)-1].op == e.Op {
+			segs[len(segs)-1].words = append(segs[len(segs)-1].words, word)
+		} else {
+			segs = append(segs, segment{op: e.Op, words: []string{word}})
+		}
+	}
+	flush()
+	return div.New(paragraphs...).Class("text-diff")
+}
+
+// paragraph renders one paragraph of segments.
+func paragraph(segs []segment) node.Node {
+	el := p.New()
+	for i, s := range segs {
+		if i > 0 {
+			el.Add(text.Static(" "))
+		}
+		words := strings.Join(s.words, " ")
+		switch s.op {
+		case myers.Insert:
+			el.Add(ins.Text(words))
+		case myers.Delete:
+			el.Add(del.Text(words))
+		default:
+			el.Add(text.Text(words))
+		}
+	}
+	return el
+}
@@ -0,0 +1,56 @@
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/article"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/html5/title"
+)
+
+func TestText(t *testing.T) {
+	page := html.New(
+		head.New(title.Text("ignored")),
+		article.New(
+			h1.Text("Launch   day"),
+			p.New(span.Text("We &"), span.Text("ship")),
+			script.RawText("var hidden = 1"),
+		),
+	)
+	if got, want := Text(page), "Launch day\nWe & ship"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := article.New(
+		h1.Text("Launch day"),
+		p.Text("The quick brown fox jumps."),
+	)
+	after := article.New(
+		h1.Text("Launch day").Class("title"),
+		p.Text("The slow brown fox <jumps>."),
+	)
+
+	got := string(Diff(before, after).Render())
+	want := `<div class="text-diff"><p>Launch day</p><p>The <del>quick</del> <ins>slow</ins> brown fox <del>jumps.</del> <ins>&lt;jumps&gt;.</ins></p></div>`
+	if got != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffParagraphs(t *testing.T) {
+	before := article.New(p.Text("one"))
+	after := article.New(p.Text("one"), p.Text("two"))
+
+	got := string(Diff(before, after).Render())
+	want := `<div class="text-diff"><p>one</p><p><ins>two</ins></p></div>`
+	if got != want {
+		t.Errorf("Diff() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+// Package theme defines design tokens - colours, spacing, fonts - in Go and
+// renders them as CSS custom properties, so components and stylesheets share
+// one source of truth.
+//
+// The base tokens are written to :root and each variant's overrides to a
+// [data-theme="name"] rule, so the theme can be switched at runtime by
+// changing a data-theme attribute, on <html> for the whole page or on any
+// element for a subtree. A variant marked with Auto also applies when the
+// user's system prefers that colour scheme and no data-theme is set.
+//
+// Components refer to tokens through Var, which panics on an unknown token so
+// typos surface in tests rather than as silently missing styles.
+//
+// Usage:
+//
+//	var Theme = theme.New(theme.Tokens{
+//	    "color-bg":   "#ffffff",
+//	    "color-text": "#1a1a1a",
+//	    "space-2":    "0.5rem",
+//	    "font-body":  "system-ui, sans-serif",
+//	}).Variant("dark", theme.Tokens{
+//	    "color-bg":   "#121212",
+//	    "color-text": "#f0f0f0",
+//	}).Auto("dark")
+//
+//	head.New(Theme.Style(), assets.HeadStyles())
+//	div.New(...).Style(Theme.Declarations("padding", "space-2", "color", "color-text"))
+//	theme.Select(html.New(...), user.Theme)
+package theme
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Tokens maps token names, such as "color-primary", to CSS values. Names
+// become custom properties ("--color-primary"). Values are written verbatim
+// into CSS and must not come from user input.
+type Tokens map[string]string
+
+// Theme is a set of base tokens with named variants. Build it once at start
+// up; it is safe for concurrent use once built.
+type Theme struct {
+	tokens   Tokens
+	variants []variant
+}
+
+type variant struct {
+	name   string
+	tokens Tokens
+	auto   bool
+}
+
+// New creates a theme from its base tokens.
+func New(tokens Tokens) *Theme {
+	for name := range tokens {
+		check(name)
+	}
+	return &Theme{tokens: tokens}
+}
+
+// check panics on a token or variant name that is not a valid identifier.
+func check(name string) {
+	valid := name != ""
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			valid = false
+		}
+	}
+	if !valid {
+		panic(fmt.Sprintf("theme: invalid name %q", name))
+	}
+}
+
+// Variant adds a named variant overriding some of the base tokens. It panics
+// if an override names a token missing from the base, as every token needs a
+// default.
+func (t *Theme) Variant(name string, overrides Tokens) *Theme {
+	check(name)
+	for token := range overrides {
+		if _, ok := t.tokens[token]; !ok {
+			panic(fmt.Sprintf("theme: variant %q overrides unknown token %q", name, token))
+		}
+	}
+	t.variants = append(t.variants, variant{name: name, tokens: overrides})
+	return t
+}
+
+// Auto applies the named variant when the user's system prefers that colour
+// scheme, "dark" or "light", and no data-theme attribute selects a variant.
+func (t *Theme) Auto(name string) *Theme {
+	for i := range t.variants {
+		if t.variants[i].name == name {
+			t.variants[i].auto = true
+		}
+	}
+	return t
+}
+
+// Variants returns the names of the variants in the order they were added.
+func (t *Theme) Variants() []string {
+	names := make([]string, len(t.variants))
+	for i, v := range t.variants {
+		names[i] = v.name
+	}
+	return names
+}
+
+// Var returns the CSS reference to a token, such as "var(--color-primary)",
+// for use in stylesheets and style attributes. It panics on an unknown token.
+func (t *Theme) Var(name string) string {
+	if _, ok := t.tokens[name]; !ok {
+		panic(fmt.Sprintf("theme: unknown token %q", name))
+	}
+	return "var(--" + name + ")"
+}
+
+// Value returns the base value of a token and whether it exists, for places
+// CSS variables cannot reach, such as emails or generated images.
+func (t *Theme) Value(name string) (string, bool) {
+	v, ok := t.tokens[name]
+	return v, ok
+}
+
+// Declarations returns style declarations from property and token name
+// pairs: Declarations("color", "color-text", "padding", "space-2") returns
+// "color:var(--color-text);padding:var(--space-2)". It panics on an odd
+// number of arguments or an unknown token.
+func (t *Theme) Declarations(pairs ...string) string {
+	if len(pairs)%2 != 0 {
+		panic("theme: Declarations requires property and token pairs")
+	}
+	var b strings.Builder
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(pairs[i])
+		b.WriteByte(':')
+		b.WriteString(t.Var(pairs[i+1]))
+	}
+	return b.String()
+}
+
+// CSS returns the custom property declarations: the base tokens on :root and
+// each variant on its [data-theme] selector, with tokens in name order.
+func (t *Theme) CSS() string {
+	var b strings.Builder
+	rule(&b, ":root", t.tokens)
+	for _, v := range t.variants {
+		sel := `[data-theme="` + v.name + `"]`
+		if v.auto {
+			b.WriteString("@media (prefers-color-scheme: " + v.name + "){")
+			rule(&b, ":root:not([data-theme])", v.tokens)
+			b.WriteByte('}')
+		}
+		rule(&b, sel, v.tokens)
+	}
+	return b.String()
+}
+
+func rule(b *strings.Builder, selector string, tokens Tokens) {
+	names := make([]string, 0, len(tokens))
+	for name := range tokens {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	b.WriteString(selector)
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString("--" + name + ":" + tokens[name])
+	}
+	b.WriteByte('}')
+}
+
+// Style declares the theme's CSS with the assets style collector of the
+// render. It renders nothing in place.
+func (t *Theme) Style() node.Node {
+	return assets.CSS("theme", t.CSS())
+}
+
+// Select sets data-theme on n, switching it and its descendants to the named
+// variant. An empty name leaves n unchanged so the base or Auto variant
+// applies.
+func Select[T node.Node](n T, name string) T {
+	if name != "" {
+		n.SetAttribute("data-theme", name)
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package theme
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent/assets"
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+)
+
+func newTheme() *Theme {
+	return New(Tokens{
+		"color-bg":   "#fff",
+		"color-text": "#111",
+		"space-2":    "0.5rem",
+	}).Variant("dark", Tokens{
+		"color-bg":   "#000",
+		"color-text": "#eee",
+	}).Variant("contrast", Tokens{
+		"color-text": "#000",
+	}).Auto("dark")
+}
+
+func TestCSS(t *testing.T) {
+	want := `:root{--color-bg:#fff;--color-text:#111;--space-2:0.5rem}` +
+		`@media (prefers-color-scheme: dark){:root:not([data-theme]){--color-bg:#000;--color-text:#eee}}` +
+		`[data-theme="dark"]{--color-bg:#000;--color-text:#eee}` +
+		`[data-theme="contrast"]{--color-text:#000}`
+	if got := newTheme().CSS(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	th := newTheme()
+	if got := th.Var("color-bg"); got != "var(--color-bg)" {
+		t.Errorf("Var: got %q", got)
+	}
+	if got := th.Declarations("color", "color-text", "padding", "space-2"); got != "color:var(--color-text);padding:var(--space-2)" {
+		t.Errorf("Declarations: got %q", got)
+	}
+	if v, ok := th.Value("space-2"); !ok || v != "0.5rem" {
+		t.Errorf("Value: got %q, %v", v, ok)
+	}
+	if got := th.Variants(); len(got) != 2 || got[0] != "dark" || got[1] != "contrast" {
+		t.Errorf("Variants: got %v", got)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"unknown var", func() { newTheme().Var("color-primary") }},
+		{"odd declarations", func() { newTheme().Declarations("color") }},
+		{"unknown override", func() { newTheme().Variant("x", Tokens{"nope": "1"}) }},
+		{"invalid token", func() { New(Tokens{"a;b": "1"}) }},
+		{"invalid variant", func() { newTheme().Variant(`x"]`, nil) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestStyleAndSelect(t *testing.T) {
+	th := newTheme()
+	page := Select(html.New(
+		head.New(assets.HeadStyles()),
+		body.New(th.Style(), Select(div.Static("x"), "contrast"), Select(div.Static("y"), "")),
+	), "dark")
+	var buf bytes.Buffer
+	if err := assets.Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `<!DOCTYPE html><html data-theme="dark"><head><style>` + th.CSS() + `</style></head>` +
+		`<body><div data-theme="contrast">x</div><div>y</div></body></html>`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
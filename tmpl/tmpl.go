@@ -0,0 +1,127 @@
+// Package tmpl converts between fluent nodes and html/template, so pages can
+// move from templates to fluent one fragment at a time: fluent components
+// can be dropped into an existing layout, and existing templates can be
+// rendered inside a fluent tree.
+//
+// Output passed in either direction is trusted as it is. Fluent escapes the
+// content it renders and html/template escapes what it executes, but a
+// template.HTML value or a raw fluent node is only as safe as its source.
+//
+// Usage:
+//
+//	// A fluent component in a template layout.
+//	layout := template.Must(template.New("layout").Funcs(tmpl.Funcs()).Parse(
+//	    `<body>{{fluent .Nav}}<main>{{.Body}}</main></body>`))
+//	layout.Execute(w, map[string]any{"Nav": Nav(user), "Body": tmpl.HTML(Dashboard(data))})
+//
+//	// A template inside a fluent page.
+//	div.New(tmpl.Template(legacy, "sidebar", data))
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// HTML renders n as trusted template.HTML, which html/template inserts
+// without escaping it again.
+func HTML(n node.Node) template.HTML {
+	if n == nil {
+		return ""
+	}
+	return template.HTML(n.Render()) //nolint:gosec // fluent output is already escaped
+}
+
+// JS renders n as trusted template.JS, for nodes whose output is a script.
+func JS(n node.Node) template.JS {
+	if n == nil {
+		return ""
+	}
+	return template.JS(n.Render()) //nolint:gosec // the node's output is trusted script
+}
+
+// Funcs returns the template functions "fluent", which renders a node as
+// template.HTML, and "fluentJS", which renders it as template.JS.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"fluent":   HTML,
+		"fluentJS": JS,
+	}
+}
+
+// Trusted returns a node that writes h as it is.
+func Trusted(h template.HTML) node.Node {
+	return trusted(h)
+}
+
+type trusted string
+
+func (t trusted) Render(w ...io.Writer) []byte {
+	if len(w) > 0 && w[0] != nil {
+		_, _ = io.WriteString(w[0], string(t))
+		return nil
+	}
+	return []byte(t)
+}
+
+func (t trusted) RenderBuilder(buf *bytes.Buffer) {
+	buf.WriteString(string(t))
+}
+
+func (t trusted) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// SetAttribute is a no-op as the markup is opaque.
+func (t trusted) SetAttribute(_ string, _ string) {
+	// trusted does not support attributes
+}
+
+// Template returns a node that executes the named template of t with data
+// each time it renders. The template's output is written as it is. If
+// execution fails nothing is written and, in renders with assertions
+// enabled, the error is reported through node.Fail.
+func Template(t *template.Template, name string, data any) node.Node {
+	return &executed{tmpl: t, name: name, data: data}
+}
+
+type executed struct {
+	tmpl *template.Template
+	name string
+	data any
+}
+
+func (e *executed) Render(w ...io.Writer) []byte {
+	return node.Render(e, w...)
+}
+
+// RenderBuilder executes the template into buf, discarding any partial
+// output if execution fails.
+func (e *executed) RenderBuilder(buf *bytes.Buffer) {
+	if e.tmpl == nil {
+		return
+	}
+	start := buf.Len()
+	if err := e.tmpl.ExecuteTemplate(buf, e.name, e.data); err != nil {
+		buf.Truncate(start)
+		node.Fail(buf, fmt.Errorf("tmpl: %w", err))
+	}
+}
+
+func (e *executed) Nodes() []node.Node {
+	return []node.Node{}
+}
+
+// Dynamic returns true as the template executes on every render.
+func (e *executed) Dynamic() bool {
+	return true
+}
+
+// SetAttribute is a no-op as the markup is opaque.
+func (e *executed) SetAttribute(_ string, _ string) {
+	// executed does not support attributes
+}
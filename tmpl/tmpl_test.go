@@ -0,0 +1,66 @@
+package tmpl
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+func TestFuncs(t *testing.T) {
+	layout := template.Must(template.New("layout").Funcs(Funcs()).Parse(
+		`<main>{{fluent .Nav}}{{.Body}}<script>var x = {{fluentJS .Script}};</script>{{.Title}}</main>`))
+	var buf bytes.Buffer
+	err := layout.Execute(&buf, map[string]any{
+		"Nav":    span.Text("a & b"),
+		"Body":   HTML(div.Text("body")),
+		"Script": Trusted(`{"n": 1}`),
+		"Title":  "<title>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<main><span>a &amp; b</span><div>body</div><script>var x = {"n": 1};</script>&lt;title&gt;</main>`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if HTML(nil) != "" || JS(nil) != "" {
+		t.Error("expected empty output for nil nodes")
+	}
+}
+
+func TestTrusted(t *testing.T) {
+	got := string(div.New(Trusted(`<b>legacy</b>`)).Render())
+	if want := `<div><b>legacy</b></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	legacy := template.Must(template.New("").Parse(
+		`{{define "greet"}}<p>Hello {{.}}</p>{{end}}{{define "broken"}}<p>{{.Missing}}</p>{{end}}`))
+
+	got := string(div.New(Template(legacy, "greet", "<you>")).Render())
+	if want := `<div><p>Hello &lt;you&gt;</p></div>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var errs []error
+	ctx := node.WithAssertions(context.Background(), func(err error) { errs = append(errs, err) })
+	var buf bytes.Buffer
+	if err := fluent.RenderContext(ctx, div.New(Template(legacy, "broken", 42)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div></div>`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "tmpl: ") {
+		t.Errorf("got %v, want one tmpl error", errs)
+	}
+}
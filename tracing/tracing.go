@@ -0,0 +1,115 @@
+// Package tracing reports renders to a distributed tracer, such as
+// OpenTelemetry, so render time shows up alongside the rest of a request.
+//
+// Each top-level Render and each named component (see node.Component) becomes
+// a span, nested as the components are, carrying the number of bytes written
+// and nodes in the tree. The package depends only on the standard library:
+// the application adapts its tracer to the Tracer function type.
+//
+// Usage with OpenTelemetry:
+//
+//	tracer := otel.Tracer("fluent")
+//	ctx := tracing.WithTracer(r.Context(), func(ctx context.Context, name string) (context.Context, func(tracing.Stats)) {
+//	    ctx, span := tracer.Start(ctx, name)
+//	    return ctx, func(s tracing.Stats) {
+//	        span.SetAttributes(attribute.Int("fluent.bytes", s.Bytes), attribute.Int("fluent.nodes", s.Nodes))
+//	        if s.Err != nil {
+//	            span.RecordError(s.Err)
+//	        }
+//	        span.End()
+//	    }
+//	})
+//	err := tracing.Render(ctx, page, w)
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/node"
+)
+
+// RenderSpan is the name of the span covering a whole Render.
+const RenderSpan = "fluent.render"
+
+// Stats describes a finished span's render.
+type Stats struct {
+	// Bytes is the size of the output written.
+	Bytes int
+	// Nodes is the number of nodes in the rendered tree. Content produced at
+	// render time, such as by node.Func, is not counted.
+	Nodes int
+	// Err is the error returned by a top-level Render. It is always nil for
+	// component spans.
+	Err error
+}
+
+// Tracer starts a span called name as a child of the span in ctx. It returns
+// a context carrying the new span, for the spans of nested components, and a
+// function that ends the span with the stats of its render.
+type Tracer func(ctx context.Context, name string) (context.Context, func(Stats))
+
+type tracerKey struct{}
+
+// WithTracer returns a context whose renders report spans to t. Renders
+// under a context without a tracer are not traced and pay no cost.
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	ctx = context.WithValue(ctx, tracerKey{}, t)
+	return node.WithComponentHook(ctx, hook)
+}
+
+// Render renders n to w like fluent.RenderContext, within a RenderSpan span
+// when ctx has a tracer.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) error {
+	t, ok := ctx.Value(tracerKey{}).(Tracer)
+	if !ok || t == nil {
+		return fluent.RenderContext(ctx, n, w, policy...)
+	}
+	ctx, end := t(ctx, RenderSpan)
+	cw := &counter{w: w}
+	err := fluent.RenderContext(ctx, n, cw, policy...)
+	end(Stats{Bytes: cw.n, Nodes: count(n), Err: err})
+	return err
+}
+
+// hook starts a span for each named component, making it the parent of the
+// spans of components nested within it.
+func hook(buf *bytes.Buffer, c *node.NamedComponent) func() {
+	s := node.SessionOf(buf)
+	t, ok := s.Context.Value(tracerKey{}).(Tracer)
+	if !ok || t == nil {
+		return nil
+	}
+	prev := s.Context
+	ctx, end := t(prev, c.Name())
+	s.Context = ctx
+	start := buf.Len()
+	return func() {
+		s.Context = prev
+		end(Stats{Bytes: buf.Len() - start, Nodes: count(c.Unwrap())})
+	}
+}
+
+// count returns the number of nodes in the tree rooted at n.
+func count(n node.Node) int {
+	total := 0
+	node.Walk(n, func(node.Node, int) bool {
+		total++
+		return true
+	})
+	return total
+}
+
+// counter counts the bytes written through it.
+type counter struct {
+	w io.Writer
+	n int
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+type spanKey struct{}
+
+type recorded struct {
+	name, parent string
+	stats        Stats
+}
+
+func recorder(spans *[]recorded) Tracer {
+	return func(ctx context.Context, name string) (context.Context, func(Stats)) {
+		parent, _ := ctx.Value(spanKey{}).(string)
+		return context.WithValue(ctx, spanKey{}, name), func(s Stats) {
+			*spans = append(*spans, recorded{name: name, parent: parent, stats: s})
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	var spans []recorded
+	ctx := WithTracer(context.Background(), recorder(&spans))
+
+	badge := node.Component("Badge", span.Static("new"))
+	page := div.New(node.Component("Card", div.New(badge)), span.Static("x"))
+	var buf bytes.Buffer
+	if err := Render(ctx, page, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []recorded{
+		{"Badge", "Card", Stats{Bytes: len("<span>new</span>"), Nodes: 2}},
+		{"Card", RenderSpan, Stats{Bytes: len("<div><span>new</span></div>"), Nodes: 4}},
+		{RenderSpan, "", Stats{Bytes: buf.Len(), Nodes: 8}},
+	}
+	if fmt.Sprint(spans) != fmt.Sprint(want) {
+		t.Errorf("got %+v, want %+v", spans, want)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	var spans []recorded
+	ctx, cancel := context.WithCancel(WithTracer(context.Background(), recorder(&spans)))
+	page := node.Func(func() node.Node {
+		cancel()
+		return span.Static("late")
+	})
+	err := Render(ctx, page, &bytes.Buffer{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if len(spans) != 1 || !errors.Is(spans[0].stats.Err, context.Canceled) {
+		t.Errorf("render span did not record the error: %+v", spans)
+	}
+}
+
+func TestUntraced(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(context.Background(), node.Component("Card", span.Static("a")), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<span>a</span>" {
+		t.Errorf("got %q", buf.String())
+	}
+}
@@ -0,0 +1,151 @@
+package turbo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/html5/attr/loading"
+	"github.com/jpl-au/fluent/node"
+)
+
+// FrameHeader is the request header naming the frame a Turbo Frame
+// navigation is for.
+const FrameHeader = "Turbo-Frame"
+
+// FrameNode is a <turbo-frame> element.
+type FrameNode struct {
+	id       string
+	children []node.Node
+	attrs    []node.Attribute
+}
+
+// Frame returns a <turbo-frame> with the given id around children.
+// Navigation inside the frame replaces only its content.
+//
+// Usage:
+//
+//	turbo.Frame("comments").Src("/posts/1/comments").Loading(loading.Lazy)
+func Frame(id string, children ...node.Node) *FrameNode {
+	return &FrameNode{id: id, children: children}
+}
+
+// ID returns the frame's id.
+func (f *FrameNode) ID() string {
+	return f.id
+}
+
+// Src sets the URL the frame loads its content from.
+func (f *FrameNode) Src(url string) *FrameNode {
+	f.SetAttribute("src", url)
+	return f
+}
+
+// Loading sets when a frame with a src loads: loading.Eager, the default,
+// or loading.Lazy, once the frame scrolls into view.
+func (f *FrameNode) Loading(l loading.Loading) *FrameNode {
+	f.SetAttribute("loading", string(l))
+	return f
+}
+
+// Target sets the frame navigated by links and forms inside this one, or
+// "_top" for the whole page.
+func (f *FrameNode) Target(target string) *FrameNode {
+	f.SetAttribute("target", target)
+	return f
+}
+
+func (f *FrameNode) Render(w ...io.Writer) []byte {
+	return node.Render(f, w...)
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (f *FrameNode) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, f)
+}
+
+// RenderBuilder writes the frame element around its children.
+func (f *FrameNode) RenderBuilder(buf *bytes.Buffer) {
+	buf.WriteString(`<turbo-frame id="`)
+	escape.WriteString(buf, f.id)
+	buf.WriteByte('"')
+	writeAttributes(buf, f.attrs)
+	buf.WriteByte('>')
+	for _, n := range f.children {
+		if n != nil {
+			n.RenderBuilder(buf)
+		}
+	}
+	buf.WriteString("</turbo-frame>")
+}
+
+// Nodes returns the frame's children.
+func (f *FrameNode) Nodes() []node.Node {
+	return f.children
+}
+
+// SetAttribute sets an attribute on the <turbo-frame> element, replacing
+// any earlier value. The value is escaped when written. Setting id changes
+// the frame's id.
+func (f *FrameNode) SetAttribute(key string, value string) {
+	if strings.EqualFold(key, "id") {
+		f.id = value
+		return
+	}
+	f.attrs = setAttribute(f.attrs, key, value)
+}
+
+// FrameID returns the id of the frame a request is for, or "" when it is
+// not a Turbo Frame navigation.
+func FrameID(r *http.Request) string {
+	return r.Header.Get(FrameHeader)
+}
+
+// FindFrame returns the frame in page with the given id, or nil. Frames
+// produced at render time, such as by node.Func, are not found.
+func FindFrame(page node.Node, id string) *FrameNode {
+	var found *FrameNode
+	node.Walk(page, func(n node.Node, _ int) bool {
+		if found != nil {
+			return false
+		}
+		if f, ok := n.(*FrameNode); ok && f.id == id {
+			found = f
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Serve writes page as HTML, rendering only the requested frame when the
+// request is a Turbo Frame navigation for a frame in page. Turbo discards
+// everything outside that frame, so the rest of the page is never rendered.
+// Nothing is written if the render fails.
+//
+// Usage:
+//
+//	func post(w http.ResponseWriter, r *http.Request) {
+//	    _ = turbo.Serve(w, r, PostPage(load(r)))
+//	}
+func Serve(w http.ResponseWriter, r *http.Request, page node.Node) error {
+	n := page
+	if id := FrameID(r); id != "" {
+		if f := FindFrame(page, id); f != nil {
+			n = f
+		}
+	}
+	buf := fluent.NewBuffer()
+	defer fluent.PutBuffer(buf)
+	if err := fluent.RenderContext(r.Context(), n, buf); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Add("Vary", FrameHeader)
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,84 @@
+package turbo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/attr/loading"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/h1"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/turbo"
+)
+
+func TestFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"children", turbo.Frame("nav", p.Text("Links")),
+			`<turbo-frame id="nav"><p>Links</p></turbo-frame>`},
+		{"lazy", turbo.Frame("comments").Src("/posts/1/comments?page=2&x=1").Loading(loading.Lazy),
+			`<turbo-frame id="comments" src="/posts/1/comments?page=2&amp;x=1" loading="lazy"></turbo-frame>`},
+		{"target", turbo.Frame("f").Target("_top"),
+			`<turbo-frame id="f" target="_top"></turbo-frame>`},
+		{"target escaped", turbo.Frame("f").Target(`a"b`),
+			`<turbo-frame id="f" target="a&#34;b"></turbo-frame>`},
+		{"attribute escaped", attr(turbo.Frame("f"), "data-v", "a&b"),
+			`<turbo-frame id="f" data-v="a&amp;b"></turbo-frame>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.node.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	rendered := 0
+	page := div.New(
+		h1.Text("Post"),
+		node.Func(func() node.Node {
+			rendered++
+			return p.Text("expensive")
+		}),
+		div.New(turbo.Frame("comments", p.Text("Comment"))),
+	)
+	tests := []struct {
+		name  string
+		frame string
+		want  string
+		calls int
+	}{
+		{"frame", "comments", `<turbo-frame id="comments"><p>Comment</p></turbo-frame>`, 0},
+		{"unknown frame", "other", `<div><h1>Post</h1><p>expensive</p><div><turbo-frame id="comments"><p>Comment</p></turbo-frame></div></div>`, 1},
+		{"page", "", `<div><h1>Post</h1><p>expensive</p><div><turbo-frame id="comments"><p>Comment</p></turbo-frame></div></div>`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered = 0
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.frame != "" {
+				r.Header.Set(turbo.FrameHeader, tt.frame)
+			}
+			w := httptest.NewRecorder()
+			if err := turbo.Serve(w, r, page); err != nil {
+				t.Fatal(err)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if rendered != tt.calls {
+				t.Errorf("page rendered %d times, want %d", rendered, tt.calls)
+			}
+			if got := w.Header().Get("Vary"); got != turbo.FrameHeader {
+				t.Errorf("Vary = %q, want %q", got, turbo.FrameHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package turbo writes Hotwire Turbo markup from fluent nodes: the
+// <turbo-stream> envelopes that update parts of a page, <turbo-frame>
+// elements, and the response helpers that serve them.
+//
+// Usage:
+//
+//	func create(w http.ResponseWriter, r *http.Request) {
+//	    msg := save(r)
+//	    if turbo.Accepts(r) {
+//	        _ = turbo.Respond(w, r,
+//	            turbo.Append("messages", Message(msg)),
+//	            turbo.Update("message-count", text.Textf("%d", count())),
+//	        )
+//	        return
+//	    }
+//	    http.Redirect(w, r, "/messages", http.StatusSeeOther)
+//	}
+package turbo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/escape"
+	"github.com/jpl-au/fluent/node"
+)
+
+// ContentType is the media type of a Turbo Stream response.
+const ContentType = "text/vnd.turbo-stream.html"
+
+// Action is a Turbo Stream action.
+type Action string
+
+// Turbo Stream actions.
+const (
+	ActionAppend  Action = "append"
+	ActionPrepend Action = "prepend"
+	ActionReplace Action = "replace"
+	ActionUpdate  Action = "update"
+	ActionRemove  Action = "remove"
+	ActionBefore  Action = "before"
+	ActionAfter   Action = "after"
+	ActionRefresh Action = "refresh"
+)
+
+// StreamNode is a <turbo-stream> element. Its content is wrapped in the
+// <template> Turbo expects, and left out for actions that take none.
+type StreamNode struct {
+	action   Action
+	target   string
+	targets  bool // target is a CSS selector
+	content  []node.Node
+	attrs    []node.Attribute
+	template bool
+}
+
+// Stream returns a stream applying action to the element whose id is
+// target, with content as the new markup.
+func Stream(action Action, target string, content ...node.Node) *StreamNode {
+	return &StreamNode{action: action, target: target, content: content, template: action != ActionRemove && action != ActionRefresh}
+}
+
+// StreamAll returns a stream applying action to every element matching the
+// CSS selector, with content as the new markup.
+func StreamAll(action Action, selector string, content ...node.Node) *StreamNode {
+	s := Stream(action, selector, content...)
+	s.targets = true
+	return s
+}
+
+// Append adds content to the end of the element with id target.
+func Append(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionAppend, target, content...)
+}
+
+// Prepend adds content to the start of the element with id target.
+func Prepend(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionPrepend, target, content...)
+}
+
+// Replace replaces the element with id target by content.
+func Replace(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionReplace, target, content...)
+}
+
+// Update replaces the content of the element with id target, keeping the
+// element itself.
+func Update(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionUpdate, target, content...)
+}
+
+// Before inserts content before the element with id target.
+func Before(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionBefore, target, content...)
+}
+
+// After inserts content after the element with id target.
+func After(target string, content ...node.Node) *StreamNode {
+	return Stream(ActionAfter, target, content...)
+}
+
+// Remove removes the element with id target.
+func Remove(target string) *StreamNode {
+	return Stream(ActionRemove, target)
+}
+
+// Refresh asks the page to reload itself, morphing where the page opts in.
+// A non-empty requestID lets the client that made the change ignore it.
+func Refresh(requestID string) *StreamNode {
+	s := Stream(ActionRefresh, "")
+	if requestID != "" {
+		s.SetAttribute("request-id", requestID)
+	}
+	return s
+}
+
+// Morph sets method="morph", so replace and update morph the existing
+// elements rather than swapping them.
+func (s *StreamNode) Morph() *StreamNode {
+	s.SetAttribute("method", "morph")
+	return s
+}
+
+func (s *StreamNode) Render(w ...io.Writer) []byte {
+	return node.Render(s, w...)
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (s *StreamNode) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, s)
+}
+
+// RenderBuilder writes the stream element and its template.
+func (s *StreamNode) RenderBuilder(buf *bytes.Buffer) {
+	buf.WriteString(`<turbo-stream action="`)
+	escape.WriteString(buf, string(s.action))
+	buf.WriteByte('"')
+	if s.target != "" {
+		if s.targets {
+			buf.WriteString(` targets="`)
+		} else {
+			buf.WriteString(` target="`)
+		}
+		escape.WriteString(buf, s.target)
+		buf.WriteByte('"')
+	}
+	writeAttributes(buf, s.attrs)
+	buf.WriteByte('>')
+	if s.template {
+		buf.WriteString("<template>")
+		for _, n := range s.content {
+			if n != nil {
+				n.RenderBuilder(buf)
+			}
+		}
+		buf.WriteString("</template>")
+	}
+	buf.WriteString("</turbo-stream>")
+}
+
+// Nodes returns the stream's content.
+func (s *StreamNode) Nodes() []node.Node {
+	if !s.template {
+		return []node.Node{}
+	}
+	return s.content
+}
+
+// SetAttribute sets an attribute on the <turbo-stream> element, replacing
+// any earlier value. The value is escaped when written. The action, target
+// and targets attributes are set by the constructor.
+func (s *StreamNode) SetAttribute(key string, value string) {
+	s.attrs = setAttribute(s.attrs, key, value)
+}
+
+// setAttribute returns attrs with key set to value, replacing any earlier
+// value.
+func setAttribute(attrs []node.Attribute, key, value string) []node.Attribute {
+	for i := range attrs {
+		if strings.EqualFold(attrs[i].Key, key) {
+			attrs[i].Value = value
+			return attrs
+		}
+	}
+	return append(attrs, node.Attribute{Key: key, Value: value})
+}
+
+// writeAttributes writes attrs, each preceded by a space, escaping their
+// values.
+func writeAttributes(buf *bytes.Buffer, attrs []node.Attribute) {
+	for _, a := range attrs {
+		buf.WriteString(" " + a.Key + `="`)
+		escape.WriteString(buf, a.Value)
+		buf.WriteByte('"')
+	}
+}
+
+// Accepts reports whether the request's Accept header lists ContentType, as
+// Turbo sends for form submissions that can take a stream response.
+func Accepts(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		for _, t := range strings.Split(v, ",") {
+			mt, _, _ := strings.Cut(t, ";")
+			if strings.EqualFold(strings.TrimSpace(mt), ContentType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Respond writes streams as a Turbo Stream response, rendering them with
+// the request's context. Nothing is written if the render fails.
+func Respond(w http.ResponseWriter, r *http.Request, streams ...node.Node) error {
+	buf := fluent.NewBuffer()
+	defer fluent.PutBuffer(buf)
+	if err := fluent.RenderContext(r.Context(), node.FuncNodes(func() []node.Node { return streams }), buf); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ContentType+"; charset=utf-8")
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,86 @@
+package turbo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/li"
+	"github.com/jpl-au/fluent/node"
+	"github.com/jpl-au/fluent/text"
+	"github.com/jpl-au/fluent/turbo"
+)
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name string
+		node node.Node
+		want string
+	}{
+		{"append", turbo.Append("messages", li.Text("Hi")),
+			`<turbo-stream action="append" target="messages"><template><li>Hi</li></template></turbo-stream>`},
+		{"update", turbo.Update("count", text.Text("<3>")),
+			`<turbo-stream action="update" target="count"><template>&lt;3&gt;</template></turbo-stream>`},
+		{"replace morph", turbo.Replace("card", li.Text("x")).Morph(),
+			`<turbo-stream action="replace" target="card" method="morph"><template><li>x</li></template></turbo-stream>`},
+		{"remove", turbo.Remove(`a"b`),
+			`<turbo-stream action="remove" target="a&#34;b"></turbo-stream>`},
+		{"all", turbo.StreamAll(turbo.ActionRemove, ".toast"),
+			`<turbo-stream action="remove" targets=".toast"></turbo-stream>`},
+		{"refresh", turbo.Refresh("abc"),
+			`<turbo-stream action="refresh" request-id="abc"></turbo-stream>`},
+		{"refresh escaped", turbo.Refresh(`a"b`),
+			`<turbo-stream action="refresh" request-id="a&#34;b"></turbo-stream>`},
+		{"attribute escaped", attr(turbo.Remove("x"), "data-v", "a&b"),
+			`<turbo-stream action="remove" target="x" data-v="a&amp;b"></turbo-stream>`},
+		{"nil content", turbo.Prepend("list", nil),
+			`<turbo-stream action="prepend" target="list"><template></template></turbo-stream>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.node.Render()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// attr sets an attribute on n and returns it.
+func attr(n node.Node, key, value string) node.Node {
+	n.SetAttribute(key, value)
+	return n
+}
+
+func TestAccepts(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"text/vnd.turbo-stream.html, text/html, application/xhtml+xml", true},
+		{"text/html;q=0.9, TEXT/VND.TURBO-STREAM.HTML;q=1", true},
+		{"text/html", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := turbo.Accepts(r); got != tt.want {
+			t.Errorf("Accepts(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestRespond(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	if err := turbo.Respond(w, r, turbo.Remove("a"), turbo.Remove("b")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Header().Get("Content-Type"), "text/vnd.turbo-stream.html; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	want := `<turbo-stream action="remove" target="a"></turbo-stream><turbo-stream action="remove" target="b"></turbo-stream>`
+	if got := w.Body.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
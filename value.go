@@ -0,0 +1,117 @@
+package fluent
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// WithValue returns a context carrying val under key, for rendering with
+// RenderContext. Nodes read it with Value, typically inside node.FuncContext,
+// so deeply nested components can use request state such as the current
+// user without it being passed through every constructor.
+//
+// As with context.WithValue, key should be of an unexported type.
+func WithValue(ctx context.Context, key, val any) context.Context {
+	return context.WithValue(ctx, key, val)
+}
+
+// Value returns the value of type T stored under key in ctx. ok is false when
+// there is no value or it has another type.
+//
+// Usage:
+//
+//	node.FuncContext(func(ctx context.Context) node.Node {
+//	    theme, _ := fluent.Value[string](ctx, themeKey{})
+//	    return div.New(...).Class("card card-" + theme)
+//	})
+func Value[T any](ctx context.Context, key any) (T, bool) {
+	v, ok := ctx.Value(key).(T)
+	return v, ok
+}
+
+// Provide makes val available under key to n and its descendants while they
+// render, overriding any value further up. Unlike WithValue it is part of the
+// tree, so a component can provide values to its own children, and it also
+// works with a plain Render.
+//
+// Usage:
+//
+//	fluent.Provide(themeKey{}, "dark", div.New(
+//	    Sidebar(),
+//	))
+func Provide(key, val any, n node.Node) node.Node {
+	return &provider{key: key, val: val, node: n}
+}
+
+type provider struct {
+	key, val any
+	node     node.Node
+}
+
+func (p *provider) Render(w ...io.Writer) []byte {
+	buf := NewBuffer()
+	p.RenderBuilder(buf)
+
+	if len(w) > 0 && w[0] != nil {
+		_, _ = buf.WriteTo(w[0])
+		PutBuffer(buf)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo implements io.WriterTo, rendering to w and returning the number of
+// bytes written and any write error.
+func (p *provider) WriteTo(w io.Writer) (int64, error) {
+	return node.WriteTo(w, p)
+}
+
+// RenderBuilder renders the wrapped node with the value added to the render
+// context. Outside a context-aware render a session is created for the
+// subtree, and anything it defers is expanded before returning.
+func (p *provider) RenderBuilder(buf *bytes.Buffer) {
+	if p.node == nil {
+		return
+	}
+	if s := node.SessionOf(buf); s != nil {
+		prev := s.Context
+		s.Context = context.WithValue(prev, p.key, p.val)
+		defer func() { s.Context = prev }()
+		p.node.RenderBuilder(buf)
+		return
+	}
+
+	tmp := NewBuffer()
+	defer PutBuffer(tmp)
+	s := &node.Session{Context: context.WithValue(context.Background(), p.key, p.val)}
+	release := node.Bind(tmp, s)
+	defer release()
+	p.node.RenderBuilder(tmp)
+	if s.Deferred() {
+		s.Expand(buf, tmp.Bytes())
+		return
+	}
+	buf.Write(tmp.Bytes())
+}
+
+func (p *provider) Nodes() []node.Node {
+	if p.node == nil {
+		return []node.Node{}
+	}
+	return []node.Node{p.node}
+}
+
+// Dynamic returns true as descendants reading the value render at render time.
+func (p *provider) Dynamic() bool {
+	return true
+}
+
+// SetAttribute forwards the attribute to the wrapped node.
+func (p *provider) SetAttribute(key string, value string) {
+	if p.node != nil {
+		p.node.SetAttribute(key, value)
+	}
+}
@@ -0,0 +1,79 @@
+package fluent_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/span"
+	"github.com/jpl-au/fluent/node"
+)
+
+type themeKey struct{}
+
+func themed() node.Node {
+	return node.FuncContext(func(ctx context.Context) node.Node {
+		theme, ok := fluent.Value[string](ctx, themeKey{})
+		if !ok {
+			theme = "none"
+		}
+		return span.Text(theme)
+	})
+}
+
+func TestValue(t *testing.T) {
+	page := div.New(
+		themed(),
+		fluent.Provide(themeKey{}, "dark", div.New(
+			themed(),
+			fluent.Provide(themeKey{}, "contrast", themed()),
+			themed(),
+		)),
+		themed(),
+	)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"without value", context.Background(), "<div><span>none</span><div><span>dark</span><span>contrast</span><span>dark</span></div><span>none</span></div>"},
+		{"with value", fluent.WithValue(context.Background(), themeKey{}, "light"), "<div><span>light</span><div><span>dark</span><span>contrast</span><span>dark</span></div><span>light</span></div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := fluent.RenderContext(tt.ctx, page, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if got, want := string(page.Render()), tests[0].want; got != want {
+		t.Errorf("Render: got %q, want %q", got, want)
+	}
+}
+
+func TestValueType(t *testing.T) {
+	ctx := fluent.WithValue(context.Background(), themeKey{}, 42)
+	if _, ok := fluent.Value[string](ctx, themeKey{}); ok {
+		t.Error("Value returned a value of the wrong type")
+	}
+	if v, ok := fluent.Value[int](ctx, themeKey{}); !ok || v != 42 {
+		t.Errorf("got %v, %v", v, ok)
+	}
+}
+
+func TestFuncNodesContext(t *testing.T) {
+	n := fluent.Provide(themeKey{}, "x", node.FuncNodesContext(func(ctx context.Context) []node.Node {
+		v, _ := fluent.Value[string](ctx, themeKey{})
+		return []node.Node{span.Text(v), nil, span.Text(v)}
+	}))
+	if got := string(n.Render()); got != "<span>x</span><span>x</span>" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package fluent
+
+import (
+	"io"
+
+	"github.com/jpl-au/fluent/node"
+)
+
+// WriterTo returns n as an io.WriterTo, so any node composes with plumbing
+// built on the standard interfaces and reports byte counts and write
+// errors, which Render discards. Nodes that
+// implement io.WriterTo themselves, as the node and text types do, are
+// returned as they are; others are rendered through a pooled buffer.
+//
+// Usage:
+//
+//	n, err := fluent.WriterTo(page).WriteTo(w)
+//	if err != nil {
+//	    log.Printf("wrote %d bytes: %v", n, err)
+//	}
+func WriterTo(n node.Node) io.WriterTo {
+	if wt, ok := n.(io.WriterTo); ok {
+		return wt
+	}
+	return writerTo{n}
+}
+
+type writerTo struct {
+	node node.Node
+}
+
+func (w writerTo) WriteTo(dst io.Writer) (int64, error) {
+	return node.WriteTo(dst, w.node)
+}
@@ -0,0 +1,26 @@
+package fluent_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/text"
+)
+
+func TestWriterTo(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := fluent.WriterTo(div.Text("hi")).WriteTo(&buf)
+	if want := "<div>hi</div>"; err != nil || buf.String() != want || n != int64(len(want)) {
+		t.Errorf("got %q, %d, %v; want %q", buf.String(), n, err, want)
+	}
+
+	txt := text.Text("x")
+	if fluent.WriterTo(txt) != txt {
+		t.Error("expected a node implementing io.WriterTo to be returned as is")
+	}
+	if n, err := fluent.WriterTo(nil).WriteTo(&buf); n != 0 || err != nil {
+		t.Errorf("got %d, %v for nil, want 0, nil", n, err)
+	}
+}
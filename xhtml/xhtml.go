@@ -0,0 +1,46 @@
+// Package xhtml renders nodes as well-formed XML, for XHTML email pipelines
+// and XML documents such as sitemaps and SVG files.
+//
+// The mode is chosen per render: Render renders as usual and rewrites the
+// output so that void elements are self-closing, every attribute is quoted
+// and has a value, and text and attribute values are escaped by XML rules.
+// HTML entities such as &nbsp; are written as the characters they stand
+// for, script and style content needing it is wrapped in CDATA, and the
+// characters XML forbids are replaced with U+FFFD. An <html> root without a
+// namespace is given the XHTML one.
+//
+// Usage:
+//
+//	w.Header().Set("Content-Type", "application/xhtml+xml; charset=utf-8")
+//	err := xhtml.Render(r.Context(), page, w)
+package xhtml
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/jpl-au/fluent"
+	"github.com/jpl-au/fluent/internal/xmlconv"
+	"github.com/jpl-au/fluent/node"
+)
+
+// Namespace is the XHTML namespace added to an <html> root.
+const Namespace = xmlconv.Namespace
+
+// Render renders n to w as XML through fluent.RenderContext.
+func Render(ctx context.Context, n node.Node, w io.Writer, policy ...fluent.DeadlinePolicy) error {
+	var buf bytes.Buffer
+	err := fluent.RenderContext(ctx, n, &buf, policy...)
+	if buf.Len() > 0 {
+		if _, werr := io.WriteString(w, Convert(buf.String())); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// Convert rewrites rendered HTML as XML.
+func Convert(src string) string {
+	return xmlconv.Convert(src)
+}
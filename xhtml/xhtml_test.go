@@ -0,0 +1,81 @@
+package xhtml
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/fluent/html5/body"
+	"github.com/jpl-au/fluent/html5/br"
+	"github.com/jpl-au/fluent/html5/div"
+	"github.com/jpl-au/fluent/html5/head"
+	"github.com/jpl-au/fluent/html5/html"
+	"github.com/jpl-au/fluent/html5/input"
+	"github.com/jpl-au/fluent/html5/p"
+	"github.com/jpl-au/fluent/html5/script"
+	"github.com/jpl-au/fluent/html5/title"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"void", `<p>a<br>b</p>`, `<p>a<br />b</p>`},
+		{"void end tag", `<br></br>`, `<br />`},
+		{"bare attribute", `<input disabled name=q>`, `<input disabled="" name="q" />`},
+		{"attribute escaping", `<a title='say "hi"' href="?a=1&b=2">x</a>`, `<a title="say &quot;hi&quot;" href="?a=1&amp;b=2">x</a>`},
+		{"entities", `<p>Fish&nbsp;&amp;&nbsp;chips &copy;</p>`, "<p>Fish\u00a0&amp;\u00a0chips \u00a9</p>"},
+		{"bare ampersand", `<p>R&D</p>`, `<p>R&amp;D</p>`},
+		{"script", `<script>if (a < b && c) {}</script>`, `<script><![CDATA[if (a < b && c) {}]]></script>`},
+		{"plain script", `<script>go()</script>`, `<script>go()</script>`},
+		{"cdata end", `<style>a::after{content:"]]>&"}</style>`, `<style><![CDATA[a::after{content:"]]]]><![CDATA[>&"}]]></style>`},
+		{"comment", `<!-- a -- b -->`, `<!-- a - - b -->`},
+		{"svg case", `<svg viewBox="0 0 1 1"><linearGradient gradientUnits="x"></linearGradient></svg>`, `<svg viewBox="0 0 1 1"><linearGradient gradientUnits="x"></linearGradient></svg>`},
+		{"namespace", `<!DOCTYPE html><html lang="en"><body></body></html>`, `<!DOCTYPE html><html lang="en" xmlns="http://www.w3.org/1999/xhtml"><body></body></html>`},
+		{"existing namespace", `<html xmlns="urn:x"></html>`, `<html xmlns="urn:x"></html>`},
+		{"control characters", "<p>a\x00b\x1bc</p>", "<p>a\ufffdb\ufffdc</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.src); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	page := html.New(
+		head.New(title.Text("Tom & Jerry")),
+		body.New(
+			div.New(p.Text("a < b"), br.New(), input.Text("q", "x").Disabled()),
+			script.RawText("if (a < b) {}"),
+		),
+	)
+	var buf bytes.Buffer
+	if err := Render(context.Background(), page, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	// The doctype is not XML; skip it before checking well-formedness.
+	dec := xml.NewDecoder(strings.NewReader(strings.TrimPrefix(out, "<!DOCTYPE html>")))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("not well-formed: %v\n%s", err, out)
+		}
+	}
+	for _, want := range []string{`<br />`, `disabled="disabled"`, `xmlns="` + Namespace + `"`, `<title>Tom &amp; Jerry</title>`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("got %q, want it to contain %q", out, want)
+		}
+	}
+}